@@ -0,0 +1,68 @@
+package pop
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// Columns of the actors, set from the context of the connection by the
+// actor function, see SetActorFunc.
+const (
+	createdByColumn = "created_by"
+	updatedByColumn = "updated_by"
+)
+
+var actorFunc func(ctx context.Context) interface{}
+
+// SetActorFunc sets the function returning the actor, e.g. the ID of the
+// signed in user, from the context of the connection. The actor is set in
+// the fields of the created_by and updated_by columns of the models: both
+// on Create, unless created_by is already set, and updated_by on Update.
+// A nil actor leaves them as they are.
+//
+//	pop.SetActorFunc(func(ctx context.Context) interface{} {
+//		if u, ok := ctx.Value(currentUserKey).(*User); ok {
+//			return u.ID
+//		}
+//		return nil
+//	})
+func SetActorFunc(f func(ctx context.Context) interface{}) {
+	actorFunc = f
+}
+
+// actor returns the actor of the context, or nil.
+func actor(ctx context.Context) interface{} {
+	if actorFunc == nil || ctx == nil {
+		return nil
+	}
+	return actorFunc(ctx)
+}
+
+func (m *Model) setCreatedBy(actor interface{}) {
+	m.setActor(createdByColumn, actor, false)
+}
+
+func (m *Model) setUpdatedBy(actor interface{}) {
+	m.setActor(updatedByColumn, actor, true)
+}
+
+// setActor sets the field of the column to the actor, not overriding the
+// one already set unless override is true.
+func (m *Model) setActor(column string, actor interface{}, override bool) {
+	if actor == nil {
+		return
+	}
+	v := reflect.Indirect(reflect.ValueOf(m.Value))
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	f, ok := fieldByColumn(v, column)
+	if !ok || (!override && !f.IsZero()) {
+		return
+	}
+	if err := setColumn(v, column, actor); err != nil {
+		log(logging.Warn, "could not set %s: %s", column, err)
+	}
+}
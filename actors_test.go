@@ -0,0 +1,70 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type actorKey struct{}
+
+type auditedWidget struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	CreatedBy int       `db:"created_by"`
+	UpdatedBy nulls.Int `db:"updated_by"`
+}
+
+func (auditedWidget) TableName() string {
+	return "audited_widgets"
+}
+
+func Test_SetActorFunc(t *testing.T) {
+	r := require.New(t)
+
+	SetActorFunc(func(ctx context.Context) interface{} {
+		return ctx.Value(actorKey{})
+	})
+	defer SetActorFunc(nil)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE audited_widgets (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, created_by INTEGER, updated_by INTEGER)").Exec())
+
+	alice := c.WithContext(context.WithValue(context.Background(), actorKey{}, 1))
+	bob := c.WithContext(context.WithValue(context.Background(), actorKey{}, 2))
+
+	w := &auditedWidget{Name: "bolt"}
+	r.NoError(alice.Create(w))
+	r.Equal(1, w.CreatedBy)
+	r.Equal(nulls.NewInt(1), w.UpdatedBy)
+
+	r.NoError(bob.Update(w))
+	r.Equal(1, w.CreatedBy)
+	r.Equal(nulls.NewInt(2), w.UpdatedBy)
+
+	found := &auditedWidget{}
+	r.NoError(c.Find(found, w.ID))
+	r.Equal(1, found.CreatedBy)
+	r.Equal(nulls.NewInt(2), found.UpdatedBy)
+
+	_, err := alice.Where("id = ?", w.ID).UpdateQuery(&auditedWidget{Name: "nut"}, "name")
+	r.NoError(err)
+	r.NoError(c.Find(found, w.ID))
+	r.Equal("nut", found.Name)
+	r.Equal(nulls.NewInt(1), found.UpdatedBy)
+
+	preset := &auditedWidget{Name: "nut", CreatedBy: 3}
+	r.NoError(bob.Create(preset))
+	r.Equal(3, preset.CreatedBy)
+	r.Equal(nulls.NewInt(2), preset.UpdatedBy)
+
+	// without actor, the fields are left as they are.
+	r.NoError(c.Update(preset))
+	r.NoError(c.Find(found, preset.ID))
+	r.Equal(nulls.NewInt(2), found.UpdatedBy)
+}
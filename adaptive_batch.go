@@ -0,0 +1,204 @@
+package pop
+
+import (
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// AdaptiveBatchSize, given as the batch size of CreateMany or EachBatch,
+// sizes each batch from the latencies of the previous ones on the same
+// table, for its statement to take about the target of
+// SetAdaptiveBatchTarget. The latencies are kept for the life of the
+// process, the first batch of a table has 100 rows.
+//
+//	err := tx.CreateMany(&events, pop.AdaptiveBatchSize)
+const AdaptiveBatchSize = -1
+
+// bounds of the adaptive batch sizes.
+const (
+	adaptiveBatchInitial = 100
+	adaptiveBatchMax     = 10000
+)
+
+// target latency of the statements of the adaptive batches.
+var adaptiveBatchTarget = 250 * time.Millisecond
+
+// SetAdaptiveBatchTarget sets the latency the statements of the batches of
+// AdaptiveBatchSize aim at. Defaults to 250ms.
+func SetAdaptiveBatchTarget(d time.Duration) {
+	adaptiveBatchTarget = d
+}
+
+// latencyHistogram is a histogram of the latencies per row of the
+// statements of a table, in buckets of powers of 2 of nanoseconds. It
+// decays to follow the recent latencies.
+type latencyHistogram struct {
+	buckets [64]float64
+	total   float64
+}
+
+// histogramDecay is the number of observations past which the counts are
+// halved.
+const histogramDecay = 64
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	if d < 1 {
+		d = 1
+	}
+	h.buckets[int(math.Log2(float64(d)))]++
+	h.total++
+	if h.total > histogramDecay {
+		for i := range h.buckets {
+			h.buckets[i] /= 2
+		}
+		h.total /= 2
+	}
+}
+
+// quantile returns the upper bound of the bucket of the q quantile.
+func (h *latencyHistogram) quantile(q float64) (time.Duration, bool) {
+	if h.total == 0 {
+		return 0, false
+	}
+	n := 0.0
+	for i, c := range h.buckets {
+		n += c
+		if n >= q*h.total {
+			return time.Duration(1) << uint(i+1), true
+		}
+	}
+	return time.Duration(math.MaxInt64), true
+}
+
+// batchSizer sizes the adaptive batches of an operation on a table.
+type batchSizer struct {
+	mu   sync.Mutex
+	hist latencyHistogram
+	last int
+}
+
+// batchSizers are the batch sizers, by operation and table.
+var batchSizers sync.Map
+
+func batchSizerFor(op operation, table string) *batchSizer {
+	s, _ := batchSizers.LoadOrStore(string(op)+" "+table, &batchSizer{})
+	return s.(*batchSizer)
+}
+
+// next returns the size of the next batch: the number of rows the 90th
+// percentile of the latencies per row fits in the target, at most twice
+// the last size, and at most limit if positive.
+func (s *batchSizer) next(limit int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := adaptiveBatchInitial
+	if perRow, ok := s.hist.quantile(0.9); ok {
+		n = int(adaptiveBatchTarget / perRow)
+		if s.last > 0 && n > 2*s.last {
+			n = 2 * s.last
+		}
+	}
+	if n > adaptiveBatchMax {
+		n = adaptiveBatchMax
+	}
+	if limit > 0 && n > limit {
+		n = limit
+	}
+	if n < 1 {
+		n = 1
+	}
+	s.last = n
+	return n
+}
+
+// observe records the latency of a batch of rows.
+func (s *batchSizer) observe(rows int, d time.Duration) {
+	if rows < 1 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hist.observe(d / time.Duration(rows))
+}
+
+// maxBatchRows returns the maximum number of rows of the multi-row inserts
+// of the model, 0 if unknown: the number of parameters of the statements
+// is limited, to 65535 on PostgreSQL and MySQL and 32766 on SQLite, and
+// the size of the MySQL ones by max_allowed_packet.
+func maxBatchRows(c *Connection, m *Model) int {
+	var maxParams int
+	switch c.Dialect.Name() {
+	case namePostgreSQL, nameCockroach, nameMySQL, nameMariaDB:
+		maxParams = 65535
+	case nameSQLite3, nameLibSQL:
+		maxParams = 32766
+	}
+	max := 0
+	if cols := len(m.Columns().Cols); maxParams > 0 && cols > 0 {
+		max = maxParams / cols
+	}
+
+	switch c.Dialect.Name() {
+	case nameMySQL, nameMariaDB:
+		if packet := maxAllowedPacket(c); packet > 0 {
+			// half of the packet, for the escaping of the values
+			n := packet / 2 / estimateRowSize(reflect.Indirect(reflect.ValueOf(m.Value)))
+			if n < 1 {
+				n = 1
+			}
+			if max == 0 || n < max {
+				max = n
+			}
+		}
+	}
+	return max
+}
+
+// maxAllowedPackets are the max_allowed_packet of the MySQL databases, by
+// dialect.
+var maxAllowedPackets sync.Map
+
+// maxAllowedPacket returns the max_allowed_packet of the MySQL database,
+// 0 if it can not be read.
+func maxAllowedPacket(c *Connection) int {
+	if n, ok := maxAllowedPackets.Load(c.Dialect); ok {
+		return n.(int)
+	}
+	var n int
+	if err := c.Store.GetContext(c.Context(), &n, "SELECT @@max_allowed_packet"); err != nil {
+		log(logging.Warn, "could not read max_allowed_packet: %s", err)
+		return 0
+	}
+	maxAllowedPackets.Store(c.Dialect, n)
+	return n
+}
+
+// estimateRowSize returns an estimation of the size of the values of the
+// fields of the struct, not following the pointers.
+func estimateRowSize(v reflect.Value) int {
+	size := 0
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			switch f.Kind() {
+			case reflect.String:
+				size += len(f.String()) + 8
+			case reflect.Slice:
+				size += f.Len() + 8
+			case reflect.Struct:
+				size += estimateRowSize(f)
+			default:
+				size += 16
+			}
+		}
+	}
+	if size < 16 {
+		size = 16
+	}
+	return size
+}
@@ -0,0 +1,64 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BatchSizer(t *testing.T) {
+	r := require.New(t)
+
+	s := &batchSizer{}
+	r.Equal(adaptiveBatchInitial, s.next(0))
+	r.Equal(50, s.next(50))
+
+	// 1ms per row: 250 rows fit in the target, after doubling from 50
+	for i := 0; i < 10; i++ {
+		s.observe(100, 100*time.Millisecond)
+	}
+	r.Equal(100, s.next(0))
+	n := s.next(0)
+	r.True(n > 100 && n <= 250, n)
+
+	// slower rows shrink the batches right away
+	for i := 0; i < 200; i++ {
+		s.observe(10, 100*time.Millisecond)
+	}
+	r.True(s.next(0) <= 25)
+
+	s = &batchSizer{}
+	s.observe(1000, time.Microsecond)
+	s.last = adaptiveBatchMax
+	r.Equal(adaptiveBatchMax, s.next(0))
+}
+
+func Test_CreateMany_Adaptive(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE logged_events (id INTEGER PRIMARY KEY, name TEXT NOT NULL, source TEXT NOT NULL DEFAULT 'db', created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)").Exec())
+
+	r.Equal(32766/5, maxBatchRows(c, NewModel(&loggedEvent{}, nil)))
+
+	events := make([]loggedEvent, 250)
+	for i := range events {
+		events[i].Name = "e"
+	}
+	r.NoError(c.CreateMany(&events, AdaptiveBatchSize))
+	r.Equal(250, events[249].ID)
+	r.NotZero(batchSizerFor(Insert, "logged_events").hist.total)
+
+	batches := 0
+	loaded := []loggedEvent{}
+	r.NoError(c.EachBatch(&loaded, AdaptiveBatchSize, func() error {
+		batches++
+		return nil
+	}))
+	r.NotZero(batches)
+	r.NotZero(batchSizerFor(Select, "logged_events").hist.total)
+}
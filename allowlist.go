@@ -0,0 +1,350 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/internal/sqlparse"
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrNotAllowed is returned for the statements a restricted connection
+// refuses to run, see Allowlist.
+var ErrNotAllowed = errors.New("statement not allowed")
+
+// allowedFunctions are the SQL functions the restricted connections
+// always allow.
+var allowedFunctions = []string{
+	"abs", "avg", "ceil", "coalesce", "count", "floor", "greatest", "least",
+	"length", "lower", "max", "min", "nullif", "round", "sum", "upper",
+}
+
+// Allowlist is what the statements of a restricted connection may read.
+// Such a connection only runs single SELECT statements, of a conservative
+// subset of SQL, which only read the allowed columns of the allowed tables
+// and only call the allowed functions. Any other statement fails with
+// ErrNotAllowed, before being sent to the database. This makes it suitable
+// to run the SQL of the users, e.g. the queries of their reports, or of
+// plugins.
+//
+// The unqualified columns must be allowed in all the tables the
+// statement, and the statements it is a subquery of, read from.
+type Allowlist struct {
+	// Tables are the names of the allowed columns, by table name,
+	// qualified by its schema if the statements do. "*" allows all the
+	// columns of the table, and SELECT *.
+	Tables map[string][]string
+	// Functions are the names of the allowed functions, along with the
+	// common aggregate and scalar ones: abs, avg, ceil, coalesce, count,
+	// floor, greatest, least, length, lower, max, min, nullif, round, sum
+	// and upper.
+	Functions []string
+}
+
+// Restrict returns a copy of the connection restricted to the statements
+// allowed by a, see Allowlist. The restriction applies to the
+// transactions started from the copy, and adds up to the ones of the
+// connection.
+//
+//	reports := pop.Connect("production").Restrict(pop.Allowlist{
+//		Tables: map[string][]string{
+//			"orders": {"id", "customer_id", "total", "created_at"},
+//			"customers": {"id", "country"},
+//		},
+//	})
+//	err := reports.RawQuery(userSQL).All(&rows)
+func (c *Connection) Restrict(a Allowlist) *Connection {
+	al := compileAllowlist(a)
+	cn := c.copy()
+	cn.allowlists = append(append([]*allowlist{}, c.allowlists...), al)
+	cn.Store = restricted(cn.Store, []*allowlist{al})
+	return cn
+}
+
+// allowlist is the compiled Allowlist.
+type allowlist struct {
+	// columns are the allowed columns, by table.
+	columns   map[string]map[string]bool
+	functions map[string]bool
+}
+
+func compileAllowlist(a Allowlist) *allowlist {
+	al := &allowlist{columns: map[string]map[string]bool{}, functions: map[string]bool{}}
+	for table, cols := range a.Tables {
+		al.columns[table] = map[string]bool{}
+		for _, col := range cols {
+			al.columns[table][col] = true
+		}
+	}
+	for _, f := range append(allowedFunctions, a.Functions...) {
+		al.functions[strings.ToLower(f)] = true
+	}
+	return al
+}
+
+// allowScope are the tables a SELECT statement reads from, by name or
+// alias, and the aliases of its columns.
+type allowScope struct {
+	parent *allowScope
+	// tables are the names of the tables, blank for the subqueries.
+	tables  map[string]string
+	aliases map[string]bool
+}
+
+// checkSelect returns an ErrNotAllowed error if s reads something not
+// allowed.
+func (a *allowlist) checkSelect(s *sqlparse.Select, parent *allowScope) error {
+	scope := &allowScope{parent: parent, tables: map[string]string{}, aliases: map[string]bool{}}
+	for _, ref := range s.From {
+		if ref.Subquery != nil {
+			if err := a.checkSelect(ref.Subquery, parent); err != nil {
+				return err
+			}
+			scope.tables[ref.Alias] = ""
+			continue
+		}
+		table := ref.Name
+		if ref.Schema != "" {
+			table = ref.Schema + "." + ref.Name
+		}
+		if a.columns[table] == nil {
+			return fmt.Errorf("%w: table %s", ErrNotAllowed, table)
+		}
+		name := ref.Alias
+		if name == "" {
+			name = ref.Name
+		}
+		scope.tables[name] = table
+	}
+
+	exprs := []sqlparse.Expr{}
+	for _, ref := range s.From {
+		exprs = append(exprs, ref.On)
+	}
+	for _, item := range s.Columns {
+		if item.Star {
+			if err := a.checkStar(item.Table, scope); err != nil {
+				return err
+			}
+		}
+		exprs = append(exprs, item.Expr)
+	}
+	exprs = append(exprs, s.Where, s.Having, s.Limit, s.Offset)
+	exprs = append(exprs, s.GroupBy...)
+	for _, e := range exprs {
+		if err := a.checkExpr(e, scope); err != nil {
+			return err
+		}
+	}
+
+	// only ORDER BY takes the aliases over the columns of the tables
+	for _, item := range s.Columns {
+		if item.Alias != "" {
+			scope.aliases[item.Alias] = true
+		}
+	}
+	for _, e := range s.OrderBy {
+		if err := a.checkExpr(e, scope); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *allowlist) checkStar(table string, scope *allowScope) error {
+	for name, t := range scope.tables {
+		if table != "" && name != table {
+			continue
+		}
+		if t != "" && !a.columns[t]["*"] {
+			return fmt.Errorf("%w: all the columns of table %s", ErrNotAllowed, t)
+		}
+	}
+	if _, ok := scope.tables[table]; table != "" && !ok {
+		return fmt.Errorf("%w: unknown table %s", ErrNotAllowed, table)
+	}
+	return nil
+}
+
+func (a *allowlist) checkExpr(e sqlparse.Expr, scope *allowScope) error {
+	// Walk visits the siblings of a node it is told to skip: the first
+	// error is kept, and stops the checks of the other nodes
+	var err error
+	sqlparse.Walk(e, func(e sqlparse.Expr) bool {
+		if err != nil {
+			return false
+		}
+		switch e := e.(type) {
+		case *sqlparse.ColumnRef:
+			err = a.checkColumn(e, scope)
+		case *sqlparse.FuncCall:
+			if !a.functions[strings.ToLower(e.Name)] {
+				err = fmt.Errorf("%w: function %s", ErrNotAllowed, e.Name)
+			}
+		case *sqlparse.Subquery:
+			err = a.checkSelect(e.Select, scope)
+			return false
+		}
+		return err == nil
+	})
+	return err
+}
+
+func (a *allowlist) checkColumn(c *sqlparse.ColumnRef, scope *allowScope) error {
+	if c.Table != "" {
+		for s := scope; s != nil; s = s.parent {
+			if t, ok := s.tables[c.Table]; ok {
+				return a.checkTableColumn(t, c.Name)
+			}
+		}
+		return fmt.Errorf("%w: unknown table %s", ErrNotAllowed, c.Table)
+	}
+	if scope.aliases[c.Name] {
+		return nil
+	}
+
+	found := false
+	for s := scope; s != nil; s = s.parent {
+		for _, t := range s.tables {
+			if err := a.checkTableColumn(t, c.Name); err != nil {
+				return err
+			}
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: column %s without table", ErrNotAllowed, c.Name)
+	}
+	return nil
+}
+
+func (a *allowlist) checkTableColumn(table, column string) error {
+	if table == "" || a.columns[table]["*"] || a.columns[table][column] {
+		return nil
+	}
+	return fmt.Errorf("%w: column %s of table %s", ErrNotAllowed, column, table)
+}
+
+// restrictedStore only runs the statements allowed by its allowlists.
+type restrictedStore struct {
+	store
+	allowlists []*allowlist
+}
+
+// restricted wraps the given store with the allowlists, if any.
+func restricted(s store, allowlists []*allowlist) store {
+	if len(allowlists) == 0 {
+		return s
+	}
+	return restrictedStore{store: s, allowlists: allowlists}
+}
+
+func (s restrictedStore) unwrap() store {
+	return s.store
+}
+
+func (s restrictedStore) check(query string) error {
+	sel, err := sqlparse.Parse(query)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNotAllowed, err)
+	}
+	for _, a := range s.allowlists {
+		if err := a.checkSelect(sel, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errNotSelect = fmt.Errorf("%w: only SELECT statements are allowed", ErrNotAllowed)
+
+func (s restrictedStore) Select(dest interface{}, query string, args ...interface{}) error {
+	if err := s.check(query); err != nil {
+		return err
+	}
+	return s.store.Select(dest, query, args...)
+}
+
+func (s restrictedStore) Get(dest interface{}, query string, args ...interface{}) error {
+	if err := s.check(query); err != nil {
+		return err
+	}
+	return s.store.Get(dest, query, args...)
+}
+
+func (s restrictedStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return nil, errNotSelect
+}
+
+func (s restrictedStore) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	if err := s.check(query); err != nil {
+		return nil, err
+	}
+	return s.store.NamedQuery(query, arg)
+}
+
+func (s restrictedStore) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return nil, errNotSelect
+}
+
+func (s restrictedStore) PrepareNamed(query string) (*sqlx.NamedStmt, error) {
+	if err := s.check(query); err != nil {
+		return nil, err
+	}
+	return s.store.PrepareNamed(query)
+}
+
+func (s restrictedStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if err := s.check(query); err != nil {
+		return err
+	}
+	return s.store.SelectContext(ctx, dest, query, args...)
+}
+
+func (s restrictedStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if err := s.check(query); err != nil {
+		return err
+	}
+	return s.store.GetContext(ctx, dest, query, args...)
+}
+
+func (s restrictedStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	return nil, errNotSelect
+}
+
+func (s restrictedStore) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	if err := s.check(query); err != nil {
+		return nil, err
+	}
+	return s.store.NamedQueryContext(ctx, query, arg)
+}
+
+func (s restrictedStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, errNotSelect
+}
+
+func (s restrictedStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	if err := s.check(query); err != nil {
+		return nil, err
+	}
+	return s.store.QueryxContext(ctx, query, args...)
+}
+
+func (s restrictedStore) PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error) {
+	if err := s.check(query); err != nil {
+		return nil, err
+	}
+	return s.store.PrepareNamedContext(ctx, query)
+}
+
+// Context returns the context of the wrapped store, for
+// Connection.Context.
+func (s restrictedStore) Context() context.Context {
+	if c, ok := s.store.(interface{ Context() context.Context }); ok {
+		return c.Context()
+	}
+	return context.TODO()
+}
@@ -0,0 +1,107 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type allowedOrder struct {
+	ID         int    `db:"id"`
+	CustomerID int    `db:"customer_id"`
+	Total      int    `db:"total"`
+	Note       string `db:"note"`
+}
+
+func (allowedOrder) TableName() string {
+	return "orders"
+}
+
+func Test_Allowlist(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE customers (id INTEGER PRIMARY KEY, country TEXT, email TEXT)").Exec())
+	r.NoError(c.RawQuery("CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER, total INTEGER, note TEXT)").Exec())
+	r.NoError(c.RawQuery("INSERT INTO customers (id, country, email) VALUES (1, 'fr', 'a@example.com'), (2, 'us', 'b@example.com')").Exec())
+	r.NoError(c.RawQuery("INSERT INTO orders (id, customer_id, total, note) VALUES (1, 1, 10, ''), (2, 1, 20, ''), (3, 2, 5, '')").Exec())
+
+	reports := c.Restrict(Allowlist{
+		Tables: map[string][]string{
+			"customers": {"id", "country"},
+			"orders":    {"*"},
+		},
+	})
+
+	orders := []allowedOrder{}
+	r.NoError(reports.Where("total > ?", 5).Order("id").All(&orders))
+	r.Len(orders, 2)
+	n, err := reports.Where("customer_id = ?", 1).Count(&allowedOrder{})
+	r.NoError(err)
+	r.Equal(2, n)
+	exists, err := reports.Where("id = ?", 3).Exists(&allowedOrder{})
+	r.NoError(err)
+	r.True(exists)
+
+	var totals []struct {
+		Country string `db:"country"`
+		Total   int    `db:"total"`
+	}
+	r.NoError(reports.RawQuery(`SELECT c.country, SUM(o.total) AS total FROM orders o
+		JOIN customers c ON c.id = o.customer_id
+		WHERE c.id IN (SELECT id FROM customers WHERE country <> ?)
+		GROUP BY c.country ORDER BY total DESC`, "de").All(&totals))
+	r.Len(totals, 2)
+	r.Equal("fr", totals[0].Country)
+	r.Equal(30, totals[0].Total)
+
+	for _, sql := range []string{
+		"SELECT email FROM customers",
+		"SELECT c.email FROM customers c",
+		"SELECT * FROM customers",
+		"SELECT id FROM orders WHERE customer_id IN (SELECT id FROM customers WHERE email LIKE '%@example.com')",
+		"SELECT note FROM orders o, customers",
+		"SELECT name FROM sqlite_master",
+		"SELECT load_extension('x')",
+		"SELECT id FROM orders WHERE x.id = 1",
+		"SELECT id FROM orders; DELETE FROM orders",
+		// a rejected node followed by an allowed sibling
+		"SELECT id FROM customers WHERE load_extension('x') = id",
+		"SELECT o.* FROM orders o JOIN customers c ON c.email = o.id",
+		"SELECT id FROM customers WHERE email = 'a' AND country = 'fr'",
+		"SELECT id FROM customers WHERE (SELECT email FROM customers LIMIT 1) = id",
+		"DELETE FROM orders",
+	} {
+		var ids []int
+		r.ErrorIs(reports.RawQuery(sql).All(&ids), ErrNotAllowed, sql)
+	}
+	r.ErrorIs(reports.RawQuery("DELETE FROM orders").Exec(), ErrNotAllowed)
+	r.ErrorIs(reports.Create(&allowedOrder{Total: 1}), ErrNotAllowed)
+
+	r.NoError(reports.Transaction(func(tx *Connection) error {
+		var ids []int
+		r.ErrorIs(tx.RawQuery("SELECT email FROM customers").All(&ids), ErrNotAllowed)
+		return tx.RawQuery("SELECT id FROM customers").All(&ids)
+	}))
+
+	n, err = c.Count(&allowedOrder{})
+	r.NoError(err)
+	r.Equal(3, n)
+	var emails []string
+	r.NoError(c.RawQuery("SELECT email FROM customers").All(&emails))
+}
+
+func Test_Allowlist_ConnectionDetails(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, &ConnectionDetails{Allowlist: &Allowlist{Tables: map[string][]string{"t": {"a"}}, Functions: []string{"typeof"}}})
+
+	var s string
+	r.NoError(c.RawQuery("SELECT typeof(1)").First(&s))
+	r.Equal("integer", s)
+	r.ErrorIs(c.RawQuery("CREATE TABLE t (a TEXT, b TEXT)").Exec(), ErrNotAllowed)
+	r.ErrorIs(c.RawQuery("SELECT b FROM t").First(&s), ErrNotAllowed)
+}
@@ -0,0 +1,61 @@
+package pop
+
+import (
+	"fmt"
+	"time"
+)
+
+// SnapshotFunc returns the connection to the snapshot of the database of c
+// as it was at t, such as a restored backup or a delayed replica, see
+// Query.AsOf.
+type SnapshotFunc func(c *Connection, t time.Time) (*Connection, error)
+
+var snapshotFunc SnapshotFunc
+
+// SetSnapshotFunc sets the function returning the connections to the
+// snapshots read by the AsOf queries on the dialects which can not read
+// the past themselves.
+//
+//	pop.SetSnapshotFunc(func(c *pop.Connection, t time.Time) (*pop.Connection, error) {
+//		return pop.Connect("backup_" + t.Format("20060102"))
+//	})
+func SetSnapshotFunc(f SnapshotFunc) {
+	snapshotFunc = f
+}
+
+// AsOf reads the records as they were at t, see Query.AsOf.
+func (c *Connection) AsOf(t time.Time) *Query {
+	return Q(c).AsOf(t)
+}
+
+// AsOf reads the records as they were at t: with AS OF SYSTEM TIME on
+// CockroachDB, from the snapshot returned by the function set with
+// SetSnapshotFunc elsewhere. The finders fail with ErrUnsupported when
+// there is no such function.
+//
+//	q.Where("id = ?", id).AsOf(time.Now().Add(-24 * time.Hour)).First(&user)
+func (q *Query) AsOf(t time.Time) *Query {
+	if q.Connection.Dialect.Name() == nameCockroach {
+		q.asOf = &t
+		return q
+	}
+	if snapshotFunc == nil {
+		q.err = errUnsupported(q.Connection.Dialect, "as of queries without snapshot function")
+		return q
+	}
+	c, err := snapshotFunc(q.Connection, t)
+	if err != nil {
+		q.err = fmt.Errorf("could not get the snapshot as of %s: %w", t.Format(time.RFC3339), err)
+		return q
+	}
+	q.Connection = c
+	return q
+}
+
+// buildAsOfClause appends the AS OF SYSTEM TIME clause of the query.
+func (sq *sqlBuilder) buildAsOfClause(sql string) string {
+	if sq.Query.asOf == nil {
+		return sql
+	}
+	return fmt.Sprintf("%s AS OF SYSTEM TIME '%s'", sql, sq.Query.asOf.UTC().Format("2006-01-02 15:04:05.999999-07:00"))
+}
@@ -0,0 +1,61 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_AsOf_Cockroach_SQL(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &cockroach{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.FixedZone("CET", 3600))
+	q := Q(c).Where("id = ?", 1).AsOf(at)
+	r.NoError(q.err)
+
+	sql := newSQLBuilder(*q, NewModel(&feedItem{}, nil)).buildSelectSQL()
+	r.True(strings.HasSuffix(sql, "FROM feed_items AS feed_items AS OF SYSTEM TIME '2024-03-01 11:30:00+00:00' WHERE id = ?"), sql)
+}
+
+func Test_AsOf_Snapshot(t *testing.T) {
+	r := require.New(t)
+
+	open := func() *Connection {
+		c := openSQLite(t, nil)
+		r.NoError(c.RawQuery("CREATE TABLE feed_items (id INTEGER PRIMARY KEY, title TEXT NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)").Exec())
+		return c
+	}
+	c, snapshot := open(), open()
+	r.NoError(c.Create(&feedItem{ID: 1, Title: "now"}))
+	r.NoError(snapshot.Create(&feedItem{ID: 1, Title: "yesterday"}))
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	item := &feedItem{}
+	err := c.AsOf(yesterday).First(item)
+	r.True(errors.Is(err, ErrUnsupported), err)
+	_, err = c.AsOf(yesterday).Count(item)
+	r.True(errors.Is(err, ErrUnsupported), err)
+
+	defer SetSnapshotFunc(nil)
+	SetSnapshotFunc(func(cn *Connection, at time.Time) (*Connection, error) {
+		r.Equal(yesterday, at)
+		return snapshot, nil
+	})
+	r.NoError(c.AsOf(yesterday).Where("id = ?", 1).First(item))
+	r.Equal("yesterday", item.Title)
+	r.NoError(c.Find(item, 1))
+	r.Equal("now", item.Title)
+
+	SetSnapshotFunc(func(cn *Connection, at time.Time) (*Connection, error) {
+		return nil, errors.New("no backup")
+	})
+	items := []feedItem{}
+	r.Error(c.AsOf(yesterday).All(&items))
+}
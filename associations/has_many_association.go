@@ -93,6 +93,9 @@ func (a *hasManyAssociation) OrderBy() string {
 
 func (a *hasManyAssociation) AfterInterface() interface{} {
 	if a.value.Kind() == reflect.Ptr {
+		if a.value.IsNil() {
+			return nil
+		}
 		return a.value.Interface()
 	}
 	return a.value.Addr().Interface()
@@ -101,13 +104,17 @@ func (a *hasManyAssociation) AfterInterface() interface{} {
 func (a *hasManyAssociation) AfterSetup() error {
 	ownerID := reflect.Indirect(reflect.ValueOf(a.owner)).FieldByName("ID").Interface()
 
-	v := a.value
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
+	v := reflect.Indirect(a.value)
+	if !v.IsValid() {
+		return nil
 	}
 
 	for i := 0; i < v.Len(); i++ {
-		fval := v.Index(i).FieldByName(a.ownerName + "ID")
+		elem := reflect.Indirect(v.Index(i))
+		if !elem.IsValid() {
+			continue
+		}
+		fval := elem.FieldByName(a.ownerName + "ID")
 		if fval.CanSet() {
 			if n := nulls.New(fval.Interface()); n != nil {
 				fval.Set(reflect.ValueOf(n.Parse(ownerID)))
@@ -122,10 +129,7 @@ func (a *hasManyAssociation) AfterSetup() error {
 }
 
 func (a *hasManyAssociation) AfterProcess() AssociationStatement {
-	v := a.value
-	if v.Kind() == reflect.Ptr {
-		v = v.Elem()
-	}
+	v := reflect.Indirect(a.value)
 
 	belongingIDFieldName := "ID"
 
@@ -134,8 +138,12 @@ func (a *hasManyAssociation) AfterProcess() AssociationStatement {
 
 	var ids []interface{}
 
-	for i := 0; i < v.Len(); i++ {
-		id := v.Index(i).FieldByName(belongingIDFieldName).Interface()
+	for i := 0; v.IsValid() && i < v.Len(); i++ {
+		elem := reflect.Indirect(v.Index(i))
+		if !elem.IsValid() {
+			continue
+		}
+		id := elem.FieldByName(belongingIDFieldName).Interface()
 		if !IsZeroOfUnderlyingType(id) {
 			ids = append(ids, id)
 		}
@@ -98,6 +98,9 @@ func (m *manyToManyAssociation) OrderBy() string {
 
 func (m *manyToManyAssociation) BeforeInterface() interface{} {
 	if m.fieldValue.Kind() == reflect.Ptr {
+		if m.fieldValue.IsNil() {
+			return nil
+		}
 		return m.fieldValue.Interface()
 	}
 	return m.fieldValue.Addr().Interface()
@@ -113,15 +116,24 @@ func (m *manyToManyAssociation) Statements() []AssociationStatement {
 	modelColumnID := fmt.Sprintf("%s%s", flect.Underscore(m.model.Type().Name()), "_id")
 	var columnFieldID string
 	i := reflect.Indirect(m.fieldValue)
+	if !i.IsValid() {
+		return statements
+	}
 	if i.Kind() == reflect.Slice || i.Kind() == reflect.Array {
 		t := i.Type().Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
 		columnFieldID = fmt.Sprintf("%s%s", flect.Underscore(t.Name()), "_id")
 	} else {
 		columnFieldID = fmt.Sprintf("%s%s", flect.Underscore(i.Type().Name()), "_id")
 	}
 
-	for i := 0; i < m.fieldValue.Len(); i++ {
-		v := m.fieldValue.Index(i)
+	for j := 0; j < i.Len(); j++ {
+		v := reflect.Indirect(i.Index(j))
+		if !v.IsValid() {
+			continue
+		}
 		manyIDValue := v.FieldByName("ID").Interface()
 		modelIDValue := m.model.FieldByName("ID").Interface()
 		stm := "INSERT INTO %s (%s,%s,%s,%s) SELECT ?,?,?,? WHERE NOT EXISTS (SELECT * FROM %s WHERE %s = ? AND %s = ?)"
@@ -0,0 +1,142 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// Batch queues the statements sent by Connection.Batch.
+type Batch struct {
+	c     *Connection
+	stmts []batchStatement
+}
+
+type batchStatement struct {
+	query string
+	args  []interface{}
+}
+
+// BatchResult is the result of a statement of a batch.
+type BatchResult struct {
+	// RowsAffected is the number of rows affected by the statement, -1
+	// when the database does not report it.
+	RowsAffected int64
+}
+
+// BatchError is the error of the statement of a batch which failed, the
+// Index-th one, or -1 when the database does not tell which one failed.
+// The batch is rolled back.
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("statement %d of the batch: %s", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// Exec queues the statement, written as the ones of RawQuery.
+func (b *Batch) Exec(query string, args ...interface{}) {
+	sql, args := b.c.RawQuery(query, args...).ToSQL(nil)
+	b.stmts = append(b.stmts, batchStatement{query: sql, args: args})
+}
+
+// errBatchFallback is returned by the dialects which can not send the
+// statements of a batch in a single round trip through the connection.
+var errBatchFallback = fmt.Errorf("batch is not supported by the connection")
+
+// Batch runs the statements queued by fn in a single round trip to the
+// database, where the dialect supports it: PostgreSQL and CockroachDB
+// through pgx batches, MySQL and MariaDB through multiple statements.
+// Elsewhere, the statements are run one at a time. The statements are run
+// in a transaction, the one of the connection if any: either all of them
+// succeed or the batch fails with a *BatchError.
+//
+//	results, err := c.Batch(func(b *pop.Batch) {
+//		b.Exec("UPDATE counters SET hits = hits + 1 WHERE page = ?", page)
+//		b.Exec("INSERT INTO visits (page, at) VALUES (?, ?)", page, time.Now())
+//	})
+func (c *Connection) Batch(fn func(b *Batch)) ([]BatchResult, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	b := &Batch{c: c}
+	fn(b)
+	if len(b.stmts) == 0 {
+		return nil, nil
+	}
+
+	var results []BatchResult
+	err := c.timeFunc("Batch", nil, func() error {
+		var err error
+		if d, ok := c.Dialect.(batchable); ok {
+			results, err = d.ExecBatch(c, b.stmts)
+			if err != errBatchFallback {
+				return err
+			}
+		}
+		if c.TX != nil {
+			results, err = c.execBatch(b.stmts)
+			return err
+		}
+		return c.Transaction(func(tx *Connection) error {
+			results, err = tx.execBatch(b.stmts)
+			return err
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// execBatch runs the statements one at a time in the transaction of c.
+func (c *Connection) execBatch(stmts []batchStatement) ([]BatchResult, error) {
+	results := make([]BatchResult, len(stmts))
+	for i, s := range stmts {
+		txlog(logging.SQL, c, s.query, s.args...)
+		res, err := c.Store.ExecContext(c.Context(), s.query, s.args...)
+		if err != nil {
+			return nil, &BatchError{Index: i, Err: err}
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			n = -1
+		}
+		results[i] = BatchResult{RowsAffected: n}
+	}
+	return results, nil
+}
+
+// rawConn returns a connection of the pool of the store, to be closed once
+// done with, or errBatchFallback when the store is not a pool.
+func rawConn(ctx context.Context, s store) (*sql.Conn, error) {
+	for {
+		switch x := s.(type) {
+		case contextStore:
+			s = x.store
+		case watchdogStore:
+			s = x.store
+		case commentStore:
+			s = x.store
+		case *dB:
+			return x.DB.Conn(ctx)
+		default:
+			return nil, errBatchFallback
+		}
+	}
+}
+
+// batchLog logs the statements of the batch sent in a single round trip.
+func batchLog(c *Connection, stmts []batchStatement) {
+	for _, s := range stmts {
+		txlog(logging.SQL, c, s.query, s.args...)
+	}
+}
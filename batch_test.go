@@ -0,0 +1,49 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Batch(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE batches (id INTEGER PRIMARY KEY, name TEXT NOT NULL)").Exec())
+
+	results, err := c.Batch(func(b *Batch) {
+		b.Exec("INSERT INTO batches (id, name) VALUES (?, ?), (?, ?)", 1, "a", 2, "b")
+		b.Exec("UPDATE batches SET name = ? WHERE id = ?", "c", 2)
+	})
+	r.NoError(err)
+	r.Equal([]BatchResult{{RowsAffected: 2}, {RowsAffected: 1}}, results)
+
+	results, err = c.Batch(func(b *Batch) {
+		b.Exec("DELETE FROM batches WHERE id = ?", 1)
+		b.Exec("INSERT INTO batches (id, name) VALUES (?, ?)", 2, "d")
+	})
+	var berr *BatchError
+	r.ErrorAs(err, &berr)
+	r.Equal(1, berr.Index)
+	r.Nil(results)
+
+	count, err := c.RawQuery("SELECT * FROM batches").Count(nil)
+	r.NoError(err)
+	r.Equal(2, count)
+
+	r.NoError(c.Transaction(func(tx *Connection) error {
+		results, err := tx.Batch(func(b *Batch) {
+			b.Exec("DELETE FROM batches WHERE id = ?", 1)
+		})
+		r.Equal([]BatchResult{{RowsAffected: 1}}, results)
+		return err
+	}))
+
+	results, err = c.Batch(func(b *Batch) {})
+	r.NoError(err)
+	r.Empty(results)
+}
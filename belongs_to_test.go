@@ -48,3 +48,25 @@ func Test_BelongsToThrough(t *testing.T) {
 	sql, _ := q.ToSQL(m)
 	r.Equal(ts(qs), sql)
 }
+
+type familyFriend struct {
+	ID int `db:"id"`
+}
+
+func (familyFriend) TableName() string {
+	return "family.friends"
+}
+
+func Test_BelongsToThrough_Schema(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	q := PDB.BelongsToThrough(&Family{ID: 1}, &familyFriend{})
+	qs := "SELECT enemies.A FROM enemies AS enemies, family.friends AS family_friends WHERE family_friends.member_id = ? AND enemies.id = family_friends.enemy_id"
+
+	m := NewModel(new(Enemy), context.Background())
+	sql, _ := q.ToSQL(m)
+	r.Equal(ts(qs), sql)
+}
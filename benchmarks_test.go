@@ -67,6 +67,34 @@ func Benchmark_Find_Raw(b *testing.B) {
 	})
 }
 
+func Benchmark_All_Pop(b *testing.B) {
+	transaction(func(tx *Connection) {
+		for i := 0; i < 500; i++ {
+			tx.Create(&User{Name: nulls.NewString("Mark Bates")})
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			users := []User{}
+			tx.All(&users)
+		}
+	})
+}
+
+func Benchmark_All_Raw(b *testing.B) {
+	transaction(func(tx *Connection) {
+		for i := 0; i < 500; i++ {
+			tx.Create(&User{Name: nulls.NewString("Mark Bates")})
+		}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for n := 0; n < b.N; n++ {
+			users := []User{}
+			tx.Store.Select(&users, "select id, user_name, email, name, alive, created_at, updated_at, birth_date, bio, price, name as full_name from users")
+		}
+	})
+}
+
 func Benchmark_translateOne(b *testing.B) {
 	q := "select * from users where id = ? and name = ? and email = ? and a = ? and b = ? and c = ? and d = ? and e = ? and f = ?"
 	for n := 0; n < b.N; n++ {
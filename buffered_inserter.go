@@ -0,0 +1,196 @@
+package pop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// ErrInserterClosed is returned by the inserts into a closed
+// BufferedInserter.
+var ErrInserterClosed = errors.New("buffered inserter is closed")
+
+// BufferedInserter creates the records in the background, by batches, so
+// the callers logging events or audit records do not wait for the
+// database. The records are lost if the batch fails, report them with
+// OnError.
+type BufferedInserter struct {
+	// OnError is called with the error of the failed batches and their
+	// records, a slice of the model. The errors are logged by default.
+	// Set it before the first insert.
+	OnError func(err error, records interface{})
+
+	c             *Connection
+	typ           reflect.Type
+	flushInterval time.Duration
+	maxBatch      int
+
+	start   sync.Once
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan reflect.Value
+	flushes chan chan struct{}
+	done    chan struct{}
+}
+
+// NewBufferedInserter returns the inserter of the records of the type of
+// model into the database of c, which must not be a transaction. Batches
+// are created when they reach maxBatch records, and at least every
+// flushInterval. Inserts block while maxBatch records are waiting for the
+// batch being created, applying backpressure on the callers.
+//
+//	events := pop.NewBufferedInserter(c, &Event{}, time.Second, 500)
+//	defer events.Close()
+//
+//	err := events.Insert(&Event{Name: "login"})
+func NewBufferedInserter(c *Connection, model interface{}, flushInterval time.Duration, maxBatch int) *BufferedInserter {
+	if maxBatch <= 0 {
+		maxBatch = 1
+	}
+	typ := reflect.TypeOf(model)
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return &BufferedInserter{
+		c:             c,
+		typ:           typ,
+		flushInterval: flushInterval,
+		maxBatch:      maxBatch,
+		queue:         make(chan reflect.Value, maxBatch),
+		flushes:       make(chan chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+// Insert queues the record, blocking while the queue is full.
+func (b *BufferedInserter) Insert(record interface{}) error {
+	return b.InsertContext(context.Background(), record)
+}
+
+// InsertContext queues the record, blocking while the queue is full or
+// until ctx is done.
+func (b *BufferedInserter) InsertContext(ctx context.Context, record interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(record))
+	if !v.IsValid() || v.Type() != b.typ {
+		return fmt.Errorf("can not insert %T with the inserter of %s", record, b.typ)
+	}
+	b.start.Do(b.run)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrInserterClosed
+	}
+	select {
+	case b.queue <- v:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush creates the queued records, and returns once they are created or
+// their batch failed.
+func (b *BufferedInserter) Flush() {
+	b.start.Do(b.run)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+	ack := make(chan struct{})
+	b.flushes <- ack
+	<-ack
+}
+
+// Close creates the queued records and stops the inserter.
+func (b *BufferedInserter) Close() {
+	b.start.Do(b.run)
+
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+	<-b.done
+}
+
+func (b *BufferedInserter) run() {
+	go func() {
+		defer close(b.done)
+
+		var ticks <-chan time.Time
+		if b.flushInterval > 0 {
+			ticker := time.NewTicker(b.flushInterval)
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
+
+		batch := b.newBatch()
+		for {
+			select {
+			case v, ok := <-b.queue:
+				if !ok {
+					b.flush(batch)
+					return
+				}
+				batch = reflect.Append(batch, v)
+				if batch.Len() >= b.maxBatch {
+					batch = b.flush(batch)
+				}
+			case <-ticks:
+				batch = b.flush(batch)
+			case ack := <-b.flushes:
+				batch = b.flush(b.drain(batch))
+				close(ack)
+			}
+		}
+	}()
+}
+
+func (b *BufferedInserter) newBatch() reflect.Value {
+	return reflect.MakeSlice(reflect.SliceOf(b.typ), 0, b.maxBatch)
+}
+
+// drain appends the queued records to the batch.
+func (b *BufferedInserter) drain(batch reflect.Value) reflect.Value {
+	for {
+		select {
+		case v, ok := <-b.queue:
+			if !ok {
+				return batch
+			}
+			batch = reflect.Append(batch, v)
+		default:
+			return batch
+		}
+	}
+}
+
+// flush creates the records of the batch and returns the next one.
+func (b *BufferedInserter) flush(batch reflect.Value) reflect.Value {
+	if batch.Len() == 0 {
+		return batch
+	}
+
+	records := reflect.New(batch.Type())
+	records.Elem().Set(batch)
+	err := b.c.Transaction(func(tx *Connection) error {
+		return tx.Create(records.Interface())
+	})
+	if err != nil {
+		if b.OnError != nil {
+			b.OnError(err, records.Elem().Interface())
+		} else {
+			log(logging.Error, "could not insert %d buffered records of %s: %s", batch.Len(), b.typ, err)
+		}
+	}
+	return b.newBatch()
+}
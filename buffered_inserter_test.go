@@ -0,0 +1,88 @@
+package pop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BufferedInserter(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	defer PDB.RawQuery("DELETE FROM users WHERE name = ?", "buffered").Exec()
+
+	b := NewBufferedInserter(PDB, &User{}, time.Hour, 2)
+	for i := 0; i < 5; i++ {
+		r.NoError(b.Insert(&User{Name: nulls.NewString("buffered")}))
+	}
+	r.Error(b.Insert(&Book{}))
+
+	b.Flush()
+	count, err := PDB.Where("name = ?", "buffered").Count(&User{})
+	r.NoError(err)
+	r.Equal(5, count)
+
+	r.NoError(b.Insert(User{Name: nulls.NewString("buffered")}))
+	b.Close()
+	count, err = PDB.Where("name = ?", "buffered").Count(&User{})
+	r.NoError(err)
+	r.Equal(6, count)
+
+	r.ErrorIs(b.Insert(&User{}), ErrInserterClosed)
+	b.Close()
+}
+
+func Test_BufferedInserter_Interval(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	defer PDB.RawQuery("DELETE FROM users WHERE name = ?", "buffered").Exec()
+
+	b := NewBufferedInserter(PDB, &User{}, 10*time.Millisecond, 100)
+	defer b.Close()
+	r.NoError(b.Insert(&User{Name: nulls.NewString("buffered")}))
+
+	r.Eventually(func() bool {
+		count, err := PDB.Where("name = ?", "buffered").Count(&User{})
+		return err == nil && count == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+type bufferedMissing struct {
+	ID int `db:"id"`
+}
+
+func Test_BufferedInserter_OnError(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	var failed []bufferedMissing
+	b := NewBufferedInserter(PDB, bufferedMissing{}, time.Hour, 1)
+	b.OnError = func(err error, records interface{}) {
+		r.Error(err)
+		failed = append(failed, records.([]bufferedMissing)...)
+	}
+	r.NoError(b.Insert(&bufferedMissing{ID: 1}))
+	b.Close()
+	r.Equal([]bufferedMissing{{ID: 1}}, failed)
+}
+
+func Test_BufferedInserter_Backpressure(t *testing.T) {
+	r := require.New(t)
+
+	b := NewBufferedInserter(&Connection{}, &User{}, time.Hour, 1)
+	b.start.Do(func() {}) // no worker, the queue is never read
+	r.NoError(b.Insert(&User{}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	r.ErrorIs(b.InsertContext(ctx, &User{}), context.DeadlineExceeded)
+}
@@ -72,7 +72,7 @@ type BeforeSaveable interface {
 }
 
 func (m *Model) beforeSave(c *Connection) error {
-	if x, ok := m.Value.(BeforeSaveable); ok {
+	if x, ok := m.Value.(BeforeSaveable); ok && !c.skipCallbacks {
 		return x.BeforeSave(c)
 	}
 	return nil
@@ -85,7 +85,7 @@ type BeforeCreateable interface {
 }
 
 func (m *Model) beforeCreate(c *Connection) error {
-	if x, ok := m.Value.(BeforeCreateable); ok {
+	if x, ok := m.Value.(BeforeCreateable); ok && !c.skipCallbacks {
 		return x.BeforeCreate(c)
 	}
 	return nil
@@ -98,7 +98,7 @@ type BeforeUpdateable interface {
 }
 
 func (m *Model) beforeUpdate(c *Connection) error {
-	if x, ok := m.Value.(BeforeUpdateable); ok {
+	if x, ok := m.Value.(BeforeUpdateable); ok && !c.skipCallbacks {
 		return x.BeforeUpdate(c)
 	}
 	return nil
@@ -111,7 +111,7 @@ type BeforeDestroyable interface {
 }
 
 func (m *Model) beforeDestroy(c *Connection) error {
-	if x, ok := m.Value.(BeforeDestroyable); ok {
+	if x, ok := m.Value.(BeforeDestroyable); ok && !c.skipCallbacks {
 		return x.BeforeDestroy(c)
 	}
 	return nil
@@ -119,13 +119,14 @@ func (m *Model) beforeDestroy(c *Connection) error {
 
 // BeforeValidateable callback will be called before a record is
 // validated during
-// ValidateAndCreate, ValidateAndUpdate, or ValidateAndSave
+// ValidateAndCreate, ValidateAndUpdate, or ValidateAndSave.
+// The validation context is available through c.ValidationContext().
 type BeforeValidateable interface {
 	BeforeValidate(*Connection) error
 }
 
 func (m *Model) beforeValidate(c *Connection) error {
-	if x, ok := m.Value.(BeforeValidateable); ok {
+	if x, ok := m.Value.(BeforeValidateable); ok && !c.skipCallbacks {
 		return x.BeforeValidate(c)
 	}
 	return nil
@@ -138,7 +139,7 @@ type AfterDestroyable interface {
 }
 
 func (m *Model) afterDestroy(c *Connection) error {
-	if x, ok := m.Value.(AfterDestroyable); ok {
+	if x, ok := m.Value.(AfterDestroyable); ok && !c.skipCallbacks {
 		return x.AfterDestroy(c)
 	}
 	return nil
@@ -151,7 +152,7 @@ type AfterUpdateable interface {
 }
 
 func (m *Model) afterUpdate(c *Connection) error {
-	if x, ok := m.Value.(AfterUpdateable); ok {
+	if x, ok := m.Value.(AfterUpdateable); ok && !c.skipCallbacks {
 		return x.AfterUpdate(c)
 	}
 	return nil
@@ -164,7 +165,7 @@ type AfterCreateable interface {
 }
 
 func (m *Model) afterCreate(c *Connection) error {
-	if x, ok := m.Value.(AfterCreateable); ok {
+	if x, ok := m.Value.(AfterCreateable); ok && !c.skipCallbacks {
 		return x.AfterCreate(c)
 	}
 	return nil
@@ -177,7 +178,7 @@ type AfterSaveable interface {
 }
 
 func (m *Model) afterSave(c *Connection) error {
-	if x, ok := m.Value.(AfterSaveable); ok {
+	if x, ok := m.Value.(AfterSaveable); ok && !c.skipCallbacks {
 		return x.AfterSave(c)
 	}
 	return nil
@@ -0,0 +1,195 @@
+package pop
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rowChecksummable is implemented by the dialects hashing the rows in the
+// database, see Query.Checksums.
+type rowChecksummable interface {
+	// rowChecksumSQL returns the expression hashing the quoted columns of
+	// a row.
+	rowChecksumSQL(cols []string) string
+}
+
+// RowChecksum returns the checksum of the values of the columns of the
+// model, all of its columns when none is given, in the order of their
+// names. Two models with the same values have the same checksum, so that
+// the changes of a copy can be detected without comparing its fields one
+// by one. RowChecksum hashes the values as the driver would write them,
+// the times in UTC: it matches the checksums computed by Query.Checksums
+// on the dialects without a hash function in the database, SQLite and
+// DuckDB, for the columns of integer, text, boolean and time types.
+func RowChecksum(model interface{}, cols ...string) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(model))
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("can not checksum %T, not a struct", model)
+	}
+	cols = checksumColumns(&Model{Value: model}, cols)
+	values := make([]interface{}, len(cols))
+	for i, col := range cols {
+		f, ok := fieldByColumn(v, col)
+		if !ok {
+			return "", fmt.Errorf("%T has no field for column %s", model, col)
+		}
+		value, err := driver.DefaultParameterConverter.ConvertValue(f.Interface())
+		if err != nil {
+			return "", fmt.Errorf("column %s: %w", col, err)
+		}
+		values[i] = value
+	}
+	return hashRow(values), nil
+}
+
+// Checksums returns the checksums of the values of the columns of the rows
+// of the table of the model matching the query, all of its columns when
+// none is given, by ID. The checksums are computed in the database on
+// PostgreSQL, CockroachDB, MySQL and MariaDB, so only the hashes are read,
+// and are comparable with the ones of the other databases of the same
+// dialect, e.g. to find the rows of a replica copy which differ from the
+// source. On the other dialects the rows are read and hashed like
+// RowChecksum does.
+//
+//	source, err := src.Where("updated_at > ?", since).Checksums(&User{})
+//	replica, err := dst.Where("updated_at > ?", since).Checksums(&User{})
+func (q *Query) Checksums(model interface{}, cols ...string) (map[string]string, error) {
+	_, inDatabase := q.Connection.Dialect.(rowChecksummable)
+	return q.checksums(model, "", cols, inDatabase)
+}
+
+// checksums returns the checksums of the rows matching the query by key,
+// the ID when empty, computed in the database or like RowChecksum does.
+func (q *Query) checksums(model interface{}, key string, cols []string, inDatabase bool) (map[string]string, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	m := NewModel(model, q.Connection.Context())
+	if key == "" {
+		if m.compositeKey() != nil {
+			return nil, errors.New("Checksums does not support composite primary keys")
+		}
+		key = m.IDField()
+	}
+	cols = checksumColumns(m, cols)
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("%T has no columns to checksum", model)
+	}
+	quoted := make([]string, len(cols))
+	for i, col := range cols {
+		quoted[i] = fmt.Sprintf("%s.%s", m.Alias(), q.Connection.Dialect.Quote(col))
+	}
+
+	checksums := map[string]string{}
+	err := q.Connection.timeFunc("Checksums", model, func() error {
+		cq := *q
+		cq.Operation = Select
+		addColumns := append([]string{fmt.Sprintf("%s.%s", m.Alias(), key)}, quoted...)
+		if inDatabase {
+			d := q.Connection.Dialect.(rowChecksummable)
+			addColumns = []string{fmt.Sprintf("%s.%s", m.Alias(), key), d.rowChecksumSQL(quoted) + " AS pop_checksum"}
+		}
+		query, args := cq.ToSQL(m, addColumns...)
+		rows, err := q.Connection.Store.QueryxContext(q.Connection.Context(), query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		// the selected columns are not in the order they were added
+		values := make([]interface{}, len(cols))
+		for rows.Next() {
+			row := map[string]interface{}{}
+			if err := rows.MapScan(row); err != nil {
+				return err
+			}
+			k := checksumKey(row[key])
+			if inDatabase {
+				checksums[k] = checksumKey(row["pop_checksum"])
+				continue
+			}
+			for i, col := range cols {
+				values[i] = row[col]
+			}
+			checksums[k] = hashRow(values)
+		}
+		return rows.Err()
+	})
+	return checksums, err
+}
+
+// Checksums returns the checksums of the rows of the table of the model,
+// see Query.Checksums.
+func (c *Connection) Checksums(model interface{}, cols ...string) (map[string]string, error) {
+	return Q(c).Checksums(model, cols...)
+}
+
+// checksumColumns returns the columns, or the readable columns of the
+// model backed by a column of its table, sorted.
+func checksumColumns(m *Model, cols []string) []string {
+	if len(cols) == 0 {
+		alias := m.Alias()
+		for _, col := range m.Columns().Readable().Cols {
+			if col.SelectSQL == alias+"."+col.Name {
+				cols = append(cols, col.Name)
+			}
+		}
+	}
+	cols = append([]string{}, cols...)
+	sort.Strings(cols)
+	return cols
+}
+
+// hashRow returns the hex SHA-256 of the driver values of a row, each
+// prefixed with its kind so that e.g. NULL and "" differ.
+func hashRow(values []interface{}) string {
+	h := sha256.New()
+	for _, v := range values {
+		switch x := v.(type) {
+		case nil:
+			h.Write([]byte{0})
+		case []byte:
+			h.Write([]byte{1})
+			h.Write([]byte(strconv.Itoa(len(x))))
+			h.Write([]byte{':'})
+			h.Write(x)
+		case string:
+			h.Write([]byte{1})
+			h.Write([]byte(strconv.Itoa(len(x))))
+			h.Write([]byte{':'})
+			h.Write([]byte(x))
+		case bool:
+			// as the integers SQLite stores them as
+			if x {
+				fmt.Fprint(h, "\x041")
+			} else {
+				fmt.Fprint(h, "\x040")
+			}
+		case time.Time:
+			h.Write([]byte{3})
+			h.Write([]byte(x.UTC().Format(time.RFC3339Nano)))
+		default:
+			fmt.Fprintf(h, "\x04%v", x)
+		}
+		h.Write([]byte{0xff})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// checksumKey returns the string of a scanned ID or checksum.
+func checksumKey(v interface{}) string {
+	switch x := v.(type) {
+	case []byte:
+		return string(x)
+	case nil:
+		return ""
+	}
+	return strings.TrimSpace(fmt.Sprint(v))
+}
@@ -0,0 +1,67 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type checksummedRow struct {
+	ID        int          `db:"id"`
+	Name      string       `db:"name"`
+	Active    bool         `db:"active"`
+	Note      nulls.String `db:"note"`
+	CheckedAt time.Time    `db:"checked_at"`
+}
+
+func Test_Checksums(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE checksummed_rows (id INTEGER PRIMARY KEY, name TEXT, active BOOLEAN, note TEXT, checked_at DATETIME)").Exec())
+	at := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	rows := []checksummedRow{
+		{Name: "a", Active: true, CheckedAt: at},
+		{Name: "b", Note: nulls.NewString("x"), CheckedAt: at},
+		{Name: "", CheckedAt: at},
+	}
+	for i := range rows {
+		r.NoError(c.Create(&rows[i]))
+	}
+
+	checksums, err := c.Checksums(&checksummedRow{})
+	r.NoError(err)
+	r.Len(checksums, 3)
+	for _, row := range rows {
+		sum, err := RowChecksum(&row)
+		r.NoError(err)
+		r.Equal(sum, checksums[strconv.Itoa(row.ID)])
+	}
+	r.NotEqual(checksums["1"], checksums["2"])
+
+	// NULL and "" differ.
+	empty, err := RowChecksum(&checksummedRow{ID: 3, Note: nulls.NewString(""), CheckedAt: at})
+	r.NoError(err)
+	r.NotEqual(checksums["3"], empty)
+
+	r.NoError(c.RawQuery("UPDATE checksummed_rows SET name = ? WHERE id = ?", "changed", rows[0].ID).Exec())
+	changed, err := c.Where("id = ?", rows[0].ID).Checksums(&checksummedRow{}, "name")
+	r.NoError(err)
+	r.Len(changed, 1)
+	sum, err := RowChecksum(&checksummedRow{Name: "changed"}, "name")
+	r.NoError(err)
+	r.Equal(sum, changed["1"])
+}
+
+func Test_rowChecksumSQL(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal(`md5(CAST(ROW(u."id", u."name") AS TEXT))`, (&postgresql{}).rowChecksumSQL([]string{`u."id"`, `u."name"`}))
+	r.Equal("MD5(JSON_ARRAY(u.`id`, u.`name`))", (&mysql{}).rowChecksumSQL([]string{"u.`id`", "u.`name`"}))
+}
@@ -0,0 +1,90 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Chunk is a part of the items processed by InChunks, in a transaction of
+// its own.
+type Chunk struct {
+	// Items is the slice of the items of the chunk, of the type of the
+	// processed items.
+	Items interface{}
+	// Offset is the index of the first item of the chunk.
+	Offset int
+	// Total is the number of processed items.
+	Total int
+}
+
+// ChunkError is the error of the chunk which failed: the chunks before
+// Offset are committed, the processing resumes from there with a Chunker
+// whose Offset is the one of the error.
+type ChunkError struct {
+	Offset int
+	Err    error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("chunk at offset %d: %s", e.Offset, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// Chunker processes the items of a slice by chunks, committing each of
+// them in a transaction of its own rather than all of them in a single
+// one holding its locks and growing the WAL until the end.
+type Chunker struct {
+	// Size is the number of items of the chunks.
+	Size int
+	// Offset is the index of the first item to process, to resume after a
+	// ChunkError.
+	Offset int
+	// Progress is called after each committed chunk with the number of
+	// processed items, starting at Offset, and the total number of items.
+	Progress func(done, total int)
+}
+
+// InChunks processes the items, a slice, by chunks of size items, each in
+// a transaction of its own.
+//
+//	err := pop.InChunks(c, users, 500, func(tx *pop.Connection, chunk pop.Chunk) error {
+//		return tx.Create(chunk.Items)
+//	})
+func InChunks(c *Connection, items interface{}, size int, fn func(tx *Connection, chunk Chunk) error) error {
+	return Chunker{Size: size}.Run(c, items, fn)
+}
+
+// Run processes the items, a slice, by chunks, see InChunks. In a
+// transaction, the chunks are transactions nested in it, run according to
+// ConnectionDetails.NestedTransactions: by default they join it and are
+// committed with it.
+func (ch Chunker) Run(c *Connection, items interface{}, fn func(tx *Connection, chunk Chunk) error) error {
+	if ch.Size <= 0 {
+		return fmt.Errorf("invalid chunk size %d", ch.Size)
+	}
+	v := reflect.Indirect(reflect.ValueOf(items))
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("can not process %T in chunks, not a slice", items)
+	}
+
+	total := v.Len()
+	for offset := ch.Offset; offset < total; offset += ch.Size {
+		end := offset + ch.Size
+		if end > total {
+			end = total
+		}
+		chunk := Chunk{Items: v.Slice(offset, end).Interface(), Offset: offset, Total: total}
+		if err := c.Transaction(func(tx *Connection) error {
+			return fn(tx, chunk)
+		}); err != nil {
+			return &ChunkError{Offset: offset, Err: err}
+		}
+		if ch.Progress != nil {
+			ch.Progress(end, total)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_InChunks(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE chunks (id INTEGER)").Exec())
+
+	ids := []int{1, 2, 3, 4, 5, 6, 7}
+	insert := func(tx *Connection, chunk Chunk) error {
+		for _, id := range chunk.Items.([]int) {
+			if id == 5 {
+				return errors.New("invalid id")
+			}
+			if err := tx.RawQuery("INSERT INTO chunks (id) VALUES (?)", id).Exec(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var progress []int
+	err := Chunker{Size: 3, Progress: func(done, total int) {
+		r.Equal(7, total)
+		progress = append(progress, done)
+	}}.Run(c, ids, insert)
+
+	var cerr *ChunkError
+	r.ErrorAs(err, &cerr)
+	r.Equal(3, cerr.Offset)
+	r.EqualError(cerr.Err, "invalid id")
+	r.Equal([]int{3}, progress)
+
+	count, err := c.RawQuery("SELECT * FROM chunks").Count(nil)
+	r.NoError(err)
+	r.Equal(3, count)
+
+	ids[4] = 50
+	r.NoError(Chunker{Size: 3, Offset: cerr.Offset, Progress: func(done, total int) {
+		progress = append(progress, done)
+	}}.Run(c, &ids, insert))
+	r.Equal([]int{3, 6, 7}, progress)
+
+	count, err = c.RawQuery("SELECT * FROM chunks").Count(nil)
+	r.NoError(err)
+	r.Equal(7, count)
+
+	r.EqualError(InChunks(c, ids, 0, insert), "invalid chunk size 0")
+	r.EqualError(InChunks(c, 42, 3, insert), "can not process int in chunks, not a slice")
+
+	// in a transaction, the chunks join it
+	r.NoError(c.RawQuery("DELETE FROM chunks").Exec())
+	err = c.Transaction(func(tx *Connection) error {
+		r.NoError(InChunks(tx, ids, 3, insert))
+		count, err := tx.RawQuery("SELECT * FROM chunks").Count(nil)
+		r.NoError(err)
+		r.Equal(7, count)
+		return errors.New("rolled back")
+	})
+	r.EqualError(err, "rolled back")
+	count, err = c.RawQuery("SELECT * FROM chunks").Count(nil)
+	r.NoError(err)
+	r.Zero(count)
+}
@@ -0,0 +1,41 @@
+package pop
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnDefaults returns the defaults of the columns of the table, keyed
+// by their lowercased name. The defaults are SQL expressions, the literals
+// being quoted, e.g. 'draft', 0 or CURRENT_TIMESTAMP. They are nil for the
+// columns without default.
+func (c *Connection) ColumnDefaults(table string) (map[string]*string, error) {
+	d, ok := c.Dialect.(columnDefaultable)
+	if !ok {
+		return nil, errUnsupported(c.Dialect, "column defaults")
+	}
+	return d.ColumnDefaults(c.Context(), c.Store, table)
+}
+
+// AlterColumnDefaultSQL returns the statement setting the default of the
+// column to the SQL expression def, or dropping it when def is nil.
+func (c *Connection) AlterColumnDefaultSQL(table, column string, def *string) (string, error) {
+	if _, ok := c.Dialect.(columnDefaultable); !ok || c.Dialect.Name() == nameSQLite3 {
+		return "", errUnsupported(c.Dialect, "altering column defaults")
+	}
+	alter := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", c.Dialect.Quote(table), c.Dialect.Quote(column))
+	if def == nil {
+		return alter + " DROP DEFAULT", nil
+	}
+	return fmt.Sprintf("%s SET DEFAULT %s", alter, *def), nil
+}
+
+// columnDefault returns the default read from the catalog of the database,
+// nil for NULL.
+func columnDefault(def *string) *string {
+	if def == nil || strings.EqualFold(strings.TrimSpace(*def), "NULL") {
+		return nil
+	}
+	s := strings.TrimSpace(*def)
+	return &s
+}
@@ -0,0 +1,58 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ColumnDefaults(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE posts (id INTEGER PRIMARY KEY, status TEXT DEFAULT 'draft', views INTEGER DEFAULT 0, body TEXT, deleted_at DATETIME DEFAULT NULL)").Exec())
+
+	defs, err := c.ColumnDefaults("posts")
+	r.NoError(err)
+	r.Len(defs, 5)
+	r.Equal("'draft'", *defs["status"])
+	r.Equal("0", *defs["views"])
+	r.Nil(defs["body"])
+	r.Nil(defs["deleted_at"])
+
+	_, err = c.ColumnDefaults("unknown")
+	r.Error(err)
+
+	_, err = c.AlterColumnDefaultSQL("posts", "status", nil)
+	r.True(errors.Is(err, ErrUnsupported))
+}
+
+func Test_AlterColumnDefaultSQL(t *testing.T) {
+	r := require.New(t)
+
+	def := "'draft'"
+	c := &Connection{Dialect: &postgresql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	stmt, err := c.AlterColumnDefaultSQL("posts", "status", &def)
+	r.NoError(err)
+	r.Equal(`ALTER TABLE "posts" ALTER COLUMN "status" SET DEFAULT 'draft'`, stmt)
+
+	c = &Connection{Dialect: &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	stmt, err = c.AlterColumnDefaultSQL("posts", "status", nil)
+	r.NoError(err)
+	r.Equal("ALTER TABLE `posts` ALTER COLUMN `status` DROP DEFAULT", stmt)
+}
+
+func Test_mysqlDefault(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("'draft'", *mysqlDefault("draft", "varchar", ""))
+	r.Equal("'it''s'", *mysqlDefault("it's", "varchar", ""))
+	r.Equal("'draft'", *mysqlDefault("'draft'", "varchar", ""))
+	r.Equal("0", *mysqlDefault("0", "int", ""))
+	r.Equal("CURRENT_TIMESTAMP", *mysqlDefault("CURRENT_TIMESTAMP", "datetime", "DEFAULT_GENERATED"))
+	r.Equal("(uuid())", *mysqlDefault("uuid()", "char", "DEFAULT_GENERATED"))
+}
@@ -0,0 +1,108 @@
+package pop
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/gobuffalo/validate/v3"
+)
+
+// ColumnSizesValidatable is implemented by the models choosing whether
+// their string fields are validated against the size of their columns.
+// They are validated by default: ValidateAndCreate, ValidateAndUpdate and
+// ValidateAndSave report the values too long for their column, or with
+// characters their character set can not store, rather than letting the
+// database truncate or reject them.
+//
+//	func (Event) ValidateColumnSizes() bool {
+//		return false
+//	}
+type ColumnSizesValidatable interface {
+	ValidateColumnSizes() bool
+}
+
+// validateColumnSizes validates the string fields of the model against the
+// size and character set of their column.
+func (m *Model) validateColumnSizes(c *Connection) *validate.Errors {
+	verrs := validate.NewErrors()
+	if x, ok := m.Value.(ColumnSizesValidatable); ok && !x.ValidateColumnSizes() {
+		return verrs
+	}
+	if c.Store == nil {
+		return verrs
+	}
+	v := reflect.Indirect(reflect.ValueOf(m.Value))
+	if v.Kind() != reflect.Struct {
+		return verrs
+	}
+
+	cols, err := c.tableColumns(m.TableName())
+	if err != nil {
+		log(logging.Warn, "could not read the columns of %s: %s", m.TableName(), err)
+		return verrs
+	}
+	for name, col := range cols {
+		if col.Size == 0 && !isUTF8MB3(col.Charset) {
+			continue
+		}
+		f, ok := fieldByColumn(v, name)
+		if !ok {
+			continue
+		}
+		s, ok := stringValue(f)
+		if !ok {
+			continue
+		}
+		if col.Size > 0 && utf8.RuneCountInString(s) > col.Size {
+			verrs.Add(name, fmt.Sprintf("%s must be at most %d characters long", name, col.Size))
+		}
+		if isUTF8MB3(col.Charset) && hasSupplementaryRune(s) {
+			verrs.Add(name, fmt.Sprintf("%s contains characters which can not be stored in %s", name, col.Charset))
+		}
+	}
+	return verrs
+}
+
+// stringValue returns the string held by the field: a string, a pointer to
+// one, or a driver.Valuer of strings such as nulls.String.
+func stringValue(f reflect.Value) (string, bool) {
+	if !f.CanInterface() {
+		return "", false
+	}
+	if v, ok := f.Interface().(driver.Valuer); ok {
+		if f.Kind() == reflect.Ptr && f.IsNil() {
+			return "", false
+		}
+		dv, err := v.Value()
+		if err != nil {
+			return "", false
+		}
+		s, ok := dv.(string)
+		return s, ok
+	}
+	f = reflect.Indirect(f)
+	if f.Kind() != reflect.String {
+		return "", false
+	}
+	return f.String(), true
+}
+
+// isUTF8MB3 reports whether the MySQL character set only stores the
+// characters of the Basic Multilingual Plane.
+func isUTF8MB3(charset string) bool {
+	charset = strings.ToLower(charset)
+	return charset == "utf8" || charset == "utf8mb3"
+}
+
+func hasSupplementaryRune(s string) bool {
+	for _, r := range s {
+		if r > 0xFFFF {
+			return true
+		}
+	}
+	return false
+}
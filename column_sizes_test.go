@@ -0,0 +1,74 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type sizedWidget struct {
+	ID    int          `db:"id"`
+	Name  string       `db:"name"`
+	Label nulls.String `db:"label"`
+	Note  *string      `db:"note"`
+	Count int          `db:"count"`
+}
+
+func (sizedWidget) TableName() string {
+	return "sized_widgets"
+}
+
+type unsizedWidget sizedWidget
+
+func (unsizedWidget) TableName() string {
+	return "sized_widgets"
+}
+
+func (unsizedWidget) ValidateColumnSizes() bool {
+	return false
+}
+
+func Test_ValidateColumnSizes(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE sized_widgets (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, label TEXT, note TEXT, count INTEGER)").Exec())
+
+	// SQLite does not limit the sizes, the ones of a MySQL table are set.
+	tableColumnsCache.Store(tableColumnsKey{details: c.Dialect.Details(), table: "sized_widgets"}, tableColumnsEntry{
+		columns: map[string]tableColumn{
+			"id":    {},
+			"name":  {Size: 5, Charset: "utf8mb4"},
+			"label": {Size: 5, Charset: "utf8mb3"},
+			"note":  {Size: 3},
+			"count": {},
+		},
+		at: time.Now(),
+	})
+
+	note := "long"
+	w := &sizedWidget{Name: "héllo", Label: nulls.NewString("a😀"), Note: &note}
+	verrs, err := c.ValidateAndCreate(w)
+	r.NoError(err)
+	r.Len(verrs.Errors, 2)
+	r.Equal([]string{"label contains characters which can not be stored in utf8mb3"}, verrs.Get("label"))
+	r.Equal([]string{"note must be at most 3 characters long"}, verrs.Get("note"))
+	r.Zero(w.ID)
+
+	w.Name = "héllos"
+	w.Label = nulls.String{}
+	w.Note = nil
+	verrs, err = c.ValidateAndCreate(w)
+	r.NoError(err)
+	r.Equal([]string{"name must be at most 5 characters long"}, verrs.Get("name"))
+	r.Len(verrs.Errors, 1)
+
+	verrs, err = c.ValidateAndCreate(&unsizedWidget{Name: "héllos"})
+	r.NoError(err)
+	r.False(verrs.HasAny())
+}
@@ -1,6 +1,24 @@
 package columns
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// TablePlaceholder is replaced by the table alias, or the table name, in
+// the SELECT expressions declared with the `select` tag.
+const TablePlaceholder = "{table}"
+
+// Data classes of the columns, declared with the `class` tag of their
+// fields, e.g. `db:"email" class:"pii"`.
+const (
+	// ClassPublic columns hold data which may be exposed.
+	ClassPublic = "public"
+	// ClassPII columns hold personal data.
+	ClassPII = "pii"
+	// ClassSecret columns hold credentials, tokens and the like.
+	ClassSecret = "secret"
+)
 
 // Column represents a SQL table column.
 type Column struct {
@@ -8,6 +26,14 @@ type Column struct {
 	Writeable bool
 	Readable  bool
 	SelectSQL string
+	// Class is the data class of the column, "" when not declared.
+	Class string
+}
+
+// Sensitive tells whether the values of the column are personal data or
+// secrets, which are not to be logged or exported as they are.
+func (c Column) Sensitive() bool {
+	return c.Class == ClassPII || c.Class == ClassSecret
 }
 
 // UpdateString returns the SQL statement to UPDATE the column.
@@ -26,3 +52,47 @@ func (c *Column) SetSelectSQL(s string) {
 	c.Writeable = false
 	c.Readable = true
 }
+
+// SelectExpression returns the SELECT statement of a computed column named
+// name: table placeholders in expr are replaced by the given table alias,
+// and "AS name" is appended if expr does not already alias its result.
+//
+//	SelectExpression("COALESCE({table}.nickname, {table}.name)", "display_name", "u")
+//	// => COALESCE(u.nickname, u.name) AS display_name
+func SelectExpression(expr, name, tableAlias string) string {
+	expr = strings.TrimSpace(expr)
+	if tableAlias != "" {
+		expr = strings.ReplaceAll(expr, TablePlaceholder, tableAlias)
+	}
+	if aliasIndex(expr) < 0 {
+		expr = fmt.Sprintf("%s AS %s", expr, name)
+	}
+	return expr
+}
+
+// aliasIndex returns the index of the " AS " keyword aliasing the result of
+// the SELECT expression, or -1 if there is none. Keywords inside
+// parentheses or quotes, e.g. "CAST(x AS int)", are skipped.
+func aliasIndex(expr string) int {
+	depth := 0
+	var quote byte
+	index := -1
+	for i := 0; i < len(expr); i++ {
+		ch := expr[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '(':
+			depth++
+		case ch == ')':
+			depth--
+		case depth == 0 && i+4 <= len(expr) && strings.EqualFold(expr[i:i+4], " AS "):
+			index = i
+		}
+	}
+	return index
+}
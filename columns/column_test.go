@@ -12,3 +12,13 @@ func Test_Column_UpdateString(t *testing.T) {
 	c := columns.Column{Name: "foo"}
 	r.Equal(c.UpdateString(), "foo = :foo")
 }
+
+func Test_SelectExpression(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("COALESCE(u.nickname, u.name) AS display_name", columns.SelectExpression("COALESCE({table}.nickname, {table}.name)", "display_name", "u"))
+	r.Equal("COALESCE(nickname, name) AS display_name", columns.SelectExpression("COALESCE(nickname, name) AS display_name", "display_name", "u"))
+	r.Equal("CAST(u.price AS int) AS price_int", columns.SelectExpression("CAST({table}.price AS int)", "price_int", "u"))
+	r.Equal("first_name as f", columns.SelectExpression("first_name as f", "first_name", "u"))
+	r.Equal("'a AS b' AS label", columns.SelectExpression("'a AS b'", "label", "u"))
+}
@@ -42,9 +42,8 @@ func (c *Columns) Add(names ...string) []*Column {
 		//eg: id id2 - select id as id2
 		// also distinct columnname
 		// and distinct on (column1) column2
-		if strings.Contains(strings.ToUpper(xs[0]), " AS ") {
-			//eg: select id as id2
-			i := strings.LastIndex(strings.ToUpper(xs[0]), " AS ")
+		if i := aliasIndex(xs[0]); i >= 0 {
+			//eg: select id as id2, or COALESCE(a, b) AS c
 			ss = xs[0]
 			xs[0] = xs[0][i+4 : len(xs[0])] //get id2
 		} else if strings.Contains(xs[0], " ") {
@@ -119,6 +118,21 @@ func (c Columns) Readable() *ReadableColumns {
 	return w
 }
 
+// Classified gets the list of the columns of one of the data classes, e.g.
+// ClassPII, from the column list.
+func (c Columns) Classified(classes ...string) Columns {
+	w := NewColumnsWithAlias(c.TableName, c.TableAlias, c.IDField)
+	for _, col := range c.Cols {
+		for _, class := range classes {
+			if col.Class == class {
+				w.Cols[col.Name] = col
+				break
+			}
+		}
+	}
+	return w
+}
+
 // colNames returns a slice of column names in a deterministic order
 func (c Columns) colNames() []string {
 	var xs []string
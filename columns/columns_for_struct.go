@@ -66,11 +66,19 @@ func ForStructWithAlias(s interface{}, tableName, tableAlias string, idField IDF
 
 				cs := columns.Add(col)
 
+				if tag := popTags.Find("class"); !tag.Empty() {
+					cs[0].Class = tag.Value
+				}
+
 				// add select clause.
 				tag = popTags.Find("select")
 				if !tag.Empty() {
 					c := cs[0]
-					c.SetSelectSQL(tag.Value)
+					tableAlias := columns.TableAlias
+					if tableAlias == "" {
+						tableAlias = columns.TableName
+					}
+					c.SetSelectSQL(SelectExpression(tag.Value, c.Name, tableAlias))
 				}
 			}
 		}
@@ -39,6 +39,28 @@ func Test_Columns_Basics(t *testing.T) {
 	}
 }
 
+type computed struct {
+	ID          int    `db:"id"`
+	DisplayName string `db:"display_name" select:"COALESCE({table}.nickname, {table}.name)"`
+	Initials    string `db:"initials" select:"SUBSTR(first_name, 1, 1) || SUBSTR(last_name, 1, 1) AS initials"`
+}
+
+func Test_Columns_Computed(t *testing.T) {
+	r := require.New(t)
+
+	c := columns.ForStructWithAlias(&computed{}, "people", "p", columns.IDField{Name: "id", Writeable: true})
+	r.Equal("COALESCE(p.nickname, p.name) AS display_name, SUBSTR(first_name, 1, 1) || SUBSTR(last_name, 1, 1) AS initials, p.id", c.Readable().SelectString())
+	r.Equal("id", c.Writeable().String())
+
+	c = columns.ForStruct(&computed{}, "people", "id")
+	r.Equal("COALESCE(people.nickname, people.name) AS display_name", c.Cols["display_name"].SelectSQL)
+
+	c = columns.NewColumnsWithAlias("people", "p", columns.IDField{Name: "id"})
+	c.Add("COALESCE(nickname, name) AS display_name", "CAST(age AS text) AS age_text")
+	r.Equal("COALESCE(nickname, name) AS display_name", c.Cols["display_name"].SelectSQL)
+	r.Equal("CAST(age AS text) AS age_text", c.Cols["age_text"].SelectSQL)
+}
+
 func Test_Columns_Add(t *testing.T) {
 	r := require.New(t)
 
@@ -115,3 +137,26 @@ func Test_Columns_ID_Field_Not_ID(t *testing.T) {
 	r.Equal(1, len(c.Cols), "%+v", c)
 	r.Equal(&columns.Column{Name: "notid", Writeable: false, Readable: true, SelectSQL: "non_standard_id.notid"}, c.Cols["notid"])
 }
+
+type classified struct {
+	ID       int    `db:"id"`
+	Email    string `db:"email" class:"pii"`
+	Token    string `db:"token" class:"secret"`
+	Nickname string `class:"public"`
+	Bio      string
+}
+
+func Test_Columns_Class(t *testing.T) {
+	r := require.New(t)
+
+	cols := columns.ForStruct(classified{}, "classifieds", "id")
+	r.Equal("Bio, Nickname, email, id, token", cols.String())
+	r.Equal(columns.ClassPII, cols.Cols["email"].Class)
+	r.True(cols.Cols["email"].Sensitive())
+	r.True(cols.Cols["token"].Sensitive())
+	r.False(cols.Cols["Nickname"].Sensitive())
+	r.Equal("", cols.Cols["Bio"].Class)
+
+	r.Equal("email, token", cols.Classified(columns.ClassPII, columns.ClassSecret).String())
+	r.Equal("Nickname", cols.Classified(columns.ClassPublic).String())
+}
@@ -5,7 +5,7 @@ import (
 	"strings"
 )
 
-var tags = "db rw select belongs_to has_many has_one fk_id primary_id order_by many_to_many"
+var tags = "db rw select belongs_to has_many has_one fk_id primary_id order_by many_to_many count"
 
 // Tag represents a field tag defined exclusively for pop package.
 type Tag struct {
@@ -51,5 +51,9 @@ func TagsFor(field reflect.StructField) Tags {
 	if len(pTags) == 0 {
 		pTags = append(pTags, Tag{field.Name, "db"})
 	}
+	// the data class does not make a column of the field on its own.
+	if class := field.Tag.Get("class"); class != "" {
+		pTags = append(pTags, Tag{class, "class"})
+	}
 	return pTags
 }
@@ -19,3 +19,18 @@ func Test_Tags_TagsFor(t *testing.T) {
 	r.Equal(tags.Find("db").Value, "first_name")
 	r.Equal(tags.Find("select").Value, "first_name as f")
 }
+
+func Test_Tags_TagsFor_Count(t *testing.T) {
+	r := require.New(t)
+
+	type counted struct {
+		BooksCount int `count:"books_count"`
+	}
+
+	f, _ := reflect.TypeOf(counted{}).FieldByName("BooksCount")
+	tags := columns.TagsFor(f)
+
+	r.Len(tags, 1)
+	r.True(tags.Find("db").Empty())
+	r.Equal("books_count", tags.Find("count").Value)
+}
@@ -13,7 +13,7 @@ func CreateDB(c *Connection) error {
 		return nil
 	}
 
-	log(logging.Info, fmt.Sprintf("create %s (%s)", deets.Database, c.URL()))
+	log(logging.Info, fmt.Sprintf("create %s (%s)", deets.Database, c))
 
 	if err := c.Dialect.CreateDB(); err != nil {
 		return fmt.Errorf("couldn't create database %s: %w", deets.Database, err)
@@ -28,7 +28,7 @@ func DropDB(c *Connection) error {
 		return nil
 	}
 
-	log(logging.Info, fmt.Sprintf("drop %s (%s)", deets.Database, c.URL()))
+	log(logging.Info, fmt.Sprintf("drop %s (%s)", deets.Database, c))
 
 	if err := c.Dialect.DropDB(); err != nil {
 		return fmt.Errorf("couldn't drop database %s: %w", deets.Database, err)
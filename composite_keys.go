@@ -0,0 +1,124 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gofrs/uuid"
+)
+
+// compositeKeyType is the primary key type of the models with a composite
+// primary key, see Model.PrimaryKeyType.
+const compositeKeyType = "composite"
+
+// keyField is a field of the composite primary key of a model.
+type keyField struct {
+	Name   string
+	Column string
+}
+
+// compositeKey returns the fields of the composite primary key of the
+// model, nil when it has none. A composite primary key is made of the
+// fields tagged `primary:"true"`, at least two of them:
+//
+//	type Membership struct {
+//		OrgID  int    `db:"org_id" primary:"true"`
+//		UserID int    `db:"user_id" primary:"true"`
+//		Role   string `db:"role"`
+//	}
+func (m *Model) compositeKey() []keyField {
+	t := reflect.TypeOf(m.Value)
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var keys []keyField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Tag.Get("primary") != "true" {
+			continue
+		}
+		column := f.Tag.Get("db")
+		if column == "" {
+			column = f.Name
+		}
+		keys = append(keys, keyField{Name: f.Name, Column: column})
+	}
+	if len(keys) < 2 {
+		return nil
+	}
+	return keys
+}
+
+// keyColumns returns the columns of the composite primary key of the
+// model, nil when it has none.
+func (m *Model) keyColumns() []string {
+	var cols []string
+	for _, k := range m.compositeKey() {
+		cols = append(cols, k.Column)
+	}
+	return cols
+}
+
+// compositeID returns the values of the fields of the composite primary
+// key, UUIDs as strings.
+func (m *Model) compositeID(keys []keyField) []interface{} {
+	el := reflect.Indirect(reflect.ValueOf(m.Value))
+	ids := make([]interface{}, len(keys))
+	for i, k := range keys {
+		v := el.FieldByName(k.Name).Interface()
+		if u, ok := v.(uuid.UUID); ok {
+			v = u.String()
+		}
+		ids[i] = v
+	}
+	return ids
+}
+
+// idArgs returns the arguments of the predicate of WhereID.
+func (m *Model) idArgs() []interface{} {
+	if keys := m.compositeKey(); keys != nil {
+		return m.compositeID(keys)
+	}
+	return []interface{}{m.ID()}
+}
+
+// whereKey returns the predicate on the primary key of the model, with
+// the columns prefixed by the alias unless it is empty.
+func (m *Model) whereKey(alias string) string {
+	prefix := ""
+	if alias != "" {
+		prefix = alias + "."
+	}
+	keys := m.compositeKey()
+	if keys == nil {
+		return fmt.Sprintf("%s%s = ?", prefix, m.IDField())
+	}
+	preds := make([]string, len(keys))
+	for i, k := range keys {
+		preds[i] = fmt.Sprintf("%s%s = ?", prefix, k.Column)
+	}
+	return strings.Join(preds, " AND ")
+}
+
+// compositeIDArgs returns the values of the composite primary key given to
+// Find, a slice of the values of the fields of the key in their order.
+func compositeIDArgs(m *Model, keys []keyField, id interface{}) ([]interface{}, error) {
+	v := reflect.ValueOf(id)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array || v.Len() != len(keys) {
+		return nil, fmt.Errorf("%T has a composite primary key, the id must be a slice of %d values", m.Value, len(keys))
+	}
+	args := make([]interface{}, len(keys))
+	for i := range args {
+		a := v.Index(i).Interface()
+		if u, ok := a.(uuid.UUID); ok {
+			a = u.String()
+		}
+		args[i] = a
+	}
+	return args, nil
+}
@@ -0,0 +1,70 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type membership struct {
+	OrgID  int    `db:"org_id" primary:"true"`
+	UserID int    `db:"user_id" primary:"true"`
+	Role   string `db:"role"`
+}
+
+func Test_Model_CompositeKey(t *testing.T) {
+	r := require.New(t)
+
+	m := NewModel(&membership{OrgID: 1, UserID: 2}, nil)
+	r.Equal("memberships.org_id = ? AND memberships.user_id = ?", m.WhereID())
+	r.Equal("memberships.org_id = :org_id AND memberships.user_id = :user_id", m.WhereNamedID())
+	r.Equal("org_id = ? AND user_id = ?", m.whereKey(""))
+	r.Equal([]interface{}{1, 2}, m.ID())
+	r.False(m.UsingAutoIncrement())
+
+	pkt, err := m.PrimaryKeyType()
+	r.NoError(err)
+	r.Equal(compositeKeyType, pkt)
+
+	type single struct {
+		ID   int    `db:"id"`
+		Code string `db:"code" primary:"true"`
+	}
+	m = NewModel(&single{ID: 1}, nil)
+	r.Nil(m.compositeKey())
+	r.Equal("singles.id = ?", m.WhereID())
+}
+
+func Test_CompositeKey_CRUD(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE memberships (org_id INTEGER NOT NULL, user_id INTEGER NOT NULL, role TEXT NOT NULL, PRIMARY KEY (org_id, user_id))").Exec())
+
+	r.NoError(c.Create(&membership{OrgID: 1, UserID: 1, Role: "owner"}))
+	r.NoError(c.Create(&membership{OrgID: 1, UserID: 2, Role: "member"}))
+	r.NoError(c.Create(&membership{OrgID: 2, UserID: 1, Role: "member"}))
+
+	m := &membership{}
+	r.NoError(c.Find(m, []interface{}{1, 2}))
+	r.Equal("member", m.Role)
+	r.Error(c.Find(m, 1))
+
+	m.Role = "admin"
+	r.NoError(c.Update(m))
+	r.NoError(c.Reload(m))
+	r.Equal("admin", m.Role)
+
+	other := &membership{}
+	r.NoError(c.Find(other, []int{2, 1}))
+	r.Equal("member", other.Role)
+
+	r.NoError(c.Destroy(m))
+	count, err := c.Count(&membership{})
+	r.NoError(err)
+	r.Equal(2, count)
+	r.Error(c.Find(&membership{}, []interface{}{1, 2}))
+}
@@ -0,0 +1,55 @@
+package pop
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/jmoiron/sqlx"
+)
+
+// connectBackoff is the first wait between two connection attempts, doubled
+// after each of them up to maxConnectBackoff.
+var (
+	connectBackoff    = 100 * time.Millisecond
+	maxConnectBackoff = 5 * time.Second
+)
+
+// waitReady pings the database until it accepts connections, retrying as
+// set by ConnectTimeout and RetryAttempts.
+func waitReady(db *sqlx.DB, deets *ConnectionDetails) error {
+	if deets.ConnectTimeout <= 0 && deets.RetryAttempts <= 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	if deets.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, deets.ConnectTimeout)
+		defer cancel()
+	}
+
+	backoff := connectBackoff
+	for attempt := 0; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if deets.RetryAttempts > 0 && attempt >= deets.RetryAttempts {
+			return fmt.Errorf("could not connect after %d attempts: %w", attempt+1, err)
+		}
+		log(logging.Warn, "could not connect to %s, retrying in %s: %v", deets.Database, backoff, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("could not connect within %s: %w", deets.ConnectTimeout, err)
+		case <-timer.C:
+		}
+		if backoff *= 2; backoff > maxConnectBackoff {
+			backoff = maxConnectBackoff
+		}
+	}
+}
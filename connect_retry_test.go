@@ -0,0 +1,55 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Open_WaitsForTheDatabase(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, &ConnectionDetails{RetryAttempts: 2})
+	r.NoError(c.Close())
+}
+
+func Test_Open_RetryAttempts(t *testing.T) {
+	r := require.New(t)
+
+	backoff := connectBackoff
+	connectBackoff = time.Millisecond
+	defer func() { connectBackoff = backoff }()
+
+	c, err := NewConnection(&ConnectionDetails{
+		URL:           "sqlite://" + filepath.Join(t.TempDir(), "missing", "x.db") + "?_fk=true",
+		RetryAttempts: 2,
+	})
+	r.NoError(err)
+	err = c.Open()
+	r.Error(err)
+	r.Contains(err.Error(), "could not connect after 3 attempts")
+}
+
+func Test_Open_ConnectTimeout(t *testing.T) {
+	r := require.New(t)
+
+	backoff := connectBackoff
+	connectBackoff = 10 * time.Millisecond
+	defer func() { connectBackoff = backoff }()
+
+	c, err := NewConnection(&ConnectionDetails{
+		URL:            "sqlite://" + filepath.Join(t.TempDir(), "missing", "x.db") + "?_fk=true",
+		ConnectTimeout: 50 * time.Millisecond,
+	})
+	r.NoError(err)
+	start := time.Now()
+	err = c.Open()
+	r.Error(err)
+	r.Contains(err.Error(), "could not connect within 50ms")
+	r.Less(int64(time.Since(start)), int64(time.Second))
+}
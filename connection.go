@@ -27,14 +27,32 @@ type Connection struct {
 	TX          *Tx
 	eager       bool
 	eagerFields []string
+	readOnly    *int32
+	allowlists  []*allowlist
+
+	selectColumns []string
+	omitColumns   []string
+
+	validationContext string
+	skipInvalid       bool
+	recoverTx         bool
+	unscoped          bool
+	skipCallbacks     bool
+	skipTimestamps    bool
 }
 
+// String returns the URL of the connection with its secrets masked, see
+// URL for the unmasked one.
 func (c *Connection) String() string {
-	return c.URL()
+	return maskSecrets(c.URL())
 }
 
-// URL returns the datasource connection string
+// URL returns the datasource connection string, the one of the details
+// the connection was last reconfigured with, see Reconfigure.
 func (c *Connection) URL() string {
+	if r := rotatingOf(c.Store); r != nil {
+		return r.load().dialect.URL()
+	}
 	return c.Dialect.URL()
 }
 
@@ -65,7 +83,7 @@ func NewConnection(deets *ConnectionDetails) (*Connection, error) {
 	if err != nil {
 		return nil, err
 	}
-	c := &Connection{}
+	c := &Connection{readOnly: new(int32)}
 	c.setID()
 
 	if nc, ok := newConnection[deets.Dialect]; ok {
@@ -110,12 +128,28 @@ func (c *Connection) Open() error {
 	if c.Dialect == nil {
 		return errors.New("invalid connection instance")
 	}
-	details := c.Dialect.Details()
-
-	db, err := openPotentiallyInstrumentedConnection(c.Dialect, c.Dialect.URL())
+	var allowlists []*allowlist
+	if al := c.Dialect.Details().Allowlist; al != nil {
+		allowlists = []*allowlist{compileAllowlist(*al)}
+	}
+	s, err := openStore(c.Dialect, allowlists)
 	if err != nil {
 		return err
 	}
+	c.allowlists = allowlists
+	c.Store = newRotatingStore(s, c.Dialect)
+	return nil
+}
+
+// openStore opens the pool of the dialect, and of its readers, and returns
+// its store restricted to the allowlists.
+func openStore(d dialect, allowlists []*allowlist) (store, error) {
+	details := d.Details()
+
+	db, err := openPotentiallyInstrumentedConnection(d, d.URL())
+	if err != nil {
+		return nil, err
+	}
 
 	db.SetMaxOpenConns(details.Pool)
 	if details.IdlePool != 0 {
@@ -127,18 +161,32 @@ func (c *Connection) Open() error {
 	if details.ConnMaxIdleTime > 0 {
 		db.SetConnMaxIdleTime(details.ConnMaxIdleTime)
 	}
-	if details.Unsafe {
+	if details.Unsafe || details.TolerateSchemaDrift {
 		db = db.Unsafe()
 	}
-	c.Store = &dB{db}
-
-	if d, ok := c.Dialect.(afterOpenable); ok {
-		if err := d.AfterOpen(c); err != nil {
-			c.Store = nil
-			return fmt.Errorf("could not open database connection: %w", err)
+	if err := waitReady(db, details); err != nil {
+		db.Close()
+		return nil, err
+	}
+	readers, err := openReaders(details)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s := watched(commented(split(&dB{DB: db, stmts: newStmtCache(db, details.StatementCacheSize)}, readers, details.ReaderPolicy), details), details)
+
+	if ao, ok := d.(afterOpenable); ok {
+		c := &Connection{Store: s, Dialect: d, readOnly: new(int32)}
+		c.setID()
+		if err := ao.AfterOpen(c); err != nil {
+			s.Close()
+			return nil, fmt.Errorf("could not open database connection: %w", err)
 		}
 	}
-	return nil
+	if allowlists != nil {
+		s = restricted(s, allowlists)
+	}
+	return s, nil
 }
 
 // Close destroys an active datasource connection
@@ -152,8 +200,16 @@ func (c *Connection) Close() error {
 
 // Transaction will start a new transaction on the connection. If the inner function
 // returns an error then the transaction will be rolled back, otherwise the transaction
-// will automatically commit at the end.
+// will automatically commit at the end. If the inner function panics, the transaction
+// is rolled back and the panic raised again, see RecoverTx.
+//
+// Transactions started in another one join it, unless the connection fails them
+// with ErrNestedTransaction or runs them in savepoints of it, see
+// ConnectionDetails.NestedTransactions.
 func (c *Connection) Transaction(fn func(tx *Connection) error) error {
+	if c.TX != nil {
+		return c.nestedTransaction(fn)
+	}
 	return c.Dialect.Lock(func() (err error) {
 		var dberr error
 
@@ -165,12 +221,7 @@ func (c *Connection) Transaction(fn func(tx *Connection) error) error {
 
 		defer func() {
 			if ex := recover(); ex != nil {
-				txlog(logging.SQL, cn, "ROLLBACK Transaction (inner function panic) ---")
-				dberr = cn.TX.Rollback()
-				if dberr != nil {
-					txlog(logging.Error, cn, "database error while inner panic rollback: %w", dberr)
-				}
-				panic(ex)
+				err = c.recoverTxPanic(cn, ex, "ROLLBACK Transaction (inner function panic) ---")
 			}
 		}()
 
@@ -184,28 +235,63 @@ func (c *Connection) Transaction(fn func(tx *Connection) error) error {
 		}
 
 		if dberr != nil {
-			return fmt.Errorf("database error on committing or rolling back transaction: %w", dberr)
+			return c.diagnoseLocks(fmt.Errorf("database error on committing or rolling back transaction: %w", dberr))
 		}
 
-		return err
+		return c.diagnoseLocks(err)
 	})
 
 }
 
 // Rollback will open a new transaction and automatically rollback that transaction
 // when the inner function returns, regardless. This can be useful for tests, etc...
-func (c *Connection) Rollback(fn func(tx *Connection)) error {
+// If the inner function panics, the panic is raised again once the transaction is
+// rolled back, see RecoverTx. In another transaction, it runs
+// in a savepoint rolled back to with the NestedTransactionSavepoint policy,
+// and fails with ErrNestedTransaction otherwise, as it would roll back the
+// outer transaction.
+func (c *Connection) Rollback(fn func(tx *Connection)) (err error) {
 	// TODO: the name of the method could be changed to express it better.
+	if c.TX != nil {
+		return c.nestedRollback(fn)
+	}
 	cn, err := c.NewTransaction()
 	if err != nil {
 		return err
 	}
 	txlog(logging.SQL, cn, "BEGIN Transaction for Rollback ---")
+
+	defer func() {
+		if ex := recover(); ex != nil {
+			err = c.recoverTxPanic(cn, ex, "ROLLBACK Transaction for Rollback (inner function panic) ---")
+		}
+	}()
+
 	fn(cn)
 	txlog(logging.SQL, cn, "ROLLBACK Transaction as planned ---")
 	return cn.TX.Rollback()
 }
 
+// recoverTxPanic rolls back the transaction cn whose inner function panicked
+// with ex, then raises the panic again, or returns it as a *TxPanic when c
+// recovers them.
+func (c *Connection) recoverTxPanic(cn *Connection, ex interface{}, msg string) error {
+	p := newTxPanic(cn, ex)
+	txlog(logging.SQL, cn, msg)
+	if dberr := cn.TX.Rollback(); dberr != nil {
+		p.RollbackErr = dberr
+		txlog(logging.Error, cn, "database error while inner panic rollback: %s", dberr)
+	}
+	if c.recoverTx {
+		txlog(logging.Error, cn, "recovered %s", p)
+		return p
+	}
+	// the panic is raised again with its own value, for the callers
+	// recovering it, and its context is logged
+	txlog(logging.Error, cn, "%s", p)
+	panic(ex)
+}
+
 // NewTransaction starts a new transaction on the connection
 func (c *Connection) NewTransaction() (*Connection, error) {
 	return c.NewTransactionContextOptions(c.Context(), nil)
@@ -226,9 +312,11 @@ func (c *Connection) NewTransactionContextOptions(ctx context.Context, options *
 		}
 
 		cn = &Connection{
-			Store:   contextStore{store: tx, ctx: ctx},
-			Dialect: c.Dialect,
-			TX:      tx,
+			Store:      restricted(contextStore{store: watched(commented(tx, c.Dialect.Details()), c.Dialect.Details()), ctx: ctx}, c.allowlists),
+			Dialect:    c.Dialect,
+			TX:         tx,
+			readOnly:   c.readOnly,
+			allowlists: c.allowlists,
 		}
 		cn.setID()
 	} else {
@@ -253,9 +341,20 @@ func (c *Connection) copy() *Connection {
 	// related PRs: #72/#73, #79/#80, and #497
 
 	cn := &Connection{
-		Store:   c.Store,
-		Dialect: c.Dialect,
-		TX:      c.TX,
+		Store:         c.Store,
+		Dialect:       c.Dialect,
+		TX:            c.TX,
+		readOnly:      c.readOnly,
+		allowlists:    c.allowlists,
+		selectColumns: c.selectColumns,
+		omitColumns:   c.omitColumns,
+
+		validationContext: c.validationContext,
+		skipInvalid:       c.skipInvalid,
+		recoverTx:         c.recoverTx,
+		unscoped:          c.unscoped,
+		skipCallbacks:     c.skipCallbacks,
+		skipTimestamps:    c.skipTimestamps,
 	}
 	cn.setID(c.ID) // ID of the source as a seed
 
@@ -280,13 +379,18 @@ func (c *Connection) disableEager() {
 
 // TruncateAll truncates all data from the datasource
 func (c *Connection) TruncateAll() error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	return c.Dialect.TruncateAll(c)
 }
 
-func (c *Connection) timeFunc(name string, fn func() error) error {
+func (c *Connection) timeFunc(name string, model interface{}, fn func() error) error {
 	start := time.Now()
-	err := fn()
-	atomic.AddInt64(&c.Elapsed, int64(time.Since(start)))
+	err := c.traceOperation(name, model, fn)
+	elapsed := time.Since(start)
+	atomic.AddInt64(&c.Elapsed, int64(elapsed))
+	logOperation(c, name, model, elapsed)
 	if err != nil {
 		return err
 	}
@@ -46,8 +46,82 @@ type ConnectionDetails struct {
 	ConnMaxLifetime time.Duration
 	// Defaults to 0 "unlimited". See https://golang.org/pkg/database/sql/#DB.SetConnMaxIdleTime
 	ConnMaxIdleTime time.Duration
+	// ConnectTimeout is how long Open waits for the database to accept
+	// connections, retrying with a backoff, e.g. for an application started
+	// along with its database. Defaults to 0, Open does not wait unless
+	// RetryAttempts is set.
+	ConnectTimeout time.Duration
+	// RetryAttempts is the number of times Open retries to connect to the
+	// database before failing, within ConnectTimeout if set. Defaults to 0,
+	// Open retries until ConnectTimeout if set, else does not wait.
+	RetryAttempts int
+	// StatementCacheSize is the number of prepared statements cached by
+	// the connection, and by each of its Readers, the least recently used
+	// being closed first. The statements run repeatedly are then parsed and
+	// planned once per database connection, rather than on each run. Only
+	// the statements with arguments are cached, but the ones commented
+	// with their correlation ID or tags, see CommentCorrelationID and
+	// CommentQueries, which differ on each request.
+	// Defaults to 0 "disabled", as required by PgBouncer in transaction
+	// pooling mode.
+	StatementCacheSize int
 	// Defaults to `false`. See https://godoc.org/github.com/jmoiron/sqlx#DB.Unsafe
 	Unsafe bool
+	// TolerateSchemaDrift lets the application and the schema briefly
+	// diverge, during rolling deploys: the result columns without struct
+	// field are ignored, as with Unsafe, and the struct fields whose column
+	// does not exist yet are not inserted nor updated. Defaults to `false`.
+	TolerateSchemaDrift bool
+	// SlowQueryThreshold enables the query watchdog: statements still running
+	// after this duration are logged along with the stack trace of the code
+	// that issued them. Defaults to 0 "disabled".
+	SlowQueryThreshold time.Duration
+	// CancelSlowQueries cancels, through their context, the statements
+	// running longer than SlowQueryThreshold. Defaults to `false`.
+	CancelSlowQueries bool
+	// DiagnoseLocks attaches to the errors of the transactions failed on a
+	// deadlock or a lock wait timeout the report of the blocking chain, see
+	// LockConflictError. Supported by PostgreSQL, MySQL and MariaDB.
+	// Defaults to `false`.
+	DiagnoseLocks bool
+	// CommentCorrelationID appends the correlation ID of the context of the
+	// statements to them, as a comment, see SetCorrelationIDFunc. Defaults
+	// to `false`.
+	CommentCorrelationID bool
+	// CommentQueries appends the tags of the context of the statements to
+	// them, as a sqlcommenter comment, see SetQueryCommentFunc and
+	// WithQueryComments. Defaults to `false`.
+	CommentQueries bool
+	// ProfilerLabels sets the pprof labels "pop.operation" and "pop.table"
+	// while the operations on the models run, so that the CPU profiles
+	// attribute their time to them. Defaults to `false`.
+	ProfilerLabels bool
+	// NestedTransactions is the policy of the transactions started in
+	// another one: "join", the default, "fail" or "savepoint". See
+	// NestedTransactionPolicy.
+	NestedTransactions NestedTransactionPolicy
+	// Readers are the URLs of the replicas of the database, of the same
+	// dialect. The SELECT statements run outside of transactions are sent
+	// to them, see WithWriter, everything else to the database. Defaults
+	// to none.
+	Readers []string
+	// ReaderPolicy is how the reads are spread over the Readers:
+	// "round-robin", the default, or "least-conn". See ReaderPolicy.
+	ReaderPolicy ReaderPolicy
+	// Allowlist restricts the connection to the statements reading the
+	// allowed tables and columns, see Allowlist. Defaults to none.
+	Allowlist *Allowlist
+	// SessionSettings are set on each connection of the pool as it is
+	// opened, with SET SESSION on MySQL and MariaDB, SET on PostgreSQL and
+	// CockroachDB, and PRAGMA on SQLite, e.g. "search_path": "app, public".
+	// The values are SQL, inserted as they are. Defaults to none.
+	SessionSettings map[string]string
+	// StrictMode sets the settings making the database reject the invalid
+	// data rather than adjust it, whatever its defaults: the strict
+	// sql_mode on MySQL and MariaDB, standard_conforming_strings on
+	// PostgreSQL, and the defensive pragmas on SQLite. SessionSettings
+	// override them. Defaults to `false`.
+	StrictMode bool
 	// Options stores Connection Details options
 	Options     map[string]string
 	optionsLock *sync.Mutex
@@ -106,7 +180,7 @@ func (cd *ConnectionDetails) withURL() error {
 	// Fallback on generic parsing if no URL parser was found for the dialect.
 	u, err := url.Parse(ul)
 	if err != nil {
-		return fmt.Errorf("couldn't parse %s: %w", ul, err)
+		return fmt.Errorf("couldn't parse %s: %w", maskSecrets(ul), err)
 	}
 	cd.Database = strings.TrimPrefix(u.Path, "/")
 
@@ -145,6 +219,12 @@ func (cd *ConnectionDetails) Finalize() error {
 		fin(cd)
 	}
 
+	switch cd.ReaderPolicy {
+	case "", ReaderRoundRobin, ReaderLeastConn:
+	default:
+		return fmt.Errorf("unsupported reader policy '%v'", cd.ReaderPolicy)
+	}
+
 	if DialectSupported(cd.Dialect) {
 		if cd.Database != "" || cd.URL != "" {
 			return nil
@@ -177,6 +257,52 @@ func (cd *ConnectionDetails) MigrationTableName() string {
 	return defaults.String(cd.Options["migration_table_name"], "schema_migration")
 }
 
+// maskedSecret replaces the passwords and the tokens in the URLs printed.
+const maskedSecret = "*****"
+
+var (
+	regexpUserInfo     = regexp.MustCompile(`^([A-Za-z][\w+.-]*://)?([^:/@()\s]*):([^@/\s]+)@`)
+	regexpSecretOption = regexp.MustCompile(`(?i)((?:^|[?&;\s])(?:--)?(?:password|passwd|pwd|sslpassword|authToken|auth_token|jwt|token|secret|api_key|access_key)=)[^&;\s]*`)
+)
+
+// maskSecrets masks the password of the user info and the secret options
+// of the URL or command line s.
+func maskSecrets(s string) string {
+	s = regexpUserInfo.ReplaceAllString(s, "${1}${2}:"+maskedSecret+"@")
+	return regexpSecretOption.ReplaceAllString(s, "${1}"+maskedSecret)
+}
+
+// SafeURL returns the URL of the connection with its password and its
+// secret options masked, to be printed or logged.
+func (cd *ConnectionDetails) SafeURL() string {
+	if cd.URL != "" {
+		return maskSecrets(cd.URL)
+	}
+	s := cd.Dialect + "://"
+	if cd.User != "" {
+		s += cd.User
+		if cd.Password != "" {
+			s += ":" + maskedSecret
+		}
+		s += "@"
+	}
+	s += cd.Host
+	if cd.Port != "" {
+		s += ":" + cd.Port
+	}
+	s += "/" + cd.Database
+	if opts := cd.OptionsString(""); opts != "" {
+		s += "?" + opts
+	}
+	return maskSecrets(s)
+}
+
+// String returns the URL of the connection with its secrets masked, so
+// printing the connection details never leaks the credentials.
+func (cd ConnectionDetails) String() string {
+	return cd.SafeURL()
+}
+
 // OptionsString returns URL parameter encoded string from options.
 func (cd *ConnectionDetails) OptionsString(s string) string {
 	if cd.RawOptions != "" {
@@ -184,7 +310,7 @@ func (cd *ConnectionDetails) OptionsString(s string) string {
 	}
 	if cd.Options != nil {
 		for k, v := range cd.Options {
-			if k == "migration_table_name" {
+			if k == "migration_table_name" || k == "compatibility" || k == "binding" {
 				continue
 			}
 
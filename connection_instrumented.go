@@ -50,13 +50,34 @@ func instrumentDriver(deets *ConnectionDetails, defaultDriverName string) (drive
 	case nameMySQL:
 		dr = mysqld.MySQLDriver{}
 		newDriverName = instrumentedDriverName + "-" + nameMySQL
-	case nameSQLite3:
+	case nameSQLite3, sqliteDriverName:
 		var err error
-		dr, err = newSQLiteDriver()
+		dr, err = newSQLiteDriver(driverName)
 		if err != nil {
 			return "", "", err
 		}
-		newDriverName = instrumentedDriverName + "-" + nameSQLite3
+		newDriverName = instrumentedDriverName + "-" + driverName
+	case nameDuckDB:
+		var err error
+		dr, err = newDuckDBDriver()
+		if err != nil {
+			return "", "", err
+		}
+		newDriverName = instrumentedDriverName + "-" + nameDuckDB
+	case nameOracle:
+		var err error
+		dr, err = newOracleDriver()
+		if err != nil {
+			return "", "", err
+		}
+		newDriverName = instrumentedDriverName + "-" + nameOracle
+	case nameSpanner:
+		var err error
+		dr, err = newSpannerDriver()
+		if err != nil {
+			return "", "", err
+		}
+		newDriverName = instrumentedDriverName + "-" + nameSpanner
 	}
 
 	sqlDriverLock.Lock()
@@ -84,6 +105,23 @@ func instrumentDriver(deets *ConnectionDetails, defaultDriverName string) (drive
 // a custom driver name when using instrumentation, this detection would fail
 // otherwise.
 func openPotentiallyInstrumentedConnection(c dialect, dsn string) (*sqlx.DB, error) {
+	statements, err := sessionStatements(c)
+	if err != nil {
+		return nil, err
+	}
+	if cn, ok := c.(connectorable); ok {
+		connector, err := cn.Connector()
+		if err != nil {
+			return nil, fmt.Errorf("could not open database connection: %w", err)
+		}
+		if connector != nil {
+			if len(statements) > 0 {
+				connector = sessionConnector{Connector: connector, statements: statements}
+			}
+			return sqlx.NewDb(sql.OpenDB(connector), c.DefaultDriver()), nil
+		}
+	}
+
 	driverName, dialect, err := instrumentDriver(c.Details(), c.DefaultDriver())
 	if err != nil {
 		return nil, err
@@ -93,6 +131,20 @@ func openPotentiallyInstrumentedConnection(c dialect, dsn string) (*sqlx.DB, err
 	if err != nil {
 		return nil, fmt.Errorf("could not open database connection: %w", err)
 	}
+	if len(statements) > 0 {
+		// the session settings are applied to each connection of the pool
+		// as it is opened.
+		connector, err := driverConnector(con.Driver(), dsn)
+		con.Close()
+		if err != nil {
+			return nil, fmt.Errorf("could not open database connection: %w", err)
+		}
+		con = sql.OpenDB(sessionConnector{Connector: connector, statements: statements})
+	}
 
-	return sqlx.NewDb(con, dialect), nil
+	db := sqlx.NewDb(con, dialect)
+	if m, ok := c.(mapperable); ok {
+		db.Mapper = m.Mapper()
+	}
+	return db, nil
 }
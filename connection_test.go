@@ -144,4 +144,126 @@ func Test_Connection_Transaction(t *testing.T) {
 			})
 		})
 	})
+
+	t.Run("Nested Fail", func(t *testing.T) {
+		c.Dialect.Details().NestedTransactions = NestedTransactionFail
+		defer func() {
+			c.Dialect.Details().NestedTransactions = ""
+		}()
+
+		err = c.Transaction(func(tx *Connection) error {
+			err := tx.Transaction(func(tx *Connection) error {
+				return nil
+			})
+			r.ErrorIs(err, ErrNestedTransaction)
+			r.ErrorIs(tx.Rollback(func(tx *Connection) {}), ErrNestedTransaction)
+			return nil
+		})
+		r.NoError(err)
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		// nested transactions join the outer one by default
+		r.NoError(c.RawQuery("CREATE TABLE nested (id INTEGER)").Exec())
+		err = c.Transaction(func(tx *Connection) error {
+			r.NoError(tx.Transaction(func(inner *Connection) error {
+				r.Equal(tx.TX.ID, inner.TX.ID)
+				return inner.RawQuery("INSERT INTO nested (id) VALUES (1)").Exec()
+			}))
+			return fmt.Errorf("failed")
+		})
+		r.EqualError(err, "failed")
+
+		count, err := c.RawQuery("SELECT * FROM nested").Count(nil)
+		r.NoError(err)
+		r.Zero(count)
+	})
+
+	t.Run("Nested Savepoint", func(t *testing.T) {
+		c.Dialect.Details().NestedTransactions = NestedTransactionSavepoint
+		defer func() {
+			c.Dialect.Details().NestedTransactions = ""
+		}()
+
+		r.NoError(c.RawQuery("CREATE TABLE savepoints (id INTEGER)").Exec())
+		err = c.Transaction(func(tx *Connection) error {
+			r.NoError(tx.RawQuery("INSERT INTO savepoints (id) VALUES (1)").Exec())
+			err := tx.Transaction(func(inner *Connection) error {
+				r.NoError(inner.RawQuery("INSERT INTO savepoints (id) VALUES (2)").Exec())
+				return fmt.Errorf("failed")
+			})
+			r.EqualError(err, "failed")
+			return tx.Transaction(func(inner *Connection) error {
+				r.NoError(inner.RawQuery("INSERT INTO savepoints (id) VALUES (3)").Exec())
+				return inner.Transaction(func(inner *Connection) error {
+					return inner.RawQuery("INSERT INTO savepoints (id) VALUES (4)").Exec()
+				})
+			})
+		})
+		r.NoError(err)
+
+		var ids []int
+		r.NoError(c.Store.Select(&ids, "SELECT id FROM savepoints ORDER BY id"))
+		r.Equal([]int{1, 3, 4}, ids)
+
+		// the nested Rollback is rolled back to its savepoint only
+		err = c.Transaction(func(tx *Connection) error {
+			r.NoError(tx.Rollback(func(inner *Connection) {
+				r.Equal(tx.TX.ID, inner.TX.ID)
+				r.NoError(inner.RawQuery("INSERT INTO savepoints (id) VALUES (5)").Exec())
+			}))
+			return tx.RawQuery("INSERT INTO savepoints (id) VALUES (6)").Exec()
+		})
+		r.NoError(err)
+		ids = nil
+		r.NoError(c.Store.Select(&ids, "SELECT id FROM savepoints ORDER BY id"))
+		r.Equal([]int{1, 3, 4, 6}, ids)
+
+		// only the outermost transaction commits
+		err = c.Transaction(func(tx *Connection) error {
+			r.NoError(tx.Transaction(func(inner *Connection) error {
+				return inner.RawQuery("INSERT INTO savepoints (id) VALUES (7)").Exec()
+			}))
+			return fmt.Errorf("failed")
+		})
+		r.EqualError(err, "failed")
+		count, err := c.RawQuery("SELECT * FROM savepoints").Count(nil)
+		r.NoError(err)
+		r.Equal(4, count)
+	})
+
+	t.Run("RecoverTx", func(t *testing.T) {
+		inner := fmt.Errorf("inner error")
+		err = c.RecoverTx().Transaction(func(c *Connection) error {
+			panic(inner)
+		})
+		var p *TxPanic
+		r.ErrorAs(err, &p)
+		r.ErrorIs(err, inner)
+		r.Contains(err.Error(), "inner error")
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		r.NoError(c.RawQuery("CREATE TABLE panics (id INTEGER)").Exec())
+		err = c.RecoverTx().Rollback(func(c *Connection) {
+			r.NoError(c.RawQuery("INSERT INTO panics (id) VALUES (1)").Exec())
+			panic("inner function panic")
+		})
+		var p *TxPanic
+		r.ErrorAs(err, &p)
+		r.Equal("inner function panic", p.Value)
+		r.Equal("INSERT INTO panics (id) VALUES (1)", p.Statement)
+		r.NotEmpty(p.Stack)
+		r.NoError(p.RollbackErr)
+
+		count, err := c.RawQuery("SELECT * FROM panics").Count(nil)
+		r.NoError(err)
+		r.Zero(count)
+
+		r.PanicsWithValue("inner function panic", func() {
+			c.Rollback(func(c *Connection) {
+				panic("inner function panic")
+			})
+		})
+	})
 }
@@ -0,0 +1,215 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var correlationIDFunc func(ctx context.Context) string
+
+var queryCommentFunc func(ctx context.Context) map[string]string
+
+// queryCommentsKey is the context key of the tags set by WithQueryComments.
+type queryCommentsKey struct{}
+
+// SetCorrelationIDFunc sets the function returning the correlation ID,
+// e.g. the request ID, of the context of the connection. The ID is logged
+// with the statements and, when ConnectionDetails.CommentCorrelationID is
+// set, appended to them as a comment, so it shows in the logs of the
+// database and in the spans of the instrumented driver.
+//
+//	pop.SetCorrelationIDFunc(func(ctx context.Context) string {
+//		id, _ := ctx.Value(requestIDKey).(string)
+//		return id
+//	})
+func SetCorrelationIDFunc(f func(ctx context.Context) string) {
+	correlationIDFunc = f
+}
+
+// correlationID returns the correlation ID of the context, or "".
+func correlationID(ctx context.Context) string {
+	if correlationIDFunc == nil || ctx == nil {
+		return ""
+	}
+	return correlationIDFunc(ctx)
+}
+
+// SetQueryCommentFunc sets the function returning the tags of the context
+// of the connection, e.g. the application, the route and the traceparent
+// of the request. When ConnectionDetails.CommentQueries is set, they are
+// appended to the statements as a sqlcommenter comment, so the slow
+// statements listed by the database, e.g. in pg_stat_statements, can be
+// traced back to the endpoints running them.
+//
+//	pop.SetQueryCommentFunc(func(ctx context.Context) map[string]string {
+//		return map[string]string{"app": "api", "traceparent": traceparent(ctx)}
+//	})
+func SetQueryCommentFunc(f func(ctx context.Context) map[string]string) {
+	queryCommentFunc = f
+}
+
+// WithQueryComments returns a copy of the context with the tags added to
+// the ones commented on its statements, see SetQueryCommentFunc, e.g. by
+// the middleware matching the route of the request. The tags override
+// the ones of the parent context and of the function.
+//
+//	ctx = pop.WithQueryComments(ctx, map[string]string{"route": "/users"})
+func WithQueryComments(ctx context.Context, tags map[string]string) context.Context {
+	merged := map[string]string{}
+	if parent, ok := ctx.Value(queryCommentsKey{}).(map[string]string); ok {
+		for k, v := range parent {
+			merged[k] = v
+		}
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, queryCommentsKey{}, merged)
+}
+
+// queryComments returns the tags of the context, those of the function
+// overridden by the ones of WithQueryComments.
+func queryComments(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	tags := map[string]string{}
+	if queryCommentFunc != nil {
+		for k, v := range queryCommentFunc(ctx) {
+			tags[k] = v
+		}
+	}
+	if set, ok := ctx.Value(queryCommentsKey{}).(map[string]string); ok {
+		for k, v := range set {
+			tags[k] = v
+		}
+	}
+	return tags
+}
+
+// sqlComment returns the sqlcommenter comment of the tags, sorted by key,
+// with their keys and values escaped, or "" if there is none:
+//
+//	/*app='api',route='%2Fusers'*/
+func sqlComment(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for k, v := range tags {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = fmt.Sprintf("%s='%s'", url.QueryEscape(k), url.QueryEscape(tags[k]))
+	}
+	return "/*" + strings.Join(pairs, ",") + "*/"
+}
+
+// isCommented returns true if the query ends with a sqlcommenter comment.
+func isCommented(query string) bool {
+	return strings.HasSuffix(query, "'*/")
+}
+
+// commentStore wraps a store and appends the correlation ID and the tags of
+// their context to the statements, as a sqlcommenter comment:
+//
+//	SELECT * FROM users /*request_id='7f3a9c',route='%2Fusers'*/
+type commentStore struct {
+	store
+	correlationID bool
+	tags          bool
+}
+
+// commented wraps the given store with the correlation ID and tags
+// comments if the connection details enable them.
+func commented(s store, deets *ConnectionDetails) store {
+	if deets == nil || (!deets.CommentCorrelationID && !deets.CommentQueries) {
+		return s
+	}
+	return commentStore{store: s, correlationID: deets.CommentCorrelationID, tags: deets.CommentQueries}
+}
+
+func (s commentStore) unwrap() store {
+	return s.store
+}
+
+// comment appends the correlation ID and the tags of the context to the
+// query.
+func (s commentStore) comment(ctx context.Context, query string) string {
+	tags := map[string]string{}
+	if s.tags {
+		tags = queryComments(ctx)
+	}
+	if s.correlationID {
+		if id := correlationID(ctx); id != "" {
+			tags["request_id"] = id
+		}
+	}
+	c := sqlComment(tags)
+	if c == "" {
+		return query
+	}
+	return query + " " + c
+}
+
+func (s commentStore) Select(dest interface{}, query string, args ...interface{}) error {
+	return s.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (s commentStore) Get(dest interface{}, query string, args ...interface{}) error {
+	return s.GetContext(context.Background(), dest, query, args...)
+}
+
+func (s commentStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return s.NamedExecContext(context.Background(), query, arg)
+}
+
+func (s commentStore) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return s.NamedQueryContext(context.Background(), query, arg)
+}
+
+func (s commentStore) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecContext(context.Background(), query, args...)
+}
+
+func (s commentStore) PrepareNamed(query string) (*sqlx.NamedStmt, error) {
+	return s.PrepareNamedContext(context.Background(), query)
+}
+
+func (s commentStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.store.SelectContext(ctx, dest, s.comment(ctx, query), args...)
+}
+
+func (s commentStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.store.GetContext(ctx, dest, s.comment(ctx, query), args...)
+}
+
+func (s commentStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	return s.store.NamedExecContext(ctx, s.comment(ctx, query), arg)
+}
+
+func (s commentStore) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	return s.store.NamedQueryContext(ctx, s.comment(ctx, query), arg)
+}
+
+func (s commentStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return s.store.QueryxContext(ctx, s.comment(ctx, query), args...)
+}
+
+func (s commentStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return s.store.ExecContext(ctx, s.comment(ctx, query), args...)
+}
+
+func (s commentStore) PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error) {
+	return s.store.PrepareNamedContext(ctx, s.comment(ctx, query))
+}
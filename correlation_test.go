@@ -0,0 +1,90 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type requestIDKey struct{}
+
+// execRecorder records the statements executed through it.
+type execRecorder struct {
+	store
+	queries []string
+}
+
+func (s *execRecorder) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	s.queries = append(s.queries, query)
+	return nil, nil
+}
+
+func Test_CommentCorrelationID(t *testing.T) {
+	r := require.New(t)
+
+	SetCorrelationIDFunc(func(ctx context.Context) string {
+		id, _ := ctx.Value(requestIDKey{}).(string)
+		return id
+	})
+	defer SetCorrelationIDFunc(nil)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req 42'*/")
+
+	rec := &execRecorder{}
+	s := commented(rec, &ConnectionDetails{CommentCorrelationID: true})
+	_, err := s.ExecContext(ctx, "SELECT 1")
+	r.NoError(err)
+	_, err = s.Exec("SELECT 2")
+	r.NoError(err)
+	_, err = commented(rec, &ConnectionDetails{}).ExecContext(ctx, "SELECT 3")
+	r.NoError(err)
+	r.Equal([]string{"SELECT 1 /*request_id='req+42%27%2A%2F'*/", "SELECT 2", "SELECT 3"}, rec.queries)
+
+	c := openSQLite(t, &ConnectionDetails{CommentCorrelationID: true})
+
+	r.NoError(c.WithContext(ctx).Transaction(func(tx *Connection) error {
+		r.NoError(tx.RawQuery("SELECT 4").Exec())
+		r.Equal("SELECT 4 /*request_id='req+42%27%2A%2F'*/", tx.TX.Statement())
+		return nil
+	}))
+}
+
+func Test_CommentQueries(t *testing.T) {
+	r := require.New(t)
+
+	SetQueryCommentFunc(func(ctx context.Context) map[string]string {
+		return map[string]string{"app": "api", "route": "unknown"}
+	})
+	defer SetQueryCommentFunc(nil)
+	SetCorrelationIDFunc(func(ctx context.Context) string {
+		id, _ := ctx.Value(requestIDKey{}).(string)
+		return id
+	})
+	defer SetCorrelationIDFunc(nil)
+
+	ctx := WithQueryComments(context.Background(), map[string]string{"route": "/users", "traceparent": "00-4bf9-01"})
+	ctx = WithQueryComments(ctx, map[string]string{"action": "list"})
+
+	rec := &execRecorder{}
+	s := commented(rec, &ConnectionDetails{CommentQueries: true})
+	_, err := s.ExecContext(ctx, "SELECT 1")
+	r.NoError(err)
+	_, err = s.ExecContext(context.WithValue(ctx, requestIDKey{}, "42"), "SELECT 2")
+	r.NoError(err)
+	_, err = commented(rec, &ConnectionDetails{CommentQueries: true, CommentCorrelationID: true}).ExecContext(context.WithValue(ctx, requestIDKey{}, "42"), "SELECT 3")
+	r.NoError(err)
+	r.Equal([]string{
+		"SELECT 1 /*action='list',app='api',route='%2Fusers',traceparent='00-4bf9-01'*/",
+		"SELECT 2 /*action='list',app='api',route='%2Fusers',traceparent='00-4bf9-01'*/",
+		"SELECT 3 /*action='list',app='api',request_id='42',route='%2Fusers',traceparent='00-4bf9-01'*/",
+	}, rec.queries)
+	r.True(isCommented(rec.queries[0]))
+
+	c := openSQLite(t, &ConnectionDetails{CommentQueries: true})
+	r.NoError(c.WithContext(ctx).RawQuery("SELECT 4").Exec())
+}
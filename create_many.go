@@ -0,0 +1,227 @@
+package pop
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/gofrs/uuid"
+)
+
+// defaultCreateManyBatchSize is the number of rows inserted by the
+// statements of CreateMany when no batch size is given.
+const defaultCreateManyBatchSize = 500
+
+// CreateMany creates the entries of the slice with multi-row INSERT
+// statements of batchSize rows at most, 500 when batchSize is not
+// positive, excluding the given columns. With AdaptiveBatchSize, the size
+// of the batches adapts to the latency of the statements. The batches are
+// capped by the number of parameters the database accepts, and on MySQL
+// by max_allowed_packet. It sets the IDs, created_at and updated_at of
+// the entries and runs their callbacks as Create does, but does not
+// create their associations. The entries are created in a transaction,
+// the one of the connection if any.
+//
+// The IDs generated by MySQL are read from the ID of the first row of the
+// statements, which requires consecutive auto-increment values, the
+// default for the inserts of a known number of rows. Oracle and Spanner
+// create the entries one at a time.
+//
+//	err := c.CreateMany(&events, 1000)
+func (c *Connection) CreateMany(model interface{}, batchSize int, excludeColumns ...string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	v := reflect.Indirect(reflect.ValueOf(model))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("can not create many %T, a slice is required", model)
+	}
+	if v.Len() == 0 {
+		return nil
+	}
+	adaptive := batchSize == AdaptiveBatchSize
+	if batchSize <= 0 && !adaptive {
+		batchSize = defaultCreateManyBatchSize
+	}
+	if c.TX == nil {
+		return c.Transaction(func(tx *Connection) error {
+			return tx.CreateMany(model, batchSize, excludeColumns...)
+		})
+	}
+
+	first := v.Index(0)
+	if first.Kind() != reflect.Ptr {
+		first = first.Addr()
+	}
+	m := NewModel(first.Interface(), c.Context())
+	limit := maxBatchRows(c, m)
+	sizer := batchSizerFor(Insert, m.TableName())
+
+	return c.timeFunc("CreateMany", model, func() error {
+		for i := 0; i < v.Len(); {
+			n := batchSize
+			if adaptive {
+				n = sizer.next(limit)
+			} else if limit > 0 && n > limit {
+				n = limit
+			}
+			j := i + n
+			if j > v.Len() {
+				j = v.Len()
+			}
+			start := time.Now()
+			if err := c.createBatch(v.Slice(i, j), excludeColumns); err != nil {
+				return err
+			}
+			if adaptive {
+				sizer.observe(j-i, time.Since(start))
+			}
+			i = j
+		}
+		return nil
+	})
+}
+
+// createBatch creates the entries of the batch with a single statement.
+func (c *Connection) createBatch(batch reflect.Value, excludeColumns []string) error {
+	models := make([]*Model, batch.Len())
+	for i := range models {
+		item := batch.Index(i)
+		if item.Kind() != reflect.Ptr {
+			item = item.Addr()
+		}
+		models[i] = NewModel(item.Interface(), c.Context())
+	}
+
+	switch c.Dialect.Name() {
+	case nameOracle, nameSpanner:
+		// no multi-row VALUES nor generated IDs to read back
+		for _, m := range models {
+			if err := c.Create(m.Value, excludeColumns...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	m := models[0]
+	keyType, err := m.PrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	autoID := m.UsingAutoIncrement() && (keyType == "int" || keyType == "int64")
+
+	now := nowFunc().Truncate(time.Microsecond)
+	by := actor(c.Context())
+	for _, m := range models {
+		if err := m.beforeSave(c); err != nil {
+			return err
+		}
+		if err := m.beforeCreate(c); err != nil {
+			return err
+		}
+		switch {
+		case keyType == "UUID" && m.ID() == emptyUUID:
+			u, err := uuid.NewV4()
+			if err != nil {
+				return err
+			}
+			m.setID(u)
+		case keyType == "string" && m.ID() == "":
+			return fmt.Errorf("missing ID value")
+		}
+		c.setTimestamps(m, now, true)
+		m.setCreatedBy(by)
+		m.setUpdatedBy(by)
+	}
+
+	cols := m.Columns()
+	cols.Remove(excludeColumns...)
+	cols = restrictColumns(cols, c.selectColumns, c.omitColumns, m.IDField(), "created_at", "updated_at", createdByColumn, updatedByColumn)
+	cols = c.existingColumns(cols)
+	if autoID {
+		cols.Remove(m.IDField())
+	}
+	w := cols.Writeable()
+	if !autoID && keyType != compositeKeyType {
+		w.Add(m.IDField())
+	}
+	if len(w.Cols) == 0 {
+		for _, m := range models {
+			if err := c.Create(m.Value, excludeColumns...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", c.Dialect.Quote(m.TableName()), w.QuotedString(c.Dialect), w.SymbolizedString())
+	returning := autoID && supports(c.Dialect, supportsReturning)
+	if returning {
+		query += " RETURNING " + c.Dialect.Quote(m.IDField())
+	}
+	query, err = beforeNamedExec(Insert, m, query)
+	if err != nil {
+		return err
+	}
+	txlog(logging.SQL, c, query, batch.Interface())
+
+	switch {
+	case returning:
+		if err := createBatchReturning(c, models, query, batch.Interface()); err != nil {
+			return err
+		}
+	default:
+		res, err := c.Store.NamedExecContext(c.Context(), query, batch.Interface())
+		if err != nil {
+			return fmt.Errorf("named insert: %w", err)
+		}
+		if autoID {
+			first, err := res.LastInsertId()
+			if err != nil {
+				return err
+			}
+			for i, m := range models {
+				m.setID(first + int64(i))
+			}
+		}
+	}
+
+	for _, m := range models {
+		if err := m.afterCreate(c); err != nil {
+			return err
+		}
+		if err := m.afterSave(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createBatchReturning runs the insert of the batch and sets the IDs
+// returned, in the order of the rows.
+func createBatchReturning(c *Connection, models []*Model, query string, batch interface{}) error {
+	rows, err := c.Store.NamedQueryContext(c.Context(), query, batch)
+	if err != nil {
+		return fmt.Errorf("named insert: %w", err)
+	}
+	defer rows.Close()
+	i := 0
+	for rows.Next() {
+		if i == len(models) {
+			return fmt.Errorf("named insert: more IDs returned than rows inserted")
+		}
+		var id sql.NullInt64
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("named insert: scan: %w", err)
+		}
+		models[i].setID(id.Int64)
+		i++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("named insert: next: %w", err)
+	}
+	return rows.Close()
+}
@@ -0,0 +1,78 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type loggedEvent struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	Source    string    `db:"source"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func (e *loggedEvent) BeforeCreate(tx *Connection) error {
+	if e.Source == "" {
+		e.Source = "app"
+	}
+	return nil
+}
+
+func Test_CreateMany(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE logged_events (id INTEGER PRIMARY KEY, name TEXT NOT NULL, source TEXT NOT NULL DEFAULT 'db', created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)").Exec())
+
+	events := make([]loggedEvent, 7)
+	for i := range events {
+		events[i].Name = string(rune('a' + i))
+	}
+	r.NoError(c.CreateMany(&events, 3))
+
+	for _, e := range events {
+		r.NotZero(e.ID)
+		r.False(e.CreatedAt.IsZero())
+		r.Equal("app", e.Source)
+
+		found := &loggedEvent{}
+		r.NoError(c.Find(found, e.ID))
+		r.Equal(e.Name, found.Name)
+	}
+
+	more := []*loggedEvent{{Name: "x", Source: "cron"}, {Name: "y"}}
+	r.NoError(c.CreateMany(more, 0, "source"))
+	r.NotZero(more[1].ID)
+	found := &loggedEvent{}
+	r.NoError(c.Find(found, more[0].ID))
+	r.Equal("x", found.Name)
+	r.Equal("db", found.Source)
+
+	count, err := c.Count(&loggedEvent{})
+	r.NoError(err)
+	r.Equal(9, count)
+
+	r.NoError(c.CreateMany(&[]loggedEvent{}, 10))
+	r.Error(c.CreateMany(&loggedEvent{}, 10))
+}
+
+func Test_CreateMany_Rollback(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE logged_events (id INTEGER PRIMARY KEY, name TEXT NOT NULL UNIQUE, source TEXT NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)").Exec())
+
+	events := []loggedEvent{{Name: "a"}, {Name: "b"}, {Name: "a"}}
+	r.Error(c.CreateMany(&events, 2))
+
+	count, err := c.Count(&loggedEvent{})
+	r.NoError(err)
+	r.Equal(0, count)
+}
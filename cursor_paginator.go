@@ -0,0 +1,239 @@
+package pop
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CursorPaginator is the keyset pagination of a query, see
+// Query.PaginateByCursor.
+type CursorPaginator struct {
+	// Cursor is the cursor of the page, "" for the first one.
+	Cursor string `json:"cursor"`
+	// Limit is the maximum number of results of the page.
+	Limit int `json:"limit"`
+	// NextCursor is the cursor of the next page, "" on the last one. It is
+	// set by All.
+	NextCursor string `json:"next_cursor"`
+
+	columns []cursorColumn
+}
+
+// cursorColumn is a column ordering the results of a cursor pagination.
+type cursorColumn struct {
+	name string
+	desc bool
+}
+
+// PaginateByCursor paginates the records with a cursor, see
+// Query.PaginateByCursor.
+//
+//	q := c.PaginateByCursor(req.URL.Query().Get("cursor"), 50, "created_at desc", "id desc")
+//	q.All(&[]User{})
+//	q.CursorPaginator.NextCursor
+func (c *Connection) PaginateByCursor(cursor string, limit int, orderColumns ...string) *Query {
+	return Q(c).PaginateByCursor(cursor, limit, orderColumns...)
+}
+
+// PaginateByCursor paginates the records with a cursor rather than an
+// offset: the page holds at most limit records following the ones of the
+// cursor, in the order of the columns, "id" when none are given. Each
+// column may be followed by "asc" or "desc", the last one must identify
+// the records and none may be NULL. The columns are added to the order
+// clauses of the query.
+//
+// All sets the cursor of the next page in q.CursorPaginator.NextCursor, ""
+// on the last page, and fails when the cursor is invalid.
+//
+//	q = q.PaginateByCursor(cursor, 50, "created_at desc", "id desc")
+//	q.All(&[]User{})
+//	q.CursorPaginator.NextCursor
+func (q *Query) PaginateByCursor(cursor string, limit int, orderColumns ...string) *Query {
+	if limit < 1 {
+		limit = PaginatorPerPageDefault
+	}
+	if len(orderColumns) == 0 {
+		orderColumns = []string{"id"}
+	}
+
+	p := &CursorPaginator{Cursor: cursor, Limit: limit}
+	for _, oc := range orderColumns {
+		fields := strings.Fields(oc)
+		col := cursorColumn{name: fields[0]}
+		if len(fields) > 1 {
+			col.desc = strings.EqualFold(fields[1], "desc")
+		}
+		p.columns = append(p.columns, col)
+		q.Order(oc)
+	}
+	q.CursorPaginator = p
+	q.limitResults = limit + 1
+
+	if cursor == "" {
+		return q
+	}
+	values, err := decodeCursor(cursor)
+	if err != nil {
+		q.err = err
+		return q
+	}
+	if len(values) != len(p.columns) {
+		q.err = fmt.Errorf("invalid cursor: %d values for %d columns", len(values), len(p.columns))
+		return q
+	}
+	where, args := cursorWhere(p.columns, values)
+	return q.Where(where, args...)
+}
+
+// cursorWhere returns the condition matching the records after the values
+// of the cursor: a > ? OR (a = ? AND b > ?) OR ...
+func cursorWhere(cols []cursorColumn, values []interface{}) (string, []interface{}) {
+	var ors []string
+	var args []interface{}
+	for i, col := range cols {
+		var ands []string
+		for j := 0; j < i; j++ {
+			ands = append(ands, cols[j].name+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if col.desc {
+			op = "<"
+		}
+		ands = append(ands, fmt.Sprintf("%s %s ?", col.name, op))
+		args = append(args, values[i])
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+	return "(" + strings.Join(ors, " OR ") + ")", args
+}
+
+// paginateCursor removes the extra record loaded to know whether there is
+// a next page and sets the cursor of that page.
+func (q *Query) paginateCursor(models interface{}) error {
+	p := q.CursorPaginator
+	if p == nil {
+		return nil
+	}
+	p.NextCursor = ""
+	v := reflect.Indirect(reflect.ValueOf(models))
+	if v.Kind() != reflect.Slice || v.Len() <= p.Limit {
+		return nil
+	}
+	v.Set(v.Slice(0, p.Limit))
+
+	last := reflect.Indirect(v.Index(p.Limit - 1))
+	values := make([]interface{}, len(p.columns))
+	for i, col := range p.columns {
+		name := col.name
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			name = name[i+1:]
+		}
+		f, ok := fieldByColumn(last, name)
+		if !ok {
+			return fmt.Errorf("cursor column %s is not a field of %s", col.name, last.Type())
+		}
+		values[i] = f.Interface()
+	}
+	cursor, err := encodeCursor(values)
+	if err != nil {
+		return err
+	}
+	p.NextCursor = cursor
+	return nil
+}
+
+// cursorValue is a typed value of an encoded cursor.
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v"`
+}
+
+// encodeCursor encodes the values of a cursor as an opaque string.
+func encodeCursor(values []interface{}) (string, error) {
+	cvs := make([]cursorValue, len(values))
+	for i, v := range values {
+		cv, err := newCursorValue(v)
+		if err != nil {
+			return "", err
+		}
+		cvs[i] = cv
+	}
+	b, err := json.Marshal(cvs)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func newCursorValue(v interface{}) (cursorValue, error) {
+	if valuer, ok := v.(driver.Valuer); ok {
+		dv, err := valuer.Value()
+		if err != nil {
+			return cursorValue{}, err
+		}
+		v = dv
+	}
+	switch x := v.(type) {
+	case nil:
+		return cursorValue{}, fmt.Errorf("invalid cursor: NULL values can not be paginated")
+	case time.Time:
+		return cursorValue{"t", x.Format(time.RFC3339Nano)}, nil
+	case []byte:
+		return cursorValue{"s", string(x)}, nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cursorValue{"i", strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cursorValue{"u", strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return cursorValue{"f", strconv.FormatFloat(rv.Float(), 'g', -1, 64)}, nil
+	case reflect.Bool:
+		return cursorValue{"b", strconv.FormatBool(rv.Bool())}, nil
+	case reflect.String:
+		return cursorValue{"s", rv.String()}, nil
+	}
+	return cursorValue{}, fmt.Errorf("invalid cursor: can not paginate values of type %T", v)
+}
+
+// decodeCursor decodes the values of an encoded cursor.
+func decodeCursor(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	var cvs []cursorValue
+	if err := json.Unmarshal(b, &cvs); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	values := make([]interface{}, len(cvs))
+	for i, cv := range cvs {
+		switch cv.Type {
+		case "t":
+			values[i], err = time.Parse(time.RFC3339Nano, cv.Value)
+		case "i":
+			values[i], err = strconv.ParseInt(cv.Value, 10, 64)
+		case "u":
+			values[i], err = strconv.ParseUint(cv.Value, 10, 64)
+		case "f":
+			values[i], err = strconv.ParseFloat(cv.Value, 64)
+		case "b":
+			values[i], err = strconv.ParseBool(cv.Value)
+		case "s":
+			values[i] = cv.Value
+		default:
+			err = fmt.Errorf("unknown value type %q", cv.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+	}
+	return values, nil
+}
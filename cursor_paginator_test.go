@@ -0,0 +1,74 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PaginateByCursor(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE feed_items (id INTEGER PRIMARY KEY, title TEXT NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)").Exec())
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 7; i++ {
+		item := &feedItem{Title: string(rune('a' + i))}
+		r.NoError(c.Create(item))
+		// pairs of items share their creation time
+		r.NoError(c.RawQuery("UPDATE feed_items SET created_at = ? WHERE id = ?", base.Add(time.Duration(i/2)*time.Hour), item.ID).Exec())
+	}
+
+	var ids []int
+	cursor, pages := "", 0
+	for {
+		items := []feedItem{}
+		q := c.Where("title <> ?", "").PaginateByCursor(cursor, 3, "created_at desc", "id desc")
+		r.NoError(q.All(&items))
+		r.True(len(items) <= 3)
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		pages++
+		cursor = q.CursorPaginator.NextCursor
+		if cursor == "" {
+			break
+		}
+	}
+	r.Equal(3, pages)
+	r.Equal([]int{7, 6, 5, 4, 3, 2, 1}, ids)
+
+	items := []feedItem{}
+	q := c.PaginateByCursor("", 10)
+	r.NoError(q.All(&items))
+	r.Len(items, 7)
+	r.Equal("", q.CursorPaginator.NextCursor)
+
+	r.Error(c.PaginateByCursor("not a cursor", 3).All(&items))
+	cursor, err := encodeCursor([]interface{}{1, 2})
+	r.NoError(err)
+	r.Error(c.PaginateByCursor(cursor, 3).All(&items))
+}
+
+func Test_PaginateByCursor_SQL(t *testing.T) {
+	r := require.New(t)
+
+	cursor, err := encodeCursor([]interface{}{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 42})
+	r.NoError(err)
+
+	c := &Connection{Dialect: &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	sql, args := Q(c).PaginateByCursor(cursor, 20, "created_at desc", "id").ToSQL(NewModel(&feedItem{}, nil))
+	r.True(strings.HasSuffix(sql, "WHERE ((created_at < ?) OR (created_at = ? AND id > ?)) ORDER BY created_at desc, id LIMIT 21"), sql)
+	r.Len(args, 3)
+	r.Equal(int64(42), args[2])
+
+	values, err := decodeCursor(cursor)
+	r.NoError(err)
+	r.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), values[0])
+}
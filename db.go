@@ -9,6 +9,8 @@ import (
 
 type dB struct {
 	*sqlx.DB
+	// stmts caches the prepared statements, if enabled.
+	stmts *stmtCache
 }
 
 func (db *dB) TransactionContext(ctx context.Context) (*Tx, error) {
@@ -30,3 +32,91 @@ func (db *dB) Rollback() error {
 func (db *dB) Commit() error {
 	return nil
 }
+
+func (db *dB) Select(dest interface{}, query string, args ...interface{}) error {
+	return db.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (db *dB) Get(dest interface{}, query string, args ...interface{}) error {
+	return db.GetContext(context.Background(), dest, query, args...)
+}
+
+func (db *dB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+func (db *dB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if !db.stmts.caches(query, args) {
+		return db.DB.SelectContext(ctx, dest, query, args...)
+	}
+	st, done, err := db.stmts.stmt(ctx, nil, query)
+	if err != nil {
+		return err
+	}
+	defer done()
+	return st.SelectContext(ctx, dest, args...)
+}
+
+func (db *dB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	if !db.stmts.caches(query, args) {
+		return db.DB.GetContext(ctx, dest, query, args...)
+	}
+	st, done, err := db.stmts.stmt(ctx, nil, query)
+	if err != nil {
+		return err
+	}
+	defer done()
+	return st.GetContext(ctx, dest, args...)
+}
+
+func (db *dB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if !db.stmts.caches(query, args) {
+		return db.DB.ExecContext(ctx, query, args...)
+	}
+	st, done, err := db.stmts.stmt(ctx, nil, query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return st.ExecContext(ctx, args...)
+}
+
+func (db *dB) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	if !db.stmts.caches(query, args) {
+		return db.DB.QueryxContext(ctx, query, args...)
+	}
+	st, done, err := db.stmts.stmt(ctx, nil, query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return st.QueryxContext(ctx, args...)
+}
+
+func (db *dB) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return db.NamedExecContext(context.Background(), query, arg)
+}
+
+func (db *dB) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return db.NamedQueryContext(context.Background(), query, arg)
+}
+
+// NamedExecContext runs the named statement, binding the serialized fields
+// of arg serialized.
+func (db *dB) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	return db.DB.NamedExecContext(ctx, query, serializedArg(db.Mapper, arg))
+}
+
+// NamedQueryContext runs the named query, binding the serialized fields of
+// arg serialized.
+func (db *dB) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	return db.DB.NamedQueryContext(ctx, query, serializedArg(db.Mapper, arg))
+}
+
+// Close closes the cached statements and the database.
+func (db *dB) Close() error {
+	if db.stmts != nil {
+		db.stmts.close()
+	}
+	return db.DB.Close()
+}
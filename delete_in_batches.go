@@ -0,0 +1,125 @@
+package pop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeleteInBatches deletes the rows of the table of the model matching the
+// query by batches, see Query.DeleteInBatches.
+func (c *Connection) DeleteInBatches(model interface{}, batchSize int, pause time.Duration, progress func(deleted int)) (int, error) {
+	return Q(c).DeleteInBatches(model, batchSize, pause, progress)
+}
+
+// DeleteInBatches deletes the rows of the table of the model matching the
+// query by batches of batchSize, in the order of their primary key, and
+// returns the number of deleted rows. Each batch is a DELETE of its own,
+// holding its locks and growing the replication lag for a short while
+// rather than for the whole purge, and DeleteInBatches sleeps for pause
+// between them. progress, if not nil, is called after each batch with the
+// number of rows deleted so far. The callbacks of the model are not run.
+//
+// The models with a DeletedAt field are soft deleted, by setting their
+// deleted_at, unless the query is Unscoped, see Connection.Unscoped. The
+// batches are capped by the MaxLimit of the model, unless the query is
+// Unlimited.
+//
+// The batches are pages of a keyset pagination on the primary key: the
+// query may not be ordered or paginated, and runs outside of a
+// transaction, which would hold the locks of all the batches.
+//
+//	n, err := c.Where("created_at < ?", cutoff).DeleteInBatches(&Event{}, 1000, 100*time.Millisecond, nil)
+func (q *Query) DeleteInBatches(model interface{}, batchSize int, pause time.Duration, progress func(deleted int)) (int, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+	if err := q.Connection.checkWritable(); err != nil {
+		return 0, err
+	}
+	if q.Connection.TX != nil {
+		return 0, errors.New("can not delete in batches in a transaction, it would hold the locks of all the batches")
+	}
+	if batchSize < 1 {
+		return 0, fmt.Errorf("invalid batch size %d", batchSize)
+	}
+	if len(q.orderClauses) > 0 || q.Paginator != nil || q.CursorPaginator != nil || q.limitResults > 0 {
+		return 0, errors.New("DeleteInBatches orders the rows by primary key: the query may not be ordered or paginated")
+	}
+	if q.RawSQL.Fragment != "" {
+		return 0, errors.New("DeleteInBatches does not support raw queries")
+	}
+
+	m := NewModel(model, q.Connection.Context())
+	if m.compositeKey() != nil {
+		return 0, errors.New("DeleteInBatches does not support composite primary keys")
+	}
+	if _, max := modelLimits(m); max > 0 && batchSize > max && !q.unlimited {
+		batchSize = max
+	}
+	key := fmt.Sprintf("%s.%s", m.Alias(), m.IDField())
+	table, id := q.Connection.Dialect.Quote(m.TableName()), q.Connection.Dialect.Quote(m.IDField())
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s IN ", table, id)
+	column := m.deletedAtColumn()
+	soft := column != "" && !q.unscoped
+	if soft {
+		column = q.Connection.Dialect.Quote(column)
+		stmt = fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s IS NULL AND %s IN ", table, column, column, id)
+	}
+
+	deleted := 0
+	var last interface{}
+	err := q.Connection.timeFunc("DeleteInBatches", model, func() error {
+		for {
+			bq := *q
+			bq.whereClauses = append(clauses{}, q.whereClauses...)
+			bq.Operation = Select
+			if last != nil {
+				bq.Where(key+" > ?", last)
+			}
+			bq.Order(key + " ASC")
+			bq.Limit(batchSize)
+
+			query, args := bq.ToSQL(m, key)
+			var ids []interface{}
+			if err := q.Connection.Store.SelectContext(q.Connection.Context(), &ids, query, args...); err != nil {
+				return err
+			}
+			if len(ids) == 0 {
+				return nil
+			}
+			for i, id := range ids {
+				if b, ok := id.([]byte); ok {
+					ids[i] = string(b)
+				}
+			}
+
+			placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+			values := ids
+			if soft {
+				values = append([]interface{}{nowFunc().Truncate(time.Microsecond)}, ids...)
+			}
+			n, err := q.Connection.RawQuery(stmt+"("+placeholders+")", values...).ExecWithCount()
+			if err != nil {
+				return err
+			}
+			deleted += n
+			if progress != nil {
+				progress(deleted)
+			}
+			if len(ids) < batchSize {
+				return nil
+			}
+			last = ids[len(ids)-1]
+			if pause > 0 {
+				select {
+				case <-time.After(pause):
+				case <-q.Connection.Context().Done():
+					return q.Connection.Context().Err()
+				}
+			}
+		}
+	})
+	return deleted, err
+}
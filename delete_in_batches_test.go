@@ -0,0 +1,102 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type purgedEvent struct {
+	ID   int    `db:"id"`
+	Kind string `db:"kind"`
+}
+
+func Test_DeleteInBatches(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE purged_events (id INTEGER PRIMARY KEY, kind TEXT)").Exec())
+	for i := 0; i < 7; i++ {
+		kind := "old"
+		if i%3 == 2 {
+			kind = "new"
+		}
+		r.NoError(c.Create(&purgedEvent{Kind: kind}))
+	}
+
+	var steps []int
+	n, err := c.Where("kind = ?", "old").DeleteInBatches(&purgedEvent{}, 2, 0, func(deleted int) {
+		steps = append(steps, deleted)
+	})
+	r.NoError(err)
+	r.Equal(5, n)
+	r.Equal([]int{2, 4, 5}, steps)
+
+	var left []purgedEvent
+	r.NoError(c.Order("id").All(&left))
+	r.Equal([]purgedEvent{{ID: 3, Kind: "new"}, {ID: 6, Kind: "new"}}, left)
+
+	n, err = c.Where("kind = ?", "gone").DeleteInBatches(&purgedEvent{}, 2, 0, nil)
+	r.NoError(err)
+	r.Equal(0, n)
+
+	_, err = c.Order("id").DeleteInBatches(&purgedEvent{}, 2, 0, nil)
+	r.Error(err)
+	_, err = c.Q().DeleteInBatches(&purgedEvent{}, 0, 0, nil)
+	r.Error(err)
+	r.NoError(c.Transaction(func(tx *Connection) error {
+		_, err := tx.Q().DeleteInBatches(&purgedEvent{}, 2, 0, nil)
+		r.Error(err)
+		return nil
+	}))
+}
+
+type purgedNote struct {
+	ID        int        `db:"id"`
+	Kind      string     `db:"kind"`
+	DeletedAt nulls.Time `db:"deleted_at"`
+}
+
+func (purgedNote) MaxLimit() int {
+	return 2
+}
+
+func Test_DeleteInBatches_SoftDelete(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE purged_notes (id INTEGER PRIMARY KEY, kind TEXT, deleted_at DATETIME)").Exec())
+	for _, kind := range []string{"old", "old", "new", "old", "old"} {
+		r.NoError(c.Create(&purgedNote{Kind: kind}))
+	}
+	r.NoError(c.RawQuery("UPDATE purged_notes SET deleted_at = CURRENT_TIMESTAMP WHERE id = 1").Exec())
+
+	// the batches are capped by the MaxLimit of the model
+	var steps []int
+	n, err := c.Where("kind = ? OR kind = ?", "old", "gone").DeleteInBatches(&purgedNote{}, 10, 0, func(deleted int) {
+		steps = append(steps, deleted)
+	})
+	r.NoError(err)
+	r.Equal(3, n)
+	r.Equal([]int{2, 3}, steps)
+
+	var left []purgedNote
+	r.NoError(c.All(&left))
+	r.Len(left, 1)
+	r.Equal("new", left[0].Kind)
+
+	count, err := c.Unscoped().Count(&purgedNote{})
+	r.NoError(err)
+	r.Equal(5, count)
+
+	n, err = c.Unscoped().Where("kind = ?", "old").DeleteInBatches(&purgedNote{}, 10, 0, nil)
+	r.NoError(err)
+	r.Equal(4, n)
+	count, err = c.Unscoped().Count(&purgedNote{})
+	r.NoError(err)
+	r.Equal(1, count)
+}
@@ -1,10 +1,15 @@
 package pop
 
 import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/WilliamNHarvey/pop/v6/columns"
 	"github.com/gobuffalo/fizz"
+	"github.com/jmoiron/sqlx/reflectx"
 )
 
 type crudable interface {
@@ -15,6 +20,11 @@ type crudable interface {
 	UpdateQuery(*Connection, *Model, columns.Columns, Query) (int64, error)
 	Destroy(*Connection, *Model) error
 	Delete(*Connection, *Model, Query) error
+
+	// updateQuerySQL and deleteSQL render the statements run by
+	// UpdateQuery and Delete.
+	updateQuerySQL(*Model, columns.Columns, Query) (string, []interface{}, error)
+	deleteSQL(*Model, Query) (string, []interface{})
 }
 
 type fizzable interface {
@@ -41,8 +51,134 @@ type dialect interface {
 	LoadSchema(io.Reader) error
 	Lock(func() error) error
 	TruncateAll(*Connection) error
+	Capabilities() capabilities
+}
+
+// capabilities are the features of SQL which not all the databases
+// support. The code relying on one checks it with supports first, and
+// reports an unsupported error rather than running SQL the database
+// rejects.
+type capabilities uint8
+
+const (
+	// supportsReturning: INSERT, UPDATE and DELETE statements return the
+	// rows they write with a RETURNING clause. MariaDB and SQLite detect it
+	// from the version of the server, and MariaDB only returns the rows of
+	// INSERT and DELETE statements.
+	supportsReturning capabilities = 1 << iota
+	// supportsSavepoints: transactions are partially rolled back to
+	// savepoints.
+	supportsSavepoints
+	// supportsSkipLocked: SELECT ... FOR UPDATE skips the locked rows with
+	// SKIP LOCKED.
+	supportsSkipLocked
+	// transactionalDDL: the schema is changed inside transactions, and
+	// the changes are rolled back with them.
+	transactionalDDL
+	// booleanType: booleans are of a BOOLEAN type compared to TRUE and
+	// FALSE, rather than integers compared to 1 and 0.
+	booleanType
+	// supportsILike: strings are matched case-insensitively with ILIKE.
+	supportsILike
+	// supportsRowLocks: SELECT ... FOR UPDATE locks the rows it reads.
+	supportsRowLocks
+	// ddlInTransactions: the schema may be changed inside transactions,
+	// which MySQL and Oracle allow but commit implicitly: the changes are
+	// not rolled back, see transactionalDDL.
+	ddlInTransactions
+)
+
+// ErrUnsupported is returned, wrapped, when a feature is not supported on
+// the database of the connection.
+var ErrUnsupported = errors.New("unsupported")
+
+// supports reports whether the dialect supports all the capabilities c.
+func supports(d dialect, c capabilities) bool {
+	return d.Capabilities()&c == c
+}
+
+// errUnsupported returns the error reporting that the feature is not
+// supported on the database of the dialect.
+func errUnsupported(d dialect, feature string) error {
+	return fmt.Errorf("%s: %w on %s", feature, ErrUnsupported, d.Name())
 }
 
 type afterOpenable interface {
 	AfterOpen(*Connection) error
 }
+
+// mapperable is implemented by the dialects matching the struct fields
+// and the columns differently than sqlx does by default.
+type mapperable interface {
+	Mapper() *reflectx.Mapper
+}
+
+// connectorable is implemented by the dialects opening some of their
+// connections from a driver.Connector rather than a DSN. Connector returns
+// nil when the DSN is to be used.
+type connectorable interface {
+	Connector() (driver.Connector, error)
+}
+
+// lockDiagnosable is implemented by the dialects reporting the locks held
+// and awaited when a transaction fails on a deadlock or a lock wait
+// timeout, see ConnectionDetails.DiagnoseLocks.
+type lockDiagnosable interface {
+	// LockConflict reports whether err is a deadlock or a lock wait
+	// timeout.
+	LockConflict(err error) bool
+	// DiagnoseLocks returns the report of the locks of the database, read
+	// from s, the conflict being err.
+	DiagnoseLocks(s store, err error) (string, error)
+}
+
+// batchable is implemented by the dialects sending the statements of a
+// batch in a single round trip, see Connection.Batch.
+type batchable interface {
+	// ExecBatch runs the statements atomically, in the transaction of c
+	// if any. It returns errBatchFallback when they have to be run one at
+	// a time.
+	ExecBatch(c *Connection, stmts []batchStatement) ([]BatchResult, error)
+}
+
+// upsertable is implemented by the dialects inserting the rows or updating
+// the conflicting ones in a single statement, see Connection.Upsert.
+type upsertable interface {
+	// Upsert inserts the columns of the model, updating the update columns
+	// of the row conflicting with it on the conflict ones. It reports false
+	// when the conflicting row was updated, or left as it is, and its
+	// columns were not read back into the model.
+	Upsert(c *Connection, model *Model, cols *columns.WriteableColumns, conflict, update []string) (bool, error)
+}
+
+// columnIntrospectable is implemented by the dialects reading the columns
+// of the tables, see tableColumns, from their catalog rather than from the
+// result of a query.
+type columnIntrospectable interface {
+	TableColumns(ctx context.Context, s store, table string) (map[string]tableColumn, error)
+}
+
+// columnDefaultable is implemented by the dialects reading the defaults of
+// the columns from their catalog, see Connection.ColumnDefaults.
+type columnDefaultable interface {
+	ColumnDefaults(ctx context.Context, s store, table string) (map[string]*string, error)
+}
+
+// schemaIntrospectable is implemented by the dialects reading the tables,
+// columns, foreign keys and indexes from their catalog, see
+// Connection.Schema.
+type schemaIntrospectable interface {
+	Schema(ctx context.Context, s store) ([]SchemaTable, error)
+}
+
+// indexUsageReadable is implemented by the dialects reading the usage of
+// the indexes from their statistics, see Connection.IndexUsage.
+type indexUsageReadable interface {
+	IndexUsage(ctx context.Context, s store) ([]IndexUsage, error)
+}
+
+// scriptable is implemented by the dialects whose drivers run a single
+// statement at a time.
+type scriptable interface {
+	SplitScript(script string) []string
+}
@@ -2,6 +2,7 @@ package pop
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"fmt"
 	"io"
@@ -64,10 +65,30 @@ func (p *cockroach) DefaultDriver() string {
 	return "pgx"
 }
 
+func (p *cockroach) Capabilities() capabilities {
+	return supportsReturning | supportsSavepoints | supportsRowLocks | supportsSkipLocked | transactionalDDL | ddlInTransactions | booleanType | supportsILike
+}
+
 func (p *cockroach) Details() *ConnectionDetails {
 	return p.ConnectionDetails
 }
 
+// ExecBatch sends the statements in a pgx batch, run by the server in an
+// implicit transaction.
+func (p *cockroach) ExecBatch(c *Connection, stmts []batchStatement) ([]BatchResult, error) {
+	return execPgxBatch(c, stmts)
+}
+
+// ColumnDefaults reads the defaults of the columns from information_schema.
+func (p *cockroach) ColumnDefaults(ctx context.Context, s store, table string) (map[string]*string, error) {
+	return pgColumnDefaults(ctx, s, table)
+}
+
+// Schema reads the tables of the current schema from pg_catalog.
+func (p *cockroach) Schema(ctx context.Context, s store) ([]SchemaTable, error) {
+	return pgSchema(ctx, s)
+}
+
 func (p *cockroach) Create(c *Connection, model *Model, cols columns.Columns) error {
 	keyType, err := model.PrimaryKeyType()
 	if err != nil {
@@ -83,6 +104,10 @@ func (p *cockroach) Create(c *Connection, model *Model, cols columns.Columns) er
 		} else {
 			query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", p.Quote(model.TableName()), model.IDField())
 		}
+		query, err = beforeNamedExec(Insert, model, query)
+		if err != nil {
+			return err
+		}
 		txlog(logging.SQL, c, query, model.Value)
 		rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
 		if err != nil {
@@ -120,6 +145,10 @@ func (p *cockroach) Create(c *Connection, model *Model, cols columns.Columns) er
 			w.Add(model.IDField())
 			query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", p.Quote(model.TableName()), w.QuotedString(p), w.SymbolizedString(), model.IDField())
 		}
+		query, err = beforeNamedExec(Insert, model, query)
+		if err != nil {
+			return err
+		}
 		txlog(logging.SQL, c, query, model.Value)
 		rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
 		if err != nil {
@@ -150,12 +179,24 @@ func (p *cockroach) Update(c *Connection, model *Model, cols columns.Columns) er
 }
 
 func (p *cockroach) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
-	return genericUpdateQuery(c, model, cols, p, query, sqlx.DOLLAR)
+	return genericUpdateQuery(c, model, cols, query)
+}
+
+func (p *cockroach) updateQuerySQL(model *Model, cols columns.Columns, query Query) (string, []interface{}, error) {
+	return genericUpdateQuerySQL(model, cols, p, query, sqlx.DOLLAR)
+}
+
+func (p *cockroach) Upsert(c *Connection, model *Model, cols *columns.WriteableColumns, conflict, update []string) (bool, error) {
+	read, err := genericUpsert(c, model, cols, conflict, update, p)
+	if err != nil {
+		return false, fmt.Errorf("cockroach upsert: %w", err)
+	}
+	return read, nil
 }
 
 func (p *cockroach) Destroy(c *Connection, model *Model) error {
 	stmt := p.TranslateSQL(fmt.Sprintf("DELETE FROM %s AS %s WHERE %s", p.Quote(model.TableName()), model.Alias(), model.WhereID()))
-	_, err := genericExec(c, stmt, model.ID())
+	_, err := genericExec(c, Delete, model, stmt, model.idArgs()...)
 	return err
 }
 
@@ -231,6 +272,20 @@ func (p *cockroach) MigrationURL() string {
 	return p.URL()
 }
 
+func (p *cockroach) rowChecksumSQL(cols []string) string {
+	return fmt.Sprintf("md5(CAST(ROW(%s) AS TEXT))", strings.Join(cols, ", "))
+}
+
+// StrictSettings returns standard_conforming_strings, always on on
+// CockroachDB, for parity with PostgreSQL.
+func (p *cockroach) StrictSettings() map[string]string {
+	return map[string]string{"standard_conforming_strings": "on"}
+}
+
+func (p *cockroach) SessionStatement(name, value string) string {
+	return fmt.Sprintf("SET %s = %s", name, value)
+}
+
 func (p *cockroach) TranslateSQL(sql string) string {
 	defer p.mu.Unlock()
 	p.mu.Lock()
@@ -23,6 +23,9 @@ func init() {
 
 type commonDialect struct {
 	ConnectionDetails *ConnectionDetails
+	// version is the version of the server, read by the AfterOpen of the
+	// dialects whose capabilities depend on it.
+	version string
 }
 
 func (commonDialect) Lock(fn func() error) error {
@@ -43,6 +46,17 @@ func (commonDialect) Quote(key string) string {
 }
 
 func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quotable) error {
+	return createModel(c, model, cols, quoter, false)
+}
+
+// returningCreate inserts the model with INSERT ... RETURNING, reading back
+// all its columns in a single round trip: the generated ID, and the
+// defaults, timestamps and values set by triggers.
+func returningCreate(c *Connection, model *Model, cols columns.Columns, quoter quotable) error {
+	return createModel(c, model, cols, quoter, true)
+}
+
+func createModel(c *Connection, model *Model, cols columns.Columns, quoter quotable, returning bool) error {
 	keyType, err := model.PrimaryKeyType()
 	if err != nil {
 		return err
@@ -55,7 +69,17 @@ func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quo
 		}
 		w := cols.Writeable()
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoter.Quote(model.TableName()), w.QuotedString(quoter), w.SymbolizedString())
+		if returning {
+			query += " RETURNING " + returnedColumns(c, model, quoter)
+		}
+		query, err = beforeNamedExec(Insert, model, query)
+		if err != nil {
+			return err
+		}
 		txlog(logging.SQL, c, query, model.Value)
+		if returning {
+			return namedQueryInto(c, model, query)
+		}
 		res, err := c.Store.NamedExecContext(model.ctx, query, model.Value)
 		if err != nil {
 			return err
@@ -71,7 +95,7 @@ func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quo
 			}
 		}
 		return nil
-	case "UUID", "string":
+	case "UUID", "string", compositeKeyType:
 		if keyType == "UUID" {
 			if model.ID() == emptyUUID {
 				u, err := uuid.NewV4()
@@ -80,13 +104,25 @@ func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quo
 				}
 				model.setID(u)
 			}
-		} else if model.ID() == "" {
+		} else if keyType == "string" && model.ID() == "" {
 			return fmt.Errorf("missing ID value")
 		}
 		w := cols.Writeable()
-		w.Add(model.IDField())
+		if keyType != compositeKeyType {
+			w.Add(model.IDField())
+		}
 		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", quoter.Quote(model.TableName()), w.QuotedString(quoter), w.SymbolizedString())
+		if returning {
+			query += " RETURNING " + returnedColumns(c, model, quoter)
+		}
+		query, err = beforeNamedExec(Insert, model, query)
+		if err != nil {
+			return err
+		}
 		txlog(logging.SQL, c, query, model.Value)
+		if returning {
+			return namedQueryInto(c, model, query)
+		}
 		if _, err := c.Store.NamedExecContext(model.ctx, query, model.Value); err != nil {
 			return fmt.Errorf("named insert: %w", err)
 		}
@@ -96,29 +132,26 @@ func genericCreate(c *Connection, model *Model, cols columns.Columns, quoter quo
 }
 
 func genericUpdate(c *Connection, model *Model, cols columns.Columns, quoter quotable) error {
-	stmt := fmt.Sprintf("UPDATE %s AS %s SET %s WHERE %s", quoter.Quote(model.TableName()), model.Alias(), cols.Writeable().QuotedUpdateString(quoter), model.WhereNamedID())
+	stmt := fmt.Sprintf("UPDATE %s AS %s SET %s WHERE %s", quoter.Quote(model.TableName()), model.Alias(), cols.Writeable().QuotedUpdateString(quoter), model.whereNamedUpdate(cols))
+	stmt, err := beforeNamedExec(Update, model, stmt)
+	if err != nil {
+		return err
+	}
 	txlog(logging.SQL, c, stmt, model.ID())
-	_, err := c.Store.NamedExecContext(model.ctx, stmt, model.Value)
+	res, err := c.Store.NamedExecContext(model.ctx, stmt, model.Value)
 	if err != nil {
 		return err
 	}
-	return nil
+	return checkStale(model, cols, res)
 }
 
-func genericUpdateQuery(c *Connection, model *Model, cols columns.Columns, quoter quotable, query Query, bindType int) (int64, error) {
-	q := fmt.Sprintf("UPDATE %s AS %s SET %s", quoter.Quote(model.TableName()), model.Alias(), cols.Writeable().QuotedUpdateString(quoter))
-
-	q, updateArgs, err := sqlx.Named(q, model.Value)
+func genericUpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
+	q, args, err := c.Dialect.updateQuerySQL(model, cols, query)
 	if err != nil {
 		return 0, err
 	}
 
-	sb := query.toSQLBuilder(model)
-	q = sb.buildWhereClauses(q)
-
-	q = sqlx.Rebind(bindType, q)
-
-	result, err := genericExec(c, q, append(updateArgs, sb.args...)...)
+	result, err := genericExec(c, Update, model, q, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -131,22 +164,44 @@ func genericUpdateQuery(c *Connection, model *Model, cols columns.Columns, quote
 	return n, err
 }
 
+func genericUpdateQuerySQL(model *Model, cols columns.Columns, quoter quotable, query Query, bindType int) (string, []interface{}, error) {
+	q := fmt.Sprintf("UPDATE %s AS %s SET %s", quoter.Quote(model.TableName()), model.Alias(), cols.Writeable().QuotedUpdateString(quoter))
+
+	q, updateArgs, err := sqlx.Named(q, model.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sb := query.toSQLBuilder(model)
+	q = sb.buildWhereClauses(q)
+
+	return sqlx.Rebind(bindType, q), append(updateArgs, sb.args...), nil
+}
+
 func genericDestroy(c *Connection, model *Model, quoter quotable) error {
 	stmt := fmt.Sprintf("DELETE FROM %s AS %s WHERE %s", quoter.Quote(model.TableName()), model.Alias(), model.WhereID())
-	_, err := genericExec(c, stmt, model.ID())
+	_, err := genericExec(c, Delete, model, stmt, model.idArgs()...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func (commonDialect) deleteSQL(model *Model, query Query) (string, []interface{}) {
+	return query.ToSQL(model)
+}
+
 func genericDelete(c *Connection, model *Model, query Query) error {
-	sqlQuery, args := query.ToSQL(model)
-	_, err := genericExec(c, sqlQuery, args...)
+	sqlQuery, args := c.Dialect.deleteSQL(model, query)
+	_, err := genericExec(c, Delete, model, sqlQuery, args...)
 	return err
 }
 
-func genericExec(c *Connection, stmt string, args ...interface{}) (sql.Result, error) {
+func genericExec(c *Connection, op operation, model *Model, stmt string, args ...interface{}) (sql.Result, error) {
+	stmt, args, err := beforeExec(op, model, stmt, args...)
+	if err != nil {
+		return nil, err
+	}
 	txlog(logging.SQL, c, stmt, args...)
 	res, err := c.Store.ExecContext(c.Context(), stmt, args...)
 	return res, err
@@ -155,7 +210,7 @@ func genericExec(c *Connection, stmt string, args ...interface{}) (sql.Result, e
 func genericSelectOne(c *Connection, model *Model, query Query) error {
 	sqlQuery, args := query.ToSQL(model)
 	txlog(logging.SQL, query.Connection, sqlQuery, args...)
-	err := c.Store.GetContext(model.ctx, model.Value, sqlQuery, args...)
+	err := selectOne(model.ctx, c, model.Value, sqlQuery, args...)
 	if err != nil {
 		return err
 	}
@@ -165,7 +220,7 @@ func genericSelectOne(c *Connection, model *Model, query Query) error {
 func genericSelectMany(c *Connection, models *Model, query Query) error {
 	sqlQuery, args := query.ToSQL(models)
 	txlog(logging.SQL, query.Connection, sqlQuery, args...)
-	err := c.Store.SelectContext(models.ctx, models.Value, sqlQuery, args...)
+	err := selectMany(models.ctx, c, models.Value, sqlQuery, args...)
 	if err != nil {
 		return err
 	}
@@ -203,7 +258,7 @@ func genericLoadSchema(d dialect, r io.Reader) error {
 }
 
 func genericDumpSchema(deets *ConnectionDetails, cmd *exec.Cmd, w io.Writer) error {
-	log(logging.SQL, strings.Join(cmd.Args, " "))
+	log(logging.SQL, maskSecrets(strings.Join(cmd.Args, " ")))
 
 	bb := &bytes.Buffer{}
 	mw := io.MultiWriter(w, bb)
@@ -0,0 +1,329 @@
+package pop
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/gobuffalo/fizz"
+	"github.com/gobuffalo/fizz/translators"
+	"github.com/jmoiron/sqlx"
+)
+
+const nameDuckDB = "duckdb"
+
+func init() {
+	AvailableDialects = append(AvailableDialects, nameDuckDB)
+	urlParser[nameDuckDB] = urlParserDuckDB
+	newConnection[nameDuckDB] = newDuckDB
+	sqlx.BindDriver(nameDuckDB, sqlx.QUESTION)
+}
+
+var _ dialect = &duckdb{}
+
+// duckdb is a dialect for DuckDB databases, mainly meant to query
+// analytical data with pop models. The driver is not bundled with pop,
+// it must be registered by importing it:
+//
+//	import _ "github.com/marcboeker/go-duckdb"
+type duckdb struct {
+	commonDialect
+}
+
+func requireDuckDB() error {
+	for _, driverName := range sql.Drivers() {
+		if driverName == nameDuckDB {
+			return nil
+		}
+	}
+	return errors.New("duckdb driver is not registered, import github.com/marcboeker/go-duckdb")
+}
+
+func (d *duckdb) Name() string {
+	return nameDuckDB
+}
+
+func (d *duckdb) DefaultDriver() string {
+	return nameDuckDB
+}
+
+func (d *duckdb) Capabilities() capabilities {
+	return supportsReturning | transactionalDDL | ddlInTransactions | booleanType | supportsILike
+}
+
+func (d *duckdb) Details() *ConnectionDetails {
+	return d.ConnectionDetails
+}
+
+func (d *duckdb) URL() string {
+	c := d.ConnectionDetails
+	if o := c.OptionsString(""); o != "" {
+		return c.Database + "?" + o
+	}
+	return c.Database
+}
+
+func (d *duckdb) MigrationURL() string {
+	return d.URL()
+}
+
+func (d *duckdb) Create(c *Connection, model *Model, cols columns.Columns) error {
+	keyType, err := model.PrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	switch keyType {
+	case "int", "int64":
+		if model.UsingAutoIncrement() {
+			cols.Remove(model.IDField())
+		}
+		w := cols.Writeable()
+		var query string
+		if len(w.Cols) > 0 {
+			query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", d.Quote(model.TableName()), w.QuotedString(d), w.SymbolizedString(), model.IDField())
+		} else {
+			query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", d.Quote(model.TableName()), model.IDField())
+		}
+		query, err = beforeNamedExec(Insert, model, query)
+		if err != nil {
+			return err
+		}
+		txlog(logging.SQL, c, query, model.Value)
+		rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
+		if err != nil {
+			return fmt.Errorf("duckdb create: %w", err)
+		}
+		defer rows.Close()
+		if !rows.Next() {
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("duckdb create: next: %w", err)
+			}
+			return fmt.Errorf("duckdb create: %w", sql.ErrNoRows)
+		}
+		var id interface{}
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("duckdb create: scan: %w", err)
+		}
+		if err := rows.Close(); err != nil {
+			return fmt.Errorf("duckdb create: close: %w", err)
+		}
+		model.setID(id)
+		return nil
+	}
+	if err := genericCreate(c, model, cols, d); err != nil {
+		return fmt.Errorf("duckdb create: %w", err)
+	}
+	return nil
+}
+
+func (d *duckdb) Update(c *Connection, model *Model, cols columns.Columns) error {
+	if err := genericUpdate(c, model, cols, d); err != nil {
+		return fmt.Errorf("duckdb update: %w", err)
+	}
+	return nil
+}
+
+func (d *duckdb) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
+	n, err := genericUpdateQuery(c, model, cols, query)
+	if err != nil {
+		return n, fmt.Errorf("duckdb update query: %w", err)
+	}
+	return n, nil
+}
+
+func (d *duckdb) updateQuerySQL(model *Model, cols columns.Columns, query Query) (string, []interface{}, error) {
+	return genericUpdateQuerySQL(model, cols, d, query, sqlx.QUESTION)
+}
+
+func (d *duckdb) Upsert(c *Connection, model *Model, cols *columns.WriteableColumns, conflict, update []string) (bool, error) {
+	read, err := genericUpsert(c, model, cols, conflict, update, d)
+	if err != nil {
+		return false, fmt.Errorf("duckdb upsert: %w", err)
+	}
+	return read, nil
+}
+
+func (d *duckdb) Destroy(c *Connection, model *Model) error {
+	if err := genericDestroy(c, model, d); err != nil {
+		return fmt.Errorf("duckdb destroy: %w", err)
+	}
+	return nil
+}
+
+func (d *duckdb) Delete(c *Connection, model *Model, query Query) error {
+	return genericDelete(c, model, query)
+}
+
+func (d *duckdb) SelectOne(c *Connection, model *Model, query Query) error {
+	if err := genericSelectOne(c, model, query); err != nil {
+		return fmt.Errorf("duckdb select one: %w", err)
+	}
+	return nil
+}
+
+func (d *duckdb) SelectMany(c *Connection, models *Model, query Query) error {
+	if err := genericSelectMany(c, models, query); err != nil {
+		return fmt.Errorf("duckdb select many: %w", err)
+	}
+	return nil
+}
+
+// CreateDB makes sure the directory of the database exists, DuckDB
+// creates the database file itself when it is first opened.
+func (d *duckdb) CreateDB() error {
+	durl := d.ConnectionDetails.Database
+	if durl == "" || strings.Contains(durl, ":memory:") {
+		log(logging.Info, "in memory db selected, no database file created.")
+		return nil
+	}
+
+	if _, err := os.Stat(durl); err == nil {
+		return fmt.Errorf("could not create DuckDB database '%s'; database exists", durl)
+	}
+	if err := os.MkdirAll(filepath.Dir(durl), 0766); err != nil {
+		return fmt.Errorf("could not create DuckDB database '%s': %w", durl, err)
+	}
+
+	log(logging.Info, "created database '%s'", durl)
+	return nil
+}
+
+func (d *duckdb) DropDB() error {
+	durl := d.ConnectionDetails.Database
+	if err := os.Remove(durl); err != nil {
+		return fmt.Errorf("could not drop DuckDB database %s: %w", durl, err)
+	}
+	// the write-ahead log is left behind when the database was not closed
+	_ = os.Remove(durl + ".wal")
+	log(logging.Info, "dropped database '%s'", durl)
+	return nil
+}
+
+func (d *duckdb) TranslateSQL(sql string) string {
+	return sql
+}
+
+func (d *duckdb) FizzTranslator() fizz.Translator {
+	return &duckdbTranslator{Postgres: translators.NewPostgres()}
+}
+
+func (d *duckdb) DumpSchema(w io.Writer) error {
+	cmd := exec.Command("duckdb", d.Details().Database, ".schema")
+	return genericDumpSchema(d.Details(), cmd, w)
+}
+
+func (d *duckdb) LoadSchema(r io.Reader) error {
+	return genericLoadSchema(d, r)
+}
+
+func (d *duckdb) TruncateAll(tx *Connection) error {
+	const tableNames = `SELECT table_schema || '.' || table_name AS name FROM information_schema.tables WHERE table_type = 'BASE TABLE' AND table_catalog = current_database()`
+	names := []struct {
+		Name string `db:"name"`
+	}{}
+
+	err := tx.RawQuery(tableNames).All(&names)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	stmts := []string{}
+	for _, n := range names {
+		stmts = append(stmts, fmt.Sprintf("DELETE FROM %s", d.Quote(n.Name)))
+	}
+	return tx.RawQuery(strings.Join(stmts, "; ")).Exec()
+}
+
+func newDuckDB(deets *ConnectionDetails) (dialect, error) {
+	if err := requireDuckDB(); err != nil {
+		return nil, err
+	}
+	deets.URL = fmt.Sprintf("duckdb://%s", deets.Database)
+	cd := &duckdb{
+		commonDialect: commonDialect{ConnectionDetails: deets},
+	}
+	return cd, nil
+}
+
+func urlParserDuckDB(cd *ConnectionDetails) error {
+	db := strings.TrimPrefix(cd.URL, "duckdb://")
+
+	dbparts := strings.Split(db, "?")
+	cd.Database = dbparts[0]
+
+	if len(dbparts) != 2 {
+		return nil
+	}
+
+	q, err := url.ParseQuery(dbparts[1])
+	if err != nil {
+		return fmt.Errorf("unable to parse duckdb query: %w", err)
+	}
+
+	for k := range q {
+		cd.setOption(k, q.Get(k))
+	}
+
+	return nil
+}
+
+func newDuckDBDriver() (driver.Driver, error) {
+	if err := requireDuckDB(); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(nameDuckDB, "")
+	if err != nil {
+		return nil, err
+	}
+	return db.Driver(), db.Close()
+}
+
+// duckdbTranslator translates migrations to DuckDB, which mostly follows
+// the PostgreSQL syntax but has no serial types: auto-incremented primary
+// keys use a sequence instead.
+type duckdbTranslator struct {
+	*translators.Postgres
+}
+
+func (duckdbTranslator) Name() string {
+	return nameDuckDB
+}
+
+func (t *duckdbTranslator) CreateTable(table fizz.Table) (string, error) {
+	s, err := t.Postgres.CreateTable(table)
+	if err != nil {
+		return "", err
+	}
+
+	var seqs []string
+	for _, c := range table.Columns {
+		if !c.Primary {
+			continue
+		}
+		var serial, colType string
+		switch c.ColType {
+		case "integer", "INT", "int":
+			serial, colType = "SERIAL", "INTEGER"
+		case "bigint", "BIGINT":
+			serial, colType = "BIGSERIAL", "BIGINT"
+		default:
+			continue
+		}
+		seq := fmt.Sprintf("%s_%s_seq", table.Name, c.Name)
+		seqs = append(seqs, fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS \"%s\";", seq))
+		s = strings.Replace(s, fmt.Sprintf("\"%s\" %s", c.Name, serial), fmt.Sprintf("\"%s\" %s DEFAULT nextval('%s')", c.Name, colType, seq), 1)
+	}
+
+	return strings.Join(append(seqs, s), "\n"), nil
+}
@@ -0,0 +1,54 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConnectionDetails_Finalize_DuckDB_URL(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		URL: "duckdb:///tmp/analytics.duckdb?access_mode=READ_ONLY",
+	}
+	err := cd.Finalize()
+	r.NoError(err)
+	r.Equal("duckdb", cd.Dialect)
+	r.Equal("/tmp/analytics.duckdb", cd.Database)
+	r.Equal(map[string]string{"access_mode": "READ_ONLY"}, cd.Options)
+
+	d := &duckdb{commonDialect{ConnectionDetails: cd}}
+	r.Equal("/tmp/analytics.duckdb?access_mode=READ_ONLY", d.URL())
+
+	cd = &ConnectionDetails{Dialect: "duckdb", Database: "analytics.duckdb"}
+	r.NoError(cd.Finalize())
+	d = &duckdb{commonDialect{ConnectionDetails: cd}}
+	r.Equal("analytics.duckdb", d.URL())
+}
+
+func Test_DuckDB_Driver_Required(t *testing.T) {
+	r := require.New(t)
+
+	_, err := newDuckDB(&ConnectionDetails{Dialect: "duckdb", Database: "analytics.duckdb"})
+	r.Error(err)
+}
+
+func Test_DuckDB_FizzTranslator(t *testing.T) {
+	r := require.New(t)
+	d := &duckdb{}
+
+	s, err := fizz.AString(`create_table("events") {
+	t.Column("id", "integer", {primary: true})
+	t.Column("name", "string")
+	t.DisableTimestamps()
+}`, d.FizzTranslator())
+	r.NoError(err)
+	r.Equal(`CREATE SEQUENCE IF NOT EXISTS "events_id_seq";
+CREATE TABLE "events" (
+"id" INTEGER DEFAULT nextval('events_id_seq') NOT NULL,
+PRIMARY KEY("id"),
+"name" VARCHAR (255) NOT NULL
+);`, s)
+}
@@ -0,0 +1,234 @@
+package pop
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/gobuffalo/fizz"
+	"github.com/gobuffalo/fizz/translators"
+	"github.com/jmoiron/sqlx"
+)
+
+const nameLibSQL = "libsql"
+
+func init() {
+	dialectSynonyms[nameLibSQL] = nameSQLite3
+	sqlx.BindDriver(nameLibSQL, sqlx.QUESTION)
+}
+
+// LibSQLEmbeddedReplica opens the embedded replicas of libSQL databases,
+// the sqlite3 connections with a sync_url option. The CGo go-libsql driver
+// is not bundled with pop, set it to its connector:
+//
+//	pop.LibSQLEmbeddedReplica = func(path, primaryURL, authToken string, syncInterval time.Duration) (driver.Connector, error) {
+//		return libsql.NewEmbeddedReplicaConnector(path, primaryURL,
+//			libsql.WithAuthToken(authToken), libsql.WithSyncInterval(syncInterval))
+//	}
+var LibSQLEmbeddedReplica func(path, primaryURL, authToken string, syncInterval time.Duration) (driver.Connector, error)
+
+// libsqlOptions are the options of the embedded replicas, handed to
+// LibSQLEmbeddedReplica rather than to the driver.
+var libsqlOptions = map[string]bool{
+	"sync_url":      true,
+	"sync_interval": true,
+}
+
+// libsqlRemote reports whether the database is a remote libSQL database,
+// e.g. a Turso one, rather than a file.
+func libsqlRemote(database string) bool {
+	for _, scheme := range []string{"libsql://", "wss://", "ws://", "https://", "http://"} {
+		if strings.HasPrefix(database, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLibSQL reports whether the connection is made with the libsql driver:
+// to a remote database, or to an embedded replica of one.
+func isLibSQL(cd *ConnectionDetails) bool {
+	return libsqlRemote(cd.Database) || cd.option("sync_url") != ""
+}
+
+func requireLibSQL() error {
+	for _, driverName := range sql.Drivers() {
+		if driverName == nameLibSQL {
+			return nil
+		}
+	}
+	return errors.New("libsql driver is not registered, import github.com/tursodatabase/libsql-client-go/libsql or github.com/tursodatabase/go-libsql")
+}
+
+// libsqlURL returns the DSN of the libsql driver: the database followed
+// by the options, but the ones of the embedded replicas.
+func libsqlURL(cd *ConnectionDetails) string {
+	var opts []string
+	for _, opt := range strings.Split(cd.OptionsString(""), "&") {
+		if k := strings.SplitN(opt, "=", 2)[0]; k != "" && !libsqlOptions[k] {
+			opts = append(opts, opt)
+		}
+	}
+	if len(opts) == 0 {
+		return cd.Database
+	}
+	return cd.Database + "?" + strings.Join(opts, "&")
+}
+
+// Connector returns the connector of the embedded replica, or nil when
+// the connection is not made to one.
+func (m *sqlite) Connector() (driver.Connector, error) {
+	deets := m.Details()
+	primaryURL := deets.option("sync_url")
+	if primaryURL == "" {
+		return nil, nil
+	}
+	if LibSQLEmbeddedReplica == nil {
+		return nil, errors.New("libsql embedded replicas are not enabled, set pop.LibSQLEmbeddedReplica")
+	}
+	var syncInterval time.Duration
+	if s := deets.option("sync_interval"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sync_interval %q: %w", s, err)
+		}
+		syncInterval = d
+	}
+	if deets.UseInstrumentedDriver {
+		log(logging.Warn, "SQL driver instrumentation is not supported for libsql embedded replicas.")
+	}
+	return LibSQLEmbeddedReplica(deets.Database, primaryURL, deets.option("authToken"), syncInterval)
+}
+
+// libsqlSchema builds the schema of the SQLite translator through the
+// libsql driver, the one of fizz only opens databases with sqlite3.
+type libsqlSchema struct {
+	translators.Schema
+	dialect *sqlite
+}
+
+func newLibSQLTranslator(m *sqlite) *translators.SQLite {
+	schema := &libsqlSchema{
+		Schema:  translators.CreateSchema(m.Details().Database, m.URL(), map[string]*fizz.Table{}),
+		dialect: m,
+	}
+	schema.Builder = schema
+	return &translators.SQLite{Schema: schema}
+}
+
+func (s *libsqlSchema) Build() error {
+	db, err := openPotentiallyInstrumentedConnection(s.dialect, s.dialect.URL())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var names []string
+	if err := db.Select(&names, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'"); err != nil {
+		return err
+	}
+	for _, name := range names {
+		table, err := s.buildTable(db, name)
+		if err != nil {
+			return fmt.Errorf("could not build the schema of %s: %w", name, err)
+		}
+		s.SetTable(table)
+	}
+	return nil
+}
+
+func (s *libsqlSchema) buildTable(db *sqlx.DB, name string) (*fizz.Table, error) {
+	table := &fizz.Table{
+		Name:    name,
+		Columns: []fizz.Column{},
+		Indexes: []fizz.Index{},
+	}
+
+	cols := []struct {
+		Name    string      `db:"name"`
+		Type    string      `db:"type"`
+		NotNull bool        `db:"notnull"`
+		Default interface{} `db:"dflt_value"`
+		PK      bool        `db:"pk"`
+	}{}
+	if err := db.Select(&cols, `SELECT "name", "type", "notnull", "dflt_value", "pk" FROM pragma_table_info(?) ORDER BY "cid"`, name); err != nil {
+		return nil, err
+	}
+	for _, c := range cols {
+		col := fizz.Column{Name: c.Name, ColType: c.Type, Primary: c.PK, Options: fizz.Options{}}
+		if !c.NotNull {
+			col.Options["null"] = true
+		}
+		if c.Default != nil {
+			col.Options["default"] = strings.Trim(fmt.Sprintf("%s", c.Default), "'")
+		}
+		table.Columns = append(table.Columns, col)
+	}
+
+	indexes := []struct {
+		Name   string `db:"name"`
+		Unique bool   `db:"unique"`
+	}{}
+	if err := db.Select(&indexes, `SELECT "name", "unique" FROM pragma_index_list(?) WHERE "name" NOT LIKE 'sqlite_%'`, name); err != nil {
+		return nil, err
+	}
+	for _, i := range indexes {
+		idx := fizz.Index{Name: i.Name, Unique: i.Unique, Columns: []string{}}
+		if err := db.Select(&idx.Columns, `SELECT "name" FROM pragma_index_info(?) ORDER BY "seqno"`, i.Name); err != nil {
+			return nil, err
+		}
+		table.Indexes = append(table.Indexes, idx)
+	}
+
+	fks := []struct {
+		Table    string `db:"table"`
+		From     string `db:"from"`
+		To       string `db:"to"`
+		OnUpdate string `db:"on_update"`
+		OnDelete string `db:"on_delete"`
+	}{}
+	if err := db.Select(&fks, `SELECT "table", "from", "to", "on_update", "on_delete" FROM pragma_foreign_key_list(?)`, name); err != nil {
+		return nil, err
+	}
+	for _, fk := range fks {
+		options := fizz.Options{}
+		if fk.OnDelete != "" {
+			options["on_delete"] = fk.OnDelete
+		}
+		if fk.OnUpdate != "" {
+			options["on_update"] = fk.OnUpdate
+		}
+		table.ForeignKeys = append(table.ForeignKeys, fizz.ForeignKey{
+			Name:       fmt.Sprintf("%s_%s_%s_fk", name, fk.Table, fk.To),
+			Column:     fk.From,
+			References: fizz.ForeignKeyRef{Table: fk.Table, Columns: []string{fk.To}},
+			Options:    options,
+		})
+	}
+
+	return table, nil
+}
+
+// dumpLibSQLSchema writes the statements creating the schema of the
+// database, read from sqlite_master as there is no sqlite3 shell for the
+// remote databases.
+func dumpLibSQLSchema(m *sqlite) (string, error) {
+	db, err := openPotentiallyInstrumentedConnection(m, m.URL())
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	var stmts []string
+	if err := db.Select(&stmts, "SELECT sql FROM sqlite_master WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%' ORDER BY CASE type WHEN 'table' THEN 0 ELSE 1 END, rowid"); err != nil {
+		return "", err
+	}
+	if len(stmts) == 0 {
+		return "", nil
+	}
+	return strings.Join(stmts, ";\n") + ";\n", nil
+}
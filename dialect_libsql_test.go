@@ -0,0 +1,93 @@
+package pop
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/fizz/translators"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConnectionDetails_Finalize_LibSQL_URL(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		URL: "libsql://my-db-org.turso.io?authToken=secret",
+	}
+	r.NoError(cd.Finalize())
+	r.Equal("sqlite3", cd.Dialect)
+	r.Equal("libsql://my-db-org.turso.io", cd.Database)
+	r.Equal(map[string]string{"authToken": "secret"}, cd.Options)
+
+	m := &sqlite{commonDialect: commonDialect{ConnectionDetails: cd}}
+	r.Equal(nameLibSQL, m.DefaultDriver())
+	r.Equal("libsql://my-db-org.turso.io?authToken=secret", m.URL())
+	r.Equal(m.URL(), m.MigrationURL())
+	r.IsType(&translators.SQLite{}, m.FizzTranslator())
+
+	cd = &ConnectionDetails{
+		Dialect: "sqlite",
+		URL:     "https://my-db-org.turso.io",
+	}
+	r.NoError(cd.Finalize())
+	r.Equal("https://my-db-org.turso.io", cd.Database)
+}
+
+func Test_LibSQL_EmbeddedReplica(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		Dialect:  "sqlite",
+		Database: "/tmp/replica.db",
+		Options: map[string]string{
+			"sync_url":      "libsql://my-db-org.turso.io",
+			"sync_interval": "1m",
+			"authToken":     "secret",
+		},
+	}
+	r.NoError(cd.Finalize())
+	m := &sqlite{commonDialect: commonDialect{ConnectionDetails: cd}}
+	r.Equal(nameLibSQL, m.DefaultDriver())
+	r.Equal("/tmp/replica.db?authToken=secret", m.URL())
+
+	defer func(fn func(string, string, string, time.Duration) (driver.Connector, error)) {
+		LibSQLEmbeddedReplica = fn
+	}(LibSQLEmbeddedReplica)
+
+	LibSQLEmbeddedReplica = nil
+	_, err := m.Connector()
+	r.Error(err)
+
+	called := errors.New("called")
+	LibSQLEmbeddedReplica = func(path, primaryURL, authToken string, syncInterval time.Duration) (driver.Connector, error) {
+		r.Equal("/tmp/replica.db", path)
+		r.Equal("libsql://my-db-org.turso.io", primaryURL)
+		r.Equal("secret", authToken)
+		r.Equal(time.Minute, syncInterval)
+		return nil, called
+	}
+	_, err = m.Connector()
+	r.ErrorIs(err, called)
+
+	m.ConnectionDetails.Options["sync_interval"] = "often"
+	_, err = m.Connector()
+	r.Error(err)
+
+	m = &sqlite{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{Database: "/tmp/local.db"}}}
+	c, err := m.Connector()
+	r.NoError(err)
+	r.Nil(c)
+}
+
+func Test_LibSQL_Remote_Database(t *testing.T) {
+	r := require.New(t)
+
+	_, err := newSQLite(&ConnectionDetails{Dialect: "sqlite3", Database: "libsql://my-db-org.turso.io"})
+	r.Error(err)
+
+	m := &sqlite{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{Database: "libsql://my-db-org.turso.io"}}}
+	r.Error(m.CreateDB())
+	r.Error(m.DropDB())
+}
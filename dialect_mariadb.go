@@ -1,6 +1,10 @@
 package pop
 
 import (
+	"fmt"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+	"github.com/WilliamNHarvey/pop/v6/logging"
 	"github.com/gobuffalo/fizz"
 	"github.com/gobuffalo/fizz/translators"
 )
@@ -11,7 +15,7 @@ func init() {
 	AvailableDialects = append(AvailableDialects, nameMariaDB)
 	urlParser[nameMariaDB] = urlParserMySQL
 	finalizer[nameMariaDB] = finalizerMySQL
-	newConnection[nameMariaDB] = newMySQL
+	newConnection[nameMariaDB] = newMariaDB
 }
 
 var _ dialect = &mariaDB{}
@@ -20,10 +24,50 @@ type mariaDB struct {
 	mysql
 }
 
+func newMariaDB(deets *ConnectionDetails) (dialect, error) {
+	cd := &mariaDB{mysql{commonDialect: commonDialect{ConnectionDetails: deets}}}
+	return cd, nil
+}
+
 func (m *mariaDB) Name() string {
 	return nameMariaDB
 }
 
+// Capabilities are the ones of MariaDB 10.6, which also returns the rows
+// written by INSERT and DELETE, but not UPDATE, statements, unless the
+// version read by AfterOpen is older than 10.5.
+func (m *mariaDB) Capabilities() capabilities {
+	c := supportsSavepoints | supportsRowLocks | supportsSkipLocked | ddlInTransactions
+	if m.version == "" || versionAtLeast(m.version, 10, 5) {
+		c |= supportsReturning
+	}
+	return c
+}
+
+// AfterOpen reads the version of the server, to know whether it supports
+// RETURNING.
+func (m *mariaDB) AfterOpen(c *Connection) error {
+	var version string
+	if err := c.Store.GetContext(c.Context(), &version, "SELECT VERSION()"); err != nil {
+		return fmt.Errorf("mariadb version: %w", err)
+	}
+	m.version = version
+	log(logging.Debug, "server: MariaDB %s", version)
+	return nil
+}
+
+// Create reads back the inserted row with RETURNING when the server
+// supports it.
+func (m *mariaDB) Create(c *Connection, model *Model, cols columns.Columns) error {
+	if !supports(m, supportsReturning) {
+		return m.mysql.Create(c, model, cols)
+	}
+	if err := returningCreate(c, model, cols, m); err != nil {
+		return fmt.Errorf("mariadb create: %w", err)
+	}
+	return nil
+}
+
 func (m *mariaDB) FizzTranslator() fizz.Translator {
 	t := translators.NewMariaDB(m.URL(), m.Details().Database)
 	return t
@@ -2,11 +2,15 @@ package pop
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os/exec"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/WilliamNHarvey/pop/v6/columns"
 	"github.com/WilliamNHarvey/pop/v6/internal/defaults"
@@ -21,6 +25,25 @@ const nameMySQL = "mysql"
 const hostMySQL = "localhost"
 const portMySQL = "3306"
 
+// Values of the compatibility option, for the MySQL-compatible distributed
+// databases.
+const (
+	compatibilityTiDB   = "tidb"
+	compatibilityVitess = "vitess"
+)
+
+// Values of the binding option. With the server binding, the statements are
+// prepared by the server and their arguments bound to them, never
+// interpolated in the statements by the driver; with the client one, the
+// driver interpolates them, saving the round trips of the preparations.
+const (
+	bindingServer = "server"
+	bindingClient = "client"
+)
+
+// mysqlOptions are the options of pop, not sent to the server.
+var mysqlOptions = []string{"compatibility", "binding"}
+
 func init() {
 	AvailableDialects = append(AvailableDialects, nameMySQL)
 	urlParser[nameMySQL] = urlParserMySQL
@@ -30,10 +53,27 @@ func init() {
 
 var _ dialect = &mysql{}
 
+// mysql is the dialect for MySQL databases. With the compatibility option
+// set to "tidb" or "vitess", it also works with the MySQL-compatible
+// distributed databases:
+//
+//   - the transactions aborted by a write conflict or a deadlock are
+//     retried, see RetryLimit and RetrySleep;
+//   - the shared locking clauses TiDB rejects are left out;
+//   - the databases of Vitess, its keyspaces, are not created nor dropped.
+//
+// Neither does pop use savepoints, nor does it expect consecutive
+// auto-incremented IDs, which TiDB allocates in batches per server.
 type mysql struct {
 	commonDialect
 }
 
+var (
+	mysqlShareLock = regexp.MustCompile(`(?i)\s+(LOCK\s+IN\s+SHARE\s+MODE|FOR\s+SHARE)\b`)
+	// the gRPC codes of the errors vttablet aborts the transactions with
+	mysqlVitessRetryable = regexp.MustCompile(`code = (Aborted|Unavailable)`)
+)
+
 func (m *mysql) Name() string {
 	return nameMySQL
 }
@@ -43,13 +83,322 @@ func (m *mysql) DefaultDriver() string {
 }
 
 func (mysql) Quote(key string) string {
-	return fmt.Sprintf("`%s`", key)
+	parts := strings.Split(key, ".")
+
+	for i, part := range parts {
+		part = strings.Trim(part, "`")
+		part = strings.TrimSpace(part)
+
+		parts[i] = fmt.Sprintf("`%v`", part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Capabilities are the ones of MySQL 8. TiDB does not skip locked rows.
+func (m *mysql) Capabilities() capabilities {
+	if m.compatibility() == compatibilityTiDB {
+		return supportsSavepoints | supportsRowLocks | ddlInTransactions
+	}
+	return supportsSavepoints | supportsRowLocks | supportsSkipLocked | ddlInTransactions
 }
 
 func (m *mysql) Details() *ConnectionDetails {
 	return m.ConnectionDetails
 }
 
+// compatibility returns the MySQL-compatible database the connection is
+// made to, if any.
+func (m *mysql) compatibility() string {
+	return m.Details().option("compatibility")
+}
+
+// Lock retries fn while it fails with an error the MySQL-compatible
+// database asks to retry the transaction for.
+func (m *mysql) Lock(fn func() error) error {
+	err := fn()
+	if m.compatibility() == "" {
+		return err
+	}
+	attempts := 0
+	for err != nil && m.retryable(err) && attempts < m.Details().RetryLimit() {
+		time.Sleep(m.Details().RetrySleep())
+		err = fn()
+		attempts++
+	}
+	return err
+}
+
+// retryable reports whether the transaction failed with err can be run
+// again: deadlocks and lock wait timeouts, the write conflicts of TiDB and
+// the transactions vttablet aborted.
+func (m *mysql) retryable(err error) bool {
+	var merr *_mysql.MySQLError
+	if errors.As(err, &merr) {
+		switch merr.Number {
+		case 1205, 1213: // lock wait timeout, deadlock
+			return true
+		case 8002, 8022, 9007: // write conflicts
+			return m.compatibility() == compatibilityTiDB
+		}
+	}
+	return m.compatibility() == compatibilityVitess && mysqlVitessRetryable.MatchString(err.Error())
+}
+
+// LockConflict reports whether err is a deadlock or a lock wait timeout.
+// The InnoDB status is not available with the compatibility modes.
+func (m *mysql) LockConflict(err error) bool {
+	var merr *_mysql.MySQLError
+	return m.compatibility() == "" && errors.As(err, &merr) && (merr.Number == 1205 || merr.Number == 1213)
+}
+
+// DiagnoseLocks reports the latest deadlock detected by InnoDB, or the
+// active transactions on lock wait timeouts.
+func (m *mysql) DiagnoseLocks(s store, err error) (string, error) {
+	status := struct {
+		Type   string `db:"Type"`
+		Name   string `db:"Name"`
+		Status string `db:"Status"`
+	}{}
+	if err := s.Get(&status, "SHOW ENGINE INNODB STATUS"); err != nil {
+		return "", err
+	}
+
+	var merr *_mysql.MySQLError
+	if errors.As(err, &merr) && merr.Number == 1213 {
+		return "latest detected deadlock:\n" + innodbStatusSection(status.Status, "LATEST DETECTED DEADLOCK"), nil
+	}
+	return "active transactions:\n" + innodbActiveTransactions(innodbStatusSection(status.Status, "TRANSACTIONS")), nil
+}
+
+// innodbActiveTransactions returns the active transactions of the
+// TRANSACTIONS section of the InnoDB status, the ones which may hold the
+// locks.
+func innodbActiveTransactions(section string) string {
+	var trxs []string
+	for _, trx := range strings.Split(section, "---TRANSACTION ")[1:] {
+		if strings.Contains(strings.SplitN(trx, "\n", 2)[0], "ACTIVE") {
+			trxs = append(trxs, "TRANSACTION "+strings.TrimSpace(trx))
+		}
+	}
+	return strings.Join(trxs, "\n")
+}
+
+// ExecBatch sends the statements as a single multi-statement query, when
+// the connection allows them and the arguments are interpolated by the
+// driver. MySQL reports the number of rows affected by the last statement
+// only, and not which one failed.
+func (m *mysql) ExecBatch(c *Connection, stmts []batchStatement) ([]BatchResult, error) {
+	if m.compatibility() != "" {
+		return nil, errBatchFallback
+	}
+	cfg, err := _mysql.ParseDSN(m.URL())
+	if err != nil || !cfg.MultiStatements {
+		return nil, errBatchFallback
+	}
+	queries := make([]string, len(stmts))
+	for i, s := range stmts {
+		if len(s.args) > 0 && !cfg.InterpolateParams {
+			return nil, errBatchFallback
+		}
+		queries[i] = strings.TrimRight(strings.TrimSpace(s.query), ";")
+	}
+	if c.TX == nil {
+		var results []BatchResult
+		err := c.Transaction(func(tx *Connection) error {
+			var err error
+			results, err = m.ExecBatch(tx, stmts)
+			return err
+		})
+		return results, err
+	}
+
+	var args []interface{}
+	for _, s := range stmts {
+		args = append(args, s.args...)
+	}
+	batchLog(c, stmts)
+	res, err := c.Store.ExecContext(c.Context(), strings.Join(queries, ";\n"), args...)
+	if err != nil {
+		return nil, &BatchError{Index: -1, Err: err}
+	}
+	results := make([]BatchResult, len(stmts))
+	for i := range results {
+		results[i] = BatchResult{RowsAffected: -1}
+	}
+	if n, err := res.RowsAffected(); err == nil {
+		results[len(results)-1].RowsAffected = n
+	}
+	return results, nil
+}
+
+// TableColumns reads the sizes and the character sets of the columns of the
+// table from information_schema, the driver does not report them.
+func (m *mysql) TableColumns(ctx context.Context, s store, table string) (map[string]tableColumn, error) {
+	schema := "DATABASE()"
+	args := []interface{}{table}
+	if parts := strings.SplitN(table, ".", 2); len(parts) == 2 {
+		schema = "?"
+		args = []interface{}{parts[0], parts[1]}
+	}
+	query := fmt.Sprintf(`SELECT column_name AS name, coalesce(character_maximum_length, 0) AS size, coalesce(character_set_name, '') AS charset
+	FROM information_schema.columns WHERE table_schema = %s AND table_name = ?`, schema)
+
+	rows := []struct {
+		Name    string `db:"name"`
+		Size    int64  `db:"size"`
+		Charset string `db:"charset"`
+	}{}
+	if err := s.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	cols := make(map[string]tableColumn, len(rows))
+	for _, r := range rows {
+		col := tableColumn{Charset: r.Charset}
+		if r.Size < math.MaxInt32 {
+			col.Size = int(r.Size)
+		}
+		cols[strings.ToLower(r.Name)] = col
+	}
+	return cols, nil
+}
+
+// ColumnDefaults reads the defaults of the columns from information_schema.
+// MySQL stores the literals unquoted, and the expressions unparenthesized.
+func (m *mysql) ColumnDefaults(ctx context.Context, s store, table string) (map[string]*string, error) {
+	schema := "DATABASE()"
+	args := []interface{}{table}
+	if parts := strings.SplitN(table, ".", 2); len(parts) == 2 {
+		schema = "?"
+		args = []interface{}{parts[0], parts[1]}
+	}
+	query := fmt.Sprintf(`SELECT column_name AS name, column_default AS def, data_type AS type, extra
+	FROM information_schema.columns WHERE table_schema = %s AND table_name = ?`, schema)
+
+	rows := []struct {
+		Name    string  `db:"name"`
+		Default *string `db:"def"`
+		Type    string  `db:"type"`
+		Extra   string  `db:"extra"`
+	}{}
+	if err := s.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	defs := make(map[string]*string, len(rows))
+	for _, r := range rows {
+		def := columnDefault(r.Default)
+		if def != nil {
+			def = mysqlDefault(*def, r.Type, r.Extra)
+		}
+		defs[strings.ToLower(r.Name)] = def
+	}
+	return defs, nil
+}
+
+// mysqlDefault returns the default of information_schema as an SQL
+// expression. MariaDB stores it as one already.
+func mysqlDefault(def, typ, extra string) *string {
+	upper := strings.ToUpper(def)
+	switch {
+	case strings.HasPrefix(def, "'"), strings.HasPrefix(def, "("):
+	case strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED"):
+		if !strings.HasPrefix(upper, "CURRENT_TIMESTAMP") {
+			def = "(" + def + ")"
+		}
+	case strings.Contains(",tinyint,smallint,mediumint,int,integer,bigint,decimal,numeric,float,double,bit,", ","+strings.ToLower(typ)+","):
+	case strings.HasPrefix(upper, "CURRENT_TIMESTAMP"):
+	default:
+		def = "'" + strings.ReplaceAll(def, "'", "''") + "'"
+	}
+	return &def
+}
+
+const mysqlSchemaColumns = `SELECT c.table_name AS table_name, c.column_name AS name, c.column_type AS type,
+	c.is_nullable = 'YES' AS nullable, c.column_default AS def, c.data_type AS data_type, c.extra AS extra, c.column_key = 'PRI' AS pk
+	FROM information_schema.columns c JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+	WHERE c.table_schema = DATABASE() AND t.table_type = 'BASE TABLE'
+	ORDER BY c.table_name, c.ordinal_position`
+
+const mysqlSchemaForeignKeys = `SELECT table_name AS table_name, constraint_name AS name, referenced_table_name AS ref_table,
+	column_name AS column_name, referenced_column_name AS ref_column
+	FROM information_schema.key_column_usage
+	WHERE table_schema = DATABASE() AND referenced_table_name IS NOT NULL
+	ORDER BY table_name, constraint_name, ordinal_position`
+
+const mysqlSchemaIndexes = `SELECT table_name AS table_name, index_name AS name, non_unique = 0 AS uniq, column_name AS column_name
+	FROM information_schema.statistics
+	WHERE table_schema = DATABASE() AND index_name <> 'PRIMARY'
+	ORDER BY table_name, index_name, seq_in_index`
+
+const mysqlIndexUsage = `SELECT table_name AS table_name, index_name AS index_name, rows_selected AS reads
+	FROM sys.schema_index_statistics WHERE table_schema = DATABASE()`
+
+// IndexUsage reads the rows read through the indexes from the sys schema.
+func (m *mysql) IndexUsage(ctx context.Context, s store) ([]IndexUsage, error) {
+	usage := []IndexUsage{}
+	err := s.SelectContext(ctx, &usage, mysqlIndexUsage)
+	return usage, err
+}
+
+// Schema reads the tables of the database from information_schema.
+func (m *mysql) Schema(ctx context.Context, s store) ([]SchemaTable, error) {
+	cols := []struct {
+		Table    string  `db:"table_name"`
+		Name     string  `db:"name"`
+		Type     string  `db:"type"`
+		Nullable bool    `db:"nullable"`
+		Default  *string `db:"def"`
+		DataType string  `db:"data_type"`
+		Extra    string  `db:"extra"`
+		PK       bool    `db:"pk"`
+	}{}
+	if err := s.SelectContext(ctx, &cols, mysqlSchemaColumns); err != nil {
+		return nil, err
+	}
+	b := newSchemaBuilder()
+	for _, c := range cols {
+		def := columnDefault(c.Default)
+		if def != nil {
+			def = mysqlDefault(*def, c.DataType, c.Extra)
+		}
+		b.addColumn(c.Table, SchemaColumn{Name: c.Name, Type: c.Type, Nullable: c.Nullable, Default: def, PrimaryKey: c.PK})
+	}
+
+	fks := []struct {
+		Table     string `db:"table_name"`
+		Name      string `db:"name"`
+		RefTable  string `db:"ref_table"`
+		Column    string `db:"column_name"`
+		RefColumn string `db:"ref_column"`
+	}{}
+	if err := s.SelectContext(ctx, &fks, mysqlSchemaForeignKeys); err != nil {
+		return nil, err
+	}
+	for _, fk := range fks {
+		b.addForeignKeyColumn(fk.Table, fk.Name, fk.Name, fk.RefTable, fk.Column, fk.RefColumn)
+	}
+
+	idxs := []struct {
+		Table  string `db:"table_name"`
+		Name   string `db:"name"`
+		Unique bool   `db:"uniq"`
+		Column string `db:"column_name"`
+	}{}
+	if err := s.SelectContext(ctx, &idxs, mysqlSchemaIndexes); err != nil {
+		return nil, err
+	}
+	for _, idx := range idxs {
+		b.addIndexColumn(idx.Table, idx.Name, idx.Unique, idx.Column)
+	}
+	return b.schema(), nil
+}
+
 func (m *mysql) URL() string {
 	cd := m.ConnectionDetails
 	if cd.URL != "" {
@@ -82,6 +431,22 @@ func (m *mysql) MigrationURL() string {
 	return m.URL()
 }
 
+func (m *mysql) rowChecksumSQL(cols []string) string {
+	return fmt.Sprintf("MD5(JSON_ARRAY(%s))", strings.Join(cols, ", "))
+}
+
+// StrictSettings returns the sql_mode rejecting the invalid and the
+// truncated values, and the zero dates.
+func (m *mysql) StrictSettings() map[string]string {
+	return map[string]string{
+		"sql_mode": "'STRICT_ALL_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION'",
+	}
+}
+
+func (m *mysql) SessionStatement(name, value string) string {
+	return fmt.Sprintf("SET SESSION %s = %s", name, value)
+}
+
 func (m *mysql) Create(c *Connection, model *Model, cols columns.Columns) error {
 	if err := genericCreate(c, model, cols, m); err != nil {
 		return fmt.Errorf("mysql create: %w", err)
@@ -97,16 +462,68 @@ func (m *mysql) Update(c *Connection, model *Model, cols columns.Columns) error
 }
 
 func (m *mysql) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
-	if n, err := genericUpdateQuery(c, model, cols, m, query, sqlx.QUESTION); err != nil {
+	if n, err := genericUpdateQuery(c, model, cols, query); err != nil {
 		return n, fmt.Errorf("mysql update query: %w", err)
 	} else {
 		return n, nil
 	}
 }
 
+func (m *mysql) updateQuerySQL(model *Model, cols columns.Columns, query Query) (string, []interface{}, error) {
+	return genericUpdateQuerySQL(model, cols, m, query, sqlx.QUESTION)
+}
+
+// Upsert ignores the conflict columns: the row conflicting on any unique
+// key is updated. The ID of the updated row is read through
+// LAST_INSERT_ID, the other columns are not read back: the row was inserted
+// when a single row is affected, 2 are reported for an updated row and none
+// for a row left as it is.
+func (m *mysql) Upsert(c *Connection, model *Model, cols *columns.WriteableColumns, conflict, update []string) (bool, error) {
+	var sets []string
+	autoID := upsertAutoID(model)
+	if autoID {
+		id := m.Quote(model.IDField())
+		sets = append(sets, fmt.Sprintf("%s = LAST_INSERT_ID(%s)", id, id))
+	}
+	for _, col := range update {
+		sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", m.Quote(col), m.Quote(col)))
+	}
+	if len(sets) == 0 {
+		// nothing to update, the conflicting row is left as it is
+		col := model.IDField()
+		if len(conflict) > 0 {
+			col = conflict[0]
+		}
+		sets = append(sets, fmt.Sprintf("%s = %s", m.Quote(col), m.Quote(col)))
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON DUPLICATE KEY UPDATE %s", m.Quote(model.TableName()), cols.QuotedString(m), cols.SymbolizedString(), strings.Join(sets, ", "))
+	query, err := beforeNamedExec(Insert, model, query)
+	if err != nil {
+		return false, err
+	}
+	txlog(logging.SQL, c, query, model.Value)
+	res, err := c.Store.NamedExecContext(model.ctx, query, model.Value)
+	if err != nil {
+		return false, fmt.Errorf("mysql upsert: %w", err)
+	}
+	if autoID {
+		id, err := res.LastInsertId()
+		if err != nil {
+			return false, fmt.Errorf("mysql upsert: %w", err)
+		}
+		model.setID(id)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("mysql upsert: %w", err)
+	}
+	return affected == 1, nil
+}
+
 func (m *mysql) Destroy(c *Connection, model *Model) error {
-	stmt := fmt.Sprintf("DELETE FROM %s  WHERE %s = ?", m.Quote(model.TableName()), model.IDField())
-	_, err := genericExec(c, stmt, model.ID())
+	stmt := fmt.Sprintf("DELETE FROM %s  WHERE %s", m.Quote(model.TableName()), model.whereKey(""))
+	_, err := genericExec(c, Delete, model, stmt, model.idArgs()...)
 	if err != nil {
 		return fmt.Errorf("mysql destroy: %w", err)
 	}
@@ -116,14 +533,17 @@ func (m *mysql) Destroy(c *Connection, model *Model) error {
 var asRegex = regexp.MustCompile(`\sAS\s\S+`) // exactly " AS non-spaces"
 
 func (m *mysql) Delete(c *Connection, model *Model, query Query) error {
+	sqlQuery, args := m.deleteSQL(model, query)
+	_, err := genericExec(c, Delete, model, sqlQuery, args...)
+	return err
+}
+
+func (m *mysql) deleteSQL(model *Model, query Query) (string, []interface{}) {
 	sqlQuery, args := query.ToSQL(model)
 	// * MySQL does not support table alias for DELETE syntax until 8.0.
 	// * Do not generate SQL manually if they may have `WHERE IN`.
 	// * Spaces are intentionally added to make it easy to see on the log.
-	sqlQuery = asRegex.ReplaceAllString(sqlQuery, "  ")
-
-	_, err := genericExec(c, sqlQuery, args...)
-	return err
+	return asRegex.ReplaceAllString(sqlQuery, "  "), args
 }
 
 func (m *mysql) SelectOne(c *Connection, model *Model, query Query) error {
@@ -143,6 +563,9 @@ func (m *mysql) SelectMany(c *Connection, models *Model, query Query) error {
 // CreateDB creates a new database, from the given connection credentials
 func (m *mysql) CreateDB() error {
 	deets := m.ConnectionDetails
+	if m.compatibility() == compatibilityVitess {
+		return fmt.Errorf("error creating MySQL database %s: Vitess keyspaces are not created with SQL", deets.Database)
+	}
 	db, err := openPotentiallyInstrumentedConnection(m, m.urlWithoutDb())
 	if err != nil {
 		return fmt.Errorf("error creating MySQL database %s: %w", deets.Database, err)
@@ -165,6 +588,9 @@ func (m *mysql) CreateDB() error {
 // DropDB drops an existing database, from the given connection credentials
 func (m *mysql) DropDB() error {
 	deets := m.ConnectionDetails
+	if m.compatibility() == compatibilityVitess {
+		return fmt.Errorf("error dropping MySQL database %s: Vitess keyspaces are not dropped with SQL", deets.Database)
+	}
 	db, err := openPotentiallyInstrumentedConnection(m, m.urlWithoutDb())
 	if err != nil {
 		return fmt.Errorf("error dropping MySQL database %s: %w", deets.Database, err)
@@ -182,7 +608,12 @@ func (m *mysql) DropDB() error {
 	return nil
 }
 
+// TranslateSQL leaves out the shared locking clauses on TiDB, which only
+// accepts them as no-ops.
 func (m *mysql) TranslateSQL(sql string) string {
+	if m.compatibility() == compatibilityTiDB {
+		return mysqlShareLock.ReplaceAllString(sql, "")
+	}
 	return sql
 }
 
@@ -236,7 +667,7 @@ func newMySQL(deets *ConnectionDetails) (dialect, error) {
 func urlParserMySQL(cd *ConnectionDetails) error {
 	cfg, err := _mysql.ParseDSN(strings.TrimPrefix(cd.URL, "mysql://"))
 	if err != nil {
-		return fmt.Errorf("the URL '%s' is not supported by MySQL driver: %w", cd.URL, err)
+		return fmt.Errorf("the URL '%s' is not supported by MySQL driver: %w", maskSecrets(cd.URL), err)
 	}
 
 	cd.User = cfg.User
@@ -246,6 +677,27 @@ func urlParserMySQL(cd *ConnectionDetails) error {
 	// NOTE: use cfg.Params if want to fill options with full parameters
 	cd.setOption("collation", cfg.Collation)
 
+	rewrite := false
+	for _, k := range mysqlOptions {
+		if v, ok := cfg.Params[k]; ok {
+			cd.setOption(k, v)
+			delete(cfg.Params, k)
+			rewrite = true
+		}
+	}
+	switch cd.option("binding") {
+	case bindingServer:
+		if cfg.InterpolateParams {
+			log(logging.Warn, "the server binding is set, interpolateParams=true is ignored.")
+		}
+		cfg.InterpolateParams = false
+	case bindingClient:
+		cfg.InterpolateParams = true
+	}
+	if rewrite {
+		cd.URL = "mysql://" + cfg.FormatDSN()
+	}
+
 	if cfg.Net == "unix" {
 		cd.Port = "socket" // trick. see: `URL()`
 		cd.Host = cfg.Addr
@@ -277,6 +729,25 @@ func finalizerMySQL(cd *ConnectionDetails) {
 		cd.setOptionWithDefault(k, cd.option(k), def)
 	}
 
+	switch c := cd.option("compatibility"); c {
+	case "", compatibilityTiDB, compatibilityVitess:
+	default:
+		log(logging.Warn, "unknown MySQL compatibility '%s', expected '%s' or '%s'.", c, compatibilityTiDB, compatibilityVitess)
+	}
+
+	switch b := cd.option("binding"); b {
+	case bindingServer:
+		if cd.option("interpolateParams") == "true" {
+			log(logging.Warn, "the server binding is set, 'interpolateParams: true' is ignored.")
+		}
+		cd.setOption("interpolateParams", "false")
+	case bindingClient:
+		cd.setOption("interpolateParams", "true")
+	case "":
+	default:
+		log(logging.Warn, "unknown MySQL binding '%s', expected '%s' or '%s'.", b, bindingServer, bindingClient)
+	}
+
 	for k, v := range forced {
 		// respect user specified options but print warning!
 		cd.setOptionWithDefault(k, cd.option(k), v)
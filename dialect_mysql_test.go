@@ -1,16 +1,28 @@
 package pop
 
 import (
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
 
+	_mysql "github.com/go-sql-driver/mysql"
 	"github.com/gobuffalo/fizz"
 	"github.com/gobuffalo/fizz/translators"
 	"github.com/stretchr/testify/require"
 )
 
+func Test_MySQL_Quotable(t *testing.T) {
+	r := require.New(t)
+	m := mysql{}
+
+	r.Equal("`table_name`", m.Quote("table_name"))
+	r.Equal("`schema`.`table_name`", m.Quote("schema.table_name"))
+	r.Equal("`schema`.`table name`", m.Quote("`schema`.`table name`"))
+}
+
 func Test_MySQL_URL_As_Is(t *testing.T) {
 	r := require.New(t)
 
@@ -192,6 +204,166 @@ func Test_MySQL_Finalizer_Preserve_User_Defined_Options(t *testing.T) {
 	r.Contains(m.URL(), "collation=utf8")
 }
 
+func Test_MySQL_Compatibility_URL(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		URL: "mysql://root@(tidb:4000)/dbase?compatibility=tidb&parseTime=true&multiStatements=true",
+	}
+	r.NoError(cd.Finalize())
+	r.Equal("tidb", cd.Options["compatibility"])
+
+	m := &mysql{commonDialect{ConnectionDetails: cd}}
+	r.Equal("tidb", m.compatibility())
+	r.NotContains(m.URL(), "compatibility")
+
+	m = &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+		Options: map[string]string{"compatibility": "vitess"},
+	}}}
+	finalizerMySQL(m.ConnectionDetails)
+	r.Equal("vitess", m.compatibility())
+	r.NotContains(m.URL(), "compatibility")
+}
+
+func Test_MySQL_Binding(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		URL: "mysql://root@(localhost:3306)/dbase?binding=server&interpolateParams=true&parseTime=true&multiStatements=true",
+	}
+	r.NoError(cd.Finalize())
+	m := &mysql{commonDialect{ConnectionDetails: cd}}
+	r.NotContains(m.URL(), "binding")
+	r.NotContains(m.URL(), "interpolateParams")
+	cfg, err := _mysql.ParseDSN(m.URL())
+	r.NoError(err)
+	r.False(cfg.InterpolateParams)
+
+	m = &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+		Options: map[string]string{"binding": "server", "interpolateParams": "true"},
+	}}}
+	finalizerMySQL(m.ConnectionDetails)
+	r.NotContains(m.URL(), "binding")
+	cfg, err = _mysql.ParseDSN(m.URL())
+	r.NoError(err)
+	r.False(cfg.InterpolateParams)
+
+	m = &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+		Options: map[string]string{"binding": "client"},
+	}}}
+	finalizerMySQL(m.ConnectionDetails)
+	cfg, err = _mysql.ParseDSN(m.URL())
+	r.NoError(err)
+	r.True(cfg.InterpolateParams)
+}
+
+func Test_MySQL_Compatibility_Lock_Retries(t *testing.T) {
+	r := require.New(t)
+
+	conflict := fmt.Errorf("database error on committing or rolling back transaction: %w", &_mysql.MySQLError{Number: 9007, Message: "Write conflict"})
+	run := func(m *mysql, failures int) (int, error) {
+		calls := 0
+		err := m.Lock(func() error {
+			calls++
+			if calls <= failures {
+				return conflict
+			}
+			return nil
+		})
+		return calls, err
+	}
+
+	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	calls, err := run(m, 1)
+	r.ErrorIs(err, conflict)
+	r.Equal(1, calls)
+
+	m.ConnectionDetails.Options = map[string]string{"compatibility": "tidb", "retry_sleep": "0s", "retry_limit": "3"}
+	calls, err = run(m, 2)
+	r.NoError(err)
+	r.Equal(3, calls)
+
+	calls, err = run(m, 10)
+	r.Error(err)
+	r.Equal(4, calls)
+
+	m.ConnectionDetails.Options["compatibility"] = "vitess"
+	r.False(m.retryable(conflict))
+	r.True(m.retryable(&_mysql.MySQLError{Number: 1213}))
+	r.True(m.retryable(errors.New("vttablet: rpc error: code = Aborted desc = transaction 1: ended")))
+	r.False(m.retryable(errors.New("Duplicate entry")))
+}
+
+func Test_MySQL_Compatibility_TranslateSQL(t *testing.T) {
+	r := require.New(t)
+
+	query := "SELECT * FROM users WHERE id = ? LOCK IN SHARE MODE"
+	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	r.Equal(query, m.TranslateSQL(query))
+
+	m.ConnectionDetails.Options = map[string]string{"compatibility": "tidb"}
+	r.Equal("SELECT * FROM users WHERE id = ?", m.TranslateSQL(query))
+	r.Equal("SELECT * FROM users FOR UPDATE", m.TranslateSQL("SELECT * FROM users FOR UPDATE"))
+	r.Equal("SELECT * FROM users", m.TranslateSQL("SELECT * FROM users FOR SHARE"))
+}
+
+func Test_MySQL_Compatibility_Vitess_Database(t *testing.T) {
+	r := require.New(t)
+	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+		Database: "commerce",
+		Options:  map[string]string{"compatibility": "vitess"},
+	}}}
+	r.EqualError(m.CreateDB(), "error creating MySQL database commerce: Vitess keyspaces are not created with SQL")
+	r.EqualError(m.DropDB(), "error dropping MySQL database commerce: Vitess keyspaces are not dropped with SQL")
+}
+
+func Test_MySQL_LockConflict(t *testing.T) {
+	r := require.New(t)
+	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+
+	r.True(m.LockConflict(fmt.Errorf("failed: %w", &_mysql.MySQLError{Number: 1213})))
+	r.True(m.LockConflict(&_mysql.MySQLError{Number: 1205}))
+	r.False(m.LockConflict(&_mysql.MySQLError{Number: 1062}))
+	r.False(m.LockConflict(errors.New("deadlock")))
+
+	m.ConnectionDetails.Options = map[string]string{"compatibility": "tidb"}
+	r.False(m.LockConflict(&_mysql.MySQLError{Number: 1213}))
+}
+
+func Test_MySQL_InnoDB_Status(t *testing.T) {
+	r := require.New(t)
+
+	status := `
+=====================================
+2024-01-02 10:00:00 INNODB MONITOR OUTPUT
+=====================================
+------------------------
+LATEST DETECTED DEADLOCK
+------------------------
+*** (1) TRANSACTION:
+UPDATE users SET name = 'a' WHERE id = 1
+*** WE ROLL BACK TRANSACTION (1)
+------------
+TRANSACTIONS
+------------
+Trx id counter 1300
+---TRANSACTION 421, not started
+0 lock struct(s), heap size 1128, 0 row lock(s)
+---TRANSACTION 1290, ACTIVE 12 sec
+2 lock struct(s), heap size 1128, 1 row lock(s)
+MySQL thread id 9, OS thread handle 1, query id 50 localhost root
+--------
+FILE I/O
+--------
+I/O thread 0 state: waiting for completed aio requests
+`
+	r.Equal("*** (1) TRANSACTION:\nUPDATE users SET name = 'a' WHERE id = 1\n*** WE ROLL BACK TRANSACTION (1)", innodbStatusSection(status, "LATEST DETECTED DEADLOCK"))
+	r.Equal("", innodbStatusSection(status, "SEMAPHORES"))
+
+	trxs := innodbActiveTransactions(innodbStatusSection(status, "TRANSACTIONS"))
+	r.Equal("TRANSACTION 1290, ACTIVE 12 sec\n2 lock struct(s), heap size 1128, 1 row lock(s)\nMySQL thread id 9, OS thread handle 1, query id 50 localhost root", trxs)
+}
+
 func (s *MySQLSuite) Test_MySQL_DDL_Operations() {
 	r := s.Require()
 
@@ -244,3 +416,27 @@ func (s *MySQLSuite) Test_MySQL_DDL_Schema() {
 	err = PDB.Dialect.DumpSchema(f)
 	r.Error(err)
 }
+
+func Test_MySQL_ExecBatch_Fallback(t *testing.T) {
+	r := require.New(t)
+
+	stmts := []batchStatement{{query: "DELETE FROM users WHERE id = ?", args: []interface{}{1}}}
+	m := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+		URL: "mysql://root@(localhost:3306)/dbase?multiStatements=true",
+	}}}
+	_, err := m.ExecBatch(&Connection{}, stmts)
+	r.Equal(errBatchFallback, err)
+
+	m = &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+		URL: "mysql://root@(localhost:3306)/dbase?interpolateParams=true",
+	}}}
+	_, err = m.ExecBatch(&Connection{}, stmts)
+	r.Equal(errBatchFallback, err)
+
+	m = &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{
+		URL:     "mysql://root@(localhost:3306)/dbase?interpolateParams=true&multiStatements=true",
+		Options: map[string]string{"compatibility": compatibilityVitess},
+	}}}
+	_, err = m.ExecBatch(&Connection{}, stmts)
+	r.Equal(errBatchFallback, err)
+}
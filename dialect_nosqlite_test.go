@@ -9,6 +9,6 @@ import (
 )
 
 func TestSqlite_NewDriver(t *testing.T) {
-	_, err := newSQLiteDriver()
+	_, err := newSQLiteDriver(nameSQLite3)
 	require.Error(t, err)
 }
@@ -0,0 +1,384 @@
+package pop
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+	"github.com/WilliamNHarvey/pop/v6/internal/defaults"
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/gobuffalo/fizz"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+const nameOracle = "oracle"
+const portOracle = "1521"
+
+func init() {
+	AvailableDialects = append(AvailableDialects, nameOracle)
+	finalizer[nameOracle] = finalizerOracle
+	newConnection[nameOracle] = newOracle
+	sqlx.BindDriver(nameOracle, sqlx.NAMED)
+}
+
+var _ dialect = &oracle{}
+
+// oracle is a dialect for Oracle databases (12c and later). The driver is
+// not bundled with pop, it must be registered by importing it:
+//
+//	import _ "github.com/sijms/go-ora/v2"
+//
+// Oracle folds unquoted identifiers to upper case: tables and columns are
+// quoted in upper case, and the columns returned by the database are
+// matched case-insensitively with the struct fields. Integer IDs are taken
+// from a "<table>_seq" sequence, as created by the fizz translator.
+type oracle struct {
+	commonDialect
+	translateCache map[string]string
+	mu             sync.Mutex
+}
+
+var (
+	oracleTableAlias   = regexp.MustCompile(`(?i)((?:\bFROM|\bJOIN|\bUPDATE|,)\s+[\w."$#]+)\s+AS\s+`)
+	oracleExists       = regexp.MustCompile(`(?is)^SELECT EXISTS \((.*)\)$`)
+	oracleNamedParam   = regexp.MustCompile(`:(\w+)`)
+	oracleScriptEnd    = regexp.MustCompile(`(?m)^\s*/\s*$`)
+	oracleStatementEnd = regexp.MustCompile(`;\s*(?:\n|$)`)
+)
+
+func requireOracle() error {
+	for _, driverName := range sql.Drivers() {
+		if driverName == nameOracle {
+			return nil
+		}
+	}
+	return errors.New("oracle driver is not registered, import github.com/sijms/go-ora/v2")
+}
+
+func (o *oracle) Name() string {
+	return nameOracle
+}
+
+func (o *oracle) DefaultDriver() string {
+	return nameOracle
+}
+
+func (o *oracle) Capabilities() capabilities {
+	return supportsSavepoints | supportsRowLocks | supportsSkipLocked | ddlInTransactions
+}
+
+func (o *oracle) Details() *ConnectionDetails {
+	return o.ConnectionDetails
+}
+
+func (o *oracle) URL() string {
+	c := o.ConnectionDetails
+	if c.URL != "" {
+		return c.URL
+	}
+	s := "oracle://%s:%s@%s:%s/%s"
+	s = fmt.Sprintf(s, url.QueryEscape(c.User), url.QueryEscape(c.Password), c.Host, c.Port, c.Database)
+	if opts := c.OptionsString(""); opts != "" {
+		s += "?" + opts
+	}
+	return s
+}
+
+func (o *oracle) MigrationURL() string {
+	return o.URL()
+}
+
+// Quote quotes each part of the identifier in upper case, the case of the
+// unquoted identifiers used in the queries.
+func (o *oracle) Quote(key string) string {
+	return oracleQuote(key)
+}
+
+func oracleQuote(key string) string {
+	parts := strings.Split(key, ".")
+
+	for i, part := range parts {
+		part = strings.Trim(part, `"`)
+		part = strings.TrimSpace(part)
+
+		parts[i] = fmt.Sprintf(`"%v"`, strings.ToUpper(part))
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// Mapper matches the upper case columns returned by Oracle with the
+// struct fields.
+func (o *oracle) Mapper() *reflectx.Mapper {
+	return reflectx.NewMapperTagFunc("db", strings.ToUpper, strings.ToUpper)
+}
+
+func (o *oracle) Create(c *Connection, model *Model, cols columns.Columns) error {
+	keyType, err := model.PrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	switch keyType {
+	case "int", "int64":
+		if model.UsingAutoIncrement() {
+			var id int64
+			query := fmt.Sprintf("SELECT %s.NEXTVAL FROM DUAL", o.Quote(oracleSequence(model.TableName())))
+			txlog(logging.SQL, c, query)
+			if err := c.Store.GetContext(model.ctx, &id, query); err != nil {
+				return fmt.Errorf("oracle create: next id: %w", err)
+			}
+			model.setID(id)
+		}
+	case "UUID":
+		if model.ID() == emptyUUID {
+			u, err := uuid.NewV4()
+			if err != nil {
+				return err
+			}
+			model.setID(u)
+		}
+	case "string":
+		if model.ID() == "" {
+			return fmt.Errorf("missing ID value")
+		}
+	case compositeKeyType:
+		// the fields of the key are written as the other ones
+	default:
+		return fmt.Errorf("can not use %s as a primary key type!", keyType)
+	}
+
+	w := cols.Writeable()
+	if keyType != compositeKeyType {
+		w.Add(model.IDField())
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", o.Quote(model.TableName()), w.QuotedString(o), w.SymbolizedString())
+	query, err = beforeNamedExec(Insert, model, oracleNamed(query))
+	if err != nil {
+		return err
+	}
+	txlog(logging.SQL, c, query, model.Value)
+	if _, err := c.Store.NamedExecContext(model.ctx, query, model.Value); err != nil {
+		return fmt.Errorf("oracle create: %w", err)
+	}
+	return nil
+}
+
+func (o *oracle) Update(c *Connection, model *Model, cols columns.Columns) error {
+	stmt := fmt.Sprintf("UPDATE %s %s SET %s WHERE %s", o.Quote(model.TableName()), model.Alias(), cols.Writeable().QuotedUpdateString(o), model.whereNamedUpdate(cols))
+	stmt, err := beforeNamedExec(Update, model, oracleNamed(stmt))
+	if err != nil {
+		return err
+	}
+	txlog(logging.SQL, c, stmt, model.ID())
+	res, err := c.Store.NamedExecContext(model.ctx, stmt, model.Value)
+	if err != nil {
+		return fmt.Errorf("oracle update: %w", err)
+	}
+	return checkStale(model, cols, res)
+}
+
+func (o *oracle) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
+	q, args, err := o.updateQuerySQL(model, cols, query)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := genericExec(c, Update, model, q, args...)
+	if err != nil {
+		return 0, fmt.Errorf("oracle update query: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (o *oracle) updateQuerySQL(model *Model, cols columns.Columns, query Query) (string, []interface{}, error) {
+	q := fmt.Sprintf("UPDATE %s %s SET %s", o.Quote(model.TableName()), model.Alias(), cols.Writeable().QuotedUpdateString(o))
+
+	q, updateArgs, err := sqlx.Named(q, model.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	sb := query.toSQLBuilder(model)
+	q = sb.buildWhereClauses(q)
+
+	return sqlx.Rebind(sqlx.NAMED, q), append(updateArgs, sb.args...), nil
+}
+
+func (o *oracle) Destroy(c *Connection, model *Model) error {
+	stmt := o.TranslateSQL(fmt.Sprintf("DELETE FROM %s %s WHERE %s", o.Quote(model.TableName()), model.Alias(), model.WhereID()))
+	if _, err := genericExec(c, Delete, model, stmt, model.idArgs()...); err != nil {
+		return fmt.Errorf("oracle destroy: %w", err)
+	}
+	return nil
+}
+
+func (o *oracle) Delete(c *Connection, model *Model, query Query) error {
+	return genericDelete(c, model, query)
+}
+
+func (o *oracle) SelectOne(c *Connection, model *Model, query Query) error {
+	return genericSelectOne(c, model, query)
+}
+
+func (o *oracle) SelectMany(c *Connection, models *Model, query Query) error {
+	return genericSelectMany(c, models, query)
+}
+
+// CreateDB is not supported: Oracle databases are created by DBAs, pop
+// connects to an existing schema.
+func (o *oracle) CreateDB() error {
+	return fmt.Errorf("could not create Oracle database %s: not supported", o.ConnectionDetails.Database)
+}
+
+// DropDB is not supported: Oracle databases are dropped by DBAs.
+func (o *oracle) DropDB() error {
+	return fmt.Errorf("could not drop Oracle database %s: not supported", o.ConnectionDetails.Database)
+}
+
+// TranslateSQL adapts the queries built by pop to Oracle: table aliases
+// have no AS, EXISTS is wrapped in a CASE and the bind variables are
+// named. The queries are paged by OffsetFetchPager.
+func (o *oracle) TranslateSQL(sql string) string {
+	defer o.mu.Unlock()
+	o.mu.Lock()
+
+	if csql, ok := o.translateCache[sql]; ok {
+		return csql
+	}
+
+	csql := oracleTableAlias.ReplaceAllString(sql, "$1 ")
+	csql = oracleExists.ReplaceAllString(csql, "SELECT CASE WHEN EXISTS ($1) THEN 1 ELSE 0 END FROM DUAL")
+	csql = sqlx.Rebind(sqlx.NAMED, csql)
+
+	o.translateCache[sql] = csql
+	return csql
+}
+
+func (o *oracle) FizzTranslator() fizz.Translator {
+	return newOracleTranslator()
+}
+
+// DumpSchema is not supported, Oracle has no standard schema dump tool.
+func (o *oracle) DumpSchema(w io.Writer) error {
+	return fmt.Errorf("unable to dump schema for %s: not supported", o.Details().Database)
+}
+
+func (o *oracle) LoadSchema(r io.Reader) error {
+	deets := o.Details()
+
+	db, err := openPotentiallyInstrumentedConnection(o, o.MigrationURL())
+	if err != nil {
+		return fmt.Errorf("unable to load schema for %s: %w", deets.Database, err)
+	}
+	defer db.Close()
+
+	contents, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range o.SplitScript(string(contents)) {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("unable to load schema for %s: %w", deets.Database, err)
+		}
+	}
+
+	log(logging.Info, "loaded schema for %s", deets.Database)
+	return nil
+}
+
+// SplitScript splits the script on the lines holding a single slash, as
+// SQL*Plus does, or on the semicolons ending the lines when there are no
+// such lines. The semicolons ending plain statements are removed, the ones
+// ending PL/SQL blocks are kept.
+func (o *oracle) SplitScript(script string) []string {
+	var parts []string
+	if oracleScriptEnd.MatchString(script) {
+		parts = oracleScriptEnd.Split(script, -1)
+	} else {
+		parts = oracleStatementEnd.Split(script, -1)
+	}
+
+	var stmts []string
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.HasSuffix(strings.ToUpper(p), "END;") {
+			p = strings.TrimSuffix(p, ";")
+		}
+		stmts = append(stmts, p)
+	}
+	return stmts
+}
+
+func (o *oracle) TruncateAll(tx *Connection) error {
+	names := []struct {
+		Name string `db:"name"`
+	}{}
+
+	err := tx.RawQuery("SELECT table_name AS name FROM user_tables WHERE table_name <> ?", strings.ToUpper(tx.MigrationTableName())).All(&names)
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		if err := tx.RawQuery(fmt.Sprintf("DELETE FROM %s", o.Quote(n.Name))).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newOracle(deets *ConnectionDetails) (dialect, error) {
+	if err := requireOracle(); err != nil {
+		return nil, err
+	}
+	cd := &oracle{
+		commonDialect:  commonDialect{ConnectionDetails: deets},
+		translateCache: map[string]string{},
+		mu:             sync.Mutex{},
+	}
+	return cd, nil
+}
+
+func finalizerOracle(cd *ConnectionDetails) {
+	cd.Port = defaults.String(cd.Port, portOracle)
+}
+
+func newOracleDriver() (driver.Driver, error) {
+	if err := requireOracle(); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(nameOracle, "oracle://localhost/newOracleDriver")
+	if err != nil {
+		return nil, err
+	}
+	return db.Driver(), db.Close()
+}
+
+// oracleSequence returns the name of the sequence of the table IDs.
+func oracleSequence(table string) string {
+	return table + "_seq"
+}
+
+// oracleNamed puts the named parameters of the query in upper case, to
+// match the fields of the Oracle mapper.
+func oracleNamed(query string) string {
+	return oracleNamedParam.ReplaceAllStringFunc(query, strings.ToUpper)
+}
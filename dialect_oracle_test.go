@@ -0,0 +1,110 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Oracle_URL(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		Dialect:  "oracle",
+		Database: "ORCLPDB1",
+		Host:     "db",
+		User:     "scott",
+		Password: "ti@ger",
+	}
+	r.NoError(cd.Finalize())
+	r.Equal(portOracle, cd.Port)
+
+	o := &oracle{commonDialect: commonDialect{ConnectionDetails: cd}}
+	r.Equal("oracle://scott:ti%40ger@db:1521/ORCLPDB1", o.URL())
+
+	_, err := newOracle(cd)
+	r.Error(err)
+}
+
+func Test_Oracle_Quotable(t *testing.T) {
+	r := require.New(t)
+	o := &oracle{}
+
+	r.Equal(`"USERS"`, o.Quote("users"))
+	r.Equal(`"BILLING"."INVOICES"`, o.Quote("billing.invoices"))
+	r.Equal(`"BILLING"."INVOICES"`, o.Quote(`"billing"."invoices"`))
+}
+
+func Test_Oracle_TranslateSQL(t *testing.T) {
+	r := require.New(t)
+	o := &oracle{translateCache: map[string]string{}}
+
+	for in, out := range map[string]string{
+		"SELECT users.id FROM users AS users WHERE id = ? FETCH FIRST 1 ROWS ONLY":             "SELECT users.id FROM users users WHERE id = :arg1 FETCH FIRST 1 ROWS ONLY",
+		"SELECT a.id FROM a AS a, b AS b JOIN c AS c ON c.id = a.id WHERE a.x = ? AND b.y = ?": "SELECT a.id FROM a a, b b JOIN c c ON c.id = a.id WHERE a.x = :arg1 AND b.y = :arg2",
+		"DELETE FROM family.members AS family_members WHERE id = ?":                            "DELETE FROM family.members family_members WHERE id = :arg1",
+		"SELECT EXISTS (SELECT users.id FROM users AS users WHERE id = ?)":                     "SELECT CASE WHEN EXISTS (SELECT users.id FROM users users WHERE id = :arg1) THEN 1 ELSE 0 END FROM DUAL",
+		"SELECT COUNT(*) AS row_count FROM (SELECT users.id FROM users AS users) a":            "SELECT COUNT(*) AS row_count FROM (SELECT users.id FROM users users) a",
+	} {
+		r.Equal(out, o.TranslateSQL(in))
+		r.Equal(out, o.TranslateSQL(in), "cached translation")
+	}
+}
+
+func Test_Oracle_Named(t *testing.T) {
+	r := require.New(t)
+	r.Equal(`UPDATE "USERS" users SET "NAME" = :NAME WHERE users.id = :ID`, oracleNamed(`UPDATE "USERS" users SET "NAME" = :name WHERE users.id = :id`))
+}
+
+func Test_Oracle_SplitScript(t *testing.T) {
+	r := require.New(t)
+	o := &oracle{}
+
+	r.Equal([]string{"CREATE TABLE a (id NUMBER)", "CREATE INDEX a_idx ON a (id)"}, o.SplitScript("CREATE TABLE a (id NUMBER);\nCREATE INDEX a_idx ON a (id);\n"))
+	r.Equal([]string{"CREATE TABLE a (id NUMBER)", "BEGIN\n  NULL;\nEND;"}, o.SplitScript("CREATE TABLE a (id NUMBER)\n/\nBEGIN\n  NULL;\nEND;\n/\n"))
+}
+
+func Test_Oracle_FizzTranslator(t *testing.T) {
+	r := require.New(t)
+	o := &oracle{}
+
+	s, err := fizz.AString(`create_table("users") {
+	t.Column("id", "integer", {primary: true})
+	t.Column("email", "string", {size: 100})
+	t.Column("bio", "text", {null: true})
+	t.Column("admin", "bool", {default: 0})
+	t.Index("email", {unique: true})
+}`, o.FizzTranslator())
+	r.NoError(err)
+	r.Equal(`CREATE SEQUENCE "USERS_SEQ"
+/
+CREATE TABLE "USERS" (
+"ID" NUMBER(10) NOT NULL,
+PRIMARY KEY ("ID"),
+"EMAIL" VARCHAR2(100) NOT NULL,
+"BIO" CLOB,
+"ADMIN" NUMBER(1) DEFAULT '0' NOT NULL,
+"CREATED_AT" TIMESTAMP NOT NULL,
+"UPDATED_AT" TIMESTAMP NOT NULL
+)
+/
+CREATE UNIQUE INDEX "USERS_EMAIL_IDX" ON "USERS" ("EMAIL")
+/`, s)
+
+	stmts := o.SplitScript(s)
+	r.Len(stmts, 3)
+
+	s, err = fizz.AString(`drop_table("users")`, o.FizzTranslator())
+	r.NoError(err)
+	stmts = o.SplitScript(s)
+	r.Len(stmts, 2)
+	r.Equal(`DROP TABLE "USERS"`, stmts[0])
+	r.Contains(stmts[1], `EXECUTE IMMEDIATE 'DROP SEQUENCE "USERS_SEQ"';`)
+	r.Contains(stmts[1], "SQLCODE != -2289")
+
+	s, err = fizz.AString(`add_foreign_key("posts", "user_id", {"users": ["id"]}, {"name": "posts_user_fk", "on_delete": "CASCADE", "on_update": "CASCADE"})`, o.FizzTranslator())
+	r.NoError(err)
+	r.Equal(`ALTER TABLE "POSTS" ADD CONSTRAINT "POSTS_USER_FK" FOREIGN KEY ("USER_ID") REFERENCES "USERS" ("ID") ON DELETE CASCADE
+/`, s)
+}
@@ -0,0 +1,255 @@
+package pop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobuffalo/fizz"
+)
+
+// oracleTranslator translates fizz migrations to Oracle. Each statement
+// is followed by a line holding a single slash, so the migration can be
+// split and run one statement at a time.
+type oracleTranslator struct{}
+
+var _ fizz.Translator = &oracleTranslator{}
+
+func newOracleTranslator() *oracleTranslator {
+	return &oracleTranslator{}
+}
+
+func (oracleTranslator) Name() string {
+	return nameOracle
+}
+
+func (t *oracleTranslator) CreateTable(table fizz.Table) (string, error) {
+	var stmts []string
+	var cols []string
+	for _, c := range table.Columns {
+		if c.Primary {
+			switch c.ColType {
+			case "string", "uuid":
+			case "integer", "INT", "int", "bigint", "BIGINT":
+				stmts = append(stmts, fmt.Sprintf("CREATE SEQUENCE %s", t.quote(oracleSequence(table.Name))))
+			default:
+				return "", fmt.Errorf("can not use %s as a primary key", c.ColType)
+			}
+		}
+		cols = append(cols, t.buildColumn(c))
+		if c.Primary {
+			cols = append(cols, fmt.Sprintf("PRIMARY KEY (%s)", t.quote(c.Name)))
+		}
+	}
+
+	if pks := table.PrimaryKeys(); len(pks) > 1 {
+		cols = append(cols, fmt.Sprintf("PRIMARY KEY (%s)", t.quoteAll(pks)))
+	}
+
+	for _, fk := range table.ForeignKeys {
+		cols = append(cols, t.buildForeignKey(fk))
+	}
+
+	stmts = append(stmts, fmt.Sprintf("CREATE TABLE %s (\n%s\n)", t.quote(table.Name), strings.Join(cols, ",\n")))
+
+	for _, i := range table.Indexes {
+		s, err := t.AddIndex(fizz.Table{
+			Name:    table.Name,
+			Indexes: []fizz.Index{i},
+		})
+		if err != nil {
+			return "", err
+		}
+		stmts = append(stmts, strings.TrimSuffix(s, "\n/"))
+	}
+
+	return oracleScript(stmts...), nil
+}
+
+func (t *oracleTranslator) DropTable(table fizz.Table) (string, error) {
+	return oracleScript(
+		fmt.Sprintf("DROP TABLE %s", t.quote(table.Name)),
+		oracleIgnoreMissingSequence(fmt.Sprintf("DROP SEQUENCE %s", t.quote(oracleSequence(table.Name)))),
+	), nil
+}
+
+func (t *oracleTranslator) RenameTable(tables []fizz.Table) (string, error) {
+	if len(tables) < 2 {
+		return "", fmt.Errorf("not enough table names supplied")
+	}
+	return oracleScript(
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", t.quote(tables[0].Name), t.quote(tables[1].Name)),
+		oracleIgnoreMissingSequence(fmt.Sprintf("RENAME %s TO %s", t.quote(oracleSequence(tables[0].Name)), t.quote(oracleSequence(tables[1].Name)))),
+	), nil
+}
+
+func (t *oracleTranslator) AddColumn(table fizz.Table) (string, error) {
+	if len(table.Columns) == 0 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	return oracleScript(fmt.Sprintf("ALTER TABLE %s ADD (%s)", t.quote(table.Name), t.buildColumn(table.Columns[0]))), nil
+}
+
+func (t *oracleTranslator) ChangeColumn(table fizz.Table) (string, error) {
+	if len(table.Columns) == 0 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	c := table.Columns[0]
+	s := fmt.Sprintf("%s %s", t.quote(c.Name), t.colType(c))
+	if d := t.buildDefault(c); d != "" {
+		s += " " + d
+	}
+	if c.Options["null"] == nil {
+		s += " NOT NULL"
+	} else {
+		s += " NULL"
+	}
+	return oracleScript(fmt.Sprintf("ALTER TABLE %s MODIFY (%s)", t.quote(table.Name), s)), nil
+}
+
+func (t *oracleTranslator) DropColumn(table fizz.Table) (string, error) {
+	if len(table.Columns) == 0 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	return oracleScript(fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", t.quote(table.Name), t.quote(table.Columns[0].Name))), nil
+}
+
+func (t *oracleTranslator) RenameColumn(table fizz.Table) (string, error) {
+	if len(table.Columns) < 2 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	return oracleScript(fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s", t.quote(table.Name), t.quote(table.Columns[0].Name), t.quote(table.Columns[1].Name))), nil
+}
+
+func (t *oracleTranslator) AddIndex(table fizz.Table) (string, error) {
+	if len(table.Indexes) == 0 {
+		return "", fmt.Errorf("not enough indexes supplied")
+	}
+	i := table.Indexes[0]
+	create := "CREATE INDEX"
+	if i.Unique {
+		create = "CREATE UNIQUE INDEX"
+	}
+	return oracleScript(fmt.Sprintf("%s %s ON %s (%s)", create, t.quote(i.Name), t.quote(table.Name), t.quoteAll(i.Columns))), nil
+}
+
+func (t *oracleTranslator) DropIndex(table fizz.Table) (string, error) {
+	if len(table.Indexes) == 0 {
+		return "", fmt.Errorf("not enough indexes supplied")
+	}
+	return oracleScript(fmt.Sprintf("DROP INDEX %s", t.quote(table.Indexes[0].Name))), nil
+}
+
+func (t *oracleTranslator) RenameIndex(table fizz.Table) (string, error) {
+	if len(table.Indexes) < 2 {
+		return "", fmt.Errorf("not enough indexes supplied")
+	}
+	return oracleScript(fmt.Sprintf("ALTER INDEX %s RENAME TO %s", t.quote(table.Indexes[0].Name), t.quote(table.Indexes[1].Name))), nil
+}
+
+func (t *oracleTranslator) AddForeignKey(table fizz.Table) (string, error) {
+	if len(table.ForeignKeys) == 0 {
+		return "", fmt.Errorf("not enough foreign keys supplied")
+	}
+	return oracleScript(fmt.Sprintf("ALTER TABLE %s ADD %s", t.quote(table.Name), t.buildForeignKey(table.ForeignKeys[0]))), nil
+}
+
+func (t *oracleTranslator) DropForeignKey(table fizz.Table) (string, error) {
+	if len(table.ForeignKeys) == 0 {
+		return "", fmt.Errorf("not enough foreign keys supplied")
+	}
+	return oracleScript(fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", t.quote(table.Name), t.quote(table.ForeignKeys[0].Name))), nil
+}
+
+func (t *oracleTranslator) buildColumn(c fizz.Column) string {
+	s := fmt.Sprintf("%s %s", t.quote(c.Name), t.colType(c))
+	if d := t.buildDefault(c); d != "" {
+		s += " " + d
+	}
+	if c.Options["null"] == nil || c.Primary {
+		s += " NOT NULL"
+	}
+	return s
+}
+
+func (t *oracleTranslator) buildDefault(c fizz.Column) string {
+	if c.Options["default"] != nil {
+		return fmt.Sprintf("DEFAULT '%v'", c.Options["default"])
+	}
+	if c.Options["default_raw"] != nil {
+		return fmt.Sprintf("DEFAULT %s", c.Options["default_raw"])
+	}
+	return ""
+}
+
+// buildForeignKey builds the constraint of the foreign key. Oracle has no
+// ON UPDATE actions, only ON DELETE ones are kept.
+func (t *oracleTranslator) buildForeignKey(fk fizz.ForeignKey) string {
+	s := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", t.quote(fk.Name), t.quote(fk.Column), t.quote(fk.References.Table), t.quoteAll(fk.References.Columns))
+	if onDelete, ok := fk.Options["on_delete"]; ok {
+		s += fmt.Sprintf(" ON DELETE %s", onDelete)
+	}
+	return s
+}
+
+func (t *oracleTranslator) colType(c fizz.Column) string {
+	switch c.ColType {
+	case "string":
+		s := "255"
+		if c.Options["size"] != nil {
+			s = fmt.Sprintf("%d", c.Options["size"])
+		}
+		return fmt.Sprintf("VARCHAR2(%s)", s)
+	case "text", "json", "jsonb":
+		return "CLOB"
+	case "uuid":
+		return "VARCHAR2(36)"
+	case "integer", "INT", "int":
+		return "NUMBER(10)"
+	case "bigint", "BIGINT":
+		return "NUMBER(19)"
+	case "smallint":
+		return "NUMBER(5)"
+	case "bool", "boolean":
+		return "NUMBER(1)"
+	case "timestamp", "time", "datetime":
+		return "TIMESTAMP"
+	case "date":
+		return "DATE"
+	case "blob", "[]byte":
+		return "BLOB"
+	case "float", "decimal":
+		if c.Options["precision"] != nil {
+			precision := c.Options["precision"]
+			if c.Options["scale"] != nil {
+				return fmt.Sprintf("NUMBER(%d,%d)", precision, c.Options["scale"])
+			}
+			return fmt.Sprintf("NUMBER(%d)", precision)
+		}
+		return "NUMBER"
+	default:
+		return c.ColType
+	}
+}
+
+func (t *oracleTranslator) quote(name string) string {
+	return oracleQuote(name)
+}
+
+func (t *oracleTranslator) quoteAll(names []string) string {
+	xs := make([]string, len(names))
+	for i, n := range names {
+		xs[i] = t.quote(n)
+	}
+	return strings.Join(xs, ", ")
+}
+
+// oracleScript ends each statement with a line holding a single slash.
+func oracleScript(stmts ...string) string {
+	return strings.Join(stmts, "\n/\n") + "\n/"
+}
+
+// oracleIgnoreMissingSequence wraps the statement in a PL/SQL block
+// ignoring the error raised when the sequence does not exist.
+func oracleIgnoreMissingSequence(stmt string) string {
+	return fmt.Sprintf("BEGIN\n  EXECUTE IMMEDIATE '%s';\nEXCEPTION\n  WHEN OTHERS THEN\n    IF SQLCODE != -2289 THEN\n      RAISE;\n    END IF;\nEND;", strings.ReplaceAll(stmt, "'", "''"))
+}
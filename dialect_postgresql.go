@@ -1,11 +1,15 @@
 package pop
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"os/exec"
+	"regexp"
+	"strings"
 	"sync"
 
 	"github.com/WilliamNHarvey/pop/v6/columns"
@@ -14,7 +18,8 @@ import (
 	"github.com/gobuffalo/fizz"
 	"github.com/gobuffalo/fizz/translators"
 	"github.com/jackc/pgconn"
-	_ "github.com/jackc/pgx/v4/stdlib" // Load pgx driver
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/stdlib" // Load pgx driver
 	"github.com/jmoiron/sqlx"
 )
 
@@ -47,10 +52,71 @@ func (p *postgresql) DefaultDriver() string {
 	return "pgx"
 }
 
+func (p *postgresql) Capabilities() capabilities {
+	return supportsReturning | supportsSavepoints | supportsRowLocks | supportsSkipLocked | transactionalDDL | ddlInTransactions | booleanType | supportsILike
+}
+
 func (p *postgresql) Details() *ConnectionDetails {
 	return p.ConnectionDetails
 }
 
+// LockConflict reports whether err is a deadlock or a lock timeout.
+func (p *postgresql) LockConflict(err error) bool {
+	var pgerr *pgconn.PgError
+	return errors.As(err, &pgerr) && (pgerr.Code == "40P01" || pgerr.Code == "55P03")
+}
+
+// DiagnoseLocks reports the open transactions and the ones blocking them,
+// after the processes of the deadlock.
+func (p *postgresql) DiagnoseLocks(s store, err error) (string, error) {
+	sessions := []struct {
+		PID       int    `db:"pid"`
+		State     string `db:"state"`
+		Seconds   int    `db:"seconds"`
+		BlockedBy string `db:"blocked_by"`
+		Query     string `db:"query"`
+	}{}
+	query := `SELECT pid, coalesce(state, '') AS state, extract(epoch FROM now() - xact_start)::int AS seconds,
+	array_to_string(pg_blocking_pids(pid), ', ') AS blocked_by, coalesce(query, '') AS query
+	FROM pg_stat_activity
+	WHERE xact_start IS NOT NULL AND pid <> pg_backend_pid() AND backend_type = 'client backend'
+	ORDER BY xact_start`
+	if err := s.Select(&sessions, query); err != nil {
+		return "", err
+	}
+
+	var lines []string
+	var pgerr *pgconn.PgError
+	if errors.As(err, &pgerr) && pgerr.Detail != "" {
+		lines = append(lines, pgerr.Detail)
+	}
+	lines = append(lines, "open transactions:")
+	for _, a := range sessions {
+		line := fmt.Sprintf("pid %d %s for %ds", a.PID, a.State, a.Seconds)
+		if a.BlockedBy != "" {
+			line += fmt.Sprintf(", blocked by %s", a.BlockedBy)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", line, a.Query))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// ExecBatch sends the statements in a pgx batch, run by the server in an
+// implicit transaction.
+func (p *postgresql) ExecBatch(c *Connection, stmts []batchStatement) ([]BatchResult, error) {
+	return execPgxBatch(c, stmts)
+}
+
+// ColumnDefaults reads the defaults of the columns from information_schema.
+func (p *postgresql) ColumnDefaults(ctx context.Context, s store, table string) (map[string]*string, error) {
+	return pgColumnDefaults(ctx, s, table)
+}
+
+// Schema reads the tables of the current schema from pg_catalog.
+func (p *postgresql) Schema(ctx context.Context, s store) ([]SchemaTable, error) {
+	return pgSchema(ctx, s)
+}
+
 func (p *postgresql) Create(c *Connection, model *Model, cols columns.Columns) error {
 	keyType, err := model.PrimaryKeyType()
 	if err != nil {
@@ -68,6 +134,10 @@ func (p *postgresql) Create(c *Connection, model *Model, cols columns.Columns) e
 		} else {
 			query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", p.Quote(model.TableName()), model.IDField())
 		}
+		query, err = beforeNamedExec(Insert, model, query)
+		if err != nil {
+			return err
+		}
 		txlog(logging.SQL, c, query, model.Value)
 		rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
 		if err != nil {
@@ -98,12 +168,24 @@ func (p *postgresql) Update(c *Connection, model *Model, cols columns.Columns) e
 }
 
 func (p *postgresql) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
-	return genericUpdateQuery(c, model, cols, p, query, sqlx.DOLLAR)
+	return genericUpdateQuery(c, model, cols, query)
+}
+
+func (p *postgresql) updateQuerySQL(model *Model, cols columns.Columns, query Query) (string, []interface{}, error) {
+	return genericUpdateQuerySQL(model, cols, p, query, sqlx.DOLLAR)
+}
+
+func (p *postgresql) Upsert(c *Connection, model *Model, cols *columns.WriteableColumns, conflict, update []string) (bool, error) {
+	read, err := genericUpsert(c, model, cols, conflict, update, p)
+	if err != nil {
+		return false, fmt.Errorf("postgres upsert: %w", err)
+	}
+	return read, nil
 }
 
 func (p *postgresql) Destroy(c *Connection, model *Model) error {
 	stmt := p.TranslateSQL(fmt.Sprintf("DELETE FROM %s AS %s WHERE %s", p.Quote(model.TableName()), model.Alias(), model.WhereID()))
-	_, err := genericExec(c, stmt, model.ID())
+	_, err := genericExec(c, Delete, model, stmt, model.idArgs()...)
 	if err != nil {
 		return err
 	}
@@ -186,6 +268,20 @@ func (p *postgresql) MigrationURL() string {
 	return p.URL()
 }
 
+func (p *postgresql) rowChecksumSQL(cols []string) string {
+	return fmt.Sprintf("md5(CAST(ROW(%s) AS TEXT))", strings.Join(cols, ", "))
+}
+
+// StrictSettings returns standard_conforming_strings, the backslashes of the
+// string literals being taken literally.
+func (p *postgresql) StrictSettings() map[string]string {
+	return map[string]string{"standard_conforming_strings": "on"}
+}
+
+func (p *postgresql) SessionStatement(name, value string) string {
+	return fmt.Sprintf("SET %s = %s", name, value)
+}
+
 func (p *postgresql) TranslateSQL(sql string) string {
 	defer p.mu.Unlock()
 	p.mu.Lock()
@@ -256,6 +352,189 @@ func urlParserPostgreSQL(cd *ConnectionDetails) error {
 	return nil
 }
 
+// execPgxBatch sends the statements in a single pgx batch, on a connection
+// of the pool of c. The server runs them in an implicit transaction, the
+// first error rolling them all back. The transactions of database/sql do
+// not give access to their pgx connection: in one, the statements are run
+// one at a time.
+func execPgxBatch(c *Connection, stmts []batchStatement) ([]BatchResult, error) {
+	if c.TX != nil {
+		return nil, errBatchFallback
+	}
+	ctx := c.Context()
+	conn, err := rawConn(ctx, c.Store)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var results []BatchResult
+	err = conn.Raw(func(dc interface{}) error {
+		sc, ok := dc.(*stdlib.Conn)
+		if !ok {
+			return errBatchFallback
+		}
+		batch := &pgx.Batch{}
+		for _, s := range stmts {
+			batch.Queue(s.query, s.args...)
+		}
+		batchLog(c, stmts)
+
+		br := sc.Conn().SendBatch(ctx, batch)
+		defer br.Close()
+		results = make([]BatchResult, len(stmts))
+		for i := range stmts {
+			tag, err := br.Exec()
+			if err != nil {
+				return &BatchError{Index: i, Err: err}
+			}
+			results[i] = BatchResult{RowsAffected: tag.RowsAffected()}
+		}
+		return br.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// pgCasts matches the casts PostgreSQL and CockroachDB append to the
+// defaults, e.g. 'draft'::character varying.
+var pgCasts = regexp.MustCompile(`(:{2,3}[A-Za-z_][A-Za-z0-9_ ]*(\([0-9, ]*\))?(\[\])?)+$`)
+
+// pgColumnDefaults reads the defaults of the columns of the table, in the
+// current schema unless the table is qualified, without their casts.
+func pgColumnDefaults(ctx context.Context, s store, table string) (map[string]*string, error) {
+	schema := "current_schema()"
+	args := []interface{}{table}
+	if parts := strings.SplitN(table, ".", 2); len(parts) == 2 {
+		schema = "$2"
+		args = []interface{}{parts[1], parts[0]}
+	}
+	query := fmt.Sprintf(`SELECT column_name AS name, column_default AS def
+	FROM information_schema.columns WHERE table_schema = %s AND table_name = $1`, schema)
+
+	rows := []struct {
+		Name    string  `db:"name"`
+		Default *string `db:"def"`
+	}{}
+	if err := s.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	defs := make(map[string]*string, len(rows))
+	for _, r := range rows {
+		if r.Default != nil && !strings.HasSuffix(*r.Default, ")") {
+			def := pgCasts.ReplaceAllString(*r.Default, "")
+			r.Default = &def
+		}
+		defs[strings.ToLower(r.Name)] = columnDefault(r.Default)
+	}
+	return defs, nil
+}
+
+const pgSchemaColumns = `SELECT cl.relname AS table_name, a.attname AS name, format_type(a.atttypid, a.atttypmod) AS type,
+	NOT a.attnotnull AS nullable, pg_get_expr(d.adbin, d.adrelid) AS def
+	FROM pg_attribute a JOIN pg_class cl ON cl.oid = a.attrelid JOIN pg_namespace n ON n.oid = cl.relnamespace
+	LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+	WHERE n.nspname = current_schema() AND cl.relkind = 'r' AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY cl.relname, a.attnum`
+
+const pgSchemaIndexes = `SELECT t.relname AS table_name, i.relname AS name, ix.indisunique AS uniq, ix.indisprimary AS pk,
+	array_to_string(array(SELECT a.attname FROM unnest(ix.indkey::int2[]) WITH ORDINALITY k(attnum, n)
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum ORDER BY k.n), ',') AS columns
+	FROM pg_index ix JOIN pg_class t ON t.oid = ix.indrelid JOIN pg_class i ON i.oid = ix.indexrelid
+	JOIN pg_namespace n ON n.oid = t.relnamespace
+	WHERE n.nspname = current_schema() AND t.relkind = 'r'
+	ORDER BY t.relname, i.relname`
+
+const pgIndexUsage = `SELECT relname AS table_name, indexrelname AS index_name, idx_scan AS reads
+	FROM pg_stat_user_indexes WHERE schemaname = current_schema()`
+
+// IndexUsage reads the scans of the indexes from pg_stat_user_indexes.
+func (p *postgresql) IndexUsage(ctx context.Context, s store) ([]IndexUsage, error) {
+	usage := []IndexUsage{}
+	err := s.SelectContext(ctx, &usage, pgIndexUsage)
+	return usage, err
+}
+
+const pgSchemaForeignKeys = `SELECT cl.relname AS table_name, co.conname AS name, rf.relname AS ref_table,
+	array_to_string(array(SELECT a.attname FROM unnest(co.conkey) WITH ORDINALITY k(attnum, n)
+		JOIN pg_attribute a ON a.attrelid = co.conrelid AND a.attnum = k.attnum ORDER BY k.n), ',') AS columns,
+	array_to_string(array(SELECT a.attname FROM unnest(co.confkey) WITH ORDINALITY k(attnum, n)
+		JOIN pg_attribute a ON a.attrelid = co.confrelid AND a.attnum = k.attnum ORDER BY k.n), ',') AS ref_columns
+	FROM pg_constraint co JOIN pg_class cl ON cl.oid = co.conrelid JOIN pg_class rf ON rf.oid = co.confrelid
+	JOIN pg_namespace n ON n.oid = cl.relnamespace
+	WHERE co.contype = 'f' AND n.nspname = current_schema()
+	ORDER BY cl.relname, co.conname`
+
+// pgSchema reads the tables of the current schema, their columns, foreign
+// keys and indexes from pg_catalog.
+func pgSchema(ctx context.Context, s store) ([]SchemaTable, error) {
+	cols := []struct {
+		Table    string  `db:"table_name"`
+		Name     string  `db:"name"`
+		Type     string  `db:"type"`
+		Nullable bool    `db:"nullable"`
+		Default  *string `db:"def"`
+	}{}
+	if err := s.SelectContext(ctx, &cols, pgSchemaColumns); err != nil {
+		return nil, err
+	}
+	b := newSchemaBuilder()
+	for _, c := range cols {
+		if c.Default != nil && !strings.HasSuffix(*c.Default, ")") {
+			def := pgCasts.ReplaceAllString(*c.Default, "")
+			c.Default = &def
+		}
+		b.addColumn(c.Table, SchemaColumn{Name: c.Name, Type: c.Type, Nullable: c.Nullable, Default: columnDefault(c.Default)})
+	}
+
+	idxs := []struct {
+		Table   string `db:"table_name"`
+		Name    string `db:"name"`
+		Unique  bool   `db:"uniq"`
+		PK      bool   `db:"pk"`
+		Columns string `db:"columns"`
+	}{}
+	if err := s.SelectContext(ctx, &idxs, pgSchemaIndexes); err != nil {
+		return nil, err
+	}
+	for _, idx := range idxs {
+		if idx.PK {
+			b.setPrimaryKey(idx.Table, splitColumns(idx.Columns))
+			continue
+		}
+		for _, c := range splitColumns(idx.Columns) {
+			b.addIndexColumn(idx.Table, idx.Name, idx.Unique, c)
+		}
+	}
+
+	fks := []struct {
+		Table      string `db:"table_name"`
+		Name       string `db:"name"`
+		RefTable   string `db:"ref_table"`
+		Columns    string `db:"columns"`
+		RefColumns string `db:"ref_columns"`
+	}{}
+	if err := s.SelectContext(ctx, &fks, pgSchemaForeignKeys); err != nil {
+		return nil, err
+	}
+	for _, fk := range fks {
+		refs := splitColumns(fk.RefColumns)
+		for i, c := range splitColumns(fk.Columns) {
+			ref := ""
+			if i < len(refs) {
+				ref = refs[i]
+			}
+			b.addForeignKeyColumn(fk.Table, fk.Name, fk.Name, fk.RefTable, c, ref)
+		}
+	}
+	return b.schema(), nil
+}
+
 func finalizerPostgreSQL(cd *ConnectionDetails) {
 	cd.Port = defaults.String(cd.Port, portPostgreSQL)
 }
@@ -1,10 +1,13 @@
 package pop
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/user"
 	"testing"
 
+	"github.com/jackc/pgconn"
 	"github.com/stretchr/testify/require"
 )
 
@@ -127,3 +130,22 @@ func Test_PostgreSQL_Quotable(t *testing.T) {
 	r.Equal(`"schema"."table_name"`, p.Quote("schema.table_name"))
 	r.Equal(`"schema"."table name"`, p.Quote(`"schema"."table name"`))
 }
+
+func Test_PostgreSQL_LockConflict(t *testing.T) {
+	r := require.New(t)
+	p := &postgresql{}
+
+	r.True(p.LockConflict(fmt.Errorf("failed: %w", &pgconn.PgError{Code: "40P01"})))
+	r.True(p.LockConflict(&pgconn.PgError{Code: "55P03"}))
+	r.False(p.LockConflict(&pgconn.PgError{Code: "23505"}))
+	r.False(p.LockConflict(errors.New("deadlock detected")))
+}
+
+func Test_PostgreSQL_pgCasts(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("'draft'", pgCasts.ReplaceAllString("'draft'::character varying", ""))
+	r.Equal("'{}'", pgCasts.ReplaceAllString("'{}'::text[]", ""))
+	r.Equal("'draft'", pgCasts.ReplaceAllString("'draft':::STRING", ""))
+	r.Equal("'1.5'", pgCasts.ReplaceAllString("'1.5'::numeric(10, 2)", ""))
+}
@@ -0,0 +1,378 @@
+package pop
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/gobuffalo/fizz"
+	"github.com/gofrs/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const nameSpanner = "spanner"
+
+func init() {
+	AvailableDialects = append(AvailableDialects, nameSpanner)
+	urlParser[nameSpanner] = urlParserSpanner
+	newConnection[nameSpanner] = newSpanner
+	sqlx.BindDriver(nameSpanner, sqlx.QUESTION)
+}
+
+var _ dialect = &spanner{}
+
+// spanner is a dialect for Google Cloud Spanner databases. The driver is
+// not bundled with pop, it must be registered by importing it:
+//
+//	import _ "github.com/googleapis/go-sql-spanner"
+//
+// The database is the path of the Spanner database, e.g.
+// "projects/my-project/instances/my-instance/databases/my-db". The host,
+// if any, is the one of the emulator.
+//
+// pop writes with DML statements rather than mutations, so the written
+// rows can be read back in the same transaction. SpannerBatchDML trades
+// that for fewer round trips. Spanner has no auto-incremented IDs: models
+// use UUID or string IDs, or int IDs tagged no_auto_increment.
+//
+// With the commit_timestamps option, the created_at and updated_at columns
+// are set to the commit timestamp of the transaction, and the migrations
+// allow them to.
+type spanner struct {
+	commonDialect
+}
+
+var (
+	spannerTimestampParam = regexp.MustCompile(`:(created_at|updated_at)\b`)
+	spannerDeleteAll      = regexp.MustCompile(`(?is)^DELETE FROM \S+( AS \S+)?$`)
+	spannerStatementEnd   = regexp.MustCompile(`;\s*(?:\n|$)`)
+)
+
+func requireSpanner() error {
+	for _, driverName := range sql.Drivers() {
+		if driverName == nameSpanner {
+			return nil
+		}
+	}
+	return errors.New("spanner driver is not registered, import github.com/googleapis/go-sql-spanner")
+}
+
+func (s *spanner) Name() string {
+	return nameSpanner
+}
+
+func (s *spanner) DefaultDriver() string {
+	return nameSpanner
+}
+
+// Capabilities has no ddlInTransactions: Spanner changes the schema outside
+// of the transactions.
+func (s *spanner) Capabilities() capabilities {
+	return booleanType
+}
+
+func (s *spanner) Details() *ConnectionDetails {
+	return s.ConnectionDetails
+}
+
+// URL returns the DSN of go-sql-spanner: the database path, prefixed by
+// the emulator host if any, followed by the options separated with ";".
+func (s *spanner) URL() string {
+	c := s.ConnectionDetails
+	dsn := c.Database
+	if c.Host != "" {
+		host := c.Host
+		if c.Port != "" {
+			host += ":" + c.Port
+		}
+		dsn = host + "/" + dsn
+	}
+	for _, opt := range strings.Split(c.OptionsString(""), "&") {
+		// commit_timestamps is a pop option, unknown to the driver
+		if opt != "" && !strings.HasPrefix(opt, "commit_timestamps=") {
+			dsn += ";" + opt
+		}
+	}
+	return dsn
+}
+
+func (s *spanner) MigrationURL() string {
+	return s.URL()
+}
+
+func (spanner) Quote(key string) string {
+	parts := strings.Split(key, ".")
+
+	for i, part := range parts {
+		part = strings.Trim(part, "`")
+		part = strings.TrimSpace(part)
+
+		parts[i] = fmt.Sprintf("`%v`", part)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+func (s *spanner) Create(c *Connection, model *Model, cols columns.Columns) error {
+	keyType, err := model.PrimaryKeyType()
+	if err != nil {
+		return err
+	}
+	switch keyType {
+	case "int", "int64":
+		if model.UsingAutoIncrement() {
+			return fmt.Errorf("spanner create: %s has no auto-incremented IDs, use UUID IDs or tag the ID no_auto_increment", model.TableName())
+		}
+	case "UUID":
+		if model.ID() == emptyUUID {
+			u, err := uuid.NewV4()
+			if err != nil {
+				return err
+			}
+			model.setID(u)
+		}
+	case "string":
+		if model.ID() == "" {
+			return fmt.Errorf("missing ID value")
+		}
+	case compositeKeyType:
+		// the fields of the key are written as the other ones
+	default:
+		return fmt.Errorf("can not use %s as a primary key type!", keyType)
+	}
+
+	w := cols.Writeable()
+	if keyType != compositeKeyType {
+		w.Add(model.IDField())
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", s.Quote(model.TableName()), w.QuotedString(s), s.namedWrite(w.SymbolizedString()))
+	query, err = beforeNamedExec(Insert, model, query)
+	if err != nil {
+		return err
+	}
+	txlog(logging.SQL, c, query, model.Value)
+	if _, err := c.Store.NamedExecContext(model.ctx, query, model.Value); err != nil {
+		return fmt.Errorf("spanner create: %w", err)
+	}
+	return nil
+}
+
+func (s *spanner) Update(c *Connection, model *Model, cols columns.Columns) error {
+	stmt := fmt.Sprintf("UPDATE %s AS %s SET %s WHERE %s", s.Quote(model.TableName()), model.Alias(), s.namedWrite(cols.Writeable().QuotedUpdateString(s)), model.whereNamedUpdate(cols))
+	stmt, err := beforeNamedExec(Update, model, stmt)
+	if err != nil {
+		return err
+	}
+	txlog(logging.SQL, c, stmt, model.ID())
+	res, err := c.Store.NamedExecContext(model.ctx, stmt, model.Value)
+	if err != nil {
+		return fmt.Errorf("spanner update: %w", err)
+	}
+	return checkStale(model, cols, res)
+}
+
+func (s *spanner) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
+	n, err := genericUpdateQuery(c, model, cols, query)
+	if err != nil {
+		return n, fmt.Errorf("spanner update query: %w", err)
+	}
+	return n, nil
+}
+
+func (s *spanner) updateQuerySQL(model *Model, cols columns.Columns, query Query) (string, []interface{}, error) {
+	return genericUpdateQuerySQL(model, cols, s, query, sqlx.QUESTION)
+}
+
+func (s *spanner) Destroy(c *Connection, model *Model) error {
+	if err := genericDestroy(c, model, s); err != nil {
+		return fmt.Errorf("spanner destroy: %w", err)
+	}
+	return nil
+}
+
+func (s *spanner) Delete(c *Connection, model *Model, query Query) error {
+	return genericDelete(c, model, query)
+}
+
+func (s *spanner) SelectOne(c *Connection, model *Model, query Query) error {
+	return genericSelectOne(c, model, query)
+}
+
+func (s *spanner) SelectMany(c *Connection, models *Model, query Query) error {
+	return genericSelectMany(c, models, query)
+}
+
+// CreateDB is not supported: Spanner databases are created with gcloud or
+// the admin API.
+func (s *spanner) CreateDB() error {
+	return fmt.Errorf("could not create Spanner database %s: not supported", s.ConnectionDetails.Database)
+}
+
+// DropDB is not supported: Spanner databases are dropped with gcloud or
+// the admin API.
+func (s *spanner) DropDB() error {
+	return fmt.Errorf("could not drop Spanner database %s: not supported", s.ConnectionDetails.Database)
+}
+
+// TranslateSQL adds the WHERE clause Spanner requires to the DELETE
+// statements without one.
+func (s *spanner) TranslateSQL(sql string) string {
+	if spannerDeleteAll.MatchString(sql) {
+		sql += " WHERE true"
+	}
+	return sql
+}
+
+// commitTimestamps reports whether the created_at and updated_at columns
+// hold commit timestamps.
+func (s *spanner) commitTimestamps() bool {
+	return s.Details().option("commit_timestamps") == "true"
+}
+
+func (s *spanner) FizzTranslator() fizz.Translator {
+	return &spannerTranslator{commitTimestamps: s.commitTimestamps()}
+}
+
+// DumpSchema is not supported, use `gcloud spanner databases ddl describe`.
+func (s *spanner) DumpSchema(w io.Writer) error {
+	return fmt.Errorf("unable to dump schema for %s: not supported", s.Details().Database)
+}
+
+func (s *spanner) LoadSchema(r io.Reader) error {
+	deets := s.Details()
+
+	db, err := openPotentiallyInstrumentedConnection(s, s.MigrationURL())
+	if err != nil {
+		return fmt.Errorf("unable to load schema for %s: %w", deets.Database, err)
+	}
+	defer db.Close()
+
+	contents, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range s.SplitScript(string(contents)) {
+		if _, err := db.Exec(stmt); err != nil {
+			return fmt.Errorf("unable to load schema for %s: %w", deets.Database, err)
+		}
+	}
+
+	log(logging.Info, "loaded schema for %s", deets.Database)
+	return nil
+}
+
+// SplitScript splits the script on the semicolons ending the lines, as
+// Spanner runs a single DDL statement at a time.
+func (s *spanner) SplitScript(script string) []string {
+	var stmts []string
+	for _, p := range spannerStatementEnd.Split(script, -1) {
+		if p = strings.TrimSpace(p); p != "" {
+			stmts = append(stmts, p)
+		}
+	}
+	return stmts
+}
+
+func (s *spanner) TruncateAll(tx *Connection) error {
+	names := []struct {
+		Name string `db:"table_name"`
+	}{}
+
+	err := tx.RawQuery("SELECT table_name FROM information_schema.tables WHERE table_schema = '' AND table_name <> ?", tx.MigrationTableName()).All(&names)
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		if err := tx.RawQuery(fmt.Sprintf("DELETE FROM %s WHERE true", s.Quote(n.Name))).Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// namedWrite replaces the created_at and updated_at parameters of the
+// written values with the commit timestamp, when enabled.
+func (s *spanner) namedWrite(stmt string) string {
+	if !s.commitTimestamps() {
+		return stmt
+	}
+	return spannerTimestampParam.ReplaceAllString(stmt, "PENDING_COMMIT_TIMESTAMP()")
+}
+
+func newSpanner(deets *ConnectionDetails) (dialect, error) {
+	if err := requireSpanner(); err != nil {
+		return nil, err
+	}
+	cd := &spanner{
+		commonDialect: commonDialect{ConnectionDetails: deets},
+	}
+	return cd, nil
+}
+
+func urlParserSpanner(cd *ConnectionDetails) error {
+	db := strings.TrimPrefix(cd.URL, "spanner://")
+
+	dbparts := strings.SplitN(db, "?", 2)
+	path := dbparts[0]
+	if i := strings.Index(path, "projects/"); i > 0 {
+		hp := strings.Split(strings.TrimSuffix(path[:i], "/"), ":")
+		cd.Host = hp[0]
+		if len(hp) > 1 {
+			cd.Port = hp[1]
+		}
+		path = path[i:]
+	}
+	cd.Database = path
+
+	if len(dbparts) == 2 {
+		for _, kv := range strings.FieldsFunc(dbparts[1], func(r rune) bool { return r == '&' || r == ';' }) {
+			xs := strings.SplitN(kv, "=", 2)
+			if len(xs) == 2 {
+				cd.setOption(xs[0], xs[1])
+			}
+		}
+	}
+	return nil
+}
+
+func newSpannerDriver() (driver.Driver, error) {
+	if err := requireSpanner(); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(nameSpanner, "projects/p/instances/i/databases/newSpannerDriver")
+	if err != nil {
+		return nil, err
+	}
+	return db.Driver(), db.Close()
+}
+
+// SpannerBatchDML runs the DML statements of fn as a single batch, in a
+// transaction. Like mutations, the batch saves round trips, but the rows
+// it writes can not be read back until the batch has run, after fn
+// returns.
+func SpannerBatchDML(c *Connection, fn func(tx *Connection) error) error {
+	if c.Dialect.Name() != nameSpanner {
+		return fmt.Errorf("batch DML is not supported by %s", c.Dialect.Name())
+	}
+	return c.Transaction(func(tx *Connection) error {
+		if _, err := tx.Store.Exec("START BATCH DML"); err != nil {
+			return err
+		}
+		if err := fn(tx); err != nil {
+			if _, abortErr := tx.Store.Exec("ABORT BATCH"); abortErr != nil {
+				log(logging.Error, "could not abort the batch: %v", abortErr)
+			}
+			return err
+		}
+		_, err := tx.Store.Exec("RUN BATCH")
+		return err
+	})
+}
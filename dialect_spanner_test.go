@@ -0,0 +1,106 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/fizz"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ConnectionDetails_Finalize_Spanner_URL(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		URL: "spanner://localhost:9010/projects/p/instances/i/databases/db?autoConfigEmulator=true&commit_timestamps=true",
+	}
+	err := cd.Finalize()
+	r.NoError(err)
+	r.Equal("spanner", cd.Dialect)
+	r.Equal("localhost", cd.Host)
+	r.Equal("9010", cd.Port)
+	r.Equal("projects/p/instances/i/databases/db", cd.Database)
+
+	s := &spanner{commonDialect{ConnectionDetails: cd}}
+	r.Equal("localhost:9010/projects/p/instances/i/databases/db;autoConfigEmulator=true", s.URL())
+	r.True(s.commitTimestamps())
+
+	cd = &ConnectionDetails{URL: "spanner://projects/p/instances/i/databases/db"}
+	r.NoError(cd.Finalize())
+	s = &spanner{commonDialect{ConnectionDetails: cd}}
+	r.Equal("projects/p/instances/i/databases/db", s.URL())
+	r.False(s.commitTimestamps())
+}
+
+func Test_Spanner_Driver_Required(t *testing.T) {
+	r := require.New(t)
+
+	_, err := newSpanner(&ConnectionDetails{Dialect: "spanner", Database: "projects/p/instances/i/databases/db"})
+	r.Error(err)
+}
+
+func Test_Spanner_Quote(t *testing.T) {
+	r := require.New(t)
+	s := spanner{}
+
+	r.Equal("`users`", s.Quote("users"))
+	r.Equal("`albums`.`title`", s.Quote("albums.title"))
+}
+
+func Test_Spanner_TranslateSQL(t *testing.T) {
+	r := require.New(t)
+	s := &spanner{}
+
+	r.Equal("DELETE FROM `users` AS users WHERE true", s.TranslateSQL("DELETE FROM `users` AS users"))
+	r.Equal("DELETE FROM `users` AS users WHERE id = ?", s.TranslateSQL("DELETE FROM `users` AS users WHERE id = ?"))
+	r.Equal("SELECT * FROM users", s.TranslateSQL("SELECT * FROM users"))
+}
+
+func Test_Spanner_CommitTimestamps(t *testing.T) {
+	r := require.New(t)
+
+	s := &spanner{commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	r.Equal(":name, :created_at, :updated_at", s.namedWrite(":name, :created_at, :updated_at"))
+
+	s.ConnectionDetails.Options = map[string]string{"commit_timestamps": "true"}
+	r.Equal(":name, PENDING_COMMIT_TIMESTAMP(), PENDING_COMMIT_TIMESTAMP()", s.namedWrite(":name, :created_at, :updated_at"))
+	r.Equal("`name` = :name, `updated_at` = PENDING_COMMIT_TIMESTAMP()", s.namedWrite("`name` = :name, `updated_at` = :updated_at"))
+}
+
+func Test_Spanner_SplitScript(t *testing.T) {
+	r := require.New(t)
+	s := &spanner{}
+
+	stmts := s.SplitScript("CREATE TABLE `a` (\n`id` STRING(36) NOT NULL\n) PRIMARY KEY (`id`);\nCREATE INDEX `a_idx` ON `a` (`id`);\n")
+	r.Equal([]string{
+		"CREATE TABLE `a` (\n`id` STRING(36) NOT NULL\n) PRIMARY KEY (`id`)",
+		"CREATE INDEX `a_idx` ON `a` (`id`)",
+	}, stmts)
+}
+
+func Test_Spanner_FizzTranslator(t *testing.T) {
+	r := require.New(t)
+	s := &spanner{commonDialect{ConnectionDetails: &ConnectionDetails{
+		Options: map[string]string{"commit_timestamps": "true"},
+	}}}
+
+	out, err := fizz.AString(`create_table("albums", {"interleave_in_parent": "singers", "on_delete": "cascade"}) {
+	t.Column("singer_id", "uuid", {})
+	t.Column("id", "uuid", {})
+	t.Column("title", "string", {"size": 100})
+	t.PrimaryKey("singer_id", "id")
+	t.Index("title", {"unique": true})
+}`, s.FizzTranslator())
+	r.NoError(err)
+	r.Equal("CREATE TABLE `albums` (\n"+
+		"`id` STRING(36) NOT NULL,\n"+
+		"`singer_id` STRING(36) NOT NULL,\n"+
+		"`title` STRING(100) NOT NULL,\n"+
+		"`created_at` TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true),\n"+
+		"`updated_at` TIMESTAMP NOT NULL OPTIONS (allow_commit_timestamp=true)\n"+
+		") PRIMARY KEY (`singer_id`, `id`),\n"+
+		"INTERLEAVE IN PARENT `singers` ON DELETE CASCADE;\n"+
+		"CREATE UNIQUE INDEX `albums_title_idx` ON `albums` (`title`);", out)
+
+	_, err = fizz.AString(`rename_column("albums", "title", "name")`, s.FizzTranslator())
+	r.Error(err)
+}
@@ -0,0 +1,235 @@
+package pop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobuffalo/fizz"
+)
+
+// spannerTranslator translates fizz migrations to the Spanner DDL. Each
+// statement ends with a semicolon and a new line, so the migration can be
+// split and run one statement at a time.
+//
+// Tables are interleaved in their parent with the interleave_in_parent
+// option, and the on_delete option sets the action on the rows of the
+// parent:
+//
+//	create_table("albums", {"interleave_in_parent": "singers", "on_delete": "cascade"}) {
+//		t.Column("singer_id", "uuid", {})
+//		t.Column("id", "uuid", {})
+//		t.PrimaryKey("singer_id", "id")
+//	}
+//
+// Timestamp columns allow commit timestamps with the commit_timestamp
+// option, which is implied for the created_at and updated_at columns when
+// the connection uses commit timestamps.
+type spannerTranslator struct {
+	commitTimestamps bool
+}
+
+var _ fizz.Translator = &spannerTranslator{}
+
+func (spannerTranslator) Name() string {
+	return nameSpanner
+}
+
+func (t *spannerTranslator) CreateTable(table fizz.Table) (string, error) {
+	pks := table.PrimaryKeys()
+	if len(pks) == 0 {
+		return "", fmt.Errorf("table %s needs a primary key", table.Name)
+	}
+
+	var cols []string
+	for _, c := range table.Columns {
+		cols = append(cols, t.buildColumn(c))
+	}
+	for _, fk := range table.ForeignKeys {
+		cols = append(cols, t.buildForeignKey(fk))
+	}
+
+	s := fmt.Sprintf("CREATE TABLE %s (\n%s\n) PRIMARY KEY (%s)", t.quote(table.Name), strings.Join(cols, ",\n"), t.quoteAll(pks))
+	if parent, ok := table.Options["interleave_in_parent"]; ok {
+		s += fmt.Sprintf(",\nINTERLEAVE IN PARENT %s", t.quote(fmt.Sprint(parent)))
+		if onDelete, ok := table.Options["on_delete"]; ok {
+			s += fmt.Sprintf(" ON DELETE %s", strings.ToUpper(fmt.Sprint(onDelete)))
+		}
+	}
+	stmts := []string{s + ";"}
+
+	for _, i := range table.Indexes {
+		s, err := t.AddIndex(fizz.Table{
+			Name:    table.Name,
+			Indexes: []fizz.Index{i},
+		})
+		if err != nil {
+			return "", err
+		}
+		stmts = append(stmts, s)
+	}
+
+	return strings.Join(stmts, "\n"), nil
+}
+
+func (t *spannerTranslator) DropTable(table fizz.Table) (string, error) {
+	return fmt.Sprintf("DROP TABLE %s;", t.quote(table.Name)), nil
+}
+
+func (t *spannerTranslator) RenameTable(tables []fizz.Table) (string, error) {
+	if len(tables) < 2 {
+		return "", fmt.Errorf("not enough table names supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", t.quote(tables[0].Name), t.quote(tables[1].Name)), nil
+}
+
+func (t *spannerTranslator) AddColumn(table fizz.Table) (string, error) {
+	if len(table.Columns) == 0 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s;", t.quote(table.Name), t.buildColumn(table.Columns[0])), nil
+}
+
+func (t *spannerTranslator) ChangeColumn(table fizz.Table) (string, error) {
+	if len(table.Columns) == 0 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s;", t.quote(table.Name), t.buildColumn(table.Columns[0])), nil
+}
+
+func (t *spannerTranslator) DropColumn(table fizz.Table) (string, error) {
+	if len(table.Columns) == 0 {
+		return "", fmt.Errorf("not enough columns supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", t.quote(table.Name), t.quote(table.Columns[0].Name)), nil
+}
+
+// RenameColumn is not supported by Spanner.
+func (t *spannerTranslator) RenameColumn(table fizz.Table) (string, error) {
+	return "", fmt.Errorf("spanner can not rename the columns of %s", table.Name)
+}
+
+func (t *spannerTranslator) AddIndex(table fizz.Table) (string, error) {
+	if len(table.Indexes) == 0 {
+		return "", fmt.Errorf("not enough indexes supplied")
+	}
+	i := table.Indexes[0]
+	create := "CREATE INDEX"
+	if i.Unique {
+		create = "CREATE UNIQUE INDEX"
+	}
+	return fmt.Sprintf("%s %s ON %s (%s);", create, t.quote(i.Name), t.quote(table.Name), t.quoteAll(i.Columns)), nil
+}
+
+func (t *spannerTranslator) DropIndex(table fizz.Table) (string, error) {
+	if len(table.Indexes) == 0 {
+		return "", fmt.Errorf("not enough indexes supplied")
+	}
+	return fmt.Sprintf("DROP INDEX %s;", t.quote(table.Indexes[0].Name)), nil
+}
+
+// RenameIndex is not supported by Spanner.
+func (t *spannerTranslator) RenameIndex(table fizz.Table) (string, error) {
+	return "", fmt.Errorf("spanner can not rename the indexes of %s", table.Name)
+}
+
+func (t *spannerTranslator) AddForeignKey(table fizz.Table) (string, error) {
+	if len(table.ForeignKeys) == 0 {
+		return "", fmt.Errorf("not enough foreign keys supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s ADD %s;", t.quote(table.Name), t.buildForeignKey(table.ForeignKeys[0])), nil
+}
+
+func (t *spannerTranslator) DropForeignKey(table fizz.Table) (string, error) {
+	if len(table.ForeignKeys) == 0 {
+		return "", fmt.Errorf("not enough foreign keys supplied")
+	}
+	return fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", t.quote(table.Name), t.quote(table.ForeignKeys[0].Name)), nil
+}
+
+func (t *spannerTranslator) buildColumn(c fizz.Column) string {
+	s := fmt.Sprintf("%s %s", t.quote(c.Name), t.colType(c))
+	if c.Options["null"] == nil || c.Primary {
+		s += " NOT NULL"
+	}
+	if c.Options["default"] != nil {
+		s += fmt.Sprintf(" DEFAULT ('%v')", c.Options["default"])
+	}
+	if c.Options["default_raw"] != nil {
+		s += fmt.Sprintf(" DEFAULT (%s)", c.Options["default_raw"])
+	}
+	if t.allowsCommitTimestamp(c) {
+		s += " OPTIONS (allow_commit_timestamp=true)"
+	}
+	return s
+}
+
+func (t *spannerTranslator) allowsCommitTimestamp(c fizz.Column) bool {
+	if t.colType(c) != "TIMESTAMP" {
+		return false
+	}
+	if v, ok := c.Options["commit_timestamp"].(bool); ok {
+		return v
+	}
+	return t.commitTimestamps && (c.Name == "created_at" || c.Name == "updated_at")
+}
+
+// buildForeignKey builds the constraint of the foreign key. Spanner has no
+// ON UPDATE actions, only ON DELETE ones are kept.
+func (t *spannerTranslator) buildForeignKey(fk fizz.ForeignKey) string {
+	s := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)", t.quote(fk.Name), t.quote(fk.Column), t.quote(fk.References.Table), t.quoteAll(fk.References.Columns))
+	if onDelete, ok := fk.Options["on_delete"]; ok {
+		s += fmt.Sprintf(" ON DELETE %s", onDelete)
+	}
+	return s
+}
+
+func (t *spannerTranslator) colType(c fizz.Column) string {
+	switch c.ColType {
+	case "string":
+		s := "255"
+		if c.Options["size"] != nil {
+			s = fmt.Sprintf("%d", c.Options["size"])
+		}
+		return fmt.Sprintf("STRING(%s)", s)
+	case "text":
+		return "STRING(MAX)"
+	case "uuid":
+		return "STRING(36)"
+	case "integer", "INT", "int", "bigint", "BIGINT", "smallint":
+		return "INT64"
+	case "bool", "boolean":
+		return "BOOL"
+	case "timestamp", "time", "datetime":
+		return "TIMESTAMP"
+	case "date":
+		return "DATE"
+	case "blob", "[]byte":
+		return "BYTES(MAX)"
+	case "float":
+		return "FLOAT64"
+	case "decimal":
+		return "NUMERIC"
+	case "json", "jsonb":
+		return "JSON"
+	case "[]string":
+		return "ARRAY<STRING(MAX)>"
+	case "[]int":
+		return "ARRAY<INT64>"
+	case "[]float":
+		return "ARRAY<FLOAT64>"
+	default:
+		return c.ColType
+	}
+}
+
+func (t *spannerTranslator) quote(name string) string {
+	return spanner{}.Quote(name)
+}
+
+func (t *spannerTranslator) quoteAll(names []string) string {
+	xs := make([]string, len(names))
+	for i, n := range names {
+		xs[i] = t.quote(n)
+	}
+	return strings.Join(xs, ", ")
+}
@@ -1,6 +1,7 @@
 package pop
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"errors"
@@ -24,6 +25,10 @@ import (
 
 const nameSQLite3 = "sqlite3"
 
+// sqliteDriverName is the driver opening the SQLite databases, the one of
+// pop adding the regexp function when built with the sqlite tag.
+var sqliteDriverName = nameSQLite3
+
 func init() {
 	AvailableDialects = append(AvailableDialects, nameSQLite3)
 	dialectSynonyms["sqlite"] = nameSQLite3
@@ -38,6 +43,9 @@ type sqlite struct {
 	commonDialect
 	gil   *sync.Mutex
 	smGil *sync.Mutex
+	// returning is set when the library, 3.35 or later, returns the
+	// written rows.
+	returning bool
 }
 
 func requireSQLite3() error {
@@ -54,22 +62,145 @@ func (m *sqlite) Name() string {
 }
 
 func (m *sqlite) DefaultDriver() string {
-	return nameSQLite3
+	if isLibSQL(m.Details()) {
+		return nameLibSQL
+	}
+	return sqliteDriverName
+}
+
+func (m *sqlite) Capabilities() capabilities {
+	c := supportsSavepoints | transactionalDDL | ddlInTransactions
+	if m.returning {
+		c |= supportsReturning
+	}
+	return c
+}
+
+// AfterOpen reads the version of the library, to know whether it supports
+// RETURNING.
+func (m *sqlite) AfterOpen(c *Connection) error {
+	var version string
+	if err := c.Store.GetContext(c.Context(), &version, "SELECT sqlite_version()"); err != nil {
+		return fmt.Errorf("sqlite version: %w", err)
+	}
+	m.returning = versionAtLeast(version, 3, 35)
+	log(logging.Debug, "server: SQLite %s", version)
+	return nil
 }
 
 func (m *sqlite) Details() *ConnectionDetails {
 	return m.ConnectionDetails
 }
 
+// ColumnDefaults reads the defaults of the columns from the table_info
+// pragma. SQLite can not alter them, see Connection.AlterColumnDefaultSQL.
+func (m *sqlite) ColumnDefaults(ctx context.Context, s store, table string) (map[string]*string, error) {
+	rows := []struct {
+		Name    string  `db:"name"`
+		Default *string `db:"dflt_value"`
+	}{}
+	if err := s.SelectContext(ctx, &rows, "SELECT name, dflt_value FROM pragma_table_info(?)", table); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	defs := make(map[string]*string, len(rows))
+	for _, r := range rows {
+		defs[strings.ToLower(r.Name)] = columnDefault(r.Default)
+	}
+	return defs, nil
+}
+
+// Schema reads the tables from sqlite_master, and their columns, foreign
+// keys and indexes from the pragmas.
+func (m *sqlite) Schema(ctx context.Context, s store) ([]SchemaTable, error) {
+	tables := []string{}
+	if err := s.SelectContext(ctx, &tables, "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name"); err != nil {
+		return nil, err
+	}
+
+	b := newSchemaBuilder()
+	for _, table := range tables {
+		cols := []struct {
+			Name    string  `db:"name"`
+			Type    string  `db:"type"`
+			NotNull bool    `db:"notnull"`
+			Default *string `db:"dflt_value"`
+			PK      int     `db:"pk"`
+		}{}
+		if err := s.SelectContext(ctx, &cols, `SELECT name, type, "notnull", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid`, table); err != nil {
+			return nil, err
+		}
+		for _, c := range cols {
+			b.addColumn(table, SchemaColumn{Name: c.Name, Type: c.Type, Nullable: !c.NotNull && c.PK == 0, Default: columnDefault(c.Default), PrimaryKey: c.PK > 0})
+		}
+
+		fks := []struct {
+			ID    string         `db:"id"`
+			Table string         `db:"table"`
+			From  string         `db:"from"`
+			To    sql.NullString `db:"to"`
+		}{}
+		if err := s.SelectContext(ctx, &fks, `SELECT id, "table", "from", "to" FROM pragma_foreign_key_list(?) ORDER BY id, seq`, table); err != nil {
+			return nil, err
+		}
+		for _, fk := range fks {
+			b.addForeignKeyColumn(table, fk.ID, "", fk.Table, fk.From, fk.To.String)
+		}
+
+		idxs := []struct {
+			Name   string `db:"name"`
+			Unique bool   `db:"unique"`
+		}{}
+		if err := s.SelectContext(ctx, &idxs, `SELECT name, "unique" FROM pragma_index_list(?) WHERE origin <> 'pk' ORDER BY name`, table); err != nil {
+			return nil, err
+		}
+		for _, idx := range idxs {
+			cols := []sql.NullString{}
+			if err := s.SelectContext(ctx, &cols, "SELECT name FROM pragma_index_info(?) ORDER BY seqno", idx.Name); err != nil {
+				return nil, err
+			}
+			for _, c := range cols {
+				b.addIndexColumn(table, idx.Name, idx.Unique, c.String)
+			}
+		}
+	}
+	return b.schema(), nil
+}
+
 func (m *sqlite) URL() string {
 	c := m.ConnectionDetails
+	if isLibSQL(c) {
+		return libsqlURL(c)
+	}
 	return c.Database + "?" + c.OptionsString("")
 }
 
 func (m *sqlite) MigrationURL() string {
+	if isLibSQL(m.ConnectionDetails) {
+		return m.URL()
+	}
 	return m.ConnectionDetails.URL
 }
 
+// StrictSettings returns the defensive pragmas: the schema is not trusted
+// to run functions with side effects from views and triggers, the pages
+// are checked as they are read, and the foreign keys and check constraints
+// are enforced.
+func (m *sqlite) StrictSettings() map[string]string {
+	return map[string]string{
+		"trusted_schema":           "OFF",
+		"cell_size_check":          "ON",
+		"foreign_keys":             "ON",
+		"ignore_check_constraints": "OFF",
+	}
+}
+
+func (m *sqlite) SessionStatement(name, value string) string {
+	return fmt.Sprintf("PRAGMA %s = %s", name, value)
+}
+
 func (m *sqlite) Create(c *Connection, model *Model, cols columns.Columns) error {
 	return m.locker(m.smGil, func() error {
 		keyType, err := model.PrimaryKeyType()
@@ -87,7 +218,17 @@ func (m *sqlite) Create(c *Connection, model *Model, cols columns.Columns) error
 			} else {
 				query = fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", m.Quote(model.TableName()))
 			}
+			if m.returning {
+				query += " RETURNING " + returnedColumns(c, model, m)
+			}
+			query, err = beforeNamedExec(Insert, model, query)
+			if err != nil {
+				return err
+			}
 			txlog(logging.SQL, c, query, model.Value)
+			if m.returning {
+				return namedQueryInto(c, model, query)
+			}
 			res, err := c.Store.NamedExecContext(model.ctx, query, model.Value)
 			if err != nil {
 				return err
@@ -101,7 +242,11 @@ func (m *sqlite) Create(c *Connection, model *Model, cols columns.Columns) error
 			}
 			return nil
 		}
-		if err := genericCreate(c, model, cols, m); err != nil {
+		create := genericCreate
+		if m.returning {
+			create = returningCreate
+		}
+		if err := create(c, model, cols, m); err != nil {
 			return fmt.Errorf("sqlite create: %w", err)
 		}
 		return nil
@@ -110,7 +255,11 @@ func (m *sqlite) Create(c *Connection, model *Model, cols columns.Columns) error
 
 func (m *sqlite) Update(c *Connection, model *Model, cols columns.Columns) error {
 	return m.locker(m.smGil, func() error {
-		if err := genericUpdate(c, model, cols, m); err != nil {
+		update := genericUpdate
+		if m.returning {
+			update = returningUpdate
+		}
+		if err := update(c, model, cols, m); err != nil {
 			return fmt.Errorf("sqlite update: %w", err)
 		}
 		return nil
@@ -120,7 +269,7 @@ func (m *sqlite) Update(c *Connection, model *Model, cols columns.Columns) error
 func (m *sqlite) UpdateQuery(c *Connection, model *Model, cols columns.Columns, query Query) (int64, error) {
 	rowsAffected := int64(0)
 	err := m.locker(m.smGil, func() error {
-		if n, err := genericUpdateQuery(c, model, cols, m, query, sqlx.QUESTION); err != nil {
+		if n, err := genericUpdateQuery(c, model, cols, query); err != nil {
 			rowsAffected = n
 			return fmt.Errorf("sqlite update query: %w", err)
 		} else {
@@ -131,6 +280,22 @@ func (m *sqlite) UpdateQuery(c *Connection, model *Model, cols columns.Columns,
 	return rowsAffected, err
 }
 
+func (m *sqlite) updateQuerySQL(model *Model, cols columns.Columns, query Query) (string, []interface{}, error) {
+	return genericUpdateQuerySQL(model, cols, m, query, sqlx.QUESTION)
+}
+
+func (m *sqlite) Upsert(c *Connection, model *Model, cols *columns.WriteableColumns, conflict, update []string) (bool, error) {
+	var read bool
+	err := m.locker(m.smGil, func() error {
+		var err error
+		if read, err = genericUpsert(c, model, cols, conflict, update, m); err != nil {
+			return fmt.Errorf("sqlite upsert: %w", err)
+		}
+		return nil
+	})
+	return read, err
+}
+
 func (m *sqlite) Destroy(c *Connection, model *Model) error {
 	return m.locker(m.smGil, func() error {
 		if err := genericDestroy(c, model, m); err != nil {
@@ -183,6 +348,9 @@ func (m *sqlite) locker(l *sync.Mutex, fn func() error) error {
 
 func (m *sqlite) CreateDB() error {
 	durl := m.ConnectionDetails.Database
+	if libsqlRemote(durl) {
+		return fmt.Errorf("could not create libSQL database '%s': remote databases are created with their provider", durl)
+	}
 
 	// Checking whether the url specifies in-memory mode
 	// as specified in https://github.com/mattn/go-sqlite3#faq
@@ -212,6 +380,9 @@ func (m *sqlite) CreateDB() error {
 }
 
 func (m *sqlite) DropDB() error {
+	if libsqlRemote(m.ConnectionDetails.Database) {
+		return fmt.Errorf("could not drop libSQL database '%s': remote databases are dropped with their provider", m.ConnectionDetails.Database)
+	}
 	err := os.Remove(m.ConnectionDetails.Database)
 	if err != nil {
 		return fmt.Errorf("could not drop SQLite database %s: %w", m.ConnectionDetails.Database, err)
@@ -225,15 +396,32 @@ func (m *sqlite) TranslateSQL(sql string) string {
 }
 
 func (m *sqlite) FizzTranslator() fizz.Translator {
+	if isLibSQL(m.Details()) {
+		return newLibSQLTranslator(m)
+	}
 	return translators.NewSQLite(m.Details().Database)
 }
 
 func (m *sqlite) DumpSchema(w io.Writer) error {
+	if isLibSQL(m.Details()) {
+		schema, err := dumpLibSQLSchema(m)
+		if err != nil {
+			return fmt.Errorf("unable to dump schema for %s: %w", m.Details().Database, err)
+		}
+		if _, err := io.WriteString(w, schema); err != nil {
+			return err
+		}
+		log(logging.Info, "dumped schema for %s", m.Details().Database)
+		return nil
+	}
 	cmd := exec.Command("sqlite3", m.Details().Database, ".schema")
 	return genericDumpSchema(m.Details(), cmd, w)
 }
 
 func (m *sqlite) LoadSchema(r io.Reader) error {
+	if isLibSQL(m.Details()) {
+		return genericLoadSchema(m, r)
+	}
 	cmd := exec.Command("sqlite3", m.ConnectionDetails.Database)
 	in, err := cmd.StdinPipe()
 	if err != nil {
@@ -279,7 +467,11 @@ func (m *sqlite) TruncateAll(tx *Connection) error {
 }
 
 func newSQLite(deets *ConnectionDetails) (dialect, error) {
-	err := requireSQLite3()
+	require := requireSQLite3
+	if isLibSQL(deets) {
+		require = requireLibSQL
+	}
+	err := require()
 	if err != nil {
 		return nil, err
 	}
@@ -317,6 +509,10 @@ func urlParserSQLite3(cd *ConnectionDetails) error {
 }
 
 func finalizerSQLite(cd *ConnectionDetails) {
+	if isLibSQL(cd) {
+		// the options below are the ones of mattn/go-sqlite3
+		return
+	}
 	defs := map[string]string{
 		"_busy_timeout": "5000",
 	}
@@ -341,12 +537,22 @@ func finalizerSQLite(cd *ConnectionDetails) {
 	}
 }
 
-func newSQLiteDriver() (driver.Driver, error) {
+func newSQLiteDriver(driverName string) (driver.Driver, error) {
+	if driverName == nameLibSQL {
+		if err := requireLibSQL(); err != nil {
+			return nil, err
+		}
+		db, err := sql.Open(nameLibSQL, "libsql://localhost")
+		if err != nil {
+			return nil, err
+		}
+		return db.Driver(), db.Close()
+	}
 	err := requireSQLite3()
 	if err != nil {
 		return nil, err
 	}
-	db, err := sql.Open(nameSQLite3, ":memory:?cache=newSQLiteDriver_temporary")
+	db, err := sql.Open(sqliteDriverName, ":memory:?cache=newSQLiteDriver_temporary")
 	if err != nil {
 		return nil, err
 	}
@@ -4,5 +4,70 @@
 package pop
 
 import (
-	_ "github.com/mattn/go-sqlite3" // Load SQLite3 CGo driver
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3" // Load SQLite3 CGo driver
 )
+
+// nameSQLite3Regexp is the sqlite3 driver with the regexp function, see
+// Query.WhereRegexp. It is registered under its own name, leaving the
+// sqlite3 driver of the other users of mattn/go-sqlite3 untouched.
+const nameSQLite3Regexp = "pop-sqlite3"
+
+func init() {
+	// SQLite has a REGEXP operator but no regexp function behind it.
+	sql.Register(nameSQLite3Regexp, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("regexp", sqliteRegexp, true)
+		},
+	})
+	sqlx.BindDriver(nameSQLite3Regexp, sqlx.QUESTION)
+	sqliteDriverName = nameSQLite3Regexp
+}
+
+// sqliteRegexpCacheSize bounds the number of patterns compiled by
+// sqliteRegexp and kept for the next rows.
+const sqliteRegexpCacheSize = 64
+
+// sqliteRegexps caches the compiled patterns of sqliteRegexp. It is
+// emptied when full, the patterns may be supplied by the users.
+var sqliteRegexps = struct {
+	sync.Mutex
+	m map[string]*regexp.Regexp
+}{m: map[string]*regexp.Regexp{}}
+
+// sqliteRegexp is the regexp function of SQLite: X REGEXP Y calls
+// regexp(Y, X). NULL matches nothing.
+func sqliteRegexp(pattern string, x interface{}) (bool, error) {
+	var s string
+	switch v := x.(type) {
+	case nil:
+		return false, nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+	sqliteRegexps.Lock()
+	re, ok := sqliteRegexps.m[pattern]
+	sqliteRegexps.Unlock()
+	if !ok {
+		var err error
+		if re, err = regexp.Compile(pattern); err != nil {
+			return false, err
+		}
+		sqliteRegexps.Lock()
+		if len(sqliteRegexps.m) >= sqliteRegexpCacheSize {
+			sqliteRegexps.m = map[string]*regexp.Regexp{}
+		}
+		sqliteRegexps.m[pattern] = re
+		sqliteRegexps.Unlock()
+	}
+	return re.MatchString(s), nil
+}
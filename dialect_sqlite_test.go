@@ -8,11 +8,34 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/gobuffalo/fizz"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/require"
 )
 
 var sqliteDefaultOptions = map[string]string{"_busy_timeout": "5000", "_fk": "true"}
 
+// openSQLite opens a connection to a new SQLite database of the test,
+// closed when the test ends. The details cd, which may be nil, are
+// completed with the dialect and the database.
+func openSQLite(t *testing.T, cd *ConnectionDetails) *Connection {
+	t.Helper()
+	if cd == nil {
+		cd = &ConnectionDetails{}
+	}
+	cd.Dialect = nameSQLite3
+	cd.Database = filepath.Join(t.TempDir(), "test.sqlite")
+	c, err := NewConnection(cd)
+	require.NoError(t, err)
+	require.NoError(t, c.Open())
+	t.Cleanup(func() {
+		if c.Store != nil {
+			c.Close()
+		}
+	})
+	return c
+}
+
 func Test_ConnectionDetails_Finalize_SQLite_URL_Only(t *testing.T) {
 	r := require.New(t)
 
@@ -192,7 +215,96 @@ func TestSqlite_CreateDB(t *testing.T) {
 
 }
 
+func TestSqlite_Returning(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.True(supports(c.Dialect, supportsReturning))
+
+	r.NoError(c.RawQuery(`CREATE TABLE widgets (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, color TEXT NOT NULL DEFAULT 'red', label TEXT GENERATED ALWAYS AS (upper(name)), note TEXT NOT NULL DEFAULT '')`).Exec())
+
+	type widget struct {
+		ID    int    `db:"id"`
+		Name  string `db:"name"`
+		Color string `db:"color" rw:"r"`
+		Label string `db:"label" rw:"r"`
+		Note  string `db:"note"`
+	}
+	w := &widget{Name: "gear"}
+	r.NoError(c.Create(w))
+	r.NotZero(w.ID)
+	r.Equal("red", w.Color)
+	r.Equal("GEAR", w.Label)
+
+	w.Name = "cog"
+	w.Color = "blue"
+	r.NoError(c.Update(w))
+	r.Equal("red", w.Color)
+	r.Equal("COG", w.Label)
+
+	// the columns left out of the update keep their values in the model
+	w.Name = "sprocket"
+	w.Note = "unsaved"
+	r.NoError(c.Update(w, "note"))
+	r.Equal("SPROCKET", w.Label)
+	r.Equal("unsaved", w.Note)
+
+	w.Name = "wheel"
+	r.NoError(c.SelectColumns("note").Update(w))
+	r.Equal("wheel", w.Name)
+	r.Equal("SPROCKET", w.Label)
+	r.Equal("unsaved", w.Note)
+}
+
+func TestSqlite_Regexp(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	var match bool
+	r.NoError(c.RawQuery("SELECT 'pop' REGEXP '^p.p$'").First(&match))
+	r.True(match)
+
+	// the sqlite3 driver of the other users is left untouched
+	db, err := sqlx.Open(nameSQLite3, ":memory:")
+	r.NoError(err)
+	defer db.Close()
+	r.Error(db.Get(&match, "SELECT 'pop' REGEXP '^p.p$'"))
+
+	for i := 0; i < 2*sqliteRegexpCacheSize; i++ {
+		_, err := sqliteRegexp(fmt.Sprintf("^%d$", i), "1")
+		r.NoError(err)
+	}
+	r.LessOrEqual(len(sqliteRegexps.m), sqliteRegexpCacheSize)
+}
+
 func TestSqlite_NewDriver(t *testing.T) {
-	_, err := newSQLiteDriver()
+	_, err := newSQLiteDriver(nameSQLite3)
 	require.NoError(t, err)
 }
+
+func Test_LibSQL_Schema_Build(t *testing.T) {
+	r := require.New(t)
+
+	db, err := sqlx.Open(nameSQLite3, ":memory:")
+	r.NoError(err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE singers (id INTEGER PRIMARY KEY, name TEXT NOT NULL DEFAULT 'unknown');
+CREATE TABLE albums (id INTEGER PRIMARY KEY, singer_id INTEGER REFERENCES singers (id) ON DELETE CASCADE, title TEXT);
+CREATE UNIQUE INDEX albums_title_idx ON albums (singer_id, title);`)
+	r.NoError(err)
+
+	s := &libsqlSchema{}
+	table, err := s.buildTable(db, "albums")
+	r.NoError(err)
+	r.Len(table.Columns, 3)
+	r.True(table.Columns[0].Primary)
+	r.Equal(true, table.Columns[2].Options["null"])
+	r.Equal([]fizz.Index{{Name: "albums_title_idx", Unique: true, Columns: []string{"singer_id", "title"}}}, table.Indexes)
+	r.Len(table.ForeignKeys, 1)
+	r.Equal("albums_singers_id_fk", table.ForeignKeys[0].Name)
+	r.Equal("CASCADE", table.ForeignKeys[0].Options["on_delete"])
+
+	table, err = s.buildTable(db, "singers")
+	r.NoError(err)
+	r.Equal("unknown", table.Columns[1].Options["default"])
+}
@@ -32,3 +32,29 @@ func Test_genericDumpSchema(t *testing.T) {
 		})
 	}
 }
+
+func Test_Dialect_Capabilities(t *testing.T) {
+	r := require.New(t)
+
+	r.True(supports(&postgresql{}, supportsReturning|supportsSkipLocked))
+	r.False(supports(&mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}, supportsReturning))
+	r.True(supports(&mariaDB{}, supportsReturning))
+	r.False(supports(&sqlite{}, supportsSkipLocked))
+	r.False(supports(&spanner{}, ddlInTransactions))
+	r.True(supports(&postgresql{}, transactionalDDL|ddlInTransactions))
+	r.False(supports(&mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}, transactionalDDL))
+	r.True(supports(&mysql{commonDialect{ConnectionDetails: &ConnectionDetails{}}}, ddlInTransactions))
+	r.False(supports(&mariaDB{}, transactionalDDL))
+	r.False(supports(&oracle{}, transactionalDDL))
+	r.True(supports(&spanner{}, booleanType))
+	r.False(supports(&sqlite{}, booleanType|supportsILike))
+	r.True(supports(&cockroach{}, booleanType|supportsILike))
+
+	tidb := &mysql{commonDialect{ConnectionDetails: &ConnectionDetails{Options: map[string]string{"compatibility": "tidb"}}}}
+	r.False(supports(tidb, supportsSkipLocked))
+	r.True(supports(tidb, supportsSavepoints))
+
+	err := errUnsupported(&sqlite{}, "SKIP LOCKED")
+	r.ErrorIs(err, ErrUnsupported)
+	r.EqualError(err, "SKIP LOCKED: unsupported on sqlite3")
+}
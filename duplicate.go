@@ -0,0 +1,119 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Duplicate inserts a copy of the model, an existing entry, with a new ID
+// and new timestamps, and returns it: a pointer to a new value of the type
+// of the model. The overrides set columns of the copy, by column name; the
+// entries with a string ID need a new one among them.
+//
+// The has_many associations given are copied too: they are loaded into the
+// model and their rows inserted with new IDs, belonging to the copy. The
+// other associations of the copy are left empty.
+//
+//	cp, err := c.Duplicate(&template, map[string]interface{}{"name": "Copy of " + template.Name}, "Tasks")
+//	copied := cp.(*Template)
+func (c *Connection) Duplicate(model interface{}, overrides map[string]interface{}, associations ...string) (interface{}, error) {
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("could not duplicate %T: model must be a pointer to a struct", model)
+	}
+	t := v.Elem().Type()
+	for _, name := range associations {
+		f, ok := t.FieldByName(name)
+		if !ok || f.Tag.Get("has_many") == "" {
+			return nil, fmt.Errorf("could not duplicate %T: %s is not a has_many association", model, name)
+		}
+		if err := c.LoadAssociation(model, name); err != nil {
+			return nil, err
+		}
+	}
+
+	cp := reflect.New(t)
+	cp.Elem().Set(v.Elem())
+	resetDuplicate(cp.Elem())
+	for i := 0; i < t.NumField(); i++ {
+		f := cp.Elem().Field(i)
+		if !isFieldAssociation(t.Field(i)) || !f.CanSet() {
+			continue
+		}
+		if !containsString(associations, t.Field(i).Name) {
+			f.Set(reflect.Zero(f.Type()))
+			continue
+		}
+		f.Set(duplicateSlice(f))
+	}
+
+	for column, value := range overrides {
+		f, ok := fieldByColumn(cp.Elem(), column)
+		if !ok || !f.CanSet() {
+			return nil, fmt.Errorf("could not duplicate %T: no field for column %s", model, column)
+		}
+		if value == nil {
+			f.Set(reflect.Zero(f.Type()))
+			continue
+		}
+		rv := reflect.ValueOf(value)
+		switch {
+		case rv.Type().AssignableTo(f.Type()):
+			f.Set(rv)
+		case rv.Type().ConvertibleTo(f.Type()):
+			f.Set(rv.Convert(f.Type()))
+		default:
+			return nil, fmt.Errorf("could not duplicate %T: %T is not a value of column %s", model, value, column)
+		}
+	}
+
+	cn := c
+	if len(associations) > 0 {
+		cn = c.Eager(associations...)
+	}
+	if err := cn.Create(cp.Interface()); err != nil {
+		return nil, err
+	}
+	return cp.Interface(), nil
+}
+
+// duplicateSlice returns a copy of the slice of entries, or pointers to
+// entries, with their IDs and timestamps reset.
+func duplicateSlice(v reflect.Value) reflect.Value {
+	s := reflect.Indirect(v)
+	if !s.IsValid() || s.Kind() != reflect.Slice {
+		return reflect.Zero(v.Type())
+	}
+	cp := reflect.MakeSlice(s.Type(), s.Len(), s.Len())
+	for i := 0; i < s.Len(); i++ {
+		elem := s.Index(i)
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				continue
+			}
+			e := reflect.New(elem.Type().Elem())
+			e.Elem().Set(elem.Elem())
+			resetDuplicate(e.Elem())
+			cp.Index(i).Set(e)
+			continue
+		}
+		cp.Index(i).Set(elem)
+		resetDuplicate(cp.Index(i))
+	}
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(s.Type())
+		p.Elem().Set(cp)
+		return p
+	}
+	return cp
+}
+
+// resetDuplicate resets the ID and the timestamps of the copy of an entry,
+// set again when it is created.
+func resetDuplicate(v reflect.Value) {
+	for _, name := range []string{"ID", "CreatedAt", "UpdatedAt"} {
+		if f := v.FieldByName(name); f.IsValid() && f.CanSet() {
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+}
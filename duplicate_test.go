@@ -0,0 +1,70 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type Checklist struct {
+	ID        int             `db:"id"`
+	Name      string          `db:"name"`
+	CreatedAt time.Time       `db:"created_at"`
+	UpdatedAt time.Time       `db:"updated_at"`
+	Items     []ChecklistItem `has_many:"checklist_items" order_by:"id"`
+}
+
+type ChecklistItem struct {
+	ID          int       `db:"id"`
+	ChecklistID int       `db:"checklist_id"`
+	Title       string    `db:"title"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func Test_Duplicate(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE checklists (id INTEGER PRIMARY KEY, name TEXT, created_at DATETIME, updated_at DATETIME)").Exec())
+	r.NoError(c.RawQuery("CREATE TABLE checklist_items (id INTEGER PRIMARY KEY, checklist_id INTEGER, title TEXT, created_at DATETIME, updated_at DATETIME)").Exec())
+
+	then := time.Date(2020, 5, 17, 10, 0, 0, 0, time.UTC)
+	tmpl := &Checklist{Name: "onboarding", CreatedAt: then, UpdatedAt: then}
+	r.NoError(c.Create(tmpl))
+	for _, title := range []string{"laptop", "badge"} {
+		r.NoError(c.Create(&ChecklistItem{ChecklistID: tmpl.ID, Title: title}))
+	}
+
+	cp, err := c.Duplicate(tmpl, map[string]interface{}{"name": "onboarding (copy)"}, "Items")
+	r.NoError(err)
+	copied := cp.(*Checklist)
+	r.NotEqual(tmpl.ID, copied.ID)
+	r.Equal("onboarding (copy)", copied.Name)
+	r.True(copied.CreatedAt.After(then))
+	r.Len(tmpl.Items, 2)
+
+	found := &Checklist{}
+	r.NoError(c.Eager().Find(found, copied.ID))
+	r.Equal("onboarding (copy)", found.Name)
+	r.Len(found.Items, 2)
+	r.Equal("laptop", found.Items[0].Title)
+	r.NotEqual(tmpl.Items[0].ID, found.Items[0].ID)
+
+	cp, err = c.Duplicate(tmpl, nil)
+	r.NoError(err)
+	r.Equal("onboarding", cp.(*Checklist).Name)
+	r.Empty(cp.(*Checklist).Items)
+	count, err := c.Count(&ChecklistItem{})
+	r.NoError(err)
+	r.Equal(4, count)
+
+	_, err = c.Duplicate(tmpl, map[string]interface{}{"color": "red"})
+	r.Error(err)
+	_, err = c.Duplicate(tmpl, nil, "Name")
+	r.Error(err)
+}
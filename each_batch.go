@@ -0,0 +1,81 @@
+package pop
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// EachBatch loads all the records by batches, see Query.EachBatch.
+func (c *Connection) EachBatch(models interface{}, batchSize int, fn func() error) error {
+	return Q(c).EachBatch(models, batchSize, fn)
+}
+
+// EachBatch loads the records of the query by batches of batchSize, in
+// the order of their primary key, into models, a pointer to a slice, and
+// calls fn after each batch. models holds one batch at a time, so the
+// records of the previous batches can be freed. Each batch is loaded by
+// All, with its eager associations and callbacks. The iteration stops at
+// the first error of fn, returned by EachBatch. With AdaptiveBatchSize,
+// the size of the batches adapts to the latency of their loading.
+//
+// The batches are pages of a keyset pagination on the primary key: the
+// query may not be ordered or paginated.
+//
+//	users := []User{}
+//	err := tx.Where("active = ?", true).EachBatch(&users, 1000, func() error {
+//		return export(users)
+//	})
+func (q *Query) EachBatch(models interface{}, batchSize int, fn func() error) error {
+	v := reflect.ValueOf(models)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("models must be a pointer to a slice; got %T", models)
+	}
+	adaptive := batchSize == AdaptiveBatchSize
+	if batchSize < 1 && !adaptive {
+		return fmt.Errorf("invalid batch size %d", batchSize)
+	}
+	if len(q.orderClauses) > 0 || q.Paginator != nil || q.CursorPaginator != nil {
+		return errors.New("EachBatch orders the records by primary key: the query may not be ordered or paginated")
+	}
+	if q.RawSQL.Fragment != "" {
+		return errors.New("EachBatch does not support raw queries")
+	}
+
+	m := NewModel(models, q.Connection.Context())
+	key := fmt.Sprintf("%s.%s", m.Alias(), m.IDField())
+	sizer := batchSizerFor(Select, m.TableName())
+
+	slice := v.Elem()
+	cursor := ""
+	for {
+		bq := *q
+		bq.whereClauses = append(clauses{}, q.whereClauses...)
+		bq.orderClauses = nil
+		n := batchSize
+		if adaptive {
+			n = sizer.next(0)
+		}
+		bq.PaginateByCursor(cursor, n, key)
+
+		slice.Set(reflect.MakeSlice(slice.Type(), 0, n))
+		start := time.Now()
+		if err := bq.All(models); err != nil {
+			return err
+		}
+		if adaptive {
+			sizer.observe(slice.Len(), time.Since(start))
+		}
+		if slice.Len() == 0 {
+			return nil
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+		cursor = bq.CursorPaginator.NextCursor
+		if cursor == "" {
+			return nil
+		}
+	}
+}
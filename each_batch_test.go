@@ -0,0 +1,66 @@
+package pop
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EachBatch(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		for i := 0; i < 5; i++ {
+			r.NoError(tx.Create(&User{UserName: fmt.Sprintf("batch-%d", i), Name: nulls.NewString("batch")}))
+		}
+		r.NoError(tx.Create(&User{UserName: "other", Name: nulls.NewString("other")}))
+
+		users := []User{}
+		sizes := []int{}
+		names := []string{}
+		err := tx.Where("user_name LIKE ?", "batch-%").EachBatch(&users, 2, func() error {
+			sizes = append(sizes, len(users))
+			for _, u := range users {
+				names = append(names, u.UserName)
+			}
+			return nil
+		})
+		r.NoError(err)
+		r.Equal([]int{2, 2, 1}, sizes)
+		r.Equal([]string{"batch-0", "batch-1", "batch-2", "batch-3", "batch-4"}, names)
+
+		calls := 0
+		stop := errors.New("stop")
+		err = tx.Where("user_name LIKE ?", "batch-%").EachBatch(&users, 2, func() error {
+			calls++
+			return stop
+		})
+		r.ErrorIs(err, stop)
+		r.Equal(1, calls)
+
+		err = tx.Where("user_name = ?", "nobody").EachBatch(&users, 2, func() error {
+			calls++
+			return nil
+		})
+		r.NoError(err)
+		r.Equal(1, calls)
+	})
+}
+
+func Test_EachBatch_Invalid(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{}
+	noop := func() error { return nil }
+	r.Error(c.EachBatch([]User{}, 10, noop))
+	r.Error(c.EachBatch(&[]User{}, 0, noop))
+	r.Error(Q(c).Order("name").EachBatch(&[]User{}, 10, noop))
+	r.Error(Q(c).Paginate(1, 10).EachBatch(&[]User{}, 10, noop))
+	r.Error(c.RawQuery("SELECT * FROM users").EachBatch(&[]User{}, 10, noop))
+}
@@ -0,0 +1,94 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EagerCycleError is returned when loading all associations with an
+// unlimited depth (see SetEagerMaxDepth) and the associations lead back to
+// a model which is already being loaded.
+type EagerCycleError struct {
+	// Path is the list of association fields forming the cycle,
+	// starting with the model type name. Example: ["User", "Books", "User"]
+	Path []string
+}
+
+func (e *EagerCycleError) Error() string {
+	return fmt.Sprintf("eager loading cycle detected: %s", strings.Join(e.Path, "."))
+}
+
+// default maximum depth used when eager loading all associations.
+var eagerMaxDepth = 1
+
+// SetEagerMaxDepth changes how deep associations are loaded when Eager
+// (or EagerPreload) is used without listing any association: 1, the
+// default, only loads the direct associations of the model, 2 also loads
+// their own associations, etc. A depth of 0 removes the limit, in which
+// case an *EagerCycleError is returned if the associations are cyclic.
+//
+// Explicitly listed associations (e.g. "Books.User") are never affected.
+func SetEagerMaxDepth(depth int) {
+	eagerMaxDepth = depth
+}
+
+// eagerWildcardFields returns the association paths to load for the given
+// model when no association is explicitly listed, honoring eagerMaxDepth.
+// Only the deepest paths are returned since loading "Books.User" also
+// loads "Books".
+func eagerWildcardFields(model interface{}) ([]string, error) {
+	t := associationElemType(reflect.TypeOf(model))
+	if t.Kind() != reflect.Struct {
+		return nil, nil
+	}
+	return eagerFieldsFor(t, []reflect.Type{t}, []string{t.Name()}, eagerMaxDepth)
+}
+
+func eagerFieldsFor(t reflect.Type, seen []reflect.Type, path []string, depth int) ([]string, error) {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !isFieldAssociation(f) {
+			continue
+		}
+
+		ft := associationElemType(f.Type)
+		fpath := append(append([]string{}, path...), f.Name)
+
+		if depth == 1 || ft.Kind() != reflect.Struct {
+			fields = append(fields, f.Name)
+			continue
+		}
+
+		if depth <= 0 {
+			for _, s := range seen {
+				if s == ft {
+					return nil, &EagerCycleError{Path: fpath}
+				}
+			}
+		}
+
+		inner, err := eagerFieldsFor(ft, append(append([]reflect.Type{}, seen...), ft), fpath, depth-1)
+		if err != nil {
+			return nil, err
+		}
+		if len(inner) == 0 {
+			fields = append(fields, f.Name)
+			continue
+		}
+		for _, in := range inner {
+			fields = append(fields, f.Name+"."+in)
+		}
+	}
+	return fields, nil
+}
+
+// associationElemType removes pointer, slice and array indirections
+// from an association type.
+func associationElemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
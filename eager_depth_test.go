@@ -0,0 +1,51 @@
+package pop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EagerWildcardFields(t *testing.T) {
+	r := require.New(t)
+	defer SetEagerMaxDepth(1)
+
+	SetEagerMaxDepth(1)
+	fields, err := eagerWildcardFields(&Body{})
+	r.NoError(err)
+	r.Equal([]string{"Head"}, fields)
+
+	SetEagerMaxDepth(2)
+	fields, err = eagerWildcardFields(&[]Body{})
+	r.NoError(err)
+	r.Equal([]string{"Head.Body"}, fields)
+
+	SetEagerMaxDepth(3)
+	fields, err = eagerWildcardFields(&Song{})
+	r.NoError(err)
+	r.Equal([]string{"ComposedBy"}, fields)
+
+	SetEagerMaxDepth(2)
+	fields, err = eagerWildcardFields(&Writer{})
+	r.NoError(err)
+	r.Equal([]string{"Addresses.TaxisToHere", "Friends", "Book.User", "Book.Writers", "Book.Taxi"}, fields)
+}
+
+func Test_EagerWildcardFields_Cycle(t *testing.T) {
+	r := require.New(t)
+	defer SetEagerMaxDepth(1)
+
+	SetEagerMaxDepth(0)
+	_, err := eagerWildcardFields(&Body{})
+	r.Error(err)
+
+	var cycleErr *EagerCycleError
+	r.True(errors.As(err, &cycleErr))
+	r.Equal([]string{"Body", "Head", "Body"}, cycleErr.Path)
+	r.Equal("eager loading cycle detected: Body.Head.Body", err.Error())
+
+	fields, err := eagerWildcardFields(&Song{})
+	r.NoError(err)
+	r.Equal([]string{"ComposedBy"}, fields)
+}
@@ -0,0 +1,94 @@
+package pop
+
+import "strings"
+
+// eagerScopes maps association paths, such as "Books" or "Books.Writers",
+// to the functions scoping the queries loading them.
+type eagerScopes map[string]func(*Query)
+
+// EagerWithQuery eager loads the association, such as "Books" or
+// "Books.Writers", with the conditions and ordering set by fn on the query
+// loading it. The order_by tag of the association still applies after the
+// ordering of fn.
+//
+//	c.EagerWithQuery("Books", func(q *pop.Query) {
+//		q.Where("published = ?", true).Order("title")
+//	}).Find(&user, id)
+func (c *Connection) EagerWithQuery(association string, fn func(q *Query)) *Query {
+	return Q(c).EagerWithQuery(association, fn)
+}
+
+// EagerWithQuery eager loads the association, such as "Books" or
+// "Books.Writers", with the conditions and ordering set by fn on the query
+// loading it. It works with EagerPreload too.
+func (q *Query) EagerWithQuery(association string, fn func(q *Query)) *Query {
+	association = strings.TrimSpace(association)
+	q.Eager(association)
+	scopes := eagerScopes{}
+	for k, v := range q.eagerScopes {
+		scopes[k] = v
+	}
+	scopes[association] = fn
+	q.eagerScopes = scopes
+	return q
+}
+
+// apply scopes the query loading the association at path, if needed.
+func (s eagerScopes) apply(path string, q *Query) {
+	if fn := s[path]; fn != nil {
+		fn(q)
+	}
+}
+
+// nested returns the scopes of the associations nested in the one at
+// path, relative to it.
+func (s eagerScopes) nested(path string) eagerScopes {
+	var nested eagerScopes
+	prefix := path + "."
+	for k, fn := range s {
+		if strings.HasPrefix(k, prefix) {
+			if nested == nil {
+				nested = eagerScopes{}
+			}
+			nested[strings.TrimPrefix(k, prefix)] = fn
+		}
+	}
+	return nested
+}
+
+// eagerGroup is a set of eager fields sharing the scope of their
+// top-level association.
+type eagerGroup struct {
+	fields []string
+	scope  func(*Query)
+}
+
+// groups splits the fields by scoped top-level association, so each
+// association can be loaded with its scope. The unscoped fields are kept
+// together in the first group.
+func (s eagerScopes) groups(fields []string) []eagerGroup {
+	if len(s) == 0 {
+		return []eagerGroup{{fields: fields}}
+	}
+	groups := []eagerGroup{{}}
+	index := map[string]int{}
+	for _, f := range fields {
+		name := strings.TrimSpace(strings.SplitN(f, ".", 2)[0])
+		fn := s[name]
+		if fn == nil {
+			groups[0].fields = append(groups[0].fields, f)
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(groups)
+			index[name] = i
+			groups = append(groups, eagerGroup{scope: fn})
+		}
+		groups[i].fields = append(groups[i].fields, f)
+	}
+	if len(groups[0].fields) == 0 {
+		groups = groups[1:]
+	}
+	return groups
+}
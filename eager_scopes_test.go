@@ -0,0 +1,30 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_eagerScopes_groups(t *testing.T) {
+	r := require.New(t)
+
+	books := func(q *Query) {}
+	writers := func(q *Query) {}
+	s := eagerScopes{"Books": books, "Books.Writers": writers}
+
+	groups := s.groups([]string{"Books.Writers", "FavoriteSong", "Books"})
+	r.Len(groups, 2)
+	r.Equal([]string{"FavoriteSong"}, groups[0].fields)
+	r.Nil(groups[0].scope)
+	r.Equal([]string{"Books.Writers", "Books"}, groups[1].fields)
+	r.NotNil(groups[1].scope)
+
+	r.Len(s.groups([]string{"Books"}), 1)
+	r.Len(eagerScopes(nil).groups(nil), 1)
+
+	nested := s.nested("Books")
+	r.Len(nested, 1)
+	r.NotNil(nested["Writers"])
+	r.Nil(s.nested("Houses"))
+}
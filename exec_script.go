@@ -0,0 +1,166 @@
+package pop
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// ExecScript runs the statements of a multi-statement SQL script, such as
+// a vendor-provided SQL file, one at a time. The script is split on the
+// semicolons ending its statements, ignoring the ones in strings, quoted
+// identifiers and comments, as well as in the dollar-quoted strings of
+// PostgreSQL, CockroachDB and DuckDB. On MySQL and MariaDB, the DELIMITER
+// lines change the delimiter of the statements which follow them, as in
+// the mysql client. SQLite runs the script as a whole, Oracle and Spanner
+// split it as their schemas are.
+//
+// The statements are not translated, so placeholders are not supported,
+// and the ones run before a failing statement are not rolled back unless
+// the script is run in a transaction.
+//
+//	err := c.ExecScript(string(vendorSQL))
+func (c *Connection) ExecScript(script string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	return c.timeFunc("ExecScript", nil, func() error {
+		for i, stmt := range execScriptStatements(c.Dialect, script) {
+			txlog(logging.SQL, c, stmt)
+			if _, err := c.Store.ExecContext(c.Context(), stmt); err != nil {
+				return fmt.Errorf("exec script statement %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
+// scriptSyntax lists the dialect specific syntax of the scripts.
+type scriptSyntax struct {
+	// dollarQuotes enables the $tag$ ... $tag$ strings.
+	dollarQuotes bool
+	// mysql enables DELIMITER lines, # comments and backslash escapes.
+	mysql bool
+}
+
+// execScriptStatements splits the script in the statements run by
+// ExecScript on the dialect.
+func execScriptStatements(d dialect, script string) []string {
+	if s, ok := d.(scriptable); ok {
+		return s.SplitScript(script)
+	}
+	switch d.Name() {
+	case nameSQLite3:
+		if strings.TrimSpace(script) == "" {
+			return nil
+		}
+		return []string{script}
+	case nameMySQL, nameMariaDB:
+		return splitScript(script, scriptSyntax{mysql: true})
+	case namePostgreSQL, nameCockroach, nameDuckDB:
+		return splitScript(script, scriptSyntax{dollarQuotes: true})
+	}
+	return splitScript(script, scriptSyntax{})
+}
+
+var (
+	scriptDelimiter = regexp.MustCompile(`(?i)^[ \t]*DELIMITER[ \t]+(\S+)[ \t]*(\r?\n|$)`)
+	scriptDollarTag = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+)
+
+// splitScript splits the script on its statement delimiters. The
+// statements holding only comments are dropped.
+func splitScript(script string, syntax scriptSyntax) []string {
+	var stmts []string
+	delimiter := ";"
+	start, code := 0, false
+	flush := func(end int) {
+		if code {
+			stmts = append(stmts, strings.TrimSpace(script[start:end]))
+		}
+		code = false
+	}
+
+	for i := 0; i < len(script); {
+		lineStart := i == 0 || script[i-1] == '\n'
+		rest := script[i:]
+		switch {
+		case syntax.mysql && lineStart && scriptDelimiter.MatchString(rest):
+			m := scriptDelimiter.FindStringSubmatch(rest)
+			flush(i)
+			delimiter = m[1]
+			i += len(m[0])
+			start = i
+		case strings.HasPrefix(rest, delimiter):
+			flush(i)
+			i += len(delimiter)
+			start = i
+		case strings.HasPrefix(rest, "--") || (syntax.mysql && rest[0] == '#'):
+			i += scriptLineEnd(rest)
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 4
+			}
+		case rest[0] == '\'' || rest[0] == '"' || rest[0] == '`':
+			code = true
+			i += scriptQuoteEnd(rest, syntax.mysql)
+		case syntax.dollarQuotes && rest[0] == '$' && (i == 0 || !isScriptIdentChar(script[i-1])) && scriptDollarTag.MatchString(rest):
+			code = true
+			tag := scriptDollarTag.FindString(rest)
+			end := strings.Index(rest[len(tag):], tag)
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += len(tag) + end + len(tag)
+			}
+		default:
+			if !isScriptSpace(rest[0]) {
+				code = true
+			}
+			i++
+		}
+	}
+	flush(len(script))
+	return stmts
+}
+
+// scriptLineEnd returns the length of the comment ending the line.
+func scriptLineEnd(s string) int {
+	if end := strings.IndexByte(s, '\n'); end >= 0 {
+		return end
+	}
+	return len(s)
+}
+
+// scriptQuoteEnd returns the length of the quoted string or identifier
+// starting s, in which the quote is escaped by doubling it, or with a
+// backslash when backslashes escape.
+func scriptQuoteEnd(s string, backslashes bool) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case backslashes && s[i] == '\\' && quote != '`':
+			i++
+		case s[i] == quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func isScriptSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+func isScriptIdentChar(b byte) bool {
+	return b == '_' || b == '$' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,68 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_splitScript(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal([]string{"CREATE TABLE a (id INT)"}, splitScript("CREATE TABLE a (id INT);\n-- trailing comment\n", scriptSyntax{}))
+
+	r.Equal([]string{
+		"INSERT INTO a (s) VALUES ('x;y'), ('it''s;')",
+		"-- why\nINSERT INTO \"b;c\" VALUES (1)",
+		"/* a; b */ SELECT 1",
+	}, splitScript("INSERT INTO a (s) VALUES ('x;y'), ('it''s;');\n-- why\nINSERT INTO \"b;c\" VALUES (1);\n/* a; b */ SELECT 1", scriptSyntax{}))
+
+	pg := "CREATE FUNCTION f() RETURNS trigger AS $body$\nBEGIN\n  NEW.x := 'a;b';\n  RETURN NEW;\nEND;\n$body$ LANGUAGE plpgsql;\nDO $$ BEGIN PERFORM 1; END $$;\nSELECT $1;"
+	r.Equal([]string{
+		"CREATE FUNCTION f() RETURNS trigger AS $body$\nBEGIN\n  NEW.x := 'a;b';\n  RETURN NEW;\nEND;\n$body$ LANGUAGE plpgsql",
+		"DO $$ BEGIN PERFORM 1; END $$",
+		"SELECT $1",
+	}, splitScript(pg, scriptSyntax{dollarQuotes: true}))
+
+	my := "DROP PROCEDURE IF EXISTS p;\nDELIMITER //\nCREATE PROCEDURE p()\nBEGIN\n  SELECT 'it\\'s;';\n  SELECT 2;\nEND //\ndelimiter ;\n# done\nCALL p();\n"
+	r.Equal([]string{
+		"DROP PROCEDURE IF EXISTS p",
+		"CREATE PROCEDURE p()\nBEGIN\n  SELECT 'it\\'s;';\n  SELECT 2;\nEND",
+		"# done\nCALL p()",
+	}, splitScript(my, scriptSyntax{mysql: true}))
+}
+
+func Test_execScriptStatements(t *testing.T) {
+	r := require.New(t)
+
+	my := &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	r.Len(execScriptStatements(my, "DELIMITER $$\nSELECT 1; SELECT 2$$\n"), 1)
+
+	o := &oracle{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	r.Equal([]string{"CREATE TABLE a (id NUMBER)", "CREATE INDEX a_idx ON a (id)"}, execScriptStatements(o, "CREATE TABLE a (id NUMBER);\nCREATE INDEX a_idx ON a (id);\n"))
+}
+
+func Test_ExecScript(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+
+	script := `CREATE TABLE script_notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL, length INTEGER);
+CREATE TRIGGER script_notes_length AFTER INSERT ON script_notes
+BEGIN
+  UPDATE script_notes SET length = length(NEW.body) WHERE id = NEW.id;
+END;
+INSERT INTO script_notes (body) VALUES ('a;b'), ('c');
+`
+	r.NoError(c.ExecScript(script))
+
+	var length int
+	r.NoError(c.RawQuery("SELECT length FROM script_notes WHERE body = ?", "a;b").First(&length))
+	r.Equal(3, length)
+
+	r.Error(c.ExecScript("INSERT INTO missing_table VALUES (1);"))
+	r.NoError(c.ExecScript("-- nothing to run\n"))
+}
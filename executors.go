@@ -24,14 +24,17 @@ func (c *Connection) Reload(model interface{}) error {
 
 // Exec runs the given query.
 func (q *Query) Exec() error {
-	return q.Connection.timeFunc("Exec", func() error {
+	if err := q.Connection.checkWritable(); err != nil {
+		return err
+	}
+	return q.Connection.timeFunc("Exec", nil, func() error {
 		sql, args := q.ToSQL(nil)
 		if sql == "" {
 			return fmt.Errorf("empty query")
 		}
 
 		txlog(logging.SQL, q.Connection, sql, args...)
-		_, err := q.Connection.Store.Exec(sql, args...)
+		_, err := q.Connection.Store.ExecContext(q.Connection.Context(), sql, args...)
 		return err
 	})
 }
@@ -39,15 +42,18 @@ func (q *Query) Exec() error {
 // ExecWithCount runs the given query, and returns the amount of
 // affected rows.
 func (q *Query) ExecWithCount() (int, error) {
+	if err := q.Connection.checkWritable(); err != nil {
+		return 0, err
+	}
 	count := int64(0)
-	return int(count), q.Connection.timeFunc("Exec", func() error {
+	return int(count), q.Connection.timeFunc("Exec", nil, func() error {
 		sql, args := q.ToSQL(nil)
 		if sql == "" {
 			return fmt.Errorf("empty query")
 		}
 
 		txlog(logging.SQL, q.Connection, sql, args...)
-		result, err := q.Connection.Store.Exec(sql, args...)
+		result, err := q.Connection.Store.ExecContext(q.Connection.Context(), sql, args...)
 		if err != nil {
 			return err
 		}
@@ -63,7 +69,7 @@ func (q *Query) ExecWithCount() (int, error) {
 // If model is a slice, each item of the slice is validated then saved in the database.
 func (c *Connection) ValidateAndSave(model interface{}, excludeColumns ...string) (*validate.Errors, error) {
 	sm := NewModel(model, c.Context())
-	if err := sm.beforeValidate(c); err != nil {
+	if err := sm.beforeValidate(c.validatingFor(ValidationSave)); err != nil {
 		return nil, err
 	}
 	verrs, err := sm.validateSave(c)
@@ -104,74 +110,174 @@ func (c *Connection) Save(model interface{}, excludeColumns ...string) error {
 // ValidateAndCreate applies validation rules on the given entry, then creates it
 // if the validation succeed, excluding the given columns.
 //
-// If model is a slice, each item of the slice is validated then created in the database.
+// If model is a slice, every item of the slice is validated first, and the
+// items are only created if they are all valid. The returned errors are then
+// keyed by the index of the item, e.g. "1.name". Use SkipInvalid to create
+// the valid items anyway.
 func (c *Connection) ValidateAndCreate(model interface{}, excludeColumns ...string) (*validate.Errors, error) {
 	sm := NewModel(model, c.Context())
+	if sm.isSlice() {
+		return c.validateAndCreateMany(sm, excludeColumns...)
+	}
 
 	isEager := c.eager
 	hasEagerFields := c.eagerFields
 
-	if err := sm.beforeValidate(c); err != nil {
-		return nil, err
-	}
-	verrs, err := sm.validateCreate(c)
-	if err != nil {
+	verrs, err := c.validateForCreate(sm)
+	if err != nil || verrs.HasAny() {
 		return verrs, err
 	}
-	if verrs.HasAny() {
-		return verrs, nil
+
+	c.eager = isEager
+	c.eagerFields = hasEagerFields
+	return verrs, c.Create(model, excludeColumns...)
+}
+
+// SkipInvalid returns a copy of the connection for which ValidateAndCreate,
+// given a slice, creates the valid items and reports the errors of the
+// invalid ones instead of creating none of them.
+//
+//	verrs, err := tx.SkipInvalid().ValidateAndCreate(&users)
+func (c *Connection) SkipInvalid() *Connection {
+	cn := c.copy()
+	cn.eager = c.eager
+	cn.eagerFields = c.eagerFields
+	cn.skipInvalid = true
+	return cn
+}
+
+// SkipCallbacks returns a copy of the connection which does not run the
+// callbacks of the models on Create, Update, Save, Destroy, Upsert and
+// their Validate variants, e.g. to copy rows between databases.
+//
+//	err := tx.SkipCallbacks().SkipTimestamps().Create(&user)
+func (c *Connection) SkipCallbacks() *Connection {
+	cn := c.copy()
+	cn.eager = c.eager
+	cn.eagerFields = c.eagerFields
+	cn.skipCallbacks = true
+	return cn
+}
+
+// SkipTimestamps returns a copy of the connection which writes the
+// CreatedAt and UpdatedAt fields of the models as they are, rather than
+// setting them to the current time.
+func (c *Connection) SkipTimestamps() *Connection {
+	cn := c.copy()
+	cn.eager = c.eager
+	cn.eagerFields = c.eagerFields
+	cn.skipTimestamps = true
+	return cn
+}
+
+// setTimestamps sets the UpdatedAt field of the model to now, and its
+// CreatedAt one when created, unless the connection skips the timestamps.
+func (c *Connection) setTimestamps(m *Model, now time.Time, created bool) {
+	if c.skipTimestamps {
+		return
+	}
+	m.setUpdatedAt(now)
+	if created {
+		m.setCreatedAt(now)
 	}
+}
+
+func (c *Connection) validateAndCreateMany(sm *Model, excludeColumns ...string) (*validate.Errors, error) {
+	isEager := c.eager
+	hasEagerFields := c.eagerFields
 
-	if c.eager {
-		asos, err := associations.ForStruct(model, c.eagerFields...)
+	verrs := validate.NewErrors()
+	var valid []interface{}
+	v := reflect.Indirect(reflect.ValueOf(sm.Value))
+	for i := 0; i < v.Len(); i++ {
+		item := v.Index(i).Addr().Interface()
+
+		c.eager = isEager
+		c.eagerFields = hasEagerFields
+		ierrs, err := c.validateForCreate(NewModel(item, sm.ctx))
 		if err != nil {
-			return verrs, fmt.Errorf("could not retrieve associations: %w", err)
+			return verrs, err
+		}
+		if !ierrs.HasAny() {
+			valid = append(valid, item)
+			continue
 		}
+		for _, key := range ierrs.Keys() {
+			for _, msg := range ierrs.Get(key) {
+				verrs.Add(fmt.Sprintf("%d.%s", i, key), msg)
+			}
+		}
+	}
 
-		if len(asos) == 0 {
-			log(logging.Debug, "no associations found for given struct, disable eager mode")
-			c.disableEager()
-			return verrs, c.Create(model, excludeColumns...)
+	if verrs.HasAny() && !c.skipInvalid {
+		return verrs, nil
+	}
+
+	for _, item := range valid {
+		c.eager = isEager
+		c.eagerFields = hasEagerFields
+		if err := c.Create(item, excludeColumns...); err != nil {
+			return verrs, err
 		}
+	}
+	return verrs, nil
+}
 
-		before := asos.AssociationsBeforeCreatable()
-		for index := range before {
-			i := before[index].BeforeInterface()
-			if i == nil {
-				continue
-			}
+// validateForCreate validates the model, and its associations in eager
+// mode, before creating it.
+func (c *Connection) validateForCreate(sm *Model) (*validate.Errors, error) {
+	if err := sm.beforeValidate(c.validatingFor(ValidationCreate)); err != nil {
+		return nil, err
+	}
+	verrs, err := sm.validateCreate(c)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
 
-			sm := NewModel(i, c.Context())
-			verrs, err := sm.validateAndOnlyCreate(c)
-			if err != nil || verrs.HasAny() {
-				return verrs, err
-			}
+	if !c.eager {
+		return verrs, nil
+	}
+
+	asos, err := associations.ForStruct(sm.Value, c.eagerFields...)
+	if err != nil {
+		return verrs, fmt.Errorf("could not retrieve associations: %w", err)
+	}
+
+	if len(asos) == 0 {
+		log(logging.Debug, "no associations found for given struct, disable eager mode")
+		c.disableEager()
+		return verrs, nil
+	}
+
+	before := asos.AssociationsBeforeCreatable()
+	for index := range before {
+		i := before[index].BeforeInterface()
+		if i == nil {
+			continue
 		}
 
-		after := asos.AssociationsAfterCreatable()
-		for index := range after {
-			i := after[index].AfterInterface()
-			if i == nil {
-				continue
-			}
+		sm := NewModel(i, c.Context())
+		verrs, err := sm.validateAndOnlyCreate(c)
+		if err != nil || verrs.HasAny() {
+			return verrs, err
+		}
+	}
 
-			sm := NewModel(i, c.Context())
-			verrs, err := sm.validateAndOnlyCreate(c)
-			if err != nil || verrs.HasAny() {
-				return verrs, err
-			}
+	after := asos.AssociationsAfterCreatable()
+	for index := range after {
+		i := after[index].AfterInterface()
+		if i == nil {
+			continue
 		}
 
-		sm := NewModel(model, c.Context())
-		verrs, err = sm.validateCreate(c)
+		sm := NewModel(i, c.Context())
+		verrs, err := sm.validateAndOnlyCreate(c)
 		if err != nil || verrs.HasAny() {
 			return verrs, err
 		}
 	}
 
-	c.eager = isEager
-	c.eagerFields = hasEagerFields
-	return verrs, c.Create(model, excludeColumns...)
+	return sm.validateCreate(c)
 }
 
 // Create add a new given entry to the database, excluding the given columns.
@@ -183,13 +289,16 @@ func (c *Connection) ValidateAndCreate(model interface{}, excludeColumns ...stri
 // * Flat (default): Associate existing nested objects only. NO creation or update of nested objects.
 // * Eager: Associate existing nested objects and create non-existent objects. NO change to existing objects.
 func (c *Connection) Create(model interface{}, excludeColumns ...string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	var isEager = c.eager
 
 	c.disableEager()
 
 	sm := NewModel(model, c.Context())
 	return sm.iterate(func(m *Model) error {
-		return c.timeFunc("Create", func() error {
+		return c.timeFunc("Create", model, func() error {
 			var localIsEager = isEager
 			asos, err := associations.ForStruct(m.Value, c.eagerFields...)
 			if err != nil {
@@ -249,11 +358,15 @@ func (c *Connection) Create(model interface{}, excludeColumns ...string) error {
 
 			if tn == sm.TableName() {
 				cols.Remove(excludeColumns...)
+				cols = restrictColumns(cols, c.selectColumns, c.omitColumns, m.IDField(), "created_at", "updated_at", createdByColumn, updatedByColumn)
 			}
+			cols = c.existingColumns(cols)
 
 			now := nowFunc().Truncate(time.Microsecond)
-			m.setUpdatedAt(now)
-			m.setCreatedAt(now)
+			c.setTimestamps(m, now, true)
+			by := actor(c.Context())
+			m.setCreatedBy(by)
+			m.setUpdatedBy(by)
 
 			if err = c.Dialect.Create(c, m, cols); err != nil {
 				return err
@@ -331,7 +444,7 @@ func (c *Connection) Create(model interface{}, excludeColumns ...string) error {
 // If model is a slice, each item of the slice is validated then updated in the database.
 func (c *Connection) ValidateAndUpdate(model interface{}, excludeColumns ...string) (*validate.Errors, error) {
 	sm := NewModel(model, c.Context())
-	if err := sm.beforeValidate(c); err != nil {
+	if err := sm.beforeValidate(c.validatingFor(ValidationUpdate)); err != nil {
 		return nil, err
 	}
 	verrs, err := sm.validateUpdate(c)
@@ -347,11 +460,19 @@ func (c *Connection) ValidateAndUpdate(model interface{}, excludeColumns ...stri
 // Update writes changes from an entry to the database, excluding the given columns.
 // It updates the `updated_at` column automatically.
 //
+// The entries with a LockVersion field, or an integer field tagged
+// `lock:"true"`, are locked optimistically: the version is incremented and
+// ErrStaleObject is returned when the row has changed version since the
+// entry was loaded.
+//
 // If model is a slice, each item of the slice is updated in the database.
 func (c *Connection) Update(model interface{}, excludeColumns ...string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	sm := NewModel(model, c.Context())
 	return sm.iterate(func(m *Model) error {
-		return c.timeFunc("Update", func() error {
+		return c.timeFunc("Update", model, func() error {
 			var err error
 
 			if err = m.beforeSave(c); err != nil {
@@ -364,15 +485,21 @@ func (c *Connection) Update(model interface{}, excludeColumns ...string) error {
 			tn := m.TableName()
 			cols := columns.ForStructWithAlias(model, tn, m.As, columns.IDField{Name: m.IDField(), Writeable: !m.UsingAutoIncrement()})
 			cols.Remove(m.IDField(), "created_at")
+			cols.Remove(m.keyColumns()...)
 
 			if tn == sm.TableName() {
 				cols.Remove(excludeColumns...)
+				cols = restrictColumns(cols, c.selectColumns, c.omitColumns, "updated_at", updatedByColumn, m.lockColumn())
 			}
+			cols = c.existingColumns(cols)
 
 			now := nowFunc().Truncate(time.Microsecond)
-			m.setUpdatedAt(now)
+			c.setTimestamps(m, now, false)
+			m.setUpdatedBy(actor(c.Context()))
 
+			restoreLock := m.incrementLockVersion(cols)
 			if err = c.Dialect.Update(c, m, cols); err != nil {
+				restoreLock()
 				return err
 			}
 			if err = m.afterUpdate(c); err != nil {
@@ -395,22 +522,42 @@ func (c *Connection) Update(model interface{}, excludeColumns ...string) error {
 // Calling UpdateQuery with no columnNames will result in only the UpdatedAt
 // column being updated.
 func (q *Query) UpdateQuery(model interface{}, columnNames ...string) (int64, error) {
+	if err := q.Connection.checkWritable(); err != nil {
+		return 0, err
+	}
+	sm, cols, err := q.updateQueryColumns(model, columnNames)
+	if err != nil {
+		return 0, err
+	}
+	return q.Connection.Dialect.UpdateQuery(q.Connection, sm, cols, *q)
+}
+
+// updateQueryColumns returns the model and the columns UpdateQuery
+// updates, and sets the UpdatedAt and UpdatedBy fields of the model.
+func (q *Query) updateQueryColumns(model interface{}, columnNames []string) (*Model, columns.Columns, error) {
 	sm := NewModel(model, q.Connection.Context())
-	modelKind := reflect.TypeOf(reflect.Indirect(reflect.ValueOf(model))).Kind()
+	modelKind := reflect.Indirect(reflect.ValueOf(model)).Kind()
 	if modelKind != reflect.Struct {
-		return 0, fmt.Errorf("model must be a struct; got %s", modelKind)
+		return nil, columns.Columns{}, fmt.Errorf("model must be a struct; got %s", modelKind)
 	}
 
 	cols := columns.NewColumnsWithAlias(sm.TableName(), sm.As, columns.IDField{Name: sm.IDField(), Writeable: !sm.UsingAutoIncrement()})
 	cols.Add(columnNames...)
-	if _, err := sm.fieldByName("UpdatedAt"); err == nil {
+	if _, err := sm.fieldByName("UpdatedAt"); err == nil && !q.Connection.skipTimestamps {
 		cols.Add("updated_at")
 	}
+	by := actor(q.Connection.Context())
+	if _, ok := fieldByColumn(reflect.Indirect(reflect.ValueOf(model)), updatedByColumn); ok && by != nil {
+		cols.Add(updatedByColumn)
+	}
 	cols.Remove(sm.IDField(), "created_at")
+	cols = restrictColumns(cols, q.selectColumns, q.omitColumns, "updated_at", updatedByColumn)
+	cols = q.Connection.existingColumns(cols)
 
 	now := nowFunc().Truncate(time.Microsecond)
-	sm.setUpdatedAt(now)
-	return q.Connection.Dialect.UpdateQuery(q.Connection, sm, cols, *q)
+	q.Connection.setTimestamps(sm, now, false)
+	sm.setUpdatedBy(by)
+	return sm, cols, nil
 }
 
 // UpdateColumns writes changes from an entry to the database, including only the given columns
@@ -419,9 +566,12 @@ func (q *Query) UpdateQuery(model interface{}, columnNames ...string) (int64, er
 //
 // If model is a slice, each item of the slice is updated in the database.
 func (c *Connection) UpdateColumns(model interface{}, columnNames ...string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	sm := NewModel(model, c.Context())
 	return sm.iterate(func(m *Model) error {
-		return c.timeFunc("Update", func() error {
+		return c.timeFunc("Update", model, func() error {
 			var err error
 
 			if err = m.beforeSave(c); err != nil {
@@ -437,16 +587,27 @@ func (c *Connection) UpdateColumns(model interface{}, columnNames ...string) err
 			if len(columnNames) > 0 && tn == sm.TableName() {
 				cols = columns.NewColumnsWithAlias(tn, m.As, columns.IDField{Name: sm.IDField(), Writeable: !sm.UsingAutoIncrement()})
 				cols.Add(columnNames...)
+				if lock := m.lockColumn(); lock != "" {
+					cols.Add(lock)
+				}
 
 			} else {
 				cols = columns.ForStructWithAlias(model, tn, m.As, columns.IDField{Name: m.IDField(), Writeable: !m.UsingAutoIncrement()})
 			}
 			cols.Remove("id", "created_at")
+			cols.Remove(m.keyColumns()...)
+			if tn == sm.TableName() {
+				cols = restrictColumns(cols, c.selectColumns, c.omitColumns, "updated_at", updatedByColumn, m.lockColumn())
+			}
+			cols = c.existingColumns(cols)
 
 			now := nowFunc().Truncate(time.Microsecond)
-			m.setUpdatedAt(now)
+			c.setTimestamps(m, now, false)
+			m.setUpdatedBy(actor(c.Context()))
 
+			restoreLock := m.incrementLockVersion(cols)
 			if err = c.Dialect.Update(c, m, cols); err != nil {
+				restoreLock()
 				return err
 			}
 			if err = m.afterUpdate(c); err != nil {
@@ -458,19 +619,28 @@ func (c *Connection) UpdateColumns(model interface{}, columnNames ...string) err
 	})
 }
 
-// Destroy deletes a given entry from the database.
+// Destroy deletes a given entry from the database. The entries with a
+// DeletedAt field are soft deleted, see Connection.Unscoped.
 //
 // If model is a slice, each item of the slice is deleted from the database.
 func (c *Connection) Destroy(model interface{}) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
 	sm := NewModel(model, c.Context())
 	return sm.iterate(func(m *Model) error {
-		return c.timeFunc("Destroy", func() error {
+		return c.timeFunc("Destroy", model, func() error {
 			var err error
 
 			if err = m.beforeDestroy(c); err != nil {
 				return err
 			}
-			if err = c.Dialect.Destroy(c, m); err != nil {
+			if column := m.deletedAtColumn(); column != "" && !c.unscoped {
+				err = c.softDestroy(m, column)
+			} else {
+				err = c.Dialect.Destroy(c, m)
+			}
+			if err != nil {
 				return err
 			}
 
@@ -480,9 +650,12 @@ func (c *Connection) Destroy(model interface{}) error {
 }
 
 func (q *Query) Delete(model interface{}) error {
+	if err := q.Connection.checkWritable(); err != nil {
+		return err
+	}
 	q.Operation = Delete
 
-	return q.Connection.timeFunc("Delete", func() error {
+	return q.Connection.timeFunc("Delete", model, func() error {
 		m := NewModel(model, q.Connection.Context())
 		err := q.Connection.Dialect.Delete(q.Connection, m, *q)
 		if err != nil {
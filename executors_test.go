@@ -216,6 +216,43 @@ func Test_ValidateAndCreate_With_Slice(t *testing.T) {
 	})
 }
 
+func Test_ValidateAndCreate_With_Slice_Errors(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	validationLogs = []string{}
+	transaction(func(tx *Connection) {
+		cars := []ValidatableCar{
+			{Name: ""},
+			{Name: "VW"},
+			{Name: ""},
+		}
+		verrs, err := tx.ValidateAndCreate(&cars)
+		r.NoError(err)
+		r.Equal(2, verrs.Count())
+		r.Len(verrs.Get("0.name"), 1)
+		r.Len(verrs.Get("2.name"), 1)
+		r.Equal([]string{"Validate", "ValidateCreate", "Validate", "ValidateCreate", "Validate", "ValidateCreate"}, validationLogs)
+		for _, car := range cars {
+			r.Zero(car.ID)
+		}
+		count, err := tx.Count(&ValidatableCar{})
+		r.NoError(err)
+		r.Equal(0, count)
+
+		verrs, err = tx.SkipInvalid().ValidateAndCreate(&cars)
+		r.NoError(err)
+		r.Equal(2, verrs.Count())
+		r.Zero(cars[0].ID)
+		r.NotZero(cars[1].ID)
+		r.Zero(cars[2].ID)
+		count, err = tx.Count(&ValidatableCar{})
+		r.NoError(err)
+		r.Equal(1, count)
+	})
+}
+
 func Test_ValidateAndUpdate(t *testing.T) {
 	if PDB == nil {
 		t.Skip("skipping integration tests")
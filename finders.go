@@ -27,9 +27,21 @@ func (c *Connection) Find(model interface{}, id interface{}) error {
 // Find the first record of the model in the database with a particular id.
 //
 //	q.Find(&User{}, 1)
+//
+// The id of a model with a composite primary key is the slice of the
+// values of the fields of the key, in their order:
+//
+//	q.Find(&Membership{}, []interface{}{orgID, userID})
 func (q *Query) Find(model interface{}, id interface{}) error {
 	m := NewModel(model, q.Connection.Context())
 	idq := m.WhereID()
+	if keys := m.compositeKey(); keys != nil {
+		args, err := compositeIDArgs(m, keys, id)
+		if err != nil {
+			return err
+		}
+		return q.Where(idq, args...).First(model)
+	}
 	switch t := id.(type) {
 	case uuid.UUID:
 		return q.Where(idq, t.String()).First(model)
@@ -67,10 +79,13 @@ func (c *Connection) First(model interface{}) error {
 //	q.Where("name = ?", "mark").First(&User{})
 func (q *Query) First(model interface{}) error {
 	var m *Model
-	err := q.Connection.timeFunc("First", func() error {
+	err := q.Connection.timeFunc("First", model, func() error {
+		if q.err != nil {
+			return q.err
+		}
 		q.Limit(1)
-		m = NewModel(model, q.Connection.Context())
-		if err := q.Connection.Dialect.SelectOne(q.Connection, m, *q); err != nil {
+		var err error
+		if m, err = q.selectOne(model); err != nil {
 			return err
 		}
 		return m.afterFind(q.Connection, false)
@@ -80,6 +95,12 @@ func (q *Query) First(model interface{}) error {
 		return err
 	}
 
+	if len(q.withCountClauses) > 0 {
+		if err := q.loadCounts(model); err != nil {
+			return err
+		}
+	}
+
 	if q.eager {
 		err := q.eagerAssociations(model)
 		q.disableEager()
@@ -104,11 +125,14 @@ func (c *Connection) Last(model interface{}) error {
 //	q.Where("name = ?", "mark").Last(&User{})
 func (q *Query) Last(model interface{}) error {
 	var m *Model
-	err := q.Connection.timeFunc("Last", func() error {
+	err := q.Connection.timeFunc("Last", model, func() error {
+		if q.err != nil {
+			return q.err
+		}
 		q.Limit(1)
 		q.Order("created_at DESC, id DESC")
-		m = NewModel(model, q.Connection.Context())
-		if err := q.Connection.Dialect.SelectOne(q.Connection, m, *q); err != nil {
+		var err error
+		if m, err = q.selectOne(model); err != nil {
 			return err
 		}
 		return m.afterFind(q.Connection, false)
@@ -118,6 +142,12 @@ func (q *Query) Last(model interface{}) error {
 		return err
 	}
 
+	if len(q.withCountClauses) > 0 {
+		if err := q.loadCounts(model); err != nil {
+			return err
+		}
+	}
+
 	if q.eager {
 		err = q.eagerAssociations(model)
 		q.disableEager()
@@ -142,17 +172,35 @@ func (c *Connection) All(models interface{}) error {
 //	q.Where("name = ?", "mark").All(&[]User{})
 func (q *Query) All(models interface{}) error {
 	var m *Model
-	err := q.Connection.timeFunc("All", func() error {
+	err := q.Connection.timeFunc("All", models, func() error {
+		if q.err != nil {
+			return q.err
+		}
 		m = NewModel(models, q.Connection.Context())
-		err := q.Connection.Dialect.SelectMany(q.Connection, m, *q)
+		sq := *q
+		sq.applyModelLimits(m)
+		capped := sq.capResults()
+		if capped && q.RawSQL.Fragment == "" {
+			sq.limitResults = maxResults + 1
+		}
+		err := q.Connection.Dialect.SelectMany(q.Connection, m, sq)
 		if err != nil {
 			return err
 		}
 
+		if capped {
+			if err := checkResultsSize(models); err != nil {
+				return err
+			}
+		}
+
 		err = q.paginateModel(models)
 		if err != nil {
 			return err
 		}
+		if err := q.paginateCursor(models); err != nil {
+			return err
+		}
 
 		return m.afterFind(q.Connection, false)
 	})
@@ -161,6 +209,12 @@ func (q *Query) All(models interface{}) error {
 		return fmt.Errorf("unable to fetch records: %w", err)
 	}
 
+	if len(q.withCountClauses) > 0 {
+		if err := q.loadCounts(models); err != nil {
+			return err
+		}
+	}
+
 	if q.eager {
 		err = q.eagerAssociations(models)
 		q.disableEager()
@@ -206,12 +260,56 @@ func (c *Connection) Load(model interface{}, fields ...string) error {
 	return err
 }
 
+// LoadAssociation loads a single association, and optionally its nested
+// associations, for an already loaded model. It respects the same tags as
+// Eager, and any value already held by the association field is replaced.
+//
+//	tx.First(&u)
+//	tx.LoadAssociation(&u, "Books")
+//	tx.LoadAssociation(&u, "Books.Writers")
+func (c *Connection) LoadAssociation(model interface{}, association string) error {
+	name := association
+	if i := strings.Index(name, "."); i != -1 {
+		name = name[:i]
+	}
+
+	t := associationElemType(reflect.TypeOf(model))
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("could not load association %s: model %T is not a struct or a slice of structs", association, model)
+	}
+	f, ok := t.FieldByName(name)
+	if !ok {
+		return fmt.Errorf("field %s does not exist in model %s", name, t.Name())
+	}
+	if !isFieldAssociation(f) {
+		return fmt.Errorf("field %s of model %s is not an association", name, t.Name())
+	}
+
+	// reset the field, loading appends to already loaded associations.
+	NewModel(model, c.Context()).iterate(func(m *Model) error {
+		fbn, err := m.fieldByName(name)
+		if err == nil {
+			fbn.Set(reflect.Zero(fbn.Type()))
+		}
+		return nil
+	})
+
+	return c.Load(model, association)
+}
+
 func (q *Query) eagerAssociations(model interface{}) error {
 	if q.eagerMode == eagerModeNil {
 		q.eagerMode = loadingAssociationsStrategy
 	}
+	if len(q.eagerFields) == 0 && eagerMaxDepth != 1 {
+		fields, err := eagerWildcardFields(model)
+		if err != nil {
+			return err
+		}
+		q.eagerFields = fields
+	}
 	if q.eagerMode == EagerPreload {
-		return preload(q.Connection, model, q.eagerFields...)
+		return preloadScoped(q.Connection, model, q.eagerScopes, q.eagerFields...)
 	}
 
 	return q.eagerDefaultAssociations(model)
@@ -241,7 +339,18 @@ func (q *Query) eagerDefaultAssociations(model interface{}) error {
 	}
 
 	// eagerAssociations for a single element
-	assos, err := associations.ForStruct(model, q.eagerFields...)
+	for _, group := range q.eagerScopes.groups(q.eagerFields) {
+		if err := q.eagerGroupAssociations(model, group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eagerGroupAssociations loads the associations of the group for a single
+// element.
+func (q *Query) eagerGroupAssociations(model interface{}, group eagerGroup) error {
+	assos, err := associations.ForStruct(model, group.fields...)
 	if err != nil {
 		return fmt.Errorf("could not retrieve associations: %w", err)
 	}
@@ -259,6 +368,9 @@ func (q *Query) eagerDefaultAssociations(model interface{}) error {
 
 		whereCondition, args := association.Constraint()
 		query = query.Where(whereCondition, args...)
+		if group.scope != nil {
+			group.scope(query)
+		}
 
 		// validates if association is Sortable
 		sortable := (*associations.AssociationSortable)(nil)
@@ -294,9 +406,10 @@ func (q *Query) eagerDefaultAssociations(model interface{}) error {
 		// load all inner associations.
 		innerAssociations := association.InnerAssociations()
 		for _, inner := range innerAssociations {
-			v = reflect.Indirect(reflect.ValueOf(model)).FieldByName(inner.Name)
+			v := reflect.Indirect(reflect.ValueOf(model)).FieldByName(inner.Name)
 			innerQuery := Q(query.Connection)
 			innerQuery.eagerFields = inner.Fields
+			innerQuery.eagerScopes = q.eagerScopes.nested(inner.Name)
 
 			switch v.Kind() {
 			case reflect.Ptr:
@@ -323,7 +436,10 @@ func (q *Query) Exists(model interface{}) (bool, error) {
 
 	var res bool
 
-	err := tmpQuery.Connection.timeFunc("Exists", func() error {
+	err := tmpQuery.Connection.timeFunc("Exists", model, func() error {
+		if tmpQuery.err != nil {
+			return tmpQuery.err
+		}
 		tmpQuery.Paginator = nil
 		tmpQuery.orderClauses = clauses{}
 		tmpQuery.limitResults = 0
@@ -339,9 +455,9 @@ func (q *Query) Exists(model interface{}) (bool, error) {
 			query = query[0 : len(query)-len(foundLimit)]
 		}
 
-		existsQuery := fmt.Sprintf("SELECT EXISTS (%s)", query)
+		existsQuery := q.Connection.Dialect.TranslateSQL(fmt.Sprintf("SELECT EXISTS (%s)", query))
 		txlog(logging.SQL, q.Connection, existsQuery, args...)
-		return q.Connection.Store.Get(&res, existsQuery, args...)
+		return q.Connection.Store.GetContext(q.Connection.Context(), &res, existsQuery, args...)
 	})
 	return res, err
 }
@@ -369,7 +485,10 @@ func (q Query) CountByField(model interface{}, field string) (int, error) {
 
 	res := &rowCount{}
 
-	err := tmpQuery.Connection.timeFunc("CountByField", func() error {
+	err := tmpQuery.Connection.timeFunc("CountByField", model, func() error {
+		if tmpQuery.err != nil {
+			return tmpQuery.err
+		}
 		tmpQuery.Paginator = nil
 		tmpQuery.orderClauses = clauses{}
 		tmpQuery.limitResults = 0
@@ -387,7 +506,7 @@ func (q Query) CountByField(model interface{}, field string) (int, error) {
 
 		countQuery := fmt.Sprintf("SELECT COUNT(%s) AS row_count FROM (%s) a", field, query)
 		txlog(logging.SQL, q.Connection, countQuery, args...)
-		return q.Connection.Store.Get(res, countQuery, args...)
+		return q.Connection.Store.GetContext(q.Connection.Context(), res, countQuery, args...)
 	})
 	return res.Count, err
 }
@@ -403,7 +522,8 @@ func (c *Connection) Select(fields ...string) *Query {
 	return c.Q().Select(fields...)
 }
 
-// Select allows to query only fields passed as parameter.
+// Select allows to query only fields passed as parameter. The fields of the
+// model for the other columns are left zero.
 // c.Select("field1", "field2").All(&model)
 // => SELECT field1, field2 FROM models
 func (q *Query) Select(fields ...string) *Query {
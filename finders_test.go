@@ -142,6 +142,46 @@ func Test_Find_Eager_Has_Many(t *testing.T) {
 	})
 }
 
+func Test_Find_EagerWithQuery(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		user := User{Name: nulls.NewString("Mark")}
+		r.NoError(tx.Create(&user))
+		for _, isbn := range []string{"PB1", "PB2", "PB3"} {
+			book := Book{Title: "Book " + isbn, Isbn: isbn, UserID: nulls.NewInt(user.ID)}
+			r.NoError(tx.Create(&book))
+			for _, name := range []string{"Ann", "Bob"} {
+				r.NoError(tx.Create(&Writer{Name: name, BookID: book.ID}))
+			}
+		}
+
+		scope := func(q *Query) {
+			q.Where("isbn <> ?", "PB2").Order("isbn desc")
+		}
+		writers := func(q *Query) {
+			q.Where("name = ?", "Bob")
+		}
+		for _, mode := range []EagerMode{EagerDefault, EagerPreload} {
+			u := User{}
+			q := tx.EagerWithQuery("Books", scope).EagerWithQuery("Books.Writers", writers).Eager("FavoriteSong")
+			q.eagerMode = mode
+			r.NoError(q.Find(&u, user.ID))
+
+			r.Len(u.Books, 2)
+			r.Equal("PB3", u.Books[0].Isbn)
+			r.Equal("PB1", u.Books[1].Isbn)
+			for _, b := range u.Books {
+				r.Len(b.Writers, 1)
+				r.Equal("Bob", b.Writers[0].Name)
+			}
+		}
+	})
+}
+
 func Test_All_Eager_Preload_Mode(t *testing.T) {
 	if PDB == nil {
 		t.Skip("skipping integration tests")
@@ -984,3 +1024,45 @@ func Test_FindMultipleInnerHasMany(t *testing.T) {
 		r.Equal(u.Books[0].Writers[0].Friends[0].FirstName, "Frank")
 	})
 }
+
+func Test_LoadAssociation(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		user := User{Name: nulls.NewString("Mark")}
+		r.NoError(tx.Create(&user))
+
+		book := Book{Title: "Pop Book", Isbn: "PB1", UserID: nulls.NewInt(user.ID)}
+		r.NoError(tx.Create(&book))
+
+		writer := Writer{Name: "Mark Bates", BookID: book.ID}
+		r.NoError(tx.Create(&writer))
+
+		u := User{}
+		r.NoError(tx.Find(&u, user.ID))
+		r.Zero(len(u.Books))
+
+		r.NoError(tx.LoadAssociation(&u, "Books"))
+		r.Len(u.Books, 1)
+		r.Equal(book.Title, u.Books[0].Title)
+		r.Zero(len(u.Books[0].Writers))
+		r.Zero(u.FavoriteSong.ID)
+
+		// loading again replaces the association instead of appending to it.
+		r.NoError(tx.LoadAssociation(&u, "Books.Writers"))
+		r.Len(u.Books, 1)
+		r.Len(u.Books[0].Writers, 1)
+		r.Equal(writer.Name, u.Books[0].Writers[0].Name)
+
+		users := Users{}
+		r.NoError(tx.Where("id = ?", user.ID).All(&users))
+		r.NoError(tx.LoadAssociation(&users, "Books"))
+		r.Len(users[0].Books, 1)
+
+		r.Error(tx.LoadAssociation(&u, "Email"))
+		r.Error(tx.LoadAssociation(&u, "Unknown"))
+	})
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/gobuffalo/attrs"
+	"github.com/gobuffalo/flect"
+	"github.com/gobuffalo/flect/name"
+)
+
+// Association is a field of the model loading an associated model, see
+// FromTable.
+type Association struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Tag is the name of the association in the struct tags.
+	Tag string `json:"tag"`
+}
+
+// FromTable returns the attributes of the model of an existing table, with
+// the nulls types for the nullable columns, and the belongs_to
+// associations of its single column foreign keys named after them, e.g.
+// User for user_id.
+func FromTable(t pop.SchemaTable) (attrs.Attrs, []Association, error) {
+	var ats attrs.Attrs
+	for _, col := range t.Columns {
+		goType := columnGoType(col.Type, col.Nullable && !col.PrimaryKey)
+		a, err := attrs.Parse(fmt.Sprintf("%s:%s:%s", col.Name, goType, goType))
+		if err != nil {
+			return nil, nil, err
+		}
+		ats = append(ats, a)
+	}
+
+	var assocs []Association
+	for _, fk := range t.ForeignKeys {
+		if len(fk.Columns) != 1 || !strings.HasSuffix(fk.Columns[0], "_id") {
+			continue
+		}
+		tag := strings.TrimSuffix(fk.Columns[0], "_id")
+		assocs = append(assocs, Association{
+			Name: name.New(tag).Pascalize().String(),
+			Type: "*" + name.New(flect.Singularize(fk.RefTable)).Pascalize().String(),
+			Tag:  tag,
+		})
+	}
+	return ats, assocs, nil
+}
+
+// columnGoType returns the Go type of the values of a column of the given
+// SQL type.
+func columnGoType(sqlType string, nullable bool) string {
+	t := strings.ToLower(sqlType)
+	if i := strings.IndexByte(t, '('); i >= 0 && !strings.HasPrefix(t, "tinyint(1)") {
+		t = t[:i]
+	}
+	t = strings.TrimSpace(strings.TrimSuffix(t, " unsigned"))
+
+	goType, null := "string", "nulls.String"
+	switch {
+	case t == "tinyint(1)" || strings.HasPrefix(t, "bool"):
+		goType, null = "bool", "nulls.Bool"
+	case t == "bigint" || t == "int8" || t == "bigserial":
+		goType, null = "int64", "nulls.Int64"
+	case t == "int" || t == "integer" || t == "smallint" || t == "tinyint" || t == "mediumint" || t == "int2" || t == "int4" || strings.HasSuffix(t, "serial"):
+		goType, null = "int", "nulls.Int"
+	case strings.Contains(t, "float") || strings.Contains(t, "double") || t == "real" || t == "numeric" || t == "decimal":
+		goType, null = "float64", "nulls.Float64"
+	case strings.HasPrefix(t, "timestamp") || strings.HasPrefix(t, "date") || strings.HasPrefix(t, "time"):
+		goType, null = "time.Time", "nulls.Time"
+	case t == "uuid":
+		goType, null = "uuid.UUID", "nulls.UUID"
+	case t == "json" || t == "jsonb":
+		goType, null = "slices.Map", "slices.Map"
+	case t == "bytea" || strings.Contains(t, "blob") || strings.Contains(t, "binary"):
+		goType, null = "[]byte", "nulls.ByteSlice"
+	}
+	if nullable {
+		return null
+	}
+	return goType
+}
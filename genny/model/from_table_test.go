@@ -0,0 +1,75 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/gobuffalo/genny/v2/gentest"
+	"github.com/gobuffalo/genny/v2/gogen"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FromTable(t *testing.T) {
+	r := require.New(t)
+
+	ats, assocs, err := FromTable(pop.SchemaTable{
+		Name: "posts",
+		Columns: []pop.SchemaColumn{
+			{Name: "id", Type: "bigint", PrimaryKey: true},
+			{Name: "user_id", Type: "uuid"},
+			{Name: "title", Type: "character varying(255)"},
+			{Name: "published_at", Type: "timestamp without time zone", Nullable: true},
+			{Name: "draft", Type: "tinyint(1)"},
+			{Name: "meta", Type: "jsonb", Nullable: true},
+		},
+		ForeignKeys: []pop.SchemaForeignKey{
+			{Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+		},
+	})
+	r.NoError(err)
+	var types []string
+	for _, a := range ats {
+		types = append(types, a.Name.Pascalize().String()+" "+a.GoType())
+	}
+	r.Equal([]string{"ID int64", "UserID uuid.UUID", "Title string", "PublishedAt nulls.Time", "Draft bool", "Meta slices.Map"}, types)
+	r.Equal([]Association{{Name: "User", Type: "*User", Tag: "user"}}, assocs)
+
+	g, err := New(&Options{Name: "posts", Attrs: ats, Associations: assocs})
+	r.NoError(err)
+	run := gentest.NewRunner()
+	r.NoError(run.With(g))
+	r.NoError(run.Run())
+
+	f, err := run.Results().Find("models/post.go")
+	r.NoError(err)
+	f, err = gogen.FmtTransformer().Transform(f)
+	r.NoError(err)
+	r.Contains(f.String(), "PublishedAt nulls.Time `json:\"published_at\" db:\"published_at\"`")
+	r.Contains(f.String(), "User        *User      `json:\"user,omitempty\" belongs_to:\"user\"`")
+	r.Contains(f.String(), "\"github.com/gobuffalo/nulls\"")
+}
+
+func Test_columnGoType(t *testing.T) {
+	r := require.New(t)
+
+	for sqlType, goType := range map[string]string{
+		"integer":                  "int",
+		"INTEGER":                  "int",
+		"int(11) unsigned":         "int",
+		"bigserial":                "int64",
+		"boolean":                  "bool",
+		"double precision":         "float64",
+		"numeric(10,2)":            "float64",
+		"datetime":                 "time.Time",
+		"date":                     "time.Time",
+		"text":                     "string",
+		"char(36)":                 "string",
+		"bytea":                    "[]byte",
+		"point":                    "string",
+		"timestamp with time zone": "time.Time",
+	} {
+		r.Equal(goType, columnGoType(sqlType, false), sqlType)
+	}
+	r.Equal("nulls.Int", columnGoType("integer", true))
+	r.Equal("nulls.String", columnGoType("varchar(255)", true))
+}
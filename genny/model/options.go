@@ -11,14 +11,15 @@ import (
 
 // Options for generating a new model
 type Options struct {
-	Name                   string      `json:"name"`
-	Attrs                  attrs.Attrs `json:"props"`
-	Path                   string      `json:"path"`
-	Package                string      `json:"package"`
-	TestPackage            string      `json:"test_package"`
-	Encoding               string      `json:"encoding"`
-	ForceDefaultID         bool        `json:"force_default_id"`
-	ForceDefaultTimestamps bool        `json:"force_default_timestamps"`
+	Name                   string        `json:"name"`
+	Attrs                  attrs.Attrs   `json:"props"`
+	Path                   string        `json:"path"`
+	Package                string        `json:"package"`
+	TestPackage            string        `json:"test_package"`
+	Encoding               string        `json:"encoding"`
+	ForceDefaultID         bool          `json:"force_default_id"`
+	ForceDefaultTimestamps bool          `json:"force_default_timestamps"`
+	Associations           []Association `json:"associations"`
 }
 
 // Validate that options are usable
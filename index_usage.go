@@ -0,0 +1,35 @@
+package pop
+
+import (
+	"sort"
+)
+
+// IndexUsage is how much an index of a table was read since the statistics
+// of the database were last reset: the number of scans of the index on
+// PostgreSQL, the number of rows read through it on MySQL and MariaDB.
+type IndexUsage struct {
+	Table string `db:"table_name"`
+	Index string `db:"index_name"`
+	Reads int64  `db:"reads"`
+}
+
+// IndexUsage returns the usage of the indexes of the tables of the
+// database, sorted by table and index, from pg_stat_user_indexes on
+// PostgreSQL and the sys schema on MySQL and MariaDB.
+func (c *Connection) IndexUsage() ([]IndexUsage, error) {
+	d, ok := c.Dialect.(indexUsageReadable)
+	if !ok {
+		return nil, errUnsupported(c.Dialect, "index usage statistics")
+	}
+	usage, err := d.IndexUsage(c.Context(), c.Store)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Table != usage[j].Table {
+			return usage[i].Table < usage[j].Table
+		}
+		return usage[i].Index < usage[j].Index
+	})
+	return usage, nil
+}
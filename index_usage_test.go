@@ -0,0 +1,20 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IndexUsage_Unsupported(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+
+	_, err := c.IndexUsage()
+	r.True(errors.Is(err, ErrUnsupported), err)
+}
@@ -0,0 +1,145 @@
+package sqlparse
+
+// Select is a SELECT statement.
+type Select struct {
+	Distinct bool
+	Columns  []SelectItem
+	// From are the tables of the FROM clause, followed by the joined ones.
+	From    []TableRef
+	Where   Expr
+	GroupBy []Expr
+	Having  Expr
+	OrderBy []Expr
+	Limit   Expr
+	Offset  Expr
+}
+
+// SelectItem is a column of the result of a SELECT statement: either an
+// expression, or all the columns of Table, or of all the tables if Table
+// is blank, when Star is set.
+type SelectItem struct {
+	Expr  Expr
+	Alias string
+	Star  bool
+	Table string
+}
+
+// TableRef is a table of a FROM clause: either a table Name, optionally
+// in a Schema, or a Subquery.
+type TableRef struct {
+	Schema   string
+	Name     string
+	Subquery *Select
+	Alias    string
+	// On is the join condition of a joined table.
+	On Expr
+}
+
+// Expr is an expression: one of the pointer types below.
+type Expr interface {
+	expr()
+}
+
+// ColumnRef is a reference to a column, qualified by the name or the
+// alias of its table if Table is not blank.
+type ColumnRef struct {
+	Table string
+	Name  string
+}
+
+// FuncCall is a call to a function, with * as argument if Star is set.
+type FuncCall struct {
+	Name     string
+	Args     []Expr
+	Star     bool
+	Distinct bool
+}
+
+// Subquery is a SELECT statement used as an expression.
+type Subquery struct {
+	Select *Select
+}
+
+// Literal is a number, string, NULL, TRUE or FALSE.
+type Literal struct {
+	Value string
+}
+
+// Param is a bind parameter: "?", "$1" or ":name".
+type Param struct {
+	Name string
+}
+
+// BinaryExpr is a binary operation, the keywords of Op being upper case.
+type BinaryExpr struct {
+	Op    string
+	Left  Expr
+	Right Expr
+}
+
+// UnaryExpr is a unary operation: "-", "NOT", "EXISTS", "IS NULL",
+// "IS NOT NULL"...
+type UnaryExpr struct {
+	Op   string
+	Expr Expr
+}
+
+// ListExpr is a parenthesized list of expressions, the right operand of
+// IN.
+type ListExpr struct {
+	Exprs []Expr
+}
+
+// BetweenExpr is a [NOT] BETWEEN operation.
+type BetweenExpr struct {
+	Expr Expr
+	Low  Expr
+	High Expr
+	Not  bool
+}
+
+// CaseExpr is a CASE expression, its When and Then being paired.
+type CaseExpr struct {
+	Operand Expr
+	When    []Expr
+	Then    []Expr
+	Else    Expr
+}
+
+func (*ColumnRef) expr()   {}
+func (*FuncCall) expr()    {}
+func (*Subquery) expr()    {}
+func (*Literal) expr()     {}
+func (*Param) expr()       {}
+func (*BinaryExpr) expr()  {}
+func (*UnaryExpr) expr()   {}
+func (*ListExpr) expr()    {}
+func (*BetweenExpr) expr() {}
+func (*CaseExpr) expr()    {}
+
+// Walk calls fn for e and, unless fn returns false, for the expressions
+// it is made of, recursively. It does not walk into subqueries.
+func Walk(e Expr, fn func(Expr) bool) {
+	if e == nil || !fn(e) {
+		return
+	}
+	var children []Expr
+	switch e := e.(type) {
+	case *FuncCall:
+		children = e.Args
+	case *BinaryExpr:
+		children = []Expr{e.Left, e.Right}
+	case *UnaryExpr:
+		children = []Expr{e.Expr}
+	case *ListExpr:
+		children = e.Exprs
+	case *BetweenExpr:
+		children = []Expr{e.Expr, e.Low, e.High}
+	case *CaseExpr:
+		children = append([]Expr{e.Operand, e.Else}, e.When...)
+		children = append(children, e.Then...)
+	}
+	for _, c := range children {
+		Walk(c, fn)
+	}
+}
@@ -0,0 +1,174 @@
+package sqlparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokQuoted
+	tokNumber
+	tokString
+	tokParam
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// is returns true if the token is the given keyword or punctuation,
+// keywords being matched regardless of their case.
+func (t token) is(s string) bool {
+	switch t.kind {
+	case tokWord:
+		return strings.EqualFold(t.text, s)
+	case tokPunct:
+		return t.text == s
+	}
+	return false
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of statement"
+	case tokString:
+		return "string"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+var punctuations = []string{"<=", ">=", "<>", "!=", "||", "(", ")", ",", ".", "*", "+", "-", "/", "%", "=", "<", ">"}
+
+// lex splits the statement into tokens. It rejects everything whose
+// meaning depends on the database rather than on the standard: comments
+// other than optimizer hints, backslashes in strings, dollar quoting,
+// casts with "::", multiple statements...
+func lex(sql string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(sql) {
+		c := sql[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(sql[i:], "/*+"):
+			end := strings.Index(sql[i+3:], "*/")
+			if end < 0 || strings.Contains(sql[i+3:i+3+end], "/*") {
+				return nil, fmt.Errorf("unterminated optimizer hint at %d", i)
+			}
+			i += 3 + end + 2
+		case strings.HasPrefix(sql[i:], "/*") || strings.HasPrefix(sql[i:], "--") || c == '#':
+			return nil, fmt.Errorf("comments are not allowed, at %d", i)
+		case isLetter(c):
+			j := i
+			for j < len(sql) && (isLetter(sql[j]) || isDigit(sql[j])) {
+				j++
+			}
+			if j < len(sql) && (sql[j] == '\'' || sql[j] == '"') {
+				return nil, fmt.Errorf("prefixed literals are not allowed, at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokWord, text: sql[i:j], pos: i})
+			i = j
+		case isDigit(c):
+			j := i
+			for j < len(sql) && isDigit(sql[j]) {
+				j++
+			}
+			if j+1 < len(sql) && sql[j] == '.' && isDigit(sql[j+1]) {
+				j++
+				for j < len(sql) && isDigit(sql[j]) {
+					j++
+				}
+			}
+			if j < len(sql) && (isLetter(sql[j]) || sql[j] == '.') {
+				return nil, fmt.Errorf("invalid number at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: sql[i:j], pos: i})
+			i = j
+		case c == '"' || c == '`':
+			s, n, err := quoted(sql[i:], c)
+			if err != nil {
+				return nil, fmt.Errorf("%w at %d", err, i)
+			}
+			if s == "" {
+				return nil, fmt.Errorf("empty identifier at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokQuoted, text: s, pos: i})
+			i += n
+		case c == '\'':
+			s, n, err := quoted(sql[i:], c)
+			if err != nil {
+				return nil, fmt.Errorf("%w at %d", err, i)
+			}
+			if strings.Contains(s, `\`) {
+				return nil, fmt.Errorf("backslashes are not allowed in strings, at %d", i)
+			}
+			tokens = append(tokens, token{kind: tokString, text: s, pos: i})
+			i += n
+		case c == '?':
+			tokens = append(tokens, token{kind: tokParam, text: "?", pos: i})
+			i++
+		case c == '$' || c == ':':
+			j := i + 1
+			for j < len(sql) && (isDigit(sql[j]) || (c == ':' && isLetter(sql[j]))) {
+				j++
+			}
+			if j == i+1 {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokParam, text: sql[i:j], pos: i})
+			i = j
+		case c == ';':
+			return nil, fmt.Errorf("multiple statements are not allowed, at %d", i)
+		default:
+			p := ""
+			for _, s := range punctuations {
+				if strings.HasPrefix(sql[i:], s) {
+					p = s
+					break
+				}
+			}
+			if p == "" {
+				return nil, fmt.Errorf("unexpected %q at %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokPunct, text: p, pos: i})
+			i += len(p)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(sql)}), nil
+}
+
+// quoted reads the quoted string starting s, the quote being escaped by
+// doubling it, and returns its unquoted value and its length.
+func quoted(s string, quote byte) (string, int, error) {
+	b := &strings.Builder{}
+	for i := 1; i < len(s); i++ {
+		if s[i] != quote {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == quote {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+		return b.String(), i + 1, nil
+	}
+	return "", 0, fmt.Errorf("unterminated %c", quote)
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
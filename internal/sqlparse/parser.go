@@ -0,0 +1,578 @@
+// Package sqlparse parses the SELECT statements of a conservative subset
+// of SQL, common to the databases supported by pop, into a syntax tree.
+// Whatever is outside of this subset is an error, so that the tree always
+// tells what the statement reads.
+package sqlparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDepth is the maximum nesting of the expressions and subqueries.
+const maxDepth = 64
+
+// reserved are the keywords which can not be used as unquoted names.
+var reserved = map[string]bool{}
+
+func init() {
+	for _, k := range strings.Fields(`ALL AND AS ASC BETWEEN BY CASE CROSS DESC DISTINCT ELSE END EXCEPT
+		EXISTS FALSE FETCH FOR FROM FULL GROUP HAVING ILIKE IN INNER INTERSECT INTO IS JOIN LEFT LIKE
+		LIMIT NATURAL NOT NULL NULLS OFFSET ON OR ORDER OUTER RIGHT SELECT THEN TRUE UNION USING WHEN
+		WHERE WINDOW WITH`) {
+		reserved[k] = true
+	}
+}
+
+// Parse parses the given SELECT statement.
+func Parse(sql string) (*Select, error) {
+	tokens, err := lex(sql)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	s, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.unexpected(t)
+	}
+	return s, nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+	depth  int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// accept consumes the next tokens if they are the given keywords or
+// punctuations.
+func (p *parser) accept(words ...string) bool {
+	if p.i+len(words) > len(p.tokens) {
+		return false
+	}
+	for j, w := range words {
+		if !p.tokens[p.i+j].is(w) {
+			return false
+		}
+	}
+	p.i += len(words)
+	return true
+}
+
+func (p *parser) expect(words ...string) error {
+	if !p.accept(words...) {
+		return fmt.Errorf("expected %s, got %s at %d", strings.Join(words, " "), p.peek(), p.peek().pos)
+	}
+	return nil
+}
+
+func (p *parser) unexpected(t token) error {
+	return fmt.Errorf("unexpected %s at %d", t, t.pos)
+}
+
+// name consumes a quoted name, or an unquoted one which is not a
+// reserved keyword.
+func (p *parser) name() (string, bool) {
+	t := p.peek()
+	if t.kind == tokQuoted || (t.kind == tokWord && !reserved[strings.ToUpper(t.text)]) {
+		p.i++
+		return t.text, true
+	}
+	return "", false
+}
+
+// alias consumes the optional alias of a column or a table.
+func (p *parser) alias() (string, error) {
+	if p.accept("AS") {
+		if n, ok := p.name(); ok {
+			return n, nil
+		}
+		return "", p.unexpected(p.peek())
+	}
+	n, _ := p.name()
+	return n, nil
+}
+
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxDepth {
+		return fmt.Errorf("statement nested too deeply, at %d", p.peek().pos)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) parseSelect() (*Select, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if err := p.expect("SELECT"); err != nil {
+		return nil, err
+	}
+	s := &Select{}
+	if p.accept("DISTINCT") {
+		s.Distinct = true
+	} else {
+		p.accept("ALL")
+	}
+
+	for {
+		item, err := p.parseSelectItem()
+		if err != nil {
+			return nil, err
+		}
+		s.Columns = append(s.Columns, item)
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	var err error
+	if p.accept("FROM") {
+		if s.From, err = p.parseFrom(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("WHERE") {
+		if s.Where, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("GROUP", "BY") {
+		if s.GroupBy, err = p.parseExprs(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("HAVING") {
+		if s.Having, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	if p.accept("ORDER", "BY") {
+		for {
+			e, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			s.OrderBy = append(s.OrderBy, e)
+			if !p.accept("ASC") {
+				p.accept("DESC")
+			}
+			if p.accept("NULLS") && !p.accept("FIRST") {
+				if err := p.expect("LAST"); err != nil {
+					return nil, err
+				}
+			}
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+	if p.accept("LIMIT") {
+		if s.Limit, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+		if p.accept(",") {
+			// MySQL's LIMIT offset, count
+			s.Offset = s.Limit
+			if s.Limit, err = p.parseExpr(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if s.Offset == nil && p.accept("OFFSET") {
+		if s.Offset, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (p *parser) parseSelectItem() (SelectItem, error) {
+	if p.accept("*") {
+		return SelectItem{Star: true}, nil
+	}
+	if t := p.peek(); (t.kind == tokWord || t.kind == tokQuoted) && p.tokens[p.i+1].is(".") && p.tokens[p.i+2].is("*") {
+		if _, ok := p.name(); !ok {
+			return SelectItem{}, p.unexpected(t)
+		}
+		p.i += 2
+		return SelectItem{Star: true, Table: t.text}, nil
+	}
+	e, err := p.parseExpr()
+	if err != nil {
+		return SelectItem{}, err
+	}
+	alias, err := p.alias()
+	return SelectItem{Expr: e, Alias: alias}, err
+}
+
+func (p *parser) parseFrom() ([]TableRef, error) {
+	var refs []TableRef
+	for {
+		ref, err := p.parseTableRef()
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+
+		for {
+			join := p.accept("JOIN") || p.accept("INNER", "JOIN") ||
+				p.accept("LEFT", "JOIN") || p.accept("LEFT", "OUTER", "JOIN") ||
+				p.accept("RIGHT", "JOIN") || p.accept("RIGHT", "OUTER", "JOIN") ||
+				p.accept("FULL", "JOIN") || p.accept("FULL", "OUTER", "JOIN")
+			cross := !join && p.accept("CROSS", "JOIN")
+			if !join && !cross {
+				break
+			}
+			ref, err := p.parseTableRef()
+			if err != nil {
+				return nil, err
+			}
+			if join {
+				if err := p.expect("ON"); err != nil {
+					return nil, err
+				}
+				if ref.On, err = p.parseExpr(); err != nil {
+					return nil, err
+				}
+			}
+			refs = append(refs, ref)
+		}
+
+		if !p.accept(",") {
+			return refs, nil
+		}
+	}
+}
+
+func (p *parser) parseTableRef() (TableRef, error) {
+	var ref TableRef
+	if p.accept("(") {
+		s, err := p.parseSelect()
+		if err != nil {
+			return ref, err
+		}
+		if err := p.expect(")"); err != nil {
+			return ref, err
+		}
+		ref.Subquery = s
+		if ref.Alias, err = p.alias(); err != nil {
+			return ref, err
+		}
+		if ref.Alias == "" {
+			return ref, fmt.Errorf("subquery without alias at %d", p.peek().pos)
+		}
+		return ref, nil
+	}
+
+	n, ok := p.name()
+	if !ok {
+		return ref, p.unexpected(p.peek())
+	}
+	ref.Name = n
+	if p.accept(".") {
+		if ref.Name, ok = p.name(); !ok {
+			return ref, p.unexpected(p.peek())
+		}
+		ref.Schema = n
+	}
+	var err error
+	ref.Alias, err = p.alias()
+	return ref, err
+}
+
+func (p *parser) parseExprs() ([]Expr, error) {
+	var exprs []Expr
+	for {
+		e, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+		if !p.accept(",") {
+			return exprs, nil
+		}
+	}
+}
+
+func (p *parser) parseExpr() (Expr, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept("OR") {
+		var right Expr
+		right, err = p.parseAnd()
+		left = &BinaryExpr{Op: "OR", Left: left, Right: right}
+	}
+	return left, err
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseNot()
+	for err == nil && p.accept("AND") {
+		var right Expr
+		right, err = p.parseNot()
+		left = &BinaryExpr{Op: "AND", Left: left, Right: right}
+	}
+	return left, err
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.accept("NOT") {
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		e, err := p.parseNot()
+		return &UnaryExpr{Op: "NOT", Expr: e}, err
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, op := range []string{"=", "<>", "!=", "<=", ">=", "<", ">"} {
+		if p.accept(op) {
+			right, err := p.parseAdditive()
+			return &BinaryExpr{Op: op, Left: left, Right: right}, err
+		}
+	}
+
+	if p.accept("IS") {
+		op := "IS "
+		if p.accept("NOT") {
+			op += "NOT "
+		}
+		t := p.next()
+		if !t.is("NULL") && !t.is("TRUE") && !t.is("FALSE") {
+			return nil, p.unexpected(t)
+		}
+		return &UnaryExpr{Op: op + strings.ToUpper(t.text), Expr: left}, nil
+	}
+
+	not := p.accept("NOT")
+	switch {
+	case p.accept("LIKE"), p.accept("ILIKE"):
+		op := strings.ToUpper(p.tokens[p.i-1].text)
+		if not {
+			op = "NOT " + op
+		}
+		right, err := p.parseAdditive()
+		return &BinaryExpr{Op: op, Left: left, Right: right}, err
+	case p.accept("IN"):
+		op := "IN"
+		if not {
+			op = "NOT IN"
+		}
+		if !p.peek().is("(") {
+			return nil, p.unexpected(p.peek())
+		}
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := right.(*Subquery); !ok {
+			if _, ok := right.(*ListExpr); !ok {
+				right = &ListExpr{Exprs: []Expr{right}}
+			}
+		}
+		return &BinaryExpr{Op: op, Left: left, Right: right}, nil
+	case p.accept("BETWEEN"):
+		low, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("AND"); err != nil {
+			return nil, err
+		}
+		high, err := p.parseAdditive()
+		return &BetweenExpr{Expr: left, Low: low, High: high, Not: not}, err
+	case not:
+		return nil, p.unexpected(p.peek())
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (Expr, error) {
+	left, err := p.parseMultiplicative()
+	for err == nil && (p.accept("+") || p.accept("-") || p.accept("||")) {
+		op := p.tokens[p.i-1].text
+		var right Expr
+		right, err = p.parseMultiplicative()
+		left = &BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, err
+}
+
+func (p *parser) parseMultiplicative() (Expr, error) {
+	left, err := p.parseUnary()
+	for err == nil && (p.accept("*") || p.accept("/") || p.accept("%")) {
+		op := p.tokens[p.i-1].text
+		var right Expr
+		right, err = p.parseUnary()
+		left = &BinaryExpr{Op: op, Left: left, Right: right}
+	}
+	return left, err
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.accept("-") || p.accept("+") {
+		op := p.tokens[p.i-1].text
+		if err := p.enter(); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		e, err := p.parseUnary()
+		return &UnaryExpr{Op: op, Expr: e}, err
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber, tokString:
+		return &Literal{Value: t.text}, nil
+	case tokParam:
+		return &Param{Name: t.text}, nil
+	case tokPunct:
+		if t.text != "(" {
+			break
+		}
+		if p.peek().is("SELECT") {
+			s, err := p.parseSelect()
+			if err != nil {
+				return nil, err
+			}
+			return &Subquery{Select: s}, p.expect(")")
+		}
+		exprs, err := p.parseExprs()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		if len(exprs) == 1 {
+			return exprs[0], nil
+		}
+		return &ListExpr{Exprs: exprs}, nil
+	case tokWord:
+		switch strings.ToUpper(t.text) {
+		case "NULL", "TRUE", "FALSE":
+			return &Literal{Value: strings.ToUpper(t.text)}, nil
+		case "EXISTS":
+			if err := p.expect("("); err != nil {
+				return nil, err
+			}
+			s, err := p.parseSelect()
+			if err != nil {
+				return nil, err
+			}
+			return &UnaryExpr{Op: "EXISTS", Expr: &Subquery{Select: s}}, p.expect(")")
+		case "CASE":
+			return p.parseCase()
+		}
+		if reserved[strings.ToUpper(t.text)] {
+			break
+		}
+		if p.peek().is("(") {
+			return p.parseCall(t.text)
+		}
+		fallthrough
+	case tokQuoted:
+		c := &ColumnRef{Name: t.text}
+		if p.accept(".") {
+			n, ok := p.name()
+			if !ok {
+				return nil, p.unexpected(p.peek())
+			}
+			c.Table, c.Name = c.Name, n
+		}
+		return c, nil
+	}
+	return nil, p.unexpected(t)
+}
+
+func (p *parser) parseCall(name string) (Expr, error) {
+	p.next() // (
+	f := &FuncCall{Name: name}
+	if p.accept("*") {
+		f.Star = true
+		return f, p.expect(")")
+	}
+	if p.accept(")") {
+		return f, nil
+	}
+	f.Distinct = p.accept("DISTINCT")
+	var err error
+	if f.Args, err = p.parseExprs(); err != nil {
+		return nil, err
+	}
+	return f, p.expect(")")
+}
+
+func (p *parser) parseCase() (Expr, error) {
+	c := &CaseExpr{}
+	var err error
+	if !p.peek().is("WHEN") {
+		if c.Operand, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	for p.accept("WHEN") {
+		when, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect("THEN"); err != nil {
+			return nil, err
+		}
+		then, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		c.When = append(c.When, when)
+		c.Then = append(c.Then, then)
+	}
+	if len(c.When) == 0 {
+		return nil, p.unexpected(p.peek())
+	}
+	if p.accept("ELSE") {
+		if c.Else, err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+	}
+	return c, p.expect("END")
+}
@@ -0,0 +1,79 @@
+package sqlparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Parse(t *testing.T) {
+	r := require.New(t)
+
+	s, err := Parse("SELECT name as full_name, users.id, COUNT(DISTINCT b.id) n FROM users AS users LEFT JOIN books b ON b.user_id = users.id WHERE users.id IN (?, ?) AND name LIKE $3 GROUP BY users.id, name HAVING COUNT(*) > 1 ORDER BY full_name DESC NULLS LAST LIMIT 10 OFFSET 20")
+	r.NoError(err)
+	r.Equal([]SelectItem{
+		{Expr: &ColumnRef{Name: "name"}, Alias: "full_name"},
+		{Expr: &ColumnRef{Table: "users", Name: "id"}},
+		{Expr: &FuncCall{Name: "COUNT", Distinct: true, Args: []Expr{&ColumnRef{Table: "b", Name: "id"}}}, Alias: "n"},
+	}, s.Columns)
+	r.Equal([]TableRef{
+		{Name: "users", Alias: "users"},
+		{Name: "books", Alias: "b", On: &BinaryExpr{Op: "=", Left: &ColumnRef{Table: "b", Name: "user_id"}, Right: &ColumnRef{Table: "users", Name: "id"}}},
+	}, s.From)
+	r.Equal(&BinaryExpr{
+		Op:    "AND",
+		Left:  &BinaryExpr{Op: "IN", Left: &ColumnRef{Table: "users", Name: "id"}, Right: &ListExpr{Exprs: []Expr{&Param{Name: "?"}, &Param{Name: "?"}}}},
+		Right: &BinaryExpr{Op: "LIKE", Left: &ColumnRef{Name: "name"}, Right: &Param{Name: "$3"}},
+	}, s.Where)
+	r.Len(s.GroupBy, 2)
+	r.Equal(&BinaryExpr{Op: ">", Left: &FuncCall{Name: "COUNT", Star: true}, Right: &Literal{Value: "1"}}, s.Having)
+	r.Equal([]Expr{&ColumnRef{Name: "full_name"}}, s.OrderBy)
+	r.Equal(&Literal{Value: "10"}, s.Limit)
+	r.Equal(&Literal{Value: "20"}, s.Offset)
+
+	s, err = Parse(`SELECT COUNT(*) AS row_count FROM (SELECT /*+ MAX_EXECUTION_TIME(10) */ "u"."id" FROM "public"."users" "u" WHERE NOT EXISTS (SELECT 1 FROM books WHERE books.user_id = u.id)) a`)
+	r.NoError(err)
+	r.Equal("a", s.From[0].Alias)
+	sub := s.From[0].Subquery
+	r.Equal(TableRef{Schema: "public", Name: "users", Alias: "u"}, sub.From[0])
+	r.Equal(&ColumnRef{Table: "u", Name: "id"}, sub.Columns[0].Expr)
+
+	s, err = Parse("SELECT t.*, CASE WHEN a BETWEEN 1 AND 2 THEN 'it''s' ELSE NULL END FROM t LIMIT 5, 10")
+	r.NoError(err)
+	r.Equal(SelectItem{Star: true, Table: "t"}, s.Columns[0])
+	r.Equal(&Literal{Value: "it's"}, s.Columns[1].Expr.(*CaseExpr).Then[0])
+	r.Equal(&Literal{Value: "5"}, s.Offset)
+	r.Equal(&Literal{Value: "10"}, s.Limit)
+}
+
+func Test_Parse_Rejected(t *testing.T) {
+	r := require.New(t)
+
+	for _, sql := range []string{
+		"DELETE FROM users",
+		"SELECT 1; DROP TABLE users",
+		"SELECT id FROM users -- comment",
+		"SELECT id FROM users /* comment */",
+		"SELECT id FROM users /*! UNION SELECT password FROM secrets */",
+		"SELECT id FROM users # comment",
+		`SELECT id FROM users WHERE name = '\' OR 1 = 1'`,
+		"SELECT $$text$$",
+		"SELECT E'text'",
+		"SELECT id::text FROM users",
+		"SELECT id FROM users UNION SELECT password FROM secrets",
+		"SELECT id FROM users FOR UPDATE",
+		"SELECT id INTO copy FROM users",
+		"SELECT id FROM (SELECT id FROM users)",
+		"SELECT id FROM a.b.c",
+		"SELECT a.b.c FROM users",
+		"SELECT id FROM users JOIN books USING (id)",
+		"WITH u AS (SELECT id FROM users) SELECT id FROM u",
+		"SELECT 'unterminated",
+		`SELECT "" FROM users`,
+		"SELECT " + strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100),
+	} {
+		_, err := Parse(sql)
+		r.Error(err, sql)
+	}
+}
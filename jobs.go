@@ -0,0 +1,132 @@
+package pop
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// DequeueJobs claims at most n jobs of the table of models, see
+// Query.DequeueJobs.
+func (c *Connection) DequeueJobs(models interface{}, n int, lockColumn string) error {
+	return Q(c).DequeueJobs(models, n, lockColumn)
+}
+
+// DequeueJobs claims at most n jobs matching the query: the rows whose
+// lockColumn is NULL, skipping the ones locked by the other workers. The
+// lockColumn of the claimed jobs is set to the current time, both in the
+// database and in models, a pointer to a slice of the jobs. The order of
+// the query is the priority of the jobs.
+//
+//	jobs := []Job{}
+//	err := c.Where("queue = ?", "mails").Order("priority desc, id").DequeueJobs(&jobs, 10, "claimed_at")
+//
+// The jobs are claimed in a transaction of their own, or in the one of the
+// connection, in which case they stay locked until it ends.
+func (q *Query) DequeueJobs(models interface{}, n int, lockColumn string) error {
+	c := q.Connection
+	// Oracle can not lock the rows of the queries limiting their results.
+	if !supports(c.Dialect, supportsSkipLocked) || c.Dialect.Name() == nameOracle {
+		return errUnsupported(c.Dialect, "SKIP LOCKED")
+	}
+	if q.RawSQL.Fragment != "" {
+		return errors.New("can not dequeue jobs with a raw query")
+	}
+	if c.TX == nil {
+		return c.Transaction(func(tx *Connection) error {
+			sq := *q
+			sq.Connection = tx
+			return sq.DequeueJobs(models, n, lockColumn)
+		})
+	}
+
+	return c.timeFunc("DequeueJobs", models, func() error {
+		m := NewModel(models, c.Context())
+		sq := *q
+		sq.whereClauses = append(append(clauses{}, q.whereClauses...), clause{Fragment: fmt.Sprintf("%s IS NULL", lockColumn)})
+		sq.limitResults = n
+		sq.rowLock, sq.rowLockWait = "FOR UPDATE", "SKIP LOCKED"
+		query, args := sq.ToSQL(m)
+
+		txlog(logging.SQL, c, query, args...)
+		if err := selectMany(m.ctx, c, models, query, args...); err != nil {
+			return err
+		}
+
+		v := reflect.Indirect(reflect.ValueOf(models))
+		if v.Len() == 0 {
+			return nil
+		}
+		now := nowFunc().Truncate(time.Microsecond)
+		for i := 0; i < v.Len(); i++ {
+			if err := setColumn(v.Index(i), lockColumn, now); err != nil {
+				return err
+			}
+		}
+		if err := c.UpdateColumns(models, lockColumn); err != nil {
+			return err
+		}
+		return m.afterFind(c, false)
+	})
+}
+
+// setColumn sets the field of the struct v mapped to the column to x. The
+// field is of the type of x, a pointer to one, or a sql.Scanner of its
+// values such as the nulls types.
+func setColumn(v reflect.Value, column string, x interface{}) error {
+	v = reflect.Indirect(v)
+	f, ok := fieldByColumn(v, column)
+	if !ok {
+		return fmt.Errorf("%s has no field for the column %s", v.Type(), column)
+	}
+
+	xv := reflect.ValueOf(x)
+	switch {
+	case convertible(xv.Type(), f.Type()):
+		f.Set(xv.Convert(f.Type()))
+	case f.Kind() == reflect.Ptr && convertible(xv.Type(), f.Type().Elem()):
+		p := reflect.New(f.Type().Elem())
+		p.Elem().Set(xv.Convert(f.Type().Elem()))
+		f.Set(p)
+	default:
+		s, ok := f.Addr().Interface().(sql.Scanner)
+		if !ok {
+			return fmt.Errorf("can not set the field of the column %s of %s to a %T", column, v.Type(), x)
+		}
+		return s.Scan(x)
+	}
+	return nil
+}
+
+// convertible reports whether the values of from convert to the type to,
+// but the integers to strings, which would be converted to characters.
+func convertible(from, to reflect.Type) bool {
+	if to.Kind() == reflect.String && from.Kind() != reflect.String {
+		return false
+	}
+	return from.ConvertibleTo(to)
+}
+
+// fieldByColumn returns the field of the struct v, or of its embedded
+// structs, whose db tag is the column.
+func fieldByColumn(v reflect.Value, column string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := strings.Split(sf.Tag.Get("db"), ",")[0]
+		if tag == column {
+			return v.Field(i), true
+		}
+		if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct {
+			if f, ok := fieldByColumn(v.Field(i), column); ok {
+				return f, true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,48 @@
+package pop
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_DequeueJobs_Unsupported(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &sqlite{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	r.ErrorIs(c.DequeueJobs(&[]User{}, 10, "claimed_at"), ErrUnsupported)
+}
+
+type job struct {
+	ID int `db:"id"`
+	jobTimes
+	ClaimedAt  time.Time  `db:"claimed_at"`
+	StartedAt  *time.Time `db:"started_at"`
+	FinishedAt nulls.Time `db:"finished_at,omitempty"`
+	Name       string     `db:"name"`
+}
+
+type jobTimes struct {
+	ScheduledAt time.Time `db:"scheduled_at"`
+}
+
+func Test_DequeueJobs_SetColumn(t *testing.T) {
+	r := require.New(t)
+
+	now := time.Now()
+	j := &job{}
+	v := reflect.ValueOf(j)
+	for _, column := range []string{"claimed_at", "started_at", "finished_at", "scheduled_at"} {
+		r.NoError(setColumn(v, column, now))
+	}
+	r.Equal(now, j.ClaimedAt)
+	r.Equal(now, *j.StartedAt)
+	r.Equal(nulls.NewTime(now), j.FinishedAt)
+	r.Equal(now, j.ScheduledAt)
+
+	r.EqualError(setColumn(v, "locked_at", now), "pop.job has no field for the column locked_at")
+	r.EqualError(setColumn(v, "name", now), "can not set the field of the column name of pop.job to a time.Time")
+}
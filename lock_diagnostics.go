@@ -0,0 +1,71 @@
+package pop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// LockConflictError is the error of the transactions failed on a deadlock
+// or a lock wait timeout, with the report of the locks of the database at
+// the time of the failure. It is only returned by the connections with
+// ConnectionDetails.DiagnoseLocks set.
+type LockConflictError struct {
+	Err error
+	// Report describes the blocking chain: the sessions waiting for a lock
+	// and the ones holding it.
+	Report string
+}
+
+func (e *LockConflictError) Error() string {
+	return fmt.Sprintf("%s\n%s", e.Err, e.Report)
+}
+
+func (e *LockConflictError) Unwrap() error {
+	return e.Err
+}
+
+// diagnoseLocks attaches the report of the locks to err when it is a lock
+// conflict. The report is read outside of the transactions, it is not made
+// for the nested ones.
+func (c *Connection) diagnoseLocks(err error) error {
+	if err == nil || c.TX != nil || !c.Dialect.Details().DiagnoseLocks {
+		return err
+	}
+	d, ok := c.Dialect.(lockDiagnosable)
+	if !ok || !d.LockConflict(err) {
+		return err
+	}
+	report, derr := d.DiagnoseLocks(c.Store, err)
+	if derr != nil {
+		log(logging.Warn, "could not diagnose the locks: %s", derr)
+		return err
+	}
+	return &LockConflictError{Err: err, Report: report}
+}
+
+// innodbStatusSection returns the lines of the section of the output of
+// SHOW ENGINE INNODB STATUS, e.g. "LATEST DETECTED DEADLOCK". Sections are
+// titled between two lines of dashes.
+func innodbStatusSection(status, title string) string {
+	lines := strings.Split(status, "\n")
+	isRule := func(i int) bool {
+		return i >= 0 && i < len(lines) && strings.Trim(lines[i], "-") == "" && lines[i] != ""
+	}
+	isTitle := func(i int) bool {
+		return isRule(i-1) && isRule(i+1) && !isRule(i)
+	}
+
+	for i := range lines {
+		if !isTitle(i) || strings.TrimSpace(lines[i]) != title {
+			continue
+		}
+		end := i + 2
+		for end < len(lines) && !isTitle(end+1) {
+			end++
+		}
+		return strings.TrimSpace(strings.Join(lines[i+2:end], "\n"))
+	}
+	return ""
+}
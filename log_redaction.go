@@ -0,0 +1,103 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+)
+
+// redacted is the representation of a logged model whose sensitive
+// columns are masked.
+type redacted string
+
+// sensitiveFieldsCache holds the sensitive fields of the model types, by
+// type.
+var sensitiveFieldsCache sync.Map
+
+// sensitiveFields returns, for each field of the struct type t, whether
+// its column is of a sensitive data class, or nil if none is.
+func sensitiveFields(t reflect.Type) []bool {
+	if cached, ok := sensitiveFieldsCache.Load(t); ok {
+		return cached.([]bool)
+	}
+	var sensitive []bool
+	for i := 0; i < t.NumField(); i++ {
+		if (columns.Column{Class: t.Field(i).Tag.Get("class")}).Sensitive() {
+			if sensitive == nil {
+				sensitive = make([]bool, t.NumField())
+			}
+			sensitive[i] = true
+		}
+	}
+	sensitiveFieldsCache.Store(t, sensitive)
+	return sensitive
+}
+
+// redactArgs returns the arguments of a logged statement with the models,
+// or slices of models, having pii or secret columns replaced by their
+// representation with the values of those columns masked.
+func redactArgs(args []interface{}) []interface{} {
+	var xargs []interface{}
+	for i, a := range args {
+		r, ok := redactArg(reflect.ValueOf(a))
+		if !ok {
+			continue
+		}
+		if xargs == nil {
+			xargs = append([]interface{}{}, args...)
+		}
+		xargs[i] = r
+	}
+	if xargs == nil {
+		return args
+	}
+	return xargs
+}
+
+func redactArg(v reflect.Value) (redacted, bool) {
+	prefix := ""
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		prefix = "&"
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		sensitive := sensitiveFields(v.Type())
+		if sensitive == nil {
+			return "", false
+		}
+		b := &strings.Builder{}
+		b.WriteString(prefix + "{")
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(v.Type().Field(i).Name + ":")
+			if sensitive[i] {
+				b.WriteString(maskedSecret)
+			} else {
+				fmt.Fprintf(b, "%v", v.Field(i))
+			}
+		}
+		b.WriteString("}")
+		return redacted(b.String()), true
+	case reflect.Slice, reflect.Array:
+		t := v.Type().Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || sensitiveFields(t) == nil {
+			return "", false
+		}
+		elems := make([]string, v.Len())
+		for i := range elems {
+			r, _ := redactArg(reflect.Indirect(v.Index(i)))
+			elems[i] = string(r)
+		}
+		return redacted(prefix + "[" + strings.Join(elems, " ") + "]"), true
+	}
+	return "", false
+}
@@ -0,0 +1,47 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type redactedAccount struct {
+	ID       int          `db:"id"`
+	Email    nulls.String `db:"email" class:"pii"`
+	Password string       `db:"password" class:"secret"`
+	Plan     string       `db:"plan" class:"public"`
+}
+
+func Test_RedactArgs(t *testing.T) {
+	r := require.New(t)
+
+	a := redactedAccount{ID: 1, Email: nulls.NewString("mark@example.com"), Password: "hunter2", Plan: "pro"}
+	args := redactArgs([]interface{}{&a, "x", 2, []redactedAccount{a}, &Song{Title: "song"}})
+	r.Equal([]interface{}{
+		redacted("&{ID:1 Email:***** Password:***** Plan:pro}"),
+		"x",
+		2,
+		redacted("[{ID:1 Email:***** Password:***** Plan:pro}]"),
+		&Song{Title: "song"},
+	}, args)
+
+	plain := []interface{}{"x", &Song{}}
+	r.Equal(plain, redactArgs(plain))
+}
+
+func Test_SetTxLogger_Redacts(t *testing.T) {
+	r := require.New(t)
+
+	old := txlog
+	defer func() { txlog = old }()
+
+	var logged []interface{}
+	SetTxLogger(func(lvl logging.Level, anon interface{}, s string, args ...interface{}) {
+		logged = args
+	})
+	txlog(logging.SQL, nil, "INSERT", &redactedAccount{ID: 1, Password: "hunter2"})
+	r.Equal([]interface{}{redacted("&{ID:1 Email:***** Password:***** Plan:}")}, logged)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	stdlog "log"
 	"os"
+	"time"
 
 	"github.com/WilliamNHarvey/pop/v6/logging"
 	"github.com/fatih/color"
@@ -20,25 +21,75 @@ func SetLogger(logger func(level logging.Level, s string, args ...interface{}))
 	log = logger
 }
 
-// SetLogger overrides the default logger.
+// SetTxLogger overrides the default logger of the statements. The models
+// among the args have the values of their pii and secret columns masked,
+// see columns.ClassPII.
 func SetTxLogger(logger func(level logging.Level, anon interface{}, s string, args ...interface{})) {
-	txlog = logger
+	txlog = func(lvl logging.Level, anon interface{}, s string, args ...interface{}) {
+		logger(lvl, anon, s, redactArgs(args)...)
+	}
+}
+
+// SetStructuredLogger sets the logger of the messages with their
+// structured fields, such as the statement, its arguments and the IDs of
+// the connection and the transaction, see the logging.Field constants. It
+// replaces the loggers set with SetLogger and SetTxLogger; use nil to go
+// back to the default one. As with the default logger, the sql and debug
+// messages are only logged in Debug mode, with the durations of the
+// operations.
+//
+//	pop.SetStructuredLogger(logging.Slog(slog.Default()))
+func SetStructuredLogger(logger logging.Logger) {
+	structuredLogger = logger
+	log = defaultLog
+	if logger == nil {
+		txlog = defaultTxLog
+		return
+	}
+	txlog = func(lvl logging.Level, anon interface{}, s string, args ...interface{}) {
+		if !Debug && lvl <= logging.Debug {
+			return
+		}
+		fields := logging.Fields{}
+		if lvl == logging.SQL {
+			fields[logging.FieldSQL] = s
+			if len(args) > 0 {
+				fields[logging.FieldArgs] = redactArgs(args)
+			}
+			s = "sql"
+			connID, txID, request := logIDs(anon)
+			fields[logging.FieldConnection] = connID
+			fields[logging.FieldTx] = txID
+			if request != "" {
+				fields[logging.FieldRequest] = request
+			}
+		} else {
+			s = fmt.Sprintf(s, args...)
+		}
+		logger.Log(lvl, s, fields)
+	}
 }
 
+var structuredLogger logging.Logger
+
 var defaultStdLogger = stdlog.New(os.Stderr, "[POP] ", stdlog.LstdFlags)
 
-var log = func(lvl logging.Level, s string, args ...interface{}) {
+var log = defaultLog
+
+func defaultLog(lvl logging.Level, s string, args ...interface{}) {
 	txlog(lvl, nil, s, args...)
 }
 
-var txlog = func(lvl logging.Level, anon interface{}, s string, args ...interface{}) {
+var txlog = defaultTxLog
+
+func defaultTxLog(lvl logging.Level, anon interface{}, s string, args ...interface{}) {
 	if !Debug && lvl <= logging.Debug {
 		return
 	}
 	if lvl == logging.SQL {
 		if len(args) > 0 {
 			xargs := make([]string, len(args))
-			for i, a := range args {
+			for i, a := range redactArgs(args) {
 				switch a.(type) {
 				case string:
 					xargs[i] = fmt.Sprintf("%q", a)
@@ -51,24 +102,15 @@ var txlog = func(lvl logging.Level, anon interface{}, s string, args ...interfac
 			s = fmt.Sprintf("%s - %s", lvl, s)
 		}
 
-		connID := ""
-		txID := 0
+		connID, txID, request := logIDs(anon)
 		extra := ""
+		if request != "" {
+			extra = fmt.Sprintf(", request=%s", request)
+		}
 		switch typed := anon.(type) {
 		case *Connection:
-			connID = typed.ID
-			if typed.TX != nil {
-				txID = typed.TX.ID
-			}
-
-			extra = printStats(&typed.Store)
-		case *Tx:
-			txID = typed.ID
+			extra += printStats(&typed.Store)
 		case store:
-			if t, ok := typed.(*Tx); ok {
-				txID = t.ID
-			}
-
 			extra = printStats(&typed)
 		}
 
@@ -85,10 +127,47 @@ var txlog = func(lvl logging.Level, anon interface{}, s string, args ...interfac
 	defaultStdLogger.Println(s)
 }
 
+// logIDs returns the IDs of the connection, the transaction and the
+// request of the logged statements run by anon.
+func logIDs(anon interface{}) (connID string, txID int, request string) {
+	switch typed := anon.(type) {
+	case *Connection:
+		connID = typed.ID
+		if typed.TX != nil {
+			txID = typed.TX.ID
+		}
+		request = correlationID(typed.Context())
+	case *Tx:
+		txID = typed.ID
+	case store:
+		if t, ok := typed.(*Tx); ok {
+			txID = t.ID
+		}
+	}
+	return connID, txID, request
+}
+
+// logOperation logs the duration of the operation on the model with the
+// structured logger, if any.
+func logOperation(c *Connection, op string, model interface{}, d time.Duration) {
+	if structuredLogger == nil || !Debug {
+		return
+	}
+	fields := logging.Fields{
+		logging.FieldOperation: op,
+		logging.FieldDuration:  d,
+	}
+	if model != nil {
+		fields[logging.FieldTable] = NewModel(model, c.Context()).TableName()
+	}
+	fields[logging.FieldConnection], fields[logging.FieldTx], _ = logIDs(c)
+	structuredLogger.Log(logging.Debug, "operation", fields)
+}
+
 // printStats returns a string represent connection pool information from
 // the given store.
 func printStats(s *store) string {
-	if db, ok := (*s).(*dB); ok {
+	if db, ok := unwrapStore(*s).(*dB); ok {
 		s := db.Stats()
 		return fmt.Sprintf(", maxconn: %d, openconn: %d, in-use: %d, idle: %d", s.MaxOpenConnections, s.OpenConnections, s.InUse, s.Idle)
 	}
@@ -0,0 +1,73 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_printStats_Wrapped(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	db := unwrapStore(c.Store).(*dB)
+	r.NotEmpty(printStats(&c.Store))
+
+	var s store = db
+	s = splitStore{store: s, readers: []*dB{db}, next: new(uint32)}
+	s = commentStore{store: s}
+	s = watchdogStore{store: s, threshold: time.Second}
+	s = timeoutStore{store: s, timeout: time.Second}
+	s = restrictedStore{store: s, allowlists: []*allowlist{{}}}
+	s = contextStore{store: s, ctx: context.Background()}
+	r.True(unwrapStore(s) == store(db))
+	r.Contains(printStats(&s), "maxconn")
+}
+
+func Test_SetStructuredLogger(t *testing.T) {
+	r := require.New(t)
+
+	type entry struct {
+		level  logging.Level
+		msg    string
+		fields logging.Fields
+	}
+	var entries []entry
+	SetStructuredLogger(logging.LoggerFunc(func(level logging.Level, msg string, fields logging.Fields) {
+		entries = append(entries, entry{level, msg, fields})
+	}))
+	defer SetStructuredLogger(nil)
+	debug := Debug
+	Debug = true
+	defer func() { Debug = debug }()
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE redacted_accounts (id INTEGER PRIMARY KEY, email TEXT, password TEXT, plan TEXT)").Exec())
+	entries = nil
+	a := &redactedAccount{Password: "hunter2", Plan: "pro"}
+	r.NoError(c.Create(a))
+
+	r.Len(entries, 2)
+	r.Equal(logging.SQL, entries[0].level)
+	r.Equal("sql", entries[0].msg)
+	r.Contains(entries[0].fields[logging.FieldSQL], "INSERT INTO \"redacted_accounts\"")
+	r.Equal([]interface{}{redacted("&{ID:0 Email:***** Password:***** Plan:pro}")}, entries[0].fields[logging.FieldArgs])
+	r.Equal(c.ID, entries[0].fields[logging.FieldConnection])
+	r.Equal(0, entries[0].fields[logging.FieldTx])
+
+	r.Equal(logging.Debug, entries[1].level)
+	r.Equal("operation", entries[1].msg)
+	r.Equal("Create", entries[1].fields[logging.FieldOperation])
+	r.Equal("redacted_accounts", entries[1].fields[logging.FieldTable])
+	r.IsType(time.Duration(0), entries[1].fields[logging.FieldDuration])
+
+	entries = nil
+	log(logging.Warn, "slow %s", "query")
+	r.Equal([]entry{{logging.Warn, "slow query", logging.Fields{}}}, entries)
+}
@@ -0,0 +1,40 @@
+package logging
+
+// Fields are the structured fields of a message, by name, see Logger.
+type Fields map[string]interface{}
+
+// The names of the fields of the messages.
+const (
+	// FieldSQL is the statement of the sql messages.
+	FieldSQL = "sql"
+	// FieldArgs are the arguments of the statement, with their sensitive
+	// values masked.
+	FieldArgs = "args"
+	// FieldDuration is the time.Duration of the operation of the
+	// operation messages.
+	FieldDuration = "duration"
+	// FieldOperation is the operation, such as "Create" or "All".
+	FieldOperation = "operation"
+	// FieldTable is the table of the model of the operation.
+	FieldTable = "table"
+	// FieldConnection is the ID of the connection.
+	FieldConnection = "connection"
+	// FieldTx is the ID of the transaction, 0 outside of them.
+	FieldTx = "tx"
+	// FieldRequest is the correlation ID of the context of the connection.
+	FieldRequest = "request"
+)
+
+// Logger logs the messages with their structured fields, rather than
+// formatted into them.
+type Logger interface {
+	Log(level Level, msg string, fields Fields)
+}
+
+// LoggerFunc is a function used as a Logger.
+type LoggerFunc func(level Level, msg string, fields Fields)
+
+// Log calls f.
+func (f LoggerFunc) Log(level Level, msg string, fields Fields) {
+	f(level, msg, fields)
+}
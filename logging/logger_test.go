@@ -0,0 +1,31 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type zapRecorder []string
+
+func (z *zapRecorder) log(level, msg string, kvs []interface{}) {
+	*z = append(*z, strings.TrimSpace(fmt.Sprintln(level, msg, kvs)))
+}
+
+func (z *zapRecorder) Debugw(msg string, kvs ...interface{}) { z.log("debug", msg, kvs) }
+func (z *zapRecorder) Infow(msg string, kvs ...interface{})  { z.log("info", msg, kvs) }
+func (z *zapRecorder) Warnw(msg string, kvs ...interface{})  { z.log("warn", msg, kvs) }
+func (z *zapRecorder) Errorw(msg string, kvs ...interface{}) { z.log("error", msg, kvs) }
+
+func Test_Zap(t *testing.T) {
+	r := require.New(t)
+
+	z := &zapRecorder{}
+	l := Zap(z)
+	l.Log(SQL, "sql", Fields{FieldSQL: "SELECT 1", FieldArgs: []interface{}{1}})
+	l.Log(Info, "done", nil)
+
+	r.Equal([]string{"debug sql [args [1] sql SELECT 1]", "info done []"}, []string(*z))
+}
@@ -0,0 +1,11 @@
+module github.com/WilliamNHarvey/pop/v6/logging/logrus
+
+go 1.16
+
+require (
+	github.com/WilliamNHarvey/pop/v6 v6.0.0
+	github.com/sirupsen/logrus v1.9.0
+	github.com/stretchr/testify v1.8.1
+)
+
+replace github.com/WilliamNHarvey/pop/v6 => ../..
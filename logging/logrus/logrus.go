@@ -0,0 +1,30 @@
+// Package logrus logs the messages of pop to a logrus logger. It is a
+// module of its own, for pop not to depend on logrus.
+//
+//	import poplogrus "github.com/WilliamNHarvey/pop/v6/logging/logrus"
+//
+//	pop.SetStructuredLogger(poplogrus.New(logrus.StandardLogger()))
+package logrus
+
+import (
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// New returns the Logger logging the messages to l, with the sql ones at
+// the debug level.
+func New(l logrus.FieldLogger) logging.Logger {
+	return logging.LoggerFunc(func(level logging.Level, msg string, fields logging.Fields) {
+		e := l.WithFields(logrus.Fields(fields))
+		switch level {
+		case logging.SQL, logging.Debug:
+			e.Debug(msg)
+		case logging.Info:
+			e.Info(msg)
+		case logging.Warn:
+			e.Warn(msg)
+		default:
+			e.Error(msg)
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package logrus
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New(t *testing.T) {
+	r := require.New(t)
+
+	out := &bytes.Buffer{}
+	lr := logrus.New()
+	lr.SetOutput(out)
+	lr.SetLevel(logrus.DebugLevel)
+	lr.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	l := New(lr)
+	l.Log(logging.SQL, "sql", logging.Fields{logging.FieldSQL: "SELECT 1"})
+	l.Log(logging.Error, "failed", nil)
+
+	r.Equal("level=debug msg=sql sql=\"SELECT 1\"\nlevel=error msg=failed\n", out.String())
+}
@@ -0,0 +1,43 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+)
+
+// LevelSQL is the slog level of the sql messages, below slog.LevelDebug.
+const LevelSQL = slog.LevelDebug - 4
+
+// Slog returns the Logger logging the messages to l, with the sql ones at
+// LevelSQL.
+func Slog(l *slog.Logger) Logger {
+	return LoggerFunc(func(level Level, msg string, fields Fields) {
+		lvl := slog.LevelError
+		switch level {
+		case SQL:
+			lvl = LevelSQL
+		case Debug:
+			lvl = slog.LevelDebug
+		case Info:
+			lvl = slog.LevelInfo
+		case Warn:
+			lvl = slog.LevelWarn
+		}
+		ctx := context.Background()
+		if !l.Enabled(ctx, lvl) {
+			return
+		}
+		attrs := make([]slog.Attr, 0, len(fields))
+		for k, v := range fields {
+			attrs = append(attrs, slog.Any(k, v))
+		}
+		sort.Slice(attrs, func(i, j int) bool {
+			return attrs[i].Key < attrs[j].Key
+		})
+		l.LogAttrs(ctx, lvl, msg, attrs...)
+	})
+}
@@ -0,0 +1,24 @@
+//go:build go1.21
+// +build go1.21
+
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Slog(t *testing.T) {
+	r := require.New(t)
+
+	out := &bytes.Buffer{}
+	l := Slog(slog.New(slog.NewTextHandler(out, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	l.Log(SQL, "sql", Fields{FieldSQL: "SELECT 1"})
+	l.Log(Warn, "slow", Fields{FieldTx: 2, FieldConnection: "conn-1"})
+
+	r.NotContains(out.String(), "SELECT 1")
+	r.Contains(out.String(), `level=WARN msg=slow connection=conn-1 tx=2`)
+}
@@ -0,0 +1,39 @@
+package logging
+
+import "sort"
+
+// ZapSugaredLogger is the part of *zap.SugaredLogger used by Zap.
+type ZapSugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// Zap returns the Logger logging the messages to l, a *zap.SugaredLogger,
+// with the sql ones at the debug level.
+//
+//	pop.SetStructuredLogger(logging.Zap(zapLogger.Sugar()))
+func Zap(l ZapSugaredLogger) Logger {
+	return LoggerFunc(func(level Level, msg string, fields Fields) {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		kvs := make([]interface{}, 0, 2*len(keys))
+		for _, k := range keys {
+			kvs = append(kvs, k, fields[k])
+		}
+		switch level {
+		case SQL, Debug:
+			l.Debugw(msg, kvs...)
+		case Info:
+			l.Infow(msg, kvs...)
+		case Warn:
+			l.Warnw(msg, kvs...)
+		default:
+			l.Errorw(msg, kvs...)
+		}
+	})
+}
@@ -0,0 +1,299 @@
+package pop
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+)
+
+func init() {
+	// the values of slices.Map decoded from JSON.
+	gob.Register([]interface{}{})
+	gob.Register(map[string]interface{}{})
+}
+
+// Marshal serializes a model, or a slice of models, with its loaded
+// associations, for caching it. Unlike a plain encoding/gob of the model,
+// it keeps the nil pointers, slices and maps apart from the empty ones,
+// so the associations not loaded stay nil, and it follows the cycles of
+// the association graph. The data is read back with Unmarshal.
+func Marshal(model interface{}) ([]byte, error) {
+	v := reflect.Indirect(reflect.ValueOf(model))
+	if !v.IsValid() {
+		return nil, fmt.Errorf("could not marshal a nil model")
+	}
+	e := &graphEncoder{seen: map[uintptr]int{}}
+	g := marshaledGraph{Type: v.Type().String()}
+	if err := e.encode(v, &g.Root); err != nil {
+		return nil, fmt.Errorf("could not marshal %s: %w", g.Type, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(g); err != nil {
+		return nil, fmt.Errorf("could not marshal %s: %w", g.Type, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal restores into model, a pointer, the model serialized by
+// Marshal. The type of model must be the one given to Marshal.
+func Unmarshal(data []byte, model interface{}) error {
+	v := reflect.ValueOf(model)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("could not unmarshal into %T: not a pointer", model)
+	}
+	g := marshaledGraph{}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&g); err != nil {
+		return fmt.Errorf("could not unmarshal %T: %w", model, err)
+	}
+	v = v.Elem()
+	if g.Type != v.Type().String() {
+		return fmt.Errorf("could not unmarshal %s into %T", g.Type, model)
+	}
+	d := &graphDecoder{refs: map[int]reflect.Value{}}
+	if err := d.decode(&g.Root, v); err != nil {
+		return fmt.Errorf("could not unmarshal %s: %w", g.Type, err)
+	}
+	return nil
+}
+
+// marshaledGraph is the encoding/gob representation of a model graph.
+type marshaledGraph struct {
+	Type string
+	Root graphNode
+}
+
+// graphNode is a value of the graph. Only the fields matching the kind of
+// the value are set.
+type graphNode struct {
+	// Nil is set for the nil pointers, slices, maps and interfaces.
+	Nil bool
+	// ID numbers a pointer the first time it is met, Ref points back to
+	// it the next times.
+	ID  int
+	Ref int
+
+	Bool   bool
+	Int    int64
+	Uint   uint64
+	Float  float64
+	String string
+	// Bytes holds []byte values and the values marshaling themselves.
+	Bytes []byte
+	// Any holds the dynamic values of interfaces.
+	Any interface{}
+
+	Fields []graphField
+	// Elems holds the elements of slices and arrays, and the keys and
+	// values of maps one after the other.
+	Elems []graphNode
+}
+
+type graphField struct {
+	Name string
+	Node graphNode
+}
+
+var (
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// marshalsItself tells whether the values of t are (un)marshaled by their
+// own methods, as time.Time and uuid.UUID.
+func marshalsItself(t reflect.Type) bool {
+	return t.Implements(binaryMarshalerType) && reflect.PtrTo(t).Implements(binaryUnmarshalerType)
+}
+
+type graphEncoder struct {
+	seen map[uintptr]int
+}
+
+func (e *graphEncoder) encode(v reflect.Value, n *graphNode) error {
+	if marshalsItself(v.Type()) {
+		b, err := v.Interface().(encoding.BinaryMarshaler).MarshalBinary()
+		n.Bytes = b
+		return err
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		n.Bool = v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n.Int = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n.Uint = v.Uint()
+	case reflect.Float32, reflect.Float64:
+		n.Float = v.Float()
+	case reflect.String:
+		n.String = v.String()
+	case reflect.Ptr:
+		if v.IsNil() {
+			n.Nil = true
+			return nil
+		}
+		if id, ok := e.seen[v.Pointer()]; ok {
+			n.Ref = id
+			return nil
+		}
+		n.ID = len(e.seen) + 1
+		e.seen[v.Pointer()] = n.ID
+		n.Elems = make([]graphNode, 1)
+		return e.encode(v.Elem(), &n.Elems[0])
+	case reflect.Interface:
+		if v.IsNil() {
+			n.Nil = true
+			return nil
+		}
+		n.Any = v.Elem().Interface()
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			gf := graphField{Name: f.Name}
+			if err := e.encode(v.Field(i), &gf.Node); err != nil {
+				return fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			n.Fields = append(n.Fields, gf)
+		}
+	case reflect.Slice:
+		if v.IsNil() {
+			n.Nil = true
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			n.Bytes = append([]byte{}, v.Bytes()...)
+			return nil
+		}
+		fallthrough
+	case reflect.Array:
+		n.Elems = make([]graphNode, v.Len())
+		for i := range n.Elems {
+			if err := e.encode(v.Index(i), &n.Elems[i]); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			n.Nil = true
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			var k, val graphNode
+			if err := e.encode(iter.Key(), &k); err != nil {
+				return err
+			}
+			if err := e.encode(iter.Value(), &val); err != nil {
+				return err
+			}
+			n.Elems = append(n.Elems, k, val)
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+type graphDecoder struct {
+	refs map[int]reflect.Value
+}
+
+func (d *graphDecoder) decode(n *graphNode, v reflect.Value) error {
+	if marshalsItself(v.Type()) {
+		return v.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(n.Bytes)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(n.Bool)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(n.Int)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(n.Uint)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(n.Float)
+	case reflect.String:
+		v.SetString(n.String)
+	case reflect.Ptr:
+		if n.Nil {
+			return nil
+		}
+		if n.Ref != 0 {
+			p, ok := d.refs[n.Ref]
+			if !ok || p.Type() != v.Type() {
+				return fmt.Errorf("invalid reference to a %s", v.Type())
+			}
+			v.Set(p)
+			return nil
+		}
+		if len(n.Elems) != 1 {
+			return fmt.Errorf("missing value of a %s", v.Type())
+		}
+		p := reflect.New(v.Type().Elem())
+		d.refs[n.ID] = p
+		v.Set(p)
+		return d.decode(&n.Elems[0], p.Elem())
+	case reflect.Interface:
+		if n.Nil || n.Any == nil {
+			return nil
+		}
+		a := reflect.ValueOf(n.Any)
+		if !a.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("cannot assign a %s to a %s", a.Type(), v.Type())
+		}
+		v.Set(a)
+	case reflect.Struct:
+		for i := range n.Fields {
+			f := v.FieldByName(n.Fields[i].Name)
+			if !f.IsValid() || !f.CanSet() {
+				continue
+			}
+			if err := d.decode(&n.Fields[i].Node, f); err != nil {
+				return fmt.Errorf("field %s: %w", n.Fields[i].Name, err)
+			}
+		}
+	case reflect.Slice:
+		if n.Nil {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte{}, n.Bytes...))
+			return nil
+		}
+		v.Set(reflect.MakeSlice(v.Type(), len(n.Elems), len(n.Elems)))
+		for i := range n.Elems {
+			if err := d.decode(&n.Elems[i], v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < len(n.Elems) && i < v.Len(); i++ {
+			if err := d.decode(&n.Elems[i], v.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if n.Nil {
+			return nil
+		}
+		t := v.Type()
+		v.Set(reflect.MakeMapWithSize(t, len(n.Elems)/2))
+		for i := 0; i+1 < len(n.Elems); i += 2 {
+			k := reflect.New(t.Key()).Elem()
+			if err := d.decode(&n.Elems[i], k); err != nil {
+				return err
+			}
+			val := reflect.New(t.Elem()).Elem()
+			if err := d.decode(&n.Elems[i+1], val); err != nil {
+				return err
+			}
+			v.SetMapIndex(k, val)
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/slices"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type marshalAuthor struct {
+	ID        int            `db:"id"`
+	Name      nulls.String   `db:"name"`
+	Born      nulls.Time     `db:"born"`
+	Meta      slices.Map     `db:"meta"`
+	CreatedAt time.Time      `db:"created_at"`
+	Books     []*marshalBook `has_many:"books"`
+	Drafts    *[]marshalBook `has_many:"drafts"`
+}
+
+type marshalBook struct {
+	ID     uuid.UUID      `db:"id"`
+	Title  string         `db:"title"`
+	Tags   slices.String  `db:"tags"`
+	Author *marshalAuthor `belongs_to:"author"`
+}
+
+func Test_Marshal_Model_Graph(t *testing.T) {
+	r := require.New(t)
+
+	born := time.Date(1965, 7, 31, 0, 0, 0, 0, time.UTC)
+	mark := &marshalAuthor{
+		ID:        1,
+		Name:      nulls.NewString("Mark"),
+		Born:      nulls.NewTime(born),
+		Meta:      slices.Map{"rank": float64(1), "genres": []interface{}{"fantasy"}},
+		CreatedAt: born,
+		Drafts:    &[]marshalBook{},
+	}
+	mark.Books = []*marshalBook{
+		{ID: uuid.Must(uuid.NewV4()), Title: "A", Tags: slices.String{"x"}, Author: mark},
+		{ID: uuid.Must(uuid.NewV4()), Title: "B", Author: mark},
+	}
+	jane := &marshalAuthor{ID: 2, Books: []*marshalBook{}}
+	authors := []*marshalAuthor{mark, jane, nil}
+
+	data, err := Marshal(authors)
+	r.NoError(err)
+
+	got := []*marshalAuthor{}
+	r.NoError(Unmarshal(data, &got))
+	r.Len(got, 3)
+	r.Nil(got[2])
+
+	m := got[0]
+	r.Equal(1, m.ID)
+	r.Equal(nulls.NewString("Mark"), m.Name)
+	r.True(m.Born.Time.Equal(born))
+	r.True(m.CreatedAt.Equal(born))
+	r.Equal(slices.Map{"rank": float64(1), "genres": []interface{}{"fantasy"}}, m.Meta)
+	r.NotNil(m.Drafts)
+	r.Empty(*m.Drafts)
+	r.Len(m.Books, 2)
+	r.Equal(mark.Books[0].ID, m.Books[0].ID)
+	r.Equal(slices.String{"x"}, m.Books[0].Tags)
+	r.Nil(m.Books[1].Tags)
+	// the cycles of the graph point back to the same records.
+	r.Same(m, m.Books[0].Author)
+	r.Same(m, m.Books[1].Author)
+
+	j := got[1]
+	r.False(j.Name.Valid)
+	r.NotNil(j.Books)
+	r.Empty(j.Books)
+	r.Nil(j.Drafts)
+	r.Nil(j.Meta)
+
+	r.Error(Unmarshal(data, &[]marshalBook{}))
+	r.Error(Unmarshal(data, got))
+}
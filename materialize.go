@@ -0,0 +1,127 @@
+package pop
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// Snapshot is a physical table holding the results of an expensive query,
+// refreshed periodically. It stands in for materialized views on the
+// dialects lacking them.
+type Snapshot struct {
+	// Table is the name of the table holding the snapshot.
+	Table string
+
+	conn    *Connection
+	query   string
+	args    []interface{}
+	stop    chan struct{}
+	stopped sync.Once
+
+	mu          sync.RWMutex
+	refreshedAt time.Time
+}
+
+// Materialize creates the destTable snapshot of the raw query, fills it
+// and, if refreshEvery is positive, refreshes it in the background every
+// refreshEvery until Stop is called. The query must be raw, as it is not
+// tied to a model.
+//
+//	q := c.RawQuery("SELECT user_id, SUM(total) AS total FROM orders GROUP BY user_id")
+//	s, err := pop.Materialize(c, q, "order_totals", 10*time.Minute)
+//	defer s.Stop()
+//	err = s.Q().Order("total desc").All(&totals)
+func Materialize(c *Connection, query *Query, destTable string, refreshEvery time.Duration) (*Snapshot, error) {
+	if query.RawSQL.Fragment == "" {
+		return nil, errors.New("materialize needs a raw query")
+	}
+
+	s := &Snapshot{
+		Table: destTable,
+		conn:  c,
+		query: query.RawSQL.Fragment,
+		args:  query.RawSQL.Arguments,
+		stop:  make(chan struct{}),
+	}
+
+	create := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s AS SELECT * FROM (%s) pop_snapshot WHERE 1 = 0", c.Dialect.Quote(destTable), s.query)
+	if err := c.RawQuery(create, s.args...).Exec(); err != nil {
+		return nil, fmt.Errorf("could not create snapshot table %s: %w", destTable, err)
+	}
+	if err := s.Refresh(); err != nil {
+		return nil, err
+	}
+
+	if refreshEvery > 0 {
+		go s.refreshEvery(refreshEvery)
+	}
+	return s, nil
+}
+
+// Refresh replaces the content of the snapshot with the current results
+// of the query. Readers see either the old or the new content.
+func (s *Snapshot) Refresh() error {
+	table := s.conn.Dialect.Quote(s.Table)
+	refresh := func(tx *Connection) error {
+		if err := tx.RawQuery(fmt.Sprintf("DELETE FROM %s", table)).Exec(); err != nil {
+			return err
+		}
+		return tx.RawQuery(fmt.Sprintf("INSERT INTO %s %s", table, s.query), s.args...).Exec()
+	}
+
+	var err error
+	if s.conn.TX != nil {
+		err = refresh(s.conn)
+	} else {
+		err = s.conn.Transaction(refresh)
+	}
+	if err != nil {
+		return fmt.Errorf("could not refresh snapshot table %s: %w", s.Table, err)
+	}
+
+	s.mu.Lock()
+	s.refreshedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// RefreshedAt returns the time of the last successful refresh.
+func (s *Snapshot) RefreshedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.refreshedAt
+}
+
+// Q returns a query reading the snapshot through any model matching its
+// columns, whatever the model's own table.
+//
+//	s.Q().Where("total > ?", 100).All(&totals)
+func (s *Snapshot) Q() *Query {
+	return s.conn.Table(s.Table)
+}
+
+// Stop ends the background refreshes of the snapshot. The table is kept.
+func (s *Snapshot) Stop() {
+	s.stopped.Do(func() {
+		close(s.stop)
+	})
+}
+
+func (s *Snapshot) refreshEvery(d time.Duration) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.Refresh(); err != nil {
+				log(logging.Error, "%v", err)
+			}
+		}
+	}
+}
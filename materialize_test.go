@@ -0,0 +1,55 @@
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type nameTotal struct {
+	Name  string `db:"name"`
+	Total int    `db:"total"`
+}
+
+func Test_Materialize(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		for _, name := range []string{"A", "A", "B"} {
+			r.NoError(tx.Create(&User{Name: nulls.NewString(name)}))
+		}
+
+		q := tx.RawQuery("SELECT name, COUNT(*) AS total FROM users WHERE name <> ? GROUP BY name", "C")
+		s, err := Materialize(tx, q, "name_totals", 0)
+		r.NoError(err)
+		defer s.Stop()
+		r.False(s.RefreshedAt().IsZero())
+
+		totals := []nameTotal{}
+		r.NoError(s.Q().Order("name").All(&totals))
+		r.Equal([]nameTotal{{"A", 2}, {"B", 1}}, totals)
+
+		r.NoError(tx.Create(&User{Name: nulls.NewString("B")}))
+		r.NoError(s.Q().Order("name").All(&totals))
+		r.Equal(1, totals[1].Total)
+
+		r.NoError(s.Refresh())
+		r.NoError(s.Q().Order("name").All(&totals))
+		r.Equal([]nameTotal{{"A", 2}, {"B", 2}}, totals)
+
+		// the table already exists and is refreshed in the background
+		s2, err := Materialize(tx, q, "name_totals", 10*time.Millisecond)
+		r.NoError(err)
+		first := s2.RefreshedAt()
+		r.Eventually(func() bool { return s2.RefreshedAt().After(first) }, time.Second, 5*time.Millisecond)
+		s2.Stop()
+		s2.Stop()
+
+		_, err = Materialize(tx, tx.Q(), "name_totals", 0)
+		r.Error(err)
+	})
+}
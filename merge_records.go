@@ -0,0 +1,98 @@
+package pop
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MergeReport reports the rows moved by MergeRecords, or to be moved by
+// MergeRecordsDryRun, by referencing column.
+type MergeReport struct {
+	Tables []MergedTable
+}
+
+// MergedTable is a column of a table referencing the merged entries, with
+// the number of its rows referencing the merged one.
+type MergedTable struct {
+	Table  string
+	Column string
+	Rows   int
+}
+
+// MergeRecords moves the rows referencing the entry of the model with ID
+// fromID to the one with ID toID, e.g. to merge duplicate accounts, in a
+// transaction. The referencing columns are the ones of the foreign keys
+// on the ID of the table of the model, read from the database; the
+// composite foreign keys are left out. The entry with ID fromID is left
+// as it is, destroy it once merged.
+//
+// The rows moved are reported by column. The merge fails and is rolled
+// back if a moved row conflicts with a unique constraint.
+//
+//	report, err := c.MergeRecords(&Customer{}, duplicate.ID, customer.ID)
+func (c *Connection) MergeRecords(model interface{}, fromID, toID interface{}) (*MergeReport, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	if c.TX == nil {
+		var report *MergeReport
+		err := c.Transaction(func(tx *Connection) error {
+			var err error
+			report, err = tx.MergeRecords(model, fromID, toID)
+			return err
+		})
+		return report, err
+	}
+
+	return c.mergeRecords(model, func(table, column string) (int, error) {
+		q := fmt.Sprintf("UPDATE %s SET %s = ? WHERE %s = ?", c.Dialect.Quote(table), c.Dialect.Quote(column), c.Dialect.Quote(column))
+		return c.RawQuery(q, toID, fromID).ExecWithCount()
+	})
+}
+
+// MergeRecordsDryRun reports the rows MergeRecords would move, without
+// moving them.
+func (c *Connection) MergeRecordsDryRun(model interface{}, fromID, toID interface{}) (*MergeReport, error) {
+	return c.mergeRecords(model, func(table, column string) (int, error) {
+		q := fmt.Sprintf("SELECT * FROM %s WHERE %s = ?", c.Dialect.Quote(table), c.Dialect.Quote(column))
+		return c.RawQuery(q, fromID).Count(nil)
+	})
+}
+
+// mergeRecords runs move on the columns referencing the ID of the table
+// of the model, returning their report.
+func (c *Connection) mergeRecords(model interface{}, move func(table, column string) (int, error)) (*MergeReport, error) {
+	m := NewModel(model, c.Context())
+	report := &MergeReport{}
+	err := c.timeFunc("MergeRecords", model, func() error {
+		schema, err := c.Schema()
+		if err != nil {
+			return err
+		}
+		for _, t := range schema {
+			for _, fk := range t.ForeignKeys {
+				if fk.RefTable != m.TableName() || len(fk.Columns) != 1 || len(fk.RefColumns) != 1 || fk.RefColumns[0] != m.IDField() {
+					continue
+				}
+				report.Tables = append(report.Tables, MergedTable{Table: t.Name, Column: fk.Columns[0]})
+			}
+		}
+		sort.Slice(report.Tables, func(i, j int) bool {
+			a, b := report.Tables[i], report.Tables[j]
+			return a.Table < b.Table || a.Table == b.Table && a.Column < b.Column
+		})
+
+		for i, t := range report.Tables {
+			n, err := move(t.Table, t.Column)
+			if err != nil {
+				return fmt.Errorf("could not merge %s.%s: %w", t.Table, t.Column, err)
+			}
+			report.Tables[i].Rows = n
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
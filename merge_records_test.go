@@ -0,0 +1,58 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mergedCustomer struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func Test_MergeRecords(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	for _, stmt := range []string{
+		"CREATE TABLE merged_customers (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE orders (id INTEGER PRIMARY KEY, customer_id INTEGER REFERENCES merged_customers (id), referrer_id INTEGER REFERENCES merged_customers (id))",
+		"CREATE TABLE profiles (id INTEGER PRIMARY KEY, customer_id INTEGER REFERENCES merged_customers (id) UNIQUE)",
+		"INSERT INTO merged_customers (id, name) VALUES (1, 'Ann'), (2, 'Ann B.')",
+		"INSERT INTO orders (customer_id, referrer_id) VALUES (2, NULL), (2, 1), (1, 2)",
+	} {
+		r.NoError(c.RawQuery(stmt).Exec())
+	}
+
+	report, err := c.MergeRecordsDryRun(&mergedCustomer{}, 2, 1)
+	r.NoError(err)
+	r.Equal([]MergedTable{
+		{Table: "orders", Column: "customer_id", Rows: 2},
+		{Table: "orders", Column: "referrer_id", Rows: 1},
+		{Table: "profiles", Column: "customer_id"},
+	}, report.Tables)
+
+	count, err := c.RawQuery("SELECT * FROM orders WHERE customer_id = 2").Count(nil)
+	r.NoError(err)
+	r.Equal(2, count)
+
+	report, err = c.MergeRecords(&mergedCustomer{}, 2, 1)
+	r.NoError(err)
+	r.Equal(2, report.Tables[0].Rows)
+	count, err = c.RawQuery("SELECT * FROM orders WHERE customer_id = 2 OR referrer_id = 2").Count(nil)
+	r.NoError(err)
+	r.Zero(count)
+
+	// the unique conflict rolls the merge back
+	r.NoError(c.RawQuery("INSERT INTO profiles (customer_id) VALUES (1), (2)").Exec())
+	r.NoError(c.RawQuery("UPDATE orders SET customer_id = 2 WHERE id = 1").Exec())
+	_, err = c.MergeRecords(&mergedCustomer{}, 2, 1)
+	r.Error(err)
+	count, err = c.RawQuery("SELECT * FROM orders WHERE customer_id = 2").Count(nil)
+	r.NoError(err)
+	r.Equal(1, count)
+}
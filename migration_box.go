@@ -33,7 +33,7 @@ func NewMigrationBox(fsys fs.FS, c *Connection) (MigrationBox, error) {
 			if content == "" {
 				return nil
 			}
-			_, err = tx.Store.Exec(content)
+			err = execMigrationContent(tx, content)
 			if err != nil {
 				return fmt.Errorf("error executing %s, sql: %s: %w", mf.Path, content, err)
 			}
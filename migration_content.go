@@ -39,3 +39,23 @@ func MigrationContent(mf Migration, c *Connection, r io.Reader, usingTemplate bo
 
 	return content, nil
 }
+
+// execMigrationContent runs the content of a migration, statement by
+// statement for the dialects whose drivers run one statement at a time.
+func execMigrationContent(tx *Connection, content string) error {
+	for _, stmt := range scriptStatements(tx.Dialect, content) {
+		if _, err := tx.Store.ExecContext(tx.Context(), stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scriptStatements splits the script in the statements to run one by one
+// on the dialect.
+func scriptStatements(d dialect, script string) []string {
+	if s, ok := d.(scriptable); ok {
+		return s.SplitScript(script)
+	}
+	return []string{script}
+}
@@ -63,7 +63,7 @@ func (m Migrator) UpLogOnly() error {
 				if exists {
 					continue
 				}
-				_, err = tx.Store.Exec(fmt.Sprintf("insert into %s (version) values ('%s')", mtn, mi.Version))
+				err = tx.RawQuery(fmt.Sprintf("insert into %s (version) values (?)", mtn), mi.Version).Exec()
 				if err != nil {
 					return fmt.Errorf("problem inserting migration version %s: %w", mi.Version, err)
 				}
@@ -98,12 +98,12 @@ func (m Migrator) UpTo(step int) (applied int, err error) {
 			if exists {
 				continue
 			}
-			err = c.Transaction(func(tx *Connection) error {
+			err = migrationTransaction(c, func(tx *Connection) error {
 				err := mi.Run(tx)
 				if err != nil {
 					return err
 				}
-				_, err = tx.Store.Exec(fmt.Sprintf("insert into %s (version) values ('%s')", mtn, mi.Version))
+				err = tx.RawQuery(fmt.Sprintf("insert into %s (version) values (?)", mtn), mi.Version).Exec()
 				if err != nil {
 					return fmt.Errorf("problem inserting migration version %s: %w", mi.Version, err)
 				}
@@ -129,37 +129,71 @@ func (m Migrator) UpTo(step int) (applied int, err error) {
 }
 
 // Down runs pending "down" migrations and rolls back the
-// database by the specified number of steps.
+// database by the specified number of steps, the last applied migrations
+// first. If step <= 0 all applied migrations are rolled back.
 func (m Migrator) Down(step int) error {
+	_, err := m.down(func(applied []string) ([]string, error) {
+		if step > 0 && len(applied) > step {
+			return applied[:step], nil
+		}
+		return applied, nil
+	})
+	return err
+}
+
+// DownTo rolls back the migrations applied after version, the last applied
+// first, leaving the database at version, and returns the number of
+// migrations rolled back. version must be applied, or "0" to roll back all
+// the migrations.
+func (m Migrator) DownTo(version string) (reverted int, err error) {
+	return m.down(func(applied []string) ([]string, error) {
+		if version == "0" {
+			return applied, nil
+		}
+		for i, v := range applied {
+			if v == version {
+				return applied[:i], nil
+			}
+		}
+		return nil, fmt.Errorf("migration version %s is not applied", version)
+	})
+}
+
+// down runs the "down" migrations of the versions selected among the
+// applied ones, sorted from the last one, in that order.
+func (m Migrator) down(selectVersions func(applied []string) ([]string, error)) (reverted int, err error) {
 	c := m.Connection
-	return m.exec(func() error {
+	err = m.exec(func() error {
 		mtn := c.MigrationTableName()
-		count, err := c.Count(mtn)
+		var applied []string
+		if err := c.RawQuery(fmt.Sprintf("select version from %s", mtn)).All(&applied); err != nil {
+			return fmt.Errorf("migration down: unable to list the applied migrations: %w", err)
+		}
+		sort.Sort(sort.Reverse(sort.StringSlice(applied)))
+		versions, err := selectVersions(applied)
 		if err != nil {
-			return fmt.Errorf("migration down: unable count existing migration: %w", err)
+			return fmt.Errorf("migration down: %w", err)
 		}
+
 		mfs := m.DownMigrations
 		mfs.Filter(func(mf Migration) bool {
 			return m.migrationIsCompatible(c.Dialect, mf)
 		})
 		sort.Sort(mfs)
-		// skip all ran migration
-		if len(mfs.Migrations) > count {
-			mfs.Migrations = mfs.Migrations[len(mfs.Migrations)-count:]
-		}
-		// run only required steps
-		if step > 0 && len(mfs.Migrations) >= step {
-			mfs.Migrations = mfs.Migrations[:step]
-		}
+		// the migrations of the dialect come before the ones for all
+		byVersion := map[string]Migration{}
 		for _, mi := range mfs.Migrations {
-			exists, err := c.Where("version = ?", mi.Version).Exists(mtn)
-			if err != nil {
-				return fmt.Errorf("problem checking for migration version %s: %w", mi.Version, err)
+			if _, ok := byVersion[mi.Version]; !ok {
+				byVersion[mi.Version] = mi
 			}
-			if !exists {
-				return fmt.Errorf("migration version %s does not exist", mi.Version)
+		}
+
+		for _, version := range versions {
+			mi, ok := byVersion[version]
+			if !ok {
+				return fmt.Errorf("migration down: no down migration for version %s", version)
 			}
-			err = c.Transaction(func(tx *Connection) error {
+			err = migrationTransaction(c, func(tx *Connection) error {
 				err := mi.Run(tx)
 				if err != nil {
 					return err
@@ -175,9 +209,11 @@ func (m Migrator) Down(step int) error {
 			}
 
 			log(logging.Info, "< %s", mi.Name)
+			reverted++
 		}
 		return nil
 	})
+	return
 }
 
 // Reset the database by running the down migrations followed by the up migrations.
@@ -202,20 +238,31 @@ func CreateSchemaMigrations(c *Connection) error {
 		return nil
 	}
 
-	return c.Transaction(func(tx *Connection) error {
+	return migrationTransaction(c, func(tx *Connection) error {
 		schemaMigrations := newSchemaMigrations(mtn)
 		smSQL, err := c.Dialect.FizzTranslator().CreateTable(schemaMigrations)
 		if err != nil {
 			return fmt.Errorf("could not build SQL for schema migration table: %w", err)
 		}
-		err = tx.RawQuery(smSQL).Exec()
-		if err != nil {
-			return fmt.Errorf("could not execute %s: %w", smSQL, err)
+		for _, stmt := range scriptStatements(c.Dialect, smSQL) {
+			err = tx.RawQuery(stmt).Exec()
+			if err != nil {
+				return fmt.Errorf("could not execute %s: %w", smSQL, err)
+			}
 		}
 		return nil
 	})
 }
 
+// migrationTransaction runs fn in a transaction, unless the dialect can not
+// change the schema inside transactions.
+func migrationTransaction(c *Connection, fn func(tx *Connection) error) error {
+	if !supports(c.Dialect, ddlInTransactions) {
+		return fn(c)
+	}
+	return c.Transaction(fn)
+}
+
 // CreateSchemaMigrations sets up a table to track migrations. This is an idempotent
 // operation.
 func (m Migrator) CreateSchemaMigrations() error {
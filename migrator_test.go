@@ -0,0 +1,64 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Migrator_DownTo(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	var reverted []string
+	m := NewMigrator(c)
+	for _, v := range []string{"1", "2", "3", "4", "5"} {
+		v := v
+		m.UpMigrations.Migrations = append(m.UpMigrations.Migrations, Migration{Version: v, Name: "m" + v, DBType: "all", Runner: func(Migration, *Connection) error {
+			return nil
+		}})
+		m.DownMigrations.Migrations = append(m.DownMigrations.Migrations, Migration{Version: v, Name: "m" + v, DBType: "all", Runner: func(Migration, *Connection) error {
+			reverted = append(reverted, v)
+			return nil
+		}})
+	}
+	// the migration of the dialect is run rather than the one for all.
+	m.DownMigrations.Migrations = append(m.DownMigrations.Migrations, Migration{Version: "5", Name: "m5", DBType: nameSQLite3, Runner: func(Migration, *Connection) error {
+		reverted = append(reverted, "5-sqlite")
+		return nil
+	}})
+	applied := func() []string {
+		var versions []string
+		r.NoError(c.RawQuery("SELECT version FROM schema_migration ORDER BY version").All(&versions))
+		return versions
+	}
+
+	_, err := m.UpTo(0)
+	r.NoError(err)
+
+	r.NoError(m.Down(2))
+	r.Equal([]string{"5-sqlite", "4"}, reverted)
+	r.Equal([]string{"1", "2", "3"}, applied())
+
+	_, err = m.DownTo("4")
+	r.Error(err)
+
+	reverted = nil
+	n, err := m.DownTo("1")
+	r.NoError(err)
+	r.Equal(2, n)
+	r.Equal([]string{"3", "2"}, reverted)
+	r.Equal([]string{"1"}, applied())
+
+	n, err = m.DownTo("1")
+	r.NoError(err)
+	r.Equal(0, n)
+
+	n, err = m.DownTo("0")
+	r.NoError(err)
+	r.Equal(1, n)
+	r.Empty(applied())
+}
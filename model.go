@@ -39,8 +39,12 @@ func NewModel(v Value, ctx context.Context) *Model {
 }
 
 // ID returns the ID of the Model. All models must have an `ID` field this is
-// of type `int`,`int64` or of type `uuid.UUID`.
+// of type `int`,`int64` or of type `uuid.UUID`, or a composite primary key
+// whose ID is the []interface{} of the values of its fields.
 func (m *Model) ID() interface{} {
+	if keys := m.compositeKey(); keys != nil {
+		return m.compositeID(keys)
+	}
 	fbn, err := m.fieldByName("ID")
 	if err != nil {
 		return nil
@@ -76,8 +80,12 @@ func (m *Model) IDField() string {
 	return dbField
 }
 
-// PrimaryKeyType gives the primary key type of the `Model`.
+// PrimaryKeyType gives the primary key type of the `Model`, "composite"
+// for a composite primary key.
 func (m *Model) PrimaryKeyType() (string, error) {
+	if m.compositeKey() != nil {
+		return compositeKeyType, nil
+	}
 	fbn, err := m.fieldByName("ID")
 	if err != nil {
 		return "", fmt.Errorf("model %T is missing required field ID", m.Value)
@@ -87,6 +95,9 @@ func (m *Model) PrimaryKeyType() (string, error) {
 
 // UsingAutoIncrement returns true if the model is not opting out of autoincrement
 func (m *Model) UsingAutoIncrement() bool {
+	if m.compositeKey() != nil {
+		return false
+	}
 	tag, err := m.tagForFieldByName("ID", "no_auto_increment")
 	// if there is no `no_auto_increment` tag, or tag isn't true, then we default to relying on auto increment
 	return err != nil || tag != "true"
@@ -181,10 +192,11 @@ func (m *Model) fieldByName(s string) (reflect.Value, error) {
 }
 
 func (m *Model) tagForFieldByName(fieldName string, tagName string) (string, error) {
-	el := reflect.TypeOf(m.Value).Elem()
-	if el.Kind() != reflect.Struct {
+	el := reflect.TypeOf(m.Value)
+	if el.Kind() != reflect.Ptr || el.Elem().Kind() != reflect.Struct {
 		return "", fmt.Errorf("model is not a struct")
 	}
+	el = el.Elem()
 	fbn, ok := el.FieldByName(fieldName)
 	if !ok {
 		return "", fmt.Errorf("model does not have a field named %s", fieldName)
@@ -193,8 +205,12 @@ func (m *Model) tagForFieldByName(fieldName string, tagName string) (string, err
 }
 
 func (m *Model) associationName() string {
-	tn := flect.Singularize(m.TableName())
-	return fmt.Sprintf("%s_id", tn)
+	tn := m.TableName()
+	if i := strings.LastIndex(tn, "."); i >= 0 {
+		// a schema qualified table, the key is named after the table only
+		tn = tn[i+1:]
+	}
+	return fmt.Sprintf("%s_id", flect.Singularize(tn))
 }
 
 func (m *Model) setID(i interface{}) {
@@ -227,6 +243,20 @@ func (m *Model) setCreatedAt(now time.Time) {
 	}
 }
 
+// keepCreatedAt returns a func setting CreatedAt back to its current
+// value.
+func (m *Model) keepCreatedAt() func() {
+	fbn, err := m.fieldByName("CreatedAt")
+	if err != nil {
+		return func() {}
+	}
+	v := reflect.New(fbn.Type()).Elem()
+	v.Set(fbn)
+	return func() {
+		fbn.Set(v)
+	}
+}
+
 func (m *Model) setUpdatedAt(now time.Time) {
 	fbn, err := m.fieldByName("UpdatedAt")
 	if err == nil {
@@ -241,7 +271,7 @@ func (m *Model) setUpdatedAt(now time.Time) {
 }
 
 func (m *Model) WhereID() string {
-	return fmt.Sprintf("%s.%s = ?", m.Alias(), m.IDField())
+	return m.whereKey(m.Alias())
 }
 
 func (m *Model) Alias() string {
@@ -253,6 +283,13 @@ func (m *Model) Alias() string {
 }
 
 func (m *Model) WhereNamedID() string {
+	if keys := m.compositeKey(); keys != nil {
+		preds := make([]string, len(keys))
+		for i, k := range keys {
+			preds[i] = fmt.Sprintf("%s.%s = :%s", m.Alias(), k.Column, k.Column)
+		}
+		return strings.Join(preds, " AND ")
+	}
 	return fmt.Sprintf("%s.%s = :%s", m.Alias(), m.IDField(), m.IDField())
 }
 
@@ -266,6 +303,14 @@ func (m *Model) iterate(fn modelIterable) error {
 		v := reflect.Indirect(reflect.ValueOf(m.Value))
 		for i := 0; i < v.Len(); i++ {
 			val := v.Index(i)
+			// the elements of the slices of pointers are the models,
+			// the nil ones are skipped.
+			if val.Kind() == reflect.Ptr {
+				if val.IsNil() {
+					continue
+				}
+				val = val.Elem()
+			}
 			newModel := &Model{
 				Value: val.Addr().Interface(),
 				ctx:   m.ctx,
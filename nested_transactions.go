@@ -0,0 +1,98 @@
+package pop
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// ErrNestedTransaction is returned by the transactions started in another
+// one with the NestedTransactionFail policy, and by the nested Rollback
+// unless it runs in a savepoint: it would roll back the outer transaction.
+var ErrNestedTransaction = errors.New("nested transaction")
+
+// NestedTransactionPolicy is what the transactions started in another one
+// do, see ConnectionDetails.NestedTransactions.
+type NestedTransactionPolicy string
+
+const (
+	// NestedTransactionJoin runs the nested transactions in the outer one:
+	// their error is returned to it, and they are committed or rolled back
+	// with it. It is the default policy.
+	NestedTransactionJoin NestedTransactionPolicy = "join"
+	// NestedTransactionFail fails the nested transactions with
+	// ErrNestedTransaction, e.g. to find the code relying on their commit.
+	NestedTransactionFail NestedTransactionPolicy = "fail"
+	// NestedTransactionSavepoint runs the nested transactions in savepoints
+	// of the outer one: their changes are rolled back to the savepoint when
+	// they fail, and committed with the outer transaction otherwise. The
+	// changes of the nested Rollback are always rolled back to the
+	// savepoint. They fail with ErrNestedTransaction on the databases
+	// without savepoints.
+	NestedTransactionSavepoint NestedTransactionPolicy = "savepoint"
+)
+
+// nestedTransaction runs fn, the function of a transaction started in the
+// one of c, according to the policy of the connection.
+func (c *Connection) nestedTransaction(fn func(tx *Connection) error) error {
+	switch c.Dialect.Details().NestedTransactions {
+	case NestedTransactionFail:
+		return fmt.Errorf("%w: transaction %d is in progress", ErrNestedTransaction, c.TX.ID)
+	case NestedTransactionSavepoint:
+		if !supports(c.Dialect, supportsSavepoints) {
+			return fmt.Errorf("%w: transaction %d is in progress", ErrNestedTransaction, c.TX.ID)
+		}
+		return c.savepointTransaction(fn, false)
+	}
+	txlog(logging.SQL, c, "JOIN Transaction ---")
+	return fn(c)
+}
+
+// nestedRollback runs fn, the function of a Rollback started in the
+// transaction of c, in a savepoint always rolled back to. Joining the
+// transaction would roll it back, so it fails with ErrNestedTransaction
+// with the other policies.
+func (c *Connection) nestedRollback(fn func(tx *Connection)) error {
+	if c.Dialect.Details().NestedTransactions == NestedTransactionSavepoint && supports(c.Dialect, supportsSavepoints) {
+		return c.savepointTransaction(func(tx *Connection) error {
+			fn(tx)
+			return nil
+		}, true)
+	}
+	return fmt.Errorf("%w: transaction %d is in progress", ErrNestedTransaction, c.TX.ID)
+}
+
+// savepointTransaction runs fn in a savepoint of the transaction of c,
+// rolled back to when fn fails, or always with rollback. A panic of fn
+// rolls back the whole transaction.
+func (c *Connection) savepointTransaction(fn func(tx *Connection) error, rollback bool) error {
+	name := fmt.Sprintf("pop_savepoint_%d", atomic.AddInt32(&c.TX.savepoints, 1))
+	exec := func(stmt string) error {
+		txlog(logging.SQL, c, stmt)
+		_, err := c.Store.ExecContext(c.Context(), stmt)
+		return err
+	}
+
+	if err := exec("SAVEPOINT " + name); err != nil {
+		return fmt.Errorf("could not create savepoint: %w", err)
+	}
+	if err := fn(c); err != nil || rollback {
+		if dberr := exec("ROLLBACK TO SAVEPOINT " + name); dberr != nil {
+			if err == nil {
+				return fmt.Errorf("database error on rolling back to savepoint: %w", dberr)
+			}
+			return fmt.Errorf("database error on rolling back to savepoint: %v: %w", dberr, err)
+		}
+		return err
+	}
+	// Oracle releases the savepoints with the transaction only.
+	if c.Dialect.Name() == nameOracle {
+		return nil
+	}
+	if err := exec("RELEASE SAVEPOINT " + name); err != nil {
+		return fmt.Errorf("could not release savepoint: %w", err)
+	}
+	return nil
+}
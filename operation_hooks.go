@@ -0,0 +1,59 @@
+package pop
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// OperationHook is called when an operation on a model starts, such as
+// "Create" or "All", with the table of the model, "" for the raw queries.
+// The returned function, if not nil, is called with the error of the
+// operation when it ends. It is meant to start and finish the spans of a
+// tracer, around the ones of its SQL driver tracing the statements.
+type OperationHook func(ctx context.Context, op string, table string) func(err error)
+
+var operationHook OperationHook
+
+// SetOperationHook sets the hook called around the operations on the
+// models. Use nil to remove it.
+//
+//	pop.SetOperationHook(func(ctx context.Context, op, table string) func(error) {
+//		span, _ := tracer.StartSpanFromContext(ctx, "pop."+op, tracer.ResourceName(table))
+//		return func(err error) { span.Finish(tracer.WithError(err)) }
+//	})
+func SetOperationHook(hook OperationHook) {
+	operationHook = hook
+}
+
+// traceOperation runs fn, the operation op on the model, with the
+// operation hook and the pprof labels of the connection, if any.
+func (c *Connection) traceOperation(op string, model interface{}, fn func() error) error {
+	labels := c.Dialect != nil && c.Dialect.Details() != nil && c.Dialect.Details().ProfilerLabels
+	hook := operationHook
+	if hook == nil && !labels {
+		return fn()
+	}
+
+	ctx := c.Context()
+	table := ""
+	if model != nil {
+		table = NewModel(model, ctx).TableName()
+	}
+	var finish func(error)
+	if hook != nil {
+		finish = hook(ctx, op, table)
+	}
+
+	var err error
+	if labels {
+		pprof.Do(ctx, pprof.Labels("pop.operation", op, "pop.table", table), func(context.Context) {
+			err = fn()
+		})
+	} else {
+		err = fn()
+	}
+	if finish != nil {
+		finish(err)
+	}
+	return err
+}
@@ -0,0 +1,42 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetOperationHook(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, &ConnectionDetails{ProfilerLabels: true})
+	r.NoError(c.RawQuery("CREATE TABLE feed_items (id INTEGER PRIMARY KEY, title TEXT NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)").Exec())
+
+	var ops []string
+	var errs []error
+	defer SetOperationHook(nil)
+	SetOperationHook(func(ctx context.Context, op, table string) func(error) {
+		r.NotNil(ctx)
+		ops = append(ops, op+" "+table)
+		return func(err error) {
+			errs = append(errs, err)
+		}
+	})
+
+	r.NoError(c.Create(&feedItem{Title: "a"}))
+	items := []feedItem{}
+	r.NoError(c.All(&items))
+	r.Len(items, 1)
+	r.NoError(c.RawQuery("DELETE FROM feed_items WHERE id = 0").Exec())
+	err := c.Find(&feedItem{}, 42)
+	r.Error(err)
+
+	r.Equal([]string{"Create feed_items", "All feed_items", "Exec ", "First feed_items"}, ops)
+	r.Len(errs, 4)
+	r.NoError(errs[0])
+	r.Equal(err, errs[3])
+}
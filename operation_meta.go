@@ -0,0 +1,42 @@
+package pop
+
+import "context"
+
+// operationMetaKey wraps the keys of the operation metadata, so they don't
+// collide with the other values of the context.
+type operationMetaKey struct {
+	key interface{}
+}
+
+// WithOperationMeta returns a copy of the context holding the value of the
+// operation metadata key, read in the callbacks and the validations of the
+// models with OperationMeta. It lets the handlers pass flags down to the
+// model hooks without global variables or struct fields.
+//
+//	ctx := pop.WithOperationMeta(r.Context(), "imported", true)
+//	err := c.WithContext(ctx).Create(&user)
+//
+//	func (u *User) AfterCreate(tx *pop.Connection) error {
+//		if imported, _ := tx.OperationMeta("imported").(bool); imported {
+//			return nil
+//		}
+//		return notify(u)
+//	}
+func WithOperationMeta(ctx context.Context, key, value interface{}) context.Context {
+	return context.WithValue(ctx, operationMetaKey{key}, value)
+}
+
+// OperationMeta returns the value of the operation metadata key set on the
+// context with WithOperationMeta, or nil.
+func OperationMeta(ctx context.Context, key interface{}) interface{} {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Value(operationMetaKey{key})
+}
+
+// OperationMeta returns the value of the operation metadata key set on the
+// context of the connection with WithOperationMeta, or nil.
+func (c *Connection) OperationMeta(key interface{}) interface{} {
+	return OperationMeta(c.Context(), key)
+}
@@ -0,0 +1,57 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobuffalo/validate/v3"
+	"github.com/stretchr/testify/require"
+)
+
+type notifiedWidget struct {
+	ID       int    `db:"id"`
+	Name     string `db:"name"`
+	Imported bool   `db:"-"`
+	Notified bool   `db:"-"`
+}
+
+func (w *notifiedWidget) Validate(tx *Connection) (*validate.Errors, error) {
+	w.Imported, _ = tx.OperationMeta("imported").(bool)
+	return validate.NewErrors(), nil
+}
+
+func (w *notifiedWidget) AfterCreate(tx *Connection) error {
+	skip, _ := tx.OperationMeta("skip notification").(bool)
+	w.Notified = !skip
+	return nil
+}
+
+func Test_OperationMeta(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE notified_widgets (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)").Exec())
+
+	w := &notifiedWidget{Name: "bolt"}
+	verrs, err := c.ValidateAndCreate(w)
+	r.NoError(err)
+	r.False(verrs.HasAny())
+	r.False(w.Imported)
+	r.True(w.Notified)
+
+	ctx := WithOperationMeta(context.Background(), "skip notification", true)
+	ctx = WithOperationMeta(ctx, "imported", true)
+	w = &notifiedWidget{Name: "nut"}
+	r.NoError(c.WithContext(ctx).Transaction(func(tx *Connection) error {
+		verrs, err := tx.ValidateAndCreate(w)
+		r.False(verrs.HasAny())
+		return err
+	}))
+	r.True(w.Imported)
+	r.False(w.Notified)
+
+	r.Nil(OperationMeta(context.WithValue(context.Background(), "imported", true), "imported"))
+}
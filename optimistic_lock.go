@@ -0,0 +1,102 @@
+package pop
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+)
+
+// ErrStaleObject is returned by Update and UpdateColumns when the row of a
+// model with a lock version was updated since the model was loaded.
+var ErrStaleObject = errors.New("stale object")
+
+// lockField returns the field and column of the lock version of the model:
+// the integer field tagged `lock:"true"`, or else the LockVersion one.
+// The column is "lock_version" unless the field has a db tag.
+func (m *Model) lockField() (string, string) {
+	t := reflect.TypeOf(m.Value)
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return "", ""
+	}
+	f, ok := reflect.StructField{}, false
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("lock") == "true" {
+			f, ok = t.Field(i), true
+			break
+		}
+	}
+	if !ok {
+		f, ok = t.FieldByName("LockVersion")
+	}
+	if !ok {
+		return "", ""
+	}
+	switch f.Type.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		return "", ""
+	}
+	if column := f.Tag.Get("db"); column != "" && column != "-" {
+		return f.Name, column
+	}
+	return f.Name, "lock_version"
+}
+
+// lockColumn returns the column of the lock version of the model, "" when
+// the model is not locked.
+func (m *Model) lockColumn() string {
+	_, column := m.lockField()
+	return column
+}
+
+// incrementLockVersion increments the lock version of the model when its
+// column is updated, and returns the function restoring it after a failed
+// update.
+func (m *Model) incrementLockVersion(cols columns.Columns) func() {
+	name, column := m.lockField()
+	if _, ok := cols.Cols[column]; name == "" || !ok {
+		return func() {}
+	}
+	fbn, err := m.fieldByName(name)
+	if err != nil {
+		return func() {}
+	}
+	version := fbn.Int()
+	fbn.SetInt(version + 1)
+	return func() { fbn.SetInt(version) }
+}
+
+// whereNamedUpdate returns the named WHERE clause of the updates of the
+// model, which also match the previous lock version when it is updated.
+func (m *Model) whereNamedUpdate(cols columns.Columns) string {
+	where := m.WhereNamedID()
+	if column := m.lockColumn(); column != "" {
+		if _, ok := cols.Cols[column]; ok {
+			where += fmt.Sprintf(" AND %s.%s = :%s - 1", m.Alias(), column, column)
+		}
+	}
+	return where
+}
+
+// checkStale returns ErrStaleObject when the update of a model with an
+// updated lock version matched no row.
+func checkStale(model *Model, cols columns.Columns, res sql.Result) error {
+	column := model.lockColumn()
+	if _, ok := cols.Cols[column]; column == "" || !ok {
+		return nil
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("update %s %v: %w", model.TableName(), model.ID(), ErrStaleObject)
+	}
+	return nil
+}
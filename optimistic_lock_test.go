@@ -0,0 +1,83 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lockedPage struct {
+	ID          int    `db:"id"`
+	Title       string `db:"title"`
+	LockVersion int    `db:"lock_version"`
+}
+
+type taggedPage struct {
+	ID       int    `db:"id"`
+	Title    string `db:"title"`
+	Revision int64  `db:"revision" lock:"true"`
+}
+
+func Test_OptimisticLock(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE locked_pages (id INTEGER PRIMARY KEY, title TEXT NOT NULL, lock_version INTEGER NOT NULL)").Exec())
+	r.NoError(c.RawQuery("CREATE TABLE tagged_pages (id INTEGER PRIMARY KEY, title TEXT NOT NULL, revision INTEGER NOT NULL)").Exec())
+
+	page := &lockedPage{Title: "draft"}
+	r.NoError(c.Create(page))
+
+	first, second := &lockedPage{}, &lockedPage{}
+	r.NoError(c.Find(first, page.ID))
+	r.NoError(c.Find(second, page.ID))
+
+	first.Title = "first"
+	r.NoError(c.Update(first))
+	r.Equal(1, first.LockVersion)
+
+	second.Title = "second"
+	err := c.Update(second)
+	r.True(errors.Is(err, ErrStaleObject), err)
+	r.Equal(0, second.LockVersion)
+	r.True(errors.Is(c.UpdateColumns(second, "title"), ErrStaleObject))
+
+	r.NoError(c.Reload(second))
+	second.Title = "second"
+	r.NoError(c.UpdateColumns(second, "title"))
+	r.Equal(2, second.LockVersion)
+
+	found := &lockedPage{}
+	r.NoError(c.Find(found, page.ID))
+	r.Equal("second", found.Title)
+	r.Equal(2, found.LockVersion)
+
+	tagged := &taggedPage{Title: "draft"}
+	r.NoError(c.Create(tagged))
+	tagged.Title = "tagged"
+	r.NoError(c.Update(tagged))
+	r.Equal(int64(1), tagged.Revision)
+	tagged.Revision = 0
+	r.True(errors.Is(c.Update(tagged), ErrStaleObject))
+}
+
+func Test_Model_lockField(t *testing.T) {
+	r := require.New(t)
+
+	name, column := NewModel(&lockedPage{}, nil).lockField()
+	r.Equal("LockVersion", name)
+	r.Equal("lock_version", column)
+	name, column = NewModel(&taggedPage{}, nil).lockField()
+	r.Equal("Revision", name)
+	r.Equal("revision", column)
+
+	type plain struct {
+		ID          int    `db:"id"`
+		LockVersion string `db:"lock_version"`
+	}
+	r.Equal("", NewModel(&plain{}, nil).lockColumn())
+}
@@ -0,0 +1,110 @@
+package pop
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Pager writes the paging clauses of the queries, for the dialects which
+// do not page them with LIMIT and OFFSET. Dialects register their pager
+// in Pagers, under their name.
+type Pager interface {
+	// Paginate returns the query returning at most limit rows of sql,
+	// after skipping offset rows. A zero limit keeps all the rows, a zero
+	// offset skips none.
+	Paginate(sql string, limit, offset int) string
+	// Paginated reports whether sql is already paged, so a raw query
+	// written with its own paging clauses is not paged again.
+	Paginated(sql string) bool
+}
+
+// Pagers holds the pagers of the dialects, keyed by the name of the
+// dialect. The queries of the dialects without a pager are paged by
+// LimitOffsetPager.
+var Pagers = map[string]Pager{}
+
+func init() {
+	Pagers[nameOracle] = OffsetFetchPager{}
+}
+
+var (
+	_ Pager = LimitOffsetPager{}
+	_ Pager = OffsetFetchPager{}
+	_ Pager = RowNumPager{}
+)
+
+// pagerFor returns the pager of the dialect.
+func pagerFor(d dialect) Pager {
+	if p, ok := Pagers[d.Name()]; ok {
+		return p
+	}
+	return LimitOffsetPager{}
+}
+
+// LimitOffsetPager pages the queries with the LIMIT and OFFSET clauses,
+// e.g. "LIMIT 20 OFFSET 40".
+type LimitOffsetPager struct{}
+
+func (LimitOffsetPager) Paginate(sql string, limit, offset int) string {
+	if limit > 0 {
+		sql = fmt.Sprintf("%s LIMIT %d", sql, limit)
+	}
+	if offset > 0 {
+		sql = fmt.Sprintf("%s OFFSET %d", sql, offset)
+	}
+	return sql
+}
+
+func (LimitOffsetPager) Paginated(sql string) bool {
+	return hasLimitOrOffset(sql)
+}
+
+// OffsetFetchPager pages the queries with the OFFSET and FETCH clauses of
+// the SQL standard, e.g. "OFFSET 40 ROWS FETCH NEXT 20 ROWS ONLY".
+type OffsetFetchPager struct{}
+
+var regexpMatchOffsetRows = regexp.MustCompile(`(?i)\s+offset\s+[0-9]+\s+rows?\s*$`)
+
+func (OffsetFetchPager) Paginate(sql string, limit, offset int) string {
+	if offset > 0 {
+		sql = fmt.Sprintf("%s OFFSET %d ROWS", sql, offset)
+		if limit > 0 {
+			sql = fmt.Sprintf("%s FETCH NEXT %d ROWS ONLY", sql, limit)
+		}
+		return sql
+	}
+	if limit > 0 {
+		sql = fmt.Sprintf("%s FETCH FIRST %d ROWS ONLY", sql, limit)
+	}
+	return sql
+}
+
+func (OffsetFetchPager) Paginated(sql string) bool {
+	return hasLimitOrOffset(sql) || regexpMatchOffsetRows.MatchString(sql)
+}
+
+// RowNumPager pages the queries by filtering on the ROWNUM pseudocolumn,
+// for Oracle databases older than 12c. The queries skipping rows return
+// their row number in an additional pop_rownum column, the connection
+// must be Unsafe to scan them into structs.
+type RowNumPager struct{}
+
+var regexpMatchRowNum = regexp.MustCompile(`(?i)\brownum\b`)
+
+func (RowNumPager) Paginate(sql string, limit, offset int) string {
+	if offset > 0 {
+		where := ""
+		if limit > 0 {
+			where = fmt.Sprintf(" WHERE ROWNUM <= %d", offset+limit)
+		}
+		return fmt.Sprintf("SELECT * FROM (SELECT pop_page.*, ROWNUM pop_rownum FROM (%s) pop_page%s) WHERE pop_rownum > %d", sql, where, offset)
+	}
+	if limit > 0 {
+		return fmt.Sprintf("SELECT * FROM (%s) WHERE ROWNUM <= %d", sql, limit)
+	}
+	return sql
+}
+
+func (RowNumPager) Paginated(sql string) bool {
+	return regexpMatchRowNum.MatchString(sql)
+}
@@ -0,0 +1,57 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LimitOffsetPager(t *testing.T) {
+	r := require.New(t)
+	p := LimitOffsetPager{}
+
+	r.Equal("SELECT * FROM users LIMIT 10", p.Paginate("SELECT * FROM users", 10, 0))
+	r.Equal("SELECT * FROM users LIMIT 10 OFFSET 20", p.Paginate("SELECT * FROM users", 10, 20))
+	r.Equal("SELECT * FROM users", p.Paginate("SELECT * FROM users", 0, 0))
+
+	r.True(p.Paginated("SELECT * FROM users LIMIT 10"))
+	r.False(p.Paginated("SELECT * FROM users"))
+}
+
+func Test_OffsetFetchPager(t *testing.T) {
+	r := require.New(t)
+	p := OffsetFetchPager{}
+
+	r.Equal("SELECT * FROM users FETCH FIRST 10 ROWS ONLY", p.Paginate("SELECT * FROM users", 10, 0))
+	r.Equal("SELECT * FROM users OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", p.Paginate("SELECT * FROM users", 10, 20))
+	r.Equal("SELECT * FROM users OFFSET 20 ROWS", p.Paginate("SELECT * FROM users", 0, 20))
+
+	r.True(p.Paginated("SELECT * FROM users FETCH FIRST 10 ROWS ONLY"))
+	r.True(p.Paginated("SELECT * FROM users OFFSET 20 ROWS"))
+	r.False(p.Paginated("SELECT * FROM users"))
+}
+
+func Test_RowNumPager(t *testing.T) {
+	r := require.New(t)
+	p := RowNumPager{}
+
+	r.Equal("SELECT * FROM (SELECT * FROM users ORDER BY id) WHERE ROWNUM <= 10", p.Paginate("SELECT * FROM users ORDER BY id", 10, 0))
+	r.Equal("SELECT * FROM (SELECT pop_page.*, ROWNUM pop_rownum FROM (SELECT * FROM users ORDER BY id) pop_page WHERE ROWNUM <= 30) WHERE pop_rownum > 20", p.Paginate("SELECT * FROM users ORDER BY id", 10, 20))
+
+	r.True(p.Paginated("SELECT * FROM users WHERE rownum <= 10"))
+	r.False(p.Paginated("SELECT * FROM users"))
+}
+
+func Test_Pagers(t *testing.T) {
+	r := require.New(t)
+
+	r.IsType(OffsetFetchPager{}, pagerFor(&oracle{}))
+	r.IsType(LimitOffsetPager{}, pagerFor(&mysql{}))
+
+	c := &Connection{Dialect: &oracle{translateCache: map[string]string{}}}
+	q, _ := Q(c).Paginate(3, 10).ToSQL(&Model{Value: &User{}}, "id")
+	r.Equal("SELECT id FROM users users OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", q)
+
+	q, _ = Q(c).RawQuery("SELECT id FROM users FETCH FIRST 5 ROWS ONLY").Paginate(3, 10).ToSQL(&Model{Value: &User{}})
+	r.Equal("SELECT id FROM users FETCH FIRST 5 ROWS ONLY", q)
+}
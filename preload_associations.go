@@ -40,6 +40,7 @@ type ModelMetaInfo struct {
 	Model        *Model
 	mapper       *reflectx.Mapper
 	nestedFields map[string][]string
+	scopes       eagerScopes
 }
 
 func (mmi *ModelMetaInfo) init() {
@@ -59,7 +60,11 @@ func (mmi *ModelMetaInfo) iterate(fn func(reflect.Value)) {
 	modelValue := reflect.Indirect(reflect.ValueOf(mmi.Model.Value))
 	if modelValue.Kind() == reflect.Slice || modelValue.Kind() == reflect.Array {
 		for i := 0; i < modelValue.Len(); i++ {
-			fn(modelValue.Index(i))
+			v := modelValue.Index(i)
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				continue
+			}
+			fn(v)
 		}
 		return
 	}
@@ -156,6 +161,9 @@ func (ami *AssociationMetaInfo) getDBFieldTaggedWith(value string) *reflectx.Fie
 
 func (ami *AssociationMetaInfo) fkName() string {
 	t := ami.Field.Type
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Slice {
+		t = t.Elem()
+	}
 	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
 		t = reflectx.Deref(t.Elem())
 	}
@@ -167,7 +175,13 @@ func (ami *AssociationMetaInfo) fkName() string {
 // preload is the query mode used to load associations from database
 // similar to the active record default approach on Rails.
 func preload(tx *Connection, model interface{}, fields ...string) error {
+	return preloadScoped(tx, model, nil, fields...)
+}
+
+// preloadScoped preloads the associations, scoping their queries.
+func preloadScoped(tx *Connection, model interface{}, scopes eagerScopes, fields ...string) error {
 	mmi := NewModelMetaInfo(NewModel(model, tx.Context()))
+	mmi.scopes = scopes
 
 	preloadFields, err := mmi.preloadFields(fields...)
 	if err != nil {
@@ -244,6 +258,7 @@ func preloadHasMany(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMetaInf
 	q := tx.Q()
 	q.eager = false
 	q.eagerFields = []string{}
+	mmi.scopes.apply(asoc.Path, q)
 
 	slice := asoc.toSlice()
 
@@ -259,7 +274,7 @@ func preloadHasMany(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMetaInf
 	// 2.1) load all nested associations from this assoc.
 	if asocNestedFields, ok := mmi.nestedFields[asoc.Path]; ok {
 		for _, asocNestedField := range asocNestedFields {
-			if err := preload(tx, slice.Interface(), asocNestedField); err != nil {
+			if err := preloadScoped(tx, slice.Interface(), mmi.scopes.nested(asoc.Path), asocNestedField); err != nil {
 				return err
 			}
 		}
@@ -279,18 +294,11 @@ func preloadHasMany(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMetaInf
 				// we checked whether the field should be set. Otherwise, we'll set a zero value!
 				//
 				// This is most likely the reason for https://github.com/gobuffalo/pop/issues/139
-				modelAssociationField := mmi.mapper.FieldByName(mvalue, asoc.Name)
-				switch {
-				case modelAssociationField.Kind() == reflect.Slice || modelAssociationField.Kind() == reflect.Array:
-					modelAssociationField.Set(reflect.Append(modelAssociationField, asocValue))
-				case modelAssociationField.Kind() == reflect.Ptr:
-					modelAssociationField.Elem().Set(reflect.Append(modelAssociationField.Elem(), asocValue))
-				default:
-					modelAssociationField.Set(asocValue)
-				}
+				appendAssociation(mmi.mapper.FieldByName(mvalue, asoc.Name), asocValue)
 			}
 		}
 	})
+	mmi.initEmptyAssociation(asoc)
 
 	return nil
 }
@@ -316,6 +324,7 @@ func preloadHasOne(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMetaInfo
 	q := tx.Q()
 	q.eager = false
 	q.eagerFields = []string{}
+	mmi.scopes.apply(asoc.Path, q)
 
 	slice := asoc.toSlice()
 	err := q.Where(fmt.Sprintf("%s in (?)", fk), ids).All(slice.Interface())
@@ -326,7 +335,7 @@ func preloadHasOne(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMetaInfo
 	// 2.1) load all nested associations from this assoc.
 	if asocNestedFields, ok := mmi.nestedFields[asoc.Path]; ok {
 		for _, asocNestedField := range asocNestedFields {
-			if err := preload(tx, slice.Interface(), asocNestedField); err != nil {
+			if err := preloadScoped(tx, slice.Interface(), mmi.scopes.nested(asoc.Path), asocNestedField); err != nil {
 				return err
 			}
 		}
@@ -385,6 +394,7 @@ func preloadBelongsTo(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMetaI
 	q := tx.Q()
 	q.eager = false
 	q.eagerFields = []string{}
+	mmi.scopes.apply(asoc.Path, q)
 
 	slice := asoc.toSlice()
 	err := q.Where(fmt.Sprintf("%s in (?)", fk), fkids).All(slice.Interface())
@@ -395,7 +405,7 @@ func preloadBelongsTo(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMetaI
 	// 2.1) load all nested associations from this assoc.
 	if asocNestedFields, ok := mmi.nestedFields[asoc.Path]; ok {
 		for _, asocNestedField := range asocNestedFields {
-			if err := preload(tx, slice.Interface(), asocNestedField); err != nil {
+			if err := preloadScoped(tx, slice.Interface(), mmi.scopes.nested(asoc.Path), asocNestedField); err != nil {
 				return err
 			}
 		}
@@ -461,13 +471,8 @@ func preloadManyToMany(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMeta
 	sql, args, _ := sqlx.In(sql, ids)
 	sql = tx.Dialect.TranslateSQL(sql)
 
-	cn, err := tx.Store.Transaction()
-	if err != nil {
-		return err
-	}
-
-	txlog(logging.SQL, cn, sql, args...)
-	rows, err := cn.Queryx(sql, args...)
+	txlog(logging.SQL, tx, sql, args...)
+	rows, err := tx.Store.QueryxContext(tx.Context(), sql, args...)
 	if err != nil {
 		return err
 	}
@@ -498,6 +503,7 @@ func preloadManyToMany(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMeta
 	q := tx.Q()
 	q.eager = false
 	q.eagerFields = []string{}
+	mmi.scopes.apply(asoc.Path, q)
 
 	if strings.TrimSpace(asoc.Field.Tag.Get("order_by")) != "" {
 		q.Order(asoc.Field.Tag.Get("order_by"))
@@ -509,7 +515,7 @@ func preloadManyToMany(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMeta
 	// 2.2) load all nested associations from this assoc.
 	if asocNestedFields, ok := mmi.nestedFields[asoc.Path]; ok {
 		for _, asocNestedField := range asocNestedFields {
-			if err := preload(tx, slice.Interface(), asocNestedField); err != nil {
+			if err := preloadScoped(tx, slice.Interface(), mmi.scopes.nested(asoc.Path), asocNestedField); err != nil {
 				return err
 			}
 		}
@@ -529,17 +535,49 @@ func preloadManyToMany(tx *Connection, asoc *AssociationMetaInfo, mmi *ModelMeta
 						// we checked whether the field should be set. Otherwise, we'll set a zero value!
 						//
 						// This is most likely the reason for https://github.com/gobuffalo/pop/issues/139
-						modelAssociationField := mmi.mapper.FieldByName(mvalue, asoc.Name)
-						modelAssociationField.Set(reflect.Append(modelAssociationField, asocValue))
+						appendAssociation(mmi.mapper.FieldByName(mvalue, asoc.Name), asocValue)
 					}
 				}
 			}
 		}
 	})
+	mmi.initEmptyAssociation(asoc)
 
 	return nil
 }
 
+// appendAssociation appends the associated record to the slice field of a
+// model, or to the slice it points to.
+func appendAssociation(field, asocValue reflect.Value) {
+	if field.Kind() == reflect.Ptr {
+		field = field.Elem()
+	}
+	field.Set(reflect.Append(field, asocValue))
+}
+
+// initEmptyAssociation sets the has_many or many_to_many association of
+// the models without associated records to an empty slice, when it is a
+// pointer to a slice or a slice of pointers, so that it tells the loaded
+// associations apart from the ones not loaded, left nil.
+func (mmi *ModelMetaInfo) initEmptyAssociation(asoc *AssociationMetaInfo) {
+	ft := asoc.Field.Type
+	ptrToSlice := ft.Kind() == reflect.Ptr && ft.Elem().Kind() == reflect.Slice
+	sliceOfPtrs := ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Ptr
+	if !ptrToSlice && !sliceOfPtrs {
+		return
+	}
+	mmi.iterate(func(mvalue reflect.Value) {
+		// FieldByName allocates the nil pointers.
+		field := mmi.mapper.FieldByName(mvalue, asoc.Name)
+		if field.Kind() == reflect.Ptr {
+			field = field.Elem()
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeSlice(field.Type(), 0, 0))
+		}
+	})
+}
+
 func isFieldNilPtr(val reflect.Value, fi *reflectx.FieldInfo) bool {
 	fieldValue := reflectx.FieldByIndexesReadOnly(val, fi.Index)
 	return fieldValue.Kind() == reflect.Ptr && fieldValue.IsNil()
@@ -1,9 +1,11 @@
 package pop
 
 import (
+	"testing"
+	"time"
+
 	"github.com/gobuffalo/nulls"
 	"github.com/stretchr/testify/require"
-	"testing"
 )
 
 func Test_New_Implementation_For_Nplus1(t *testing.T) {
@@ -373,3 +375,102 @@ func Test_New_Implementation_For_HasMany_Ptr_Field(t *testing.T) {
 		SetEagerMode(EagerDefault)
 	})
 }
+
+// pointerAssocsUser and pointerAssocsBook have all the columns of users
+// and books, which are cached by table.
+type pointerAssocsUser struct {
+	ID           int           `db:"id"`
+	UserName     string        `db:"user_name"`
+	Email        string        `db:"email"`
+	Name         nulls.String  `db:"name"`
+	Alive        nulls.Bool    `db:"alive"`
+	CreatedAt    time.Time     `db:"created_at"`
+	UpdatedAt    time.Time     `db:"updated_at"`
+	BirthDate    nulls.Time    `db:"birth_date"`
+	Bio          nulls.String  `db:"bio"`
+	Price        nulls.Float64 `db:"price"`
+	FullName     nulls.String  `db:"full_name" select:"name as full_name"`
+	Books        *[]Book       `has_many:"books" order_by:"title asc" fk_id:"user_id"`
+	BookPtrs     []*Book       `has_many:"books" order_by:"title asc" fk_id:"user_id"`
+	FavoriteSong *Song         `has_one:"song" fk_id:"u_id"`
+	Houses       *Addresses    `many_to_many:"users_addresses"`
+}
+
+func (pointerAssocsUser) TableName() string {
+	return "users"
+}
+
+type pointerAssocsBook struct {
+	ID          int                `db:"id"`
+	Title       string             `db:"title"`
+	Isbn        string             `db:"isbn"`
+	UserID      nulls.Int          `db:"user_id"`
+	User        *pointerAssocsUser `belongs_to:"user"`
+	Description string             `db:"description"`
+	TaxiID      nulls.Int          `db:"taxi_id"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+}
+
+func (pointerAssocsBook) TableName() string {
+	return "books"
+}
+
+func Test_Preload_Pointer_Associations(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		a := require.New(t)
+
+		mark := User{Name: nulls.NewString("Mark")}
+		a.NoError(tx.Create(&mark))
+		jane := User{Name: nulls.NewString("Jane")}
+		a.NoError(tx.Create(&jane))
+		for _, title := range []string{"b", "a"} {
+			a.NoError(tx.Create(&Book{Title: title, UserID: nulls.NewInt(mark.ID)}))
+		}
+		a.NoError(tx.Create(&Book{Title: "orphan"}))
+		song := Song{Title: "Pop", UserID: mark.ID}
+		a.NoError(tx.Create(&song))
+		address := Address{Street: "Pop"}
+		a.NoError(tx.Create(&address))
+		a.NoError(tx.Create(&UsersAddress{UserID: mark.ID, AddressID: address.ID}))
+
+		users := []*pointerAssocsUser{}
+		a.NoError(tx.EagerPreload().Order("id").All(&users))
+		a.Len(users, 2)
+
+		a.NotNil(users[0].Books)
+		a.Len(*users[0].Books, 2)
+		a.Equal("a", (*users[0].Books)[0].Title)
+		a.Len(users[0].BookPtrs, 2)
+		a.Equal("a", users[0].BookPtrs[0].Title)
+		a.NotNil(users[0].FavoriteSong)
+		a.Equal(song.ID, users[0].FavoriteSong.ID)
+		a.NotNil(users[0].Houses)
+		a.Len(*users[0].Houses, 1)
+
+		// loaded but empty, rather than not loaded
+		a.NotNil(users[1].Books)
+		a.Empty(*users[1].Books)
+		a.NotNil(users[1].BookPtrs)
+		a.Empty(users[1].BookPtrs)
+		a.NotNil(users[1].Houses)
+		a.Empty(*users[1].Houses)
+		a.Nil(users[1].FavoriteSong)
+
+		// not loaded
+		user := &pointerAssocsUser{}
+		a.NoError(tx.EagerPreload("FavoriteSong").Find(user, jane.ID))
+		a.Nil(user.Books)
+		a.Nil(user.BookPtrs)
+
+		books := []*pointerAssocsBook{}
+		a.NoError(tx.EagerPreload("User.Books").Order("title").All(&books))
+		a.Len(books, 3)
+		a.Equal(mark.ID, books[0].User.ID)
+		a.Len(*books[0].User.Books, 2)
+		a.Nil(books[2].User)
+	})
+}
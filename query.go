@@ -3,6 +3,7 @@ package pop
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/WilliamNHarvey/pop/v6/logging"
 )
@@ -11,6 +12,8 @@ type operation string
 
 const (
 	Select operation = "SELECT"
+	Insert operation = "INSERT"
+	Update operation = "UPDATE"
 	Delete operation = "DELETE"
 )
 
@@ -20,19 +23,35 @@ type Query struct {
 	RawSQL                  *clause
 	limitResults            int
 	addColumns              []string
+	selectColumns           []string
+	omitColumns             []string
 	eagerMode               EagerMode
 	eager                   bool
 	eagerFields             []string
+	eagerScopes             eagerScopes
 	whereClauses            clauses
+	whereHasClauses         whereHasClauses
+	withCountClauses        withCountClauses
 	orderClauses            clauses
 	fromClauses             fromClauses
+	table                   string
 	belongsToThroughClauses belongsToThroughClauses
 	joinClauses             joinClauses
 	groupClauses            groupClauses
 	havingClauses           havingClauses
 	Paginator               *Paginator
+	CursorPaginator         *CursorPaginator
 	Connection              *Connection
 	Operation               operation
+	unscoped                bool
+	asOf                    *time.Time
+	rowLock                 string
+	rowLockWait             string
+	timeout                 time.Duration
+	unlimited               bool
+	// err is the error of the building of the query, returned by the
+	// finders.
+	err error
 }
 
 // Clone will fill targetQ query with the connection used in q, if
@@ -43,20 +62,36 @@ func (q *Query) Clone(targetQ *Query) {
 
 	targetQ.limitResults = q.limitResults
 	targetQ.whereClauses = q.whereClauses
+	targetQ.whereHasClauses = q.whereHasClauses
 	targetQ.orderClauses = q.orderClauses
 	targetQ.fromClauses = q.fromClauses
+	targetQ.table = q.table
 	targetQ.belongsToThroughClauses = q.belongsToThroughClauses
 	targetQ.joinClauses = q.joinClauses
 	targetQ.groupClauses = q.groupClauses
 	targetQ.havingClauses = q.havingClauses
 	targetQ.addColumns = q.addColumns
+	targetQ.selectColumns = q.selectColumns
+	targetQ.omitColumns = q.omitColumns
 	targetQ.Operation = q.Operation
+	targetQ.unscoped = q.unscoped
+	targetQ.asOf = q.asOf
+	targetQ.rowLock = q.rowLock
+	targetQ.rowLockWait = q.rowLockWait
+	targetQ.timeout = q.timeout
+	targetQ.unlimited = q.unlimited
+	targetQ.err = q.err
 
 	if q.Paginator != nil {
 		paginator := *q.Paginator
 		targetQ.Paginator = &paginator
 	}
 
+	if q.CursorPaginator != nil {
+		paginator := *q.CursorPaginator
+		targetQ.CursorPaginator = &paginator
+	}
+
 	if q.Connection != nil {
 		connection := *q.Connection
 		targetQ.Connection = &connection
@@ -200,12 +235,15 @@ func (q *Query) EagerPreload(fields ...string) *Query {
 // Q will create a new "empty" query from the current connection.
 func Q(c *Connection) *Query {
 	return &Query{
-		RawSQL:      &clause{},
-		Connection:  c,
-		eager:       c.eager,
-		eagerFields: c.eagerFields,
-		eagerMode:   eagerModeNil,
-		Operation:   Select,
+		RawSQL:        &clause{},
+		Connection:    c,
+		eager:         c.eager,
+		eagerFields:   c.eagerFields,
+		eagerMode:     eagerModeNil,
+		selectColumns: c.selectColumns,
+		omitColumns:   c.omitColumns,
+		Operation:     Select,
+		unscoped:      c.unscoped,
 	}
 }
 
@@ -223,6 +261,9 @@ func (q Query) ToSQL(model *Model, addColumns ...string) (string, []interface{})
 // ToSQLBuilder returns a new `SQLBuilder` that can be used to generate SQL,
 // get arguments, and more.
 func (q Query) toSQLBuilder(model *Model, addColumns ...string) *sqlBuilder {
+	if queryRewriter != nil {
+		q = *q.WithAST(queryRewriter(q.AST()))
+	}
 	if len(q.addColumns) != 0 {
 		addColumns = q.addColumns
 	}
@@ -0,0 +1,282 @@
+package pop
+
+// QueryNode is a node of the AST of a query, see Query.AST. The nodes are
+// values: changing them does not change the query they were read from.
+type QueryNode interface {
+	queryNode()
+}
+
+// RawNode is the SQL of a raw query, see RawQuery.
+type RawNode struct {
+	SQL  string
+	Args []interface{}
+}
+
+// TableNode is the table the query reads the model from, see Query.Table.
+type TableNode struct {
+	Name string
+}
+
+// ColumnsNode holds the columns of the query: Select are the ones added by
+// Query.Select, Only and Omit the ones of SelectColumns and OmitColumns.
+type ColumnsNode struct {
+	Select []string
+	Only   []string
+	Omit   []string
+}
+
+// FromNode is a table added to the FROM clause, with its alias.
+type FromNode struct {
+	From string
+	As   string
+}
+
+// JoinNode is a join, of Type e.g. "LEFT JOIN".
+type JoinNode struct {
+	Type  string
+	Table string
+	On    string
+	Args  []interface{}
+}
+
+// WhereNode is a condition of the WHERE clause.
+type WhereNode struct {
+	Fragment string
+	Args     []interface{}
+}
+
+// GroupNode is a field of the GROUP BY clause.
+type GroupNode struct {
+	Field string
+}
+
+// HavingNode is a condition of the HAVING clause.
+type HavingNode struct {
+	Condition string
+	Args      []interface{}
+}
+
+// OrderNode is an expression of the ORDER BY clause.
+type OrderNode struct {
+	Fragment string
+	Args     []interface{}
+}
+
+// LimitNode is the limit of the results of the query.
+type LimitNode struct {
+	Limit int
+}
+
+// PaginateNode is the page of the results of the query, see
+// Query.Paginate.
+type PaginateNode struct {
+	Page    int
+	PerPage int
+}
+
+func (RawNode) queryNode()      {}
+func (TableNode) queryNode()    {}
+func (ColumnsNode) queryNode()  {}
+func (FromNode) queryNode()     {}
+func (JoinNode) queryNode()     {}
+func (WhereNode) queryNode()    {}
+func (GroupNode) queryNode()    {}
+func (HavingNode) queryNode()   {}
+func (OrderNode) queryNode()    {}
+func (LimitNode) queryNode()    {}
+func (PaginateNode) queryNode() {}
+
+// QueryAST is the immutable representation of a query, as a list of
+// nodes in the order of their SQL. The clauses depending on the model,
+// such as the association filters of WhereHas and the eager loading, are
+// not part of it and are kept as they are by Query.Rewrite.
+type QueryAST struct {
+	nodes []QueryNode
+}
+
+// QueryVisitor visits the nodes of a query, see QueryAST.Walk. Visit
+// returns the node replacing the visited one, the visited one to keep it,
+// or nil to remove it.
+type QueryVisitor interface {
+	Visit(node QueryNode) QueryNode
+}
+
+// QueryVisitorFunc is a function visiting the nodes of a query.
+type QueryVisitorFunc func(node QueryNode) QueryNode
+
+// Visit calls f(node).
+func (f QueryVisitorFunc) Visit(node QueryNode) QueryNode {
+	return f(node)
+}
+
+// AST returns the representation of the query, for the tools inspecting
+// or rewriting the queries without parsing their SQL.
+//
+//	for _, n := range q.AST().Nodes() {
+//		if w, ok := n.(pop.WhereNode); ok && strings.Contains(w.Fragment, "LIKE '%") {
+//			log.Printf("leading wildcard in %q", w.Fragment)
+//		}
+//	}
+func (q *Query) AST() *QueryAST {
+	var nodes []QueryNode
+	if q.RawSQL != nil && q.RawSQL.Fragment != "" {
+		nodes = append(nodes, RawNode{SQL: q.RawSQL.Fragment, Args: copyArgs(q.RawSQL.Arguments)})
+	}
+	if q.table != "" {
+		nodes = append(nodes, TableNode{Name: q.table})
+	}
+	if len(q.addColumns)+len(q.selectColumns)+len(q.omitColumns) > 0 {
+		nodes = append(nodes, ColumnsNode{
+			Select: copyStrings(q.addColumns),
+			Only:   copyStrings(q.selectColumns),
+			Omit:   copyStrings(q.omitColumns),
+		})
+	}
+	for _, c := range q.fromClauses {
+		nodes = append(nodes, FromNode{From: c.From, As: c.As})
+	}
+	for _, c := range q.joinClauses {
+		nodes = append(nodes, JoinNode{Type: c.JoinType, Table: c.Table, On: c.On, Args: copyArgs(c.Arguments)})
+	}
+	for _, c := range q.whereClauses {
+		nodes = append(nodes, WhereNode{Fragment: c.Fragment, Args: copyArgs(c.Arguments)})
+	}
+	for _, c := range q.groupClauses {
+		nodes = append(nodes, GroupNode{Field: c.Field})
+	}
+	for _, c := range q.havingClauses {
+		nodes = append(nodes, HavingNode{Condition: c.Condition, Args: copyArgs(c.Arguments)})
+	}
+	for _, c := range q.orderClauses {
+		nodes = append(nodes, OrderNode{Fragment: c.Fragment, Args: copyArgs(c.Arguments)})
+	}
+	if q.limitResults > 0 {
+		nodes = append(nodes, LimitNode{Limit: q.limitResults})
+	}
+	if q.Paginator != nil {
+		nodes = append(nodes, PaginateNode{Page: q.Paginator.Page, PerPage: q.Paginator.PerPage})
+	}
+	return &QueryAST{nodes: nodes}
+}
+
+// Nodes returns the nodes of the query, in the order of their SQL.
+func (a *QueryAST) Nodes() []QueryNode {
+	return append([]QueryNode{}, a.nodes...)
+}
+
+// Walk visits the nodes of the query in the order of their SQL, and returns
+// the AST of the nodes returned by the visitor. The AST walked is left as
+// it is.
+func (a *QueryAST) Walk(v QueryVisitor) *QueryAST {
+	nodes := make([]QueryNode, 0, len(a.nodes))
+	for _, n := range a.nodes {
+		if n = v.Visit(n); n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+	return &QueryAST{nodes: nodes}
+}
+
+// Append returns the AST of the nodes of a followed by the given ones.
+func (a *QueryAST) Append(nodes ...QueryNode) *QueryAST {
+	return &QueryAST{nodes: append(a.Nodes(), nodes...)}
+}
+
+// Rewrite returns a copy of the query whose clauses are the nodes returned
+// by the visitor, see QueryAST.Walk.
+//
+//	q = q.Rewrite(pop.QueryVisitorFunc(func(n pop.QueryNode) pop.QueryNode {
+//		if l, ok := n.(pop.LimitNode); ok && l.Limit > 1000 {
+//			return pop.LimitNode{Limit: 1000}
+//		}
+//		return n
+//	}))
+func (q *Query) Rewrite(v QueryVisitor) *Query {
+	return q.WithAST(q.AST().Walk(v))
+}
+
+// WithAST returns a copy of the query whose clauses are the nodes of the
+// AST.
+func (q *Query) WithAST(a *QueryAST) *Query {
+	nq := *q
+	nq.RawSQL = &clause{}
+	nq.table = ""
+	nq.addColumns, nq.selectColumns, nq.omitColumns = nil, nil, nil
+	nq.fromClauses = nil
+	nq.joinClauses = nil
+	nq.whereClauses = nil
+	nq.groupClauses = nil
+	nq.havingClauses = nil
+	nq.orderClauses = nil
+	nq.limitResults = 0
+	nq.Paginator = nil
+
+	for _, n := range a.nodes {
+		switch n := n.(type) {
+		case RawNode:
+			nq.RawSQL = &clause{Fragment: n.SQL, Arguments: copyArgs(n.Args)}
+		case TableNode:
+			nq.table = n.Name
+		case ColumnsNode:
+			nq.addColumns = append(nq.addColumns, n.Select...)
+			nq.selectColumns = append(nq.selectColumns, n.Only...)
+			nq.omitColumns = append(nq.omitColumns, n.Omit...)
+		case FromNode:
+			nq.fromClauses = append(nq.fromClauses, fromClause{From: n.From, As: n.As})
+		case JoinNode:
+			nq.joinClauses = append(nq.joinClauses, joinClause{JoinType: n.Type, Table: n.Table, On: n.On, Arguments: copyArgs(n.Args)})
+		case WhereNode:
+			nq.whereClauses = append(nq.whereClauses, clause{Fragment: n.Fragment, Arguments: copyArgs(n.Args)})
+		case GroupNode:
+			nq.groupClauses = append(nq.groupClauses, GroupClause{Field: n.Field})
+		case HavingNode:
+			nq.havingClauses = append(nq.havingClauses, HavingClause{Condition: n.Condition, Arguments: copyArgs(n.Args)})
+		case OrderNode:
+			nq.orderClauses = append(nq.orderClauses, clause{Fragment: n.Fragment, Arguments: copyArgs(n.Args)})
+		case LimitNode:
+			nq.limitResults = n.Limit
+		case PaginateNode:
+			// keep the paginator of the query, which gets the totals
+			if p := q.Paginator; p != nil && p.Page == n.Page && p.PerPage == n.PerPage {
+				nq.Paginator = p
+			} else {
+				nq.Paginator = NewPaginator(n.Page, n.PerPage)
+			}
+		}
+	}
+	return &nq
+}
+
+// QueryRewriter rewrites the AST of the queries, see SetQueryRewriter.
+type QueryRewriter func(a *QueryAST) *QueryAST
+
+var queryRewriter QueryRewriter
+
+// SetQueryRewriter sets the function rewriting the queries built by pop,
+// before their SQL is built. Use nil to remove it.
+//
+//	pop.SetQueryRewriter(func(a *pop.QueryAST) *pop.QueryAST {
+//		return a.Walk(pop.QueryVisitorFunc(func(n pop.QueryNode) pop.QueryNode {
+//			if l, ok := n.(pop.LimitNode); ok && l.Limit > 1000 {
+//				return pop.LimitNode{Limit: 1000}
+//			}
+//			return n
+//		}))
+//	})
+func SetQueryRewriter(r QueryRewriter) {
+	queryRewriter = r
+}
+
+func copyArgs(args []interface{}) []interface{} {
+	if args == nil {
+		return nil
+	}
+	return append([]interface{}{}, args...)
+}
+
+func copyStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string{}, s...)
+}
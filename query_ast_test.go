@@ -0,0 +1,74 @@
+package pop
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Query_AST(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	q := PDB.Where("name = ?", "Mark").LeftJoin("books", "books.user_id = users.id").Order("id desc").GroupBy("users.id").Limit(5)
+	r.Equal([]QueryNode{
+		JoinNode{Type: "LEFT JOIN", Table: "books", On: "books.user_id = users.id"},
+		WhereNode{Fragment: "name = ?", Args: []interface{}{"Mark"}},
+		GroupNode{Field: "users.id"},
+		OrderNode{Fragment: "id desc"},
+		LimitNode{Limit: 5},
+	}, q.AST().Nodes())
+
+	nodes := q.AST().Nodes()
+	nodes[1].(WhereNode).Args[0] = "Ann"
+	r.Equal("Mark", q.whereClauses[0].Arguments[0])
+
+	raw := PDB.RawQuery("SELECT * FROM users WHERE id = ?", 1)
+	r.Equal([]QueryNode{RawNode{SQL: "SELECT * FROM users WHERE id = ?", Args: []interface{}{1}}}, raw.AST().Nodes())
+}
+
+func Test_Query_Rewrite(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	m := NewModel(&User{}, PDB.Context())
+	q := PDB.Where("name = ?", "Mark").Order("id desc").Limit(5000).Paginate(2, 10)
+	rq := q.Rewrite(QueryVisitorFunc(func(n QueryNode) QueryNode {
+		switch n := n.(type) {
+		case LimitNode:
+			if n.Limit > 1000 {
+				return LimitNode{Limit: 1000}
+			}
+		case OrderNode:
+			return nil
+		case WhereNode:
+			n.Fragment = strings.ToUpper(n.Fragment)
+			return n
+		}
+		return n
+	}))
+	r.Equal(5000, q.limitResults)
+	r.Len(q.orderClauses, 1)
+	r.Equal(1000, rq.limitResults)
+	r.Empty(rq.orderClauses)
+	r.Same(q.Paginator, rq.Paginator)
+
+	sql, args := rq.ToSQL(m)
+	r.Contains(sql, "WHERE NAME = ")
+	r.NotContains(sql, "ORDER BY")
+	r.Equal([]interface{}{"Mark"}, args)
+
+	SetQueryRewriter(func(a *QueryAST) *QueryAST {
+		return a.Append(WhereNode{Fragment: "users.id > ?", Args: []interface{}{0}})
+	})
+	defer SetQueryRewriter(nil)
+
+	sql, args = PDB.Where("name = ?", "Mark").ToSQL(m)
+	r.True(strings.HasSuffix(sql, ts("FROM users AS users WHERE name = ? AND users.id > ?")), sql)
+	r.Equal([]interface{}{"Mark", 0}, args)
+}
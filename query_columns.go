@@ -0,0 +1,95 @@
+package pop
+
+import (
+	"reflect"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+)
+
+// SelectColumns restricts the columns of the model read and written through
+// the returned connection to the given ones. The ID, created_at and
+// updated_at columns are still written when needed.
+//
+//	c.SelectColumns("email").Update(&user) // only updates the email
+func (c *Connection) SelectColumns(names ...string) *Connection {
+	con := c.copy()
+	con.selectColumns = append(append([]string{}, c.selectColumns...), names...)
+	return con
+}
+
+// SelectColumns restricts the columns of the model read, and written by
+// UpdateQuery, to the given ones.
+//
+//	q.SelectColumns("id", "email").All(&users)
+func (q *Query) SelectColumns(names ...string) *Query {
+	q.selectColumns = append(append([]string{}, q.selectColumns...), names...)
+	return q
+}
+
+// OmitColumns prevents the given columns of the model from being read or
+// written through the returned connection, whatever the struct holds.
+//
+//	c.OmitColumns("admin").Update(&user) // never writes the admin flag
+func (c *Connection) OmitColumns(names ...string) *Connection {
+	con := c.copy()
+	con.omitColumns = append(append([]string{}, c.omitColumns...), names...)
+	return con
+}
+
+// OmitColumns prevents the given columns of the model from being read, or
+// written by UpdateQuery.
+//
+//	q.OmitColumns("bio").All(&users)
+func (q *Query) OmitColumns(names ...string) *Query {
+	q.omitColumns = append(append([]string{}, q.omitColumns...), names...)
+	return q
+}
+
+// selectOne reads the first record of the query into the model. When the
+// query reads some of the columns only, the record is read into a zero
+// value first, so the fields of the other columns are left zero rather than
+// keeping what the model held.
+func (q *Query) selectOne(model interface{}) (*Model, error) {
+	v := reflect.ValueOf(model)
+	restricted := len(q.addColumns)+len(q.selectColumns)+len(q.omitColumns) > 0
+	if !restricted || v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		m := NewModel(model, q.Connection.Context())
+		return m, q.Connection.Dialect.SelectOne(q.Connection, m, *q)
+	}
+
+	fresh := reflect.New(v.Elem().Type())
+	if err := q.Connection.Dialect.SelectOne(q.Connection, NewModel(fresh.Interface(), q.Connection.Context()), *q); err != nil {
+		return nil, err
+	}
+	v.Elem().Set(fresh.Elem())
+	return NewModel(model, q.Connection.Context()), nil
+}
+
+// restrictColumns returns a copy of cols without the omitted columns and,
+// if only is not empty, without the columns missing from only or keep.
+func restrictColumns(cols columns.Columns, only, omit []string, keep ...string) columns.Columns {
+	if len(only) == 0 && len(omit) == 0 {
+		return cols
+	}
+
+	rc := columns.NewColumnsWithAlias(cols.TableName, cols.TableAlias, cols.IDField)
+	for name, col := range cols.Cols {
+		if len(only) > 0 && !containsString(only, name) && !containsString(keep, name) {
+			continue
+		}
+		if containsString(omit, name) {
+			continue
+		}
+		rc.Cols[name] = col
+	}
+	return rc
+}
+
+func containsString(xs []string, s string) bool {
+	for _, x := range xs {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SelectColumns_OmitColumns_ToSQL(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		user := NewModel(&User{}, tx.Context())
+
+		q, _ := tx.Q().SelectColumns("id", "email").ToSQL(user)
+		r.Equal("SELECT users.email, users.id FROM users AS users", q)
+
+		q, _ = tx.Q().OmitColumns("bio", "full_name", "price").ToSQL(user)
+		r.Equal("SELECT users.alive, users.birth_date, users.created_at, users.email, users.id, users.name, users.updated_at, users.user_name FROM users AS users", q)
+
+		q, _ = tx.OmitColumns("bio").SelectColumns("id", "bio").Q().ToSQL(user)
+		r.Equal("SELECT users.id FROM users AS users", q)
+
+		// the cached columns of the model are left untouched
+		q, _ = tx.Q().ToSQL(user)
+		r.Contains(q, "users.bio")
+	})
+}
+
+func Test_SelectColumns_OmitColumns_Writes(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		u := &User{Name: nulls.NewString("Mark"), Bio: nulls.NewString("Bio"), Email: "mark@example.com"}
+		r.NoError(tx.OmitColumns("bio").Create(u))
+
+		f := &User{}
+		r.NoError(tx.Find(f, u.ID))
+		r.False(f.Bio.Valid)
+		r.Equal("mark@example.com", f.Email)
+
+		u.Bio = nulls.NewString("New Bio")
+		u.Email = "jane@example.com"
+		u.Name = nulls.NewString("Jane")
+		r.NoError(tx.SelectColumns("email").Update(u))
+
+		r.NoError(tx.Find(f, u.ID))
+		r.Equal("jane@example.com", f.Email)
+		r.Equal("Mark", f.Name.String)
+		r.False(f.Bio.Valid)
+
+		r.NoError(tx.OmitColumns("email").UpdateColumns(u, "email", "name"))
+		r.NoError(tx.Find(f, u.ID))
+		r.Equal("jane@example.com", f.Email)
+		r.Equal("Jane", f.Name.String)
+
+		u.Bio = nulls.NewString("Query Bio")
+		_, err := tx.Where("id = ?", u.ID).OmitColumns("bio").UpdateQuery(u, "bio", "name")
+		r.NoError(err)
+		r.NoError(tx.Find(f, u.ID))
+		r.False(f.Bio.Valid)
+
+		users := []User{}
+		r.NoError(tx.Q().SelectColumns("id", "name").All(&users))
+		r.Len(users, 1)
+		r.Equal("Jane", users[0].Name.String)
+		r.Empty(users[0].Email)
+	})
+}
+
+func Test_Select_Leaves_Unselected_Fields_Zero(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		u := &User{Name: nulls.NewString("Mark"), Bio: nulls.NewString("Bio"), Email: "mark@example.com"}
+		r.NoError(tx.Create(u))
+
+		f := &User{}
+		r.NoError(tx.Find(f, u.ID))
+		r.Equal("Bio", f.Bio.String)
+
+		r.NoError(tx.Select("id", "name").Find(f, u.ID))
+		r.Equal(u.ID, f.ID)
+		r.Equal("Mark", f.Name.String)
+		r.False(f.Bio.Valid)
+		r.Empty(f.Email)
+
+		r.NoError(tx.Q().SelectColumns("email").Last(f))
+		r.Equal("mark@example.com", f.Email)
+		r.Zero(f.ID)
+		r.False(f.Name.Valid)
+
+		f = &User{Email: "kept@example.com"}
+		r.Error(tx.Select("id").Where("id = ?", -1).First(f))
+		r.Equal("kept@example.com", f.Email)
+	})
+}
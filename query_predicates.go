@@ -0,0 +1,137 @@
+package pop
+
+import "fmt"
+
+// WhereTrue will append a where clause matching the rows whose boolean
+// column is true, compared to TRUE or to 1 depending on the database.
+//
+//	c.WhereTrue("active").All(&users)
+func (c *Connection) WhereTrue(column string) *Query {
+	return Q(c).WhereTrue(column)
+}
+
+// WhereTrue will append a where clause matching the rows whose boolean
+// column is true, compared to TRUE or to 1 depending on the database.
+//
+//	q.WhereTrue("users.active")
+func (q *Query) WhereTrue(column string) *Query {
+	return q.Where(fmt.Sprintf("%s = %s", column, q.booleanLiteral(true)))
+}
+
+// WhereFalse will append a where clause matching the rows whose boolean
+// column is false, compared to FALSE or to 0 depending on the database.
+//
+//	c.WhereFalse("active").All(&users)
+func (c *Connection) WhereFalse(column string) *Query {
+	return Q(c).WhereFalse(column)
+}
+
+// WhereFalse will append a where clause matching the rows whose boolean
+// column is false, compared to FALSE or to 0 depending on the database.
+//
+//	q.WhereFalse("users.active")
+func (q *Query) WhereFalse(column string) *Query {
+	return q.Where(fmt.Sprintf("%s = %s", column, q.booleanLiteral(false)))
+}
+
+// WhereNull will append a where clause matching the rows whose column is
+// NULL.
+//
+//	c.WhereNull("deleted_at").All(&users)
+func (c *Connection) WhereNull(column string) *Query {
+	return Q(c).WhereNull(column)
+}
+
+// WhereNull will append a where clause matching the rows whose column is
+// NULL.
+//
+//	q.WhereNull("users.deleted_at")
+func (q *Query) WhereNull(column string) *Query {
+	return q.Where(fmt.Sprintf("%s IS NULL", column))
+}
+
+// WhereNotNull will append a where clause matching the rows whose column
+// is not NULL.
+//
+//	c.WhereNotNull("confirmed_at").All(&users)
+func (c *Connection) WhereNotNull(column string) *Query {
+	return Q(c).WhereNotNull(column)
+}
+
+// WhereNotNull will append a where clause matching the rows whose column
+// is not NULL.
+//
+//	q.WhereNotNull("users.confirmed_at")
+func (q *Query) WhereNotNull(column string) *Query {
+	return q.Where(fmt.Sprintf("%s IS NOT NULL", column))
+}
+
+// WhereILike will append a where clause matching the rows whose column is
+// LIKE the pattern, ignoring the case: with ILIKE where the database has
+// it, comparing the lowercased column and pattern otherwise.
+//
+//	c.WhereILike("email", "%@example.com").All(&users)
+func (c *Connection) WhereILike(column, pattern string) *Query {
+	return Q(c).WhereILike(column, pattern)
+}
+
+// WhereILike will append a where clause matching the rows whose column is
+// LIKE the pattern, ignoring the case: with ILIKE where the database has
+// it, comparing the lowercased column and pattern otherwise.
+//
+//	q.WhereILike("users.email", "%@example.com")
+func (q *Query) WhereILike(column, pattern string) *Query {
+	if supports(q.Connection.Dialect, supportsILike) {
+		return q.Where(fmt.Sprintf("%s ILIKE ?", column), pattern)
+	}
+	return q.Where(fmt.Sprintf("LOWER(%s) LIKE LOWER(?)", column), pattern)
+}
+
+// WhereRegexp will append a where clause matching the rows whose column
+// contains a match of the regular expression: with ~ on PostgreSQL and
+// CockroachDB, REGEXP on MySQL, MariaDB and SQLite, and the regexp
+// functions of DuckDB, Oracle and Spanner. The syntax of the expressions
+// is the one of the database, Go's for SQLite.
+//
+//	c.WhereRegexp("sku", "^[A-Z]{3}-[0-9]+$").All(&products)
+func (c *Connection) WhereRegexp(column, pattern string) *Query {
+	return Q(c).WhereRegexp(column, pattern)
+}
+
+// WhereRegexp will append a where clause matching the rows whose column
+// contains a match of the regular expression: with ~ on PostgreSQL and
+// CockroachDB, REGEXP on MySQL, MariaDB and SQLite, and the regexp
+// functions of DuckDB, Oracle and Spanner. The syntax of the expressions
+// is the one of the database, Go's for SQLite.
+//
+//	q.WhereRegexp("products.sku", "^[A-Z]{3}-[0-9]+$")
+func (q *Query) WhereRegexp(column, pattern string) *Query {
+	var stmt string
+	switch q.Connection.Dialect.Name() {
+	case namePostgreSQL, nameCockroach:
+		stmt = "%s ~ ?"
+	case nameDuckDB:
+		stmt = "regexp_matches(%s, ?)"
+	case nameOracle:
+		stmt = "REGEXP_LIKE(%s, ?)"
+	case nameSpanner:
+		stmt = "REGEXP_CONTAINS(%s, ?)"
+	default:
+		stmt = "%s REGEXP ?"
+	}
+	return q.Where(fmt.Sprintf(stmt, column), pattern)
+}
+
+// booleanLiteral returns the SQL of the boolean on the database of the
+// query.
+func (q *Query) booleanLiteral(b bool) string {
+	switch {
+	case supports(q.Connection.Dialect, booleanType) && b:
+		return "TRUE"
+	case supports(q.Connection.Dialect, booleanType):
+		return "FALSE"
+	case b:
+		return "1"
+	}
+	return "0"
+}
@@ -0,0 +1,69 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Query_Predicates(t *testing.T) {
+	r := require.New(t)
+
+	pg := &Connection{Dialect: &postgresql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	q := Q(pg).WhereTrue("active").WhereFalse("banned").WhereNull("deleted_at").WhereNotNull("confirmed_at").WhereILike("email", "%@example.com")
+	r.Equal(clauses{
+		{"active = TRUE", nil},
+		{"banned = FALSE", nil},
+		{"deleted_at IS NULL", nil},
+		{"confirmed_at IS NOT NULL", nil},
+		{"email ILIKE ?", []interface{}{"%@example.com"}},
+	}, q.whereClauses)
+	r.Equal(clauses{{"sku ~ ?", []interface{}{"^A"}}}, Q(pg).WhereRegexp("sku", "^A").whereClauses)
+
+	my := &Connection{Dialect: &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	q = Q(my).WhereTrue("active").WhereFalse("banned").WhereILike("email", "%@example.com")
+	r.Equal(clauses{
+		{"active = 1", nil},
+		{"banned = 0", nil},
+		{"LOWER(email) LIKE LOWER(?)", []interface{}{"%@example.com"}},
+	}, q.whereClauses)
+	r.Equal(clauses{{"sku REGEXP ?", []interface{}{"^A"}}}, Q(my).WhereRegexp("sku", "^A").whereClauses)
+
+	sp := &Connection{Dialect: &spanner{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	r.Equal(clauses{{"REGEXP_CONTAINS(sku, ?)", []interface{}{"^A"}}}, Q(sp).WhereRegexp("sku", "^A").whereClauses)
+}
+
+func Test_Query_Predicates_SQLite(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE accounts (id INTEGER PRIMARY KEY, email TEXT NOT NULL, active BOOLEAN NOT NULL, deleted_at DATETIME)").Exec())
+	r.NoError(c.RawQuery("INSERT INTO accounts (email, active, deleted_at) VALUES ('Ann@Example.com', ?, NULL), ('bob@example.org', ?, NULL), ('cid@example.com', ?, CURRENT_TIMESTAMP)", true, false, true).Exec())
+
+	type account struct {
+		ID        int        `db:"id"`
+		Email     string     `db:"email"`
+		Active    bool       `db:"active"`
+		DeletedAt *time.Time `db:"deleted_at"`
+	}
+	count := func(q *Query) int {
+		n, err := q.Unscoped().Count(&account{})
+		r.NoError(err)
+		return n
+	}
+	r.Equal(2, count(c.WhereTrue("active")))
+	r.Equal(1, count(c.WhereFalse("active")))
+	r.Equal(2, count(c.WhereNull("deleted_at")))
+	r.Equal(1, count(c.WhereNotNull("deleted_at")))
+	r.Equal(2, count(c.WhereILike("email", "%@EXAMPLE.COM")))
+	r.Equal(1, count(c.WhereTrue("active").WhereNull("deleted_at").WhereILike("email", "ann@%")))
+	r.Equal(2, count(c.WhereRegexp("email", `\.com$`)))
+	r.Equal(1, count(c.WhereRegexp("email", "^[A-Z]")))
+	r.Equal(0, count(c.WhereRegexp("deleted_at", "^x")))
+	_, err := c.WhereRegexp("email", "(").Count(&account{})
+	r.Error(err)
+}
@@ -0,0 +1,47 @@
+package pop
+
+// SelectSQL returns the SELECT statement All runs for the query, with its
+// arguments, in the SQL of the connection's dialect. It is meant for
+// logging, EXPLAIN tooling, or running the statement with another
+// executor; the statement is not run.
+func (q *Query) SelectSQL(models interface{}) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	m := NewModel(models, q.Connection.Context())
+	sq := *q
+	sq.Operation = Select
+	sq.applyModelLimits(m)
+	if sq.capResults() && q.RawSQL.Fragment == "" {
+		sq.limitResults = maxResults + 1
+	}
+	sql, args := sq.ToSQL(m)
+	return sql, args, nil
+}
+
+// UpdateSQL returns the UPDATE statement UpdateQuery runs for the query,
+// model and columns, with its arguments. As UpdateQuery does, it sets the
+// UpdatedAt field of the model, whose value is one of the arguments. The
+// statement is not run.
+func (q *Query) UpdateSQL(model interface{}, columnNames ...string) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	sm, cols, err := q.updateQueryColumns(model, columnNames)
+	if err != nil {
+		return "", nil, err
+	}
+	return q.Connection.Dialect.updateQuerySQL(sm, cols, *q)
+}
+
+// DeleteSQL returns the DELETE statement Delete runs for the query and
+// model, with its arguments. The statement is not run.
+func (q *Query) DeleteSQL(model interface{}) (string, []interface{}, error) {
+	if q.err != nil {
+		return "", nil, q.err
+	}
+	sq := *q
+	sq.Operation = Delete
+	sql, args := q.Connection.Dialect.deleteSQL(NewModel(model, q.Connection.Context()), sq)
+	return sql, args, nil
+}
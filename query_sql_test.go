@@ -0,0 +1,55 @@
+package pop
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type feedItem struct {
+	ID        int       `db:"id"`
+	Title     string    `db:"title"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func Test_Query_SQL(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &postgresql{
+		commonDialect:  commonDialect{ConnectionDetails: &ConnectionDetails{}},
+		translateCache: map[string]string{},
+	}}
+
+	sql, args, err := c.Where("title = ?", "pop").Order("id").SelectSQL(&[]feedItem{})
+	r.NoError(err)
+	r.Equal(`SELECT feed_items.created_at, feed_items.id, feed_items.title, feed_items.updated_at FROM feed_items AS feed_items WHERE title = $1 ORDER BY id`, sql)
+	r.Equal([]interface{}{"pop"}, args)
+
+	item := &feedItem{Title: "soda"}
+	sql, args, err = c.Where("id = ?", 1).UpdateSQL(item, "title")
+	r.NoError(err)
+	r.Equal(`UPDATE "feed_items" AS feed_items SET "title" = $1, "updated_at" = $2 WHERE id = $3`, sql)
+	r.Equal([]interface{}{"soda", item.UpdatedAt, 1}, args)
+	r.False(item.UpdatedAt.IsZero())
+
+	sql, args, err = c.Where("id = ?", 1).DeleteSQL(&feedItem{})
+	r.NoError(err)
+	r.Equal(`DELETE FROM feed_items AS feed_items WHERE id = $1`, sql)
+	r.Equal([]interface{}{1}, args)
+
+	my := &Connection{Dialect: &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	sql, _, err = my.Where("id = ?", 1).DeleteSQL(&feedItem{})
+	r.NoError(err)
+	r.Equal(`DELETE FROM feed_items   WHERE id = ?`, sql)
+
+	_, _, err = c.Where("id = ?", 1).UpdateSQL(&[]feedItem{})
+	r.Error(err)
+
+	q := c.Q()
+	q.err = errors.New("deferred")
+	_, _, err = q.SelectSQL(&[]feedItem{})
+	r.EqualError(err, "deferred")
+}
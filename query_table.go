@@ -0,0 +1,22 @@
+package pop
+
+// Table makes the query read the model from the given table instead of
+// the one returned by its TableName. The table keeps the alias of the
+// model, so clauses written against the model still apply. This allows
+// querying a foreign table holding the same rows, such as a MySQL table
+// of another database or a Postgres FDW table:
+//
+//	c.Table("reporting.users").Where("users.active = ?", true).All(&users)
+func (c *Connection) Table(name string) *Query {
+	return Q(c).Table(name)
+}
+
+// Table makes the query read the model from the given table instead of
+// the one returned by its TableName. The table keeps the alias of the
+// model, so clauses written against the model still apply.
+//
+//	q.Table("otherdb.users").Join("orders", "orders.user_id = users.id").All(&users)
+func (q *Query) Table(name string) *Query {
+	q.table = name
+	return q
+}
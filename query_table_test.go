@@ -0,0 +1,43 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Query_Table_ToSQL(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		q, _ := tx.Table("otherdb.users").SelectColumns("id").Where("users.id = ?", 1).ToSQL(NewModel(&User{}, tx.Context()))
+		r.Equal(ts("SELECT users.id FROM otherdb.users AS users WHERE users.id = ?"), q)
+
+		q, _ = tx.Table("reporting.members").Join("orders", "orders.member_id = family_members.id").ToSQL(NewModel(&Family{}, tx.Context()))
+		r.Equal("SELECT family_members.created_at, family_members.first_name, family_members.id, family_members.last_name, family_members.updated_at FROM reporting.members AS family_members JOIN orders ON orders.member_id = family_members.id", q)
+	})
+}
+
+func Test_Query_Table(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	if PDB.Dialect.Name() != nameSQLite3 {
+		t.Skip("main is the schema of the sqlite database only")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		r.NoError(tx.Create(&User{Name: nulls.NewString("Mark")}))
+
+		users := []User{}
+		r.NoError(tx.Table("main.users").Where("users.name = ?", "Mark").All(&users))
+		r.Len(users, 1)
+
+		count, err := tx.Table("main.users").Count(&User{})
+		r.NoError(err)
+		r.Equal(1, count)
+	})
+}
@@ -0,0 +1,141 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Timeout returns a query running its statements with a timeout, see
+// Query.Timeout.
+func (c *Connection) Timeout(d time.Duration) *Query {
+	return Q(c).Timeout(d)
+}
+
+// Timeout runs the statements of the query with a timeout of d: their
+// context, the one of the connection, is canceled once past d and the
+// statements fail with context.DeadlineExceeded. The drivers abort the
+// canceled statements on the server, and MySQL also gets the timeout as
+// a MAX_EXECUTION_TIME hint of its SELECT statements.
+//
+//	err := tx.WithContext(r.Context()).Timeout(30 * time.Second).Where("year = ?", 2024).All(&sales)
+func (q *Query) Timeout(d time.Duration) *Query {
+	if d <= 0 {
+		return q
+	}
+	q.timeout = d
+	cn := q.Connection.copy()
+	cn.Store = timeoutStore{store: cn.Store, timeout: d}
+	q.Connection = cn
+	return q
+}
+
+// buildTimeoutHint adds the optimizer hint aborting the SELECT statement
+// past the timeout of the query, on MySQL.
+func (sq *sqlBuilder) buildTimeoutHint(sql string) string {
+	if sq.Query.timeout <= 0 || sq.Query.Connection.Dialect.Name() != nameMySQL {
+		return sql
+	}
+	ms := sq.Query.timeout.Milliseconds()
+	if ms < 1 {
+		ms = 1
+	}
+	return fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */ %s", ms, sql[len("SELECT "):])
+}
+
+// timeoutStore runs the statements with a timeout.
+type timeoutStore struct {
+	store
+	timeout time.Duration
+}
+
+func (s timeoutStore) unwrap() store {
+	return s.store
+}
+
+// Context returns the context of the wrapped store, used by the methods
+// not taking one.
+func (s timeoutStore) Context() context.Context {
+	if c, ok := s.store.(interface{ Context() context.Context }); ok {
+		return c.Context()
+	}
+	return context.Background()
+}
+
+func (s timeoutStore) Select(dest interface{}, query string, args ...interface{}) error {
+	return s.SelectContext(s.Context(), dest, query, args...)
+}
+
+func (s timeoutStore) Get(dest interface{}, query string, args ...interface{}) error {
+	return s.GetContext(s.Context(), dest, query, args...)
+}
+
+func (s timeoutStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return s.NamedExecContext(s.Context(), query, arg)
+}
+
+func (s timeoutStore) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return s.NamedQueryContext(s.Context(), query, arg)
+}
+
+func (s timeoutStore) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecContext(s.Context(), query, args...)
+}
+
+func (s timeoutStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.store.SelectContext(ctx, dest, query, args...)
+}
+
+func (s timeoutStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.store.GetContext(ctx, dest, query, args...)
+}
+
+func (s timeoutStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.store.NamedExecContext(ctx, query, arg)
+}
+
+func (s timeoutStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	return s.store.ExecContext(ctx, query, args...)
+}
+
+// rowsContext returns the context of a statement returning rows, canceled
+// once past the timeout. The rows outlive the call and are read with the
+// context, so it is released at the timeout only, unless the returned
+// function is called when the statement fails.
+func (s timeoutStore) rowsContext(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	timer := time.AfterFunc(s.timeout, cancel)
+	return ctx, func() {
+		timer.Stop()
+		cancel()
+	}
+}
+
+func (s timeoutStore) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	ctx, release := s.rowsContext(ctx)
+	rows, err := s.store.NamedQueryContext(ctx, query, arg)
+	if err != nil {
+		release()
+	}
+	return rows, err
+}
+
+func (s timeoutStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	ctx, release := s.rowsContext(ctx)
+	rows, err := s.store.QueryxContext(ctx, query, args...)
+	if err != nil {
+		release()
+	}
+	return rows, err
+}
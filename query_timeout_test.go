@@ -0,0 +1,52 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Query_Timeout_SQL(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	sql, _ := c.Timeout(1500*time.Millisecond).Where("id = ?", 1).ToSQL(NewModel(&User{}, nil))
+	r.True(strings.HasPrefix(sql, "SELECT /*+ MAX_EXECUTION_TIME(1500) */ name as full_name,"), sql)
+
+	sql, _ = Q(c).Where("id = ?", 1).ToSQL(NewModel(&User{}, nil))
+	r.True(strings.HasPrefix(sql, "SELECT name as full_name,"), sql)
+
+	c = &Connection{Dialect: &mariaDB{mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}}
+	sql, _ = c.Timeout(time.Second).Where("id = ?", 1).ToSQL(NewModel(&User{}, nil))
+	r.True(strings.HasPrefix(sql, "SELECT name as full_name,"), sql)
+}
+
+func Test_Query_Timeout(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+
+	const endless = "WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n) SELECT COUNT(*) FROM n"
+
+	var n int
+	err := c.Timeout(50 * time.Millisecond).RawQuery(endless).First(&n)
+	r.ErrorIs(err, context.DeadlineExceeded)
+
+	_, err = c.Timeout(50 * time.Millisecond).RawQuery(endless).Count(nil)
+	r.ErrorIs(err, context.DeadlineExceeded)
+
+	err = c.Timeout(time.Minute).RawQuery("SELECT 42").First(&n)
+	r.NoError(err)
+	r.Equal(42, n)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = c.WithContext(ctx).Timeout(time.Minute).RawQuery("SELECT 42").First(&n)
+	r.ErrorIs(err, context.Canceled)
+}
@@ -0,0 +1,136 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/jmoiron/sqlx"
+)
+
+// watchdogStore wraps a store and keeps an eye on the statements it runs:
+// the ones still running after threshold are logged with the stack trace of
+// the calling goroutine and, if cancel is set, canceled through their
+// context.
+type watchdogStore struct {
+	store
+	threshold time.Duration
+	cancel    bool
+}
+
+// watched wraps the given store with a watchdog if the connection details
+// enable it.
+func watched(s store, deets *ConnectionDetails) store {
+	if deets == nil || deets.SlowQueryThreshold <= 0 {
+		return s
+	}
+	return watchdogStore{store: s, threshold: deets.SlowQueryThreshold, cancel: deets.CancelSlowQueries}
+}
+
+func (s watchdogStore) unwrap() store {
+	return s.store
+}
+
+// watch starts watching the given statement, canceling the returned context
+// once past the threshold if cancel is set. The returned function must be
+// called once the statement is done.
+func (s watchdogStore) watch(ctx context.Context, query string, cancel bool) (context.Context, func()) {
+	pcs := make([]uintptr, 32)
+	pcs = pcs[:runtime.Callers(3, pcs)]
+
+	stop := func() {}
+	if cancel {
+		ctx, stop = context.WithCancel(ctx)
+	}
+
+	start := time.Now()
+	timer := time.AfterFunc(s.threshold, func() {
+		log(logging.Warn, "query running for more than %s: %s\n%s", s.threshold, query, formatStack(pcs))
+		if cancel {
+			log(logging.Warn, "canceling query after %s: %s", time.Since(start), query)
+			stop()
+		}
+	})
+	return ctx, func() {
+		timer.Stop()
+		stop()
+	}
+}
+
+func (s watchdogStore) Select(dest interface{}, query string, args ...interface{}) error {
+	return s.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (s watchdogStore) Get(dest interface{}, query string, args ...interface{}) error {
+	return s.GetContext(context.Background(), dest, query, args...)
+}
+
+func (s watchdogStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	return s.NamedExecContext(context.Background(), query, arg)
+}
+
+func (s watchdogStore) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return s.NamedQueryContext(context.Background(), query, arg)
+}
+
+func (s watchdogStore) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return s.ExecContext(context.Background(), query, args...)
+}
+
+func (s watchdogStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, done := s.watch(ctx, query, s.cancel)
+	defer done()
+	return s.store.SelectContext(ctx, dest, query, args...)
+}
+
+func (s watchdogStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	ctx, done := s.watch(ctx, query, s.cancel)
+	defer done()
+	return s.store.GetContext(ctx, dest, query, args...)
+}
+
+func (s watchdogStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	ctx, done := s.watch(ctx, query, s.cancel)
+	defer done()
+	return s.store.NamedExecContext(ctx, query, arg)
+}
+
+// NamedQueryContext only logs slow statements: the returned rows outlive
+// the call, so its context can not be canceled when done.
+func (s watchdogStore) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	_, done := s.watch(ctx, query, false)
+	defer done()
+	return s.store.NamedQueryContext(ctx, query, arg)
+}
+
+// QueryxContext only logs slow statements, like NamedQueryContext.
+func (s watchdogStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	_, done := s.watch(ctx, query, false)
+	defer done()
+	return s.store.QueryxContext(ctx, query, args...)
+}
+
+func (s watchdogStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, done := s.watch(ctx, query, s.cancel)
+	defer done()
+	return s.store.ExecContext(ctx, query, args...)
+}
+
+// formatStack formats the given program counters the same way a panic
+// does.
+func formatStack(pcs []uintptr) string {
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
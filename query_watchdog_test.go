@@ -0,0 +1,80 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/stretchr/testify/require"
+)
+
+// sleepyStore is a store whose statements run for the given duration,
+// unless their context is canceled.
+type sleepyStore struct {
+	store
+	duration time.Duration
+}
+
+func (s sleepyStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	select {
+	case <-time.After(s.duration):
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func Test_Watchdog(t *testing.T) {
+	r := require.New(t)
+
+	var mu sync.Mutex
+	var logs []string
+	oldLog := log
+	defer func() { log = oldLog }()
+	SetLogger(func(lvl logging.Level, s string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, fmt.Sprintf(s, args...))
+	})
+
+	r.Equal(sleepyStore{}, watched(sleepyStore{}, &ConnectionDetails{}))
+
+	s := watched(sleepyStore{duration: time.Second}, &ConnectionDetails{
+		SlowQueryThreshold: 10 * time.Millisecond,
+	})
+	_, err := s.Exec("SELECT pg_sleep(1)")
+	r.NoError(err)
+
+	mu.Lock()
+	r.Len(logs, 1)
+	r.Contains(logs[0], "query running for more than 10ms: SELECT pg_sleep(1)")
+	r.Contains(logs[0], "Test_Watchdog")
+	logs = nil
+	mu.Unlock()
+
+	s = watched(sleepyStore{duration: time.Second}, &ConnectionDetails{
+		SlowQueryThreshold: 10 * time.Millisecond,
+		CancelSlowQueries:  true,
+	})
+	_, err = s.ExecContext(context.Background(), "SELECT pg_sleep(1)")
+	r.ErrorIs(err, context.Canceled)
+
+	mu.Lock()
+	r.Len(logs, 2)
+	r.Contains(logs[1], "canceling query after")
+	logs = nil
+	mu.Unlock()
+
+	// fast statements are left alone
+	s = watched(sleepyStore{}, &ConnectionDetails{
+		SlowQueryThreshold: time.Second,
+		CancelSlowQueries:  true,
+	})
+	_, err = s.Exec("SELECT 1")
+	r.NoError(err)
+	r.Len(logs, 0)
+}
@@ -0,0 +1,56 @@
+package pop
+
+import "github.com/WilliamNHarvey/pop/v6/logging"
+
+// WhereHas will append an EXISTS sub-query to the query, restricting the
+// results to records having at least one matching record for the given
+// association. The optional function can be used to add conditions to the
+// association sub-query, using the association table name or alias.
+//
+//	c.WhereHas("Books", func(q *Query) {
+//		q.Where("books.published = ?", true)
+//	}).All(&users)
+func (c *Connection) WhereHas(association string, fn func(q *Query)) *Query {
+	return Q(c).WhereHas(association, fn)
+}
+
+// WhereHas will append an EXISTS sub-query to the query, restricting the
+// results to records having at least one matching record for the given
+// association. The optional function can be used to add conditions to the
+// association sub-query, using the association table name or alias.
+//
+//	q.WhereHas("Books", func(q *Query) {
+//		q.Where("books.published = ?", true)
+//	}).All(&users)
+func (q *Query) WhereHas(association string, fn func(q *Query)) *Query {
+	return q.whereHas(association, false, fn)
+}
+
+// WhereDoesntHave is the negation of WhereHas: it restricts the results to
+// records having no matching record for the given association.
+//
+//	c.WhereDoesntHave("Books", nil).All(&users)
+func (c *Connection) WhereDoesntHave(association string, fn func(q *Query)) *Query {
+	return Q(c).WhereDoesntHave(association, fn)
+}
+
+// WhereDoesntHave is the negation of WhereHas: it restricts the results to
+// records having no matching record for the given association.
+//
+//	q.WhereDoesntHave("Books", nil).All(&users)
+func (q *Query) WhereDoesntHave(association string, fn func(q *Query)) *Query {
+	return q.whereHas(association, true, fn)
+}
+
+func (q *Query) whereHas(association string, negate bool, fn func(q *Query)) *Query {
+	if q.RawSQL.Fragment != "" {
+		log(logging.Warn, "Query is setup to use raw SQL")
+		return q
+	}
+	sub := Q(q.Connection)
+	if fn != nil {
+		fn(sub)
+	}
+	q.whereHasClauses = append(q.whereHasClauses, whereHasClause{association, negate, sub})
+	return q
+}
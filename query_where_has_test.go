@@ -0,0 +1,78 @@
+package pop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WhereHas_SQL(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	u := NewModel(&User{}, context.Background())
+
+	q := PDB.WhereHas("Books", func(q *Query) {
+		q.Where("books.title = ?", "Pop")
+	})
+	sql, args := q.ToSQL(u, "users.id")
+	r.Equal(ts("SELECT users.id FROM users AS users WHERE EXISTS (SELECT 1 FROM books AS books WHERE books.user_id = users.id AND books.title = ?)"), sql)
+	r.Equal([]interface{}{"Pop"}, args)
+
+	q = PDB.Where("users.name = ?", "Mark").WhereDoesntHave("FavoriteSong", nil)
+	sql, args = q.ToSQL(u, "users.id")
+	r.Equal(ts("SELECT users.id FROM users AS users WHERE users.name = ? AND NOT EXISTS (SELECT 1 FROM songs AS songs WHERE songs.u_id = users.id)"), sql)
+	r.Equal([]interface{}{"Mark"}, args)
+
+	q = PDB.WhereHas("Houses", nil)
+	sql, _ = q.ToSQL(u, "users.id")
+	r.Equal(ts("SELECT users.id FROM users AS users WHERE EXISTS (SELECT 1 FROM addresses AS addresses JOIN users_addresses ON users_addresses.address_id = addresses.id WHERE users_addresses.user_id = users.id)"), sql)
+
+	b := NewModel(&Book{}, context.Background())
+	q = PDB.WhereHas("User", nil)
+	sql, _ = q.ToSQL(b, "books.id")
+	r.Equal(ts("SELECT books.id FROM books AS books WHERE EXISTS (SELECT 1 FROM users AS users WHERE users.id = books.user_id)"), sql)
+
+	q = PDB.WhereHas("Title", nil)
+	sql, _ = q.ToSQL(b, "books.id")
+	r.Equal(ts("SELECT books.id FROM books AS books WHERE 1 = 0"), sql)
+}
+
+func Test_WhereHas(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		u1 := &User{Name: nulls.NewString("A")}
+		u2 := &User{Name: nulls.NewString("B")}
+		r.NoError(tx.Create(u1))
+		r.NoError(tx.Create(u2))
+
+		r.NoError(tx.Create(&Book{Title: "Pop", Isbn: "PB1", UserID: nulls.NewInt(u1.ID)}))
+		r.NoError(tx.Create(&Book{Title: "Buffalo", Isbn: "PB2", UserID: nulls.NewInt(u1.ID)}))
+
+		users := Users{}
+		r.NoError(tx.WhereHas("Books", nil).All(&users))
+		r.Len(users, 1)
+		r.Equal(u1.ID, users[0].ID)
+
+		users = Users{}
+		r.NoError(tx.WhereHas("Books", func(q *Query) {
+			q.Where("books.title = ?", "Soda")
+		}).All(&users))
+		r.Len(users, 0)
+
+		users = Users{}
+		r.NoError(tx.WhereDoesntHave("Books", nil).All(&users))
+		r.Len(users, 1)
+		r.Equal(u2.ID, users[0].ID)
+
+		count, err := tx.WhereHas("Books", nil).Count(&User{})
+		r.NoError(err)
+		r.Equal(1, count)
+	})
+}
@@ -0,0 +1,203 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// withCountClause holds an association whose records should be counted
+// and the name of the `count` tagged field receiving the result.
+type withCountClause struct {
+	Association string
+	Field       string
+}
+
+type withCountClauses []withCountClause
+
+// WithCount loads the number of records of the given association into the
+// model field tagged with `count:"<field>"`. Counts for all the loaded
+// records are fetched with a single grouped query.
+//
+//	type User struct {
+//		ID         int   `db:"id"`
+//		Books      Books `has_many:"books"`
+//		BooksCount int   `count:"books_count"`
+//	}
+//
+//	c.WithCount("Books", "books_count").All(&users)
+func (c *Connection) WithCount(association string, field string) *Query {
+	return Q(c).WithCount(association, field)
+}
+
+// WithCount loads the number of records of the given association into the
+// model field tagged with `count:"<field>"`. Counts for all the loaded
+// records are fetched with a single grouped query.
+//
+//	q.WithCount("Books", "books_count").All(&users)
+func (q *Query) WithCount(association string, field string) *Query {
+	q.withCountClauses = append(q.withCountClauses, withCountClause{association, field})
+	return q
+}
+
+type associationCount struct {
+	ID    interface{} `db:"id"`
+	Count int64       `db:"row_count"`
+}
+
+// loadCounts runs the grouped count queries registered with WithCount
+// and fills the tagged fields of the given model.
+func (q *Query) loadCounts(model interface{}) error {
+	mmi := NewModelMetaInfo(NewModel(model, q.Connection.Context()))
+
+	ids := []interface{}{}
+	mmi.Model.iterate(func(m *Model) error {
+		ids = append(ids, m.ID())
+		return nil
+	})
+	if len(ids) == 0 {
+		return nil
+	}
+
+	for _, wc := range q.withCountClauses {
+		asoc := mmi.GetByPath(wc.Association)
+		if asoc == nil {
+			return fmt.Errorf("field %s does not exist in model %s", wc.Association, mmi.Model.TableName())
+		}
+
+		var target *reflectx.FieldInfo
+		for _, fi := range mmi.Index {
+			if fi.Field.Tag.Get("count") == wc.Field {
+				target = fi
+				break
+			}
+		}
+		if target == nil {
+			return fmt.Errorf("no field tagged with count:%q in model %s", wc.Field, mmi.Model.TableName())
+		}
+
+		countsByID, err := q.associationCounts(mmi, asoc, wc.Association, ids)
+		if err != nil {
+			return err
+		}
+
+		mmi.iterate(func(mvalue reflect.Value) {
+			id := mmi.mapper.FieldByName(mvalue, "ID").Interface()
+			field := reflect.Indirect(mmi.mapper.FieldByName(mvalue, target.Path))
+			count := reflect.ValueOf(countsByID[fmt.Sprintf("%v", id)])
+			field.Set(count.Convert(field.Type()))
+		})
+	}
+	return nil
+}
+
+// associationCounts returns the number of records of the association of
+// the model with each of the IDs, by ID, counted with a single grouped
+// query. The soft deleted records are not counted unless the query is
+// unscoped.
+func (q *Query) associationCounts(mmi *ModelMetaInfo, asoc *reflectx.FieldInfo, association string, ids []interface{}) (map[string]int64, error) {
+	// the soft deleted records of the association are not counted
+	ami := NewAssociationMetaInfo(asoc)
+	asocModel := NewModel(ami.toSlice().Interface(), q.Connection.Context())
+	deletedAt := ""
+	if !q.unscoped {
+		deletedAt = asocModel.deletedAtColumn()
+	}
+
+	var table, fk, from string
+	var where []string
+	switch {
+	case asoc.Field.Tag.Get("has_many") != "" || asoc.Field.Tag.Get("has_one") != "":
+		fk = asoc.Field.Tag.Get("fk_id")
+		if fk == "" {
+			fk = mmi.Model.associationName()
+		}
+		table = asocModel.TableName()
+		from = table
+		if deletedAt != "" {
+			where = append(where, fmt.Sprintf("%s IS NULL", deletedAt))
+		}
+	case asoc.Field.Tag.Get("many_to_many") != "":
+		table = asoc.Field.Tag.Get("many_to_many")
+		fk = mmi.Model.associationName()
+		if strings.Contains(table, ":") {
+			fk = strings.TrimSpace(table[strings.Index(table, ":")+1:])
+			table = strings.TrimSpace(table[:strings.Index(table, ":")])
+		}
+		from = table
+		if deletedAt != "" {
+			asocTable := asocModel.TableName()
+			from = fmt.Sprintf("%s JOIN %s ON %s.%s = %s.id", table, asocTable, table, ami.fkName(), asocTable)
+			where = append(where, fmt.Sprintf("%s.%s IS NULL", asocTable, deletedAt))
+		}
+		fk = fmt.Sprintf("%s.%s", table, fk)
+	default:
+		return nil, fmt.Errorf("can not count association %s of model %s", association, mmi.Model.TableName())
+	}
+	where = append(where, fmt.Sprintf("%s in (?)", fk))
+
+	sql := fmt.Sprintf("SELECT %s AS id, COUNT(*) AS row_count FROM %s WHERE %s GROUP BY %s", fk, from, strings.Join(where, " AND "), fk)
+	sql, args, err := sqlx.In(sql, ids)
+	if err != nil {
+		return nil, err
+	}
+	sql = q.Connection.Dialect.TranslateSQL(sql)
+
+	counts := []associationCount{}
+	txlog(logging.SQL, q.Connection, sql, args...)
+	if err := q.Connection.Store.SelectContext(q.Connection.Context(), &counts, sql, args...); err != nil {
+		return nil, err
+	}
+
+	countsByID := map[string]int64{}
+	for _, c := range counts {
+		if b, ok := c.ID.([]uint8); ok { // -> it's UUID
+			c.ID = string(b)
+		}
+		countsByID[fmt.Sprintf("%v", c.ID)] = c.Count
+	}
+	return countsByID, nil
+}
+
+// CountAssociation returns the number of records of the association of
+// the model, see Query.CountAssociation.
+func (c *Connection) CountAssociation(model interface{}, association string) (int, error) {
+	return Q(c).CountAssociation(model, association)
+}
+
+// CountAssociation returns the number of records of the has_many, has_one
+// or many_to_many association of the model, a struct with its ID set,
+// with a COUNT query rather than by loading them. The soft deleted
+// records are not counted unless the query is unscoped. To count the
+// associations of a slice of models with a single query, see WithCount.
+//
+//	n, err := c.CountAssociation(&user, "Books")
+func (q *Query) CountAssociation(model interface{}, association string) (int, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+	if reflect.Indirect(reflect.ValueOf(model)).Kind() != reflect.Struct {
+		return 0, fmt.Errorf("can not count the associations of %T, not a struct", model)
+	}
+	mmi := NewModelMetaInfo(NewModel(model, q.Connection.Context()))
+	asoc := mmi.GetByPath(association)
+	if asoc == nil {
+		return 0, fmt.Errorf("field %s does not exist in model %s", association, mmi.Model.TableName())
+	}
+
+	var count int
+	err := q.Connection.timeFunc("CountAssociation", model, func() error {
+		id := mmi.Model.ID()
+		counts, err := q.associationCounts(mmi, asoc, association, []interface{}{id})
+		if err != nil {
+			return err
+		}
+		count = int(counts[fmt.Sprintf("%v", id)])
+		return nil
+	})
+	return count, err
+}
@@ -0,0 +1,107 @@
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type UserWithCounts struct {
+	ID          int           `db:"id"`
+	UserName    string        `db:"user_name"`
+	Email       string        `db:"email"`
+	Name        nulls.String  `db:"name"`
+	Alive       nulls.Bool    `db:"alive"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at"`
+	BirthDate   nulls.Time    `db:"birth_date"`
+	Bio         nulls.String  `db:"bio"`
+	Price       nulls.Float64 `db:"price"`
+	FullName    nulls.String  `db:"full_name" select:"name as full_name"`
+	Books       Books         `has_many:"books"`
+	BooksCount  int           `count:"books_count"`
+	Houses      Addresses     `many_to_many:"users_addresses"`
+	HousesCount int64         `count:"houses_count"`
+}
+
+func (UserWithCounts) TableName() string {
+	return "users"
+}
+
+func Test_WithCount(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		u1 := &User{Name: nulls.NewString("A")}
+		u2 := &User{Name: nulls.NewString("B")}
+		r.NoError(tx.Create(u1))
+		r.NoError(tx.Create(u2))
+
+		r.NoError(tx.Create(&Book{Title: "Pop", Isbn: "PB1", UserID: nulls.NewInt(u1.ID)}))
+		r.NoError(tx.Create(&Book{Title: "Buffalo", Isbn: "PB2", UserID: nulls.NewInt(u1.ID)}))
+		r.NoError(tx.Create(&Book{Title: "Soda", Isbn: "PB3", UserID: nulls.NewInt(u2.ID)}))
+
+		a := &Address{Street: "Pop Avenue", HouseNumber: 1}
+		r.NoError(tx.Create(a))
+		r.NoError(tx.Create(&UsersAddress{UserID: u2.ID, AddressID: a.ID}))
+
+		users := []UserWithCounts{}
+		err := tx.WithCount("Books", "books_count").WithCount("Houses", "houses_count").Order("id asc").All(&users)
+		r.NoError(err)
+		r.Len(users, 2)
+		r.Equal(2, users[0].BooksCount)
+		r.Equal(int64(0), users[0].HousesCount)
+		r.Equal(1, users[1].BooksCount)
+		r.Equal(int64(1), users[1].HousesCount)
+		r.Len(users[0].Books, 0)
+
+		user := UserWithCounts{}
+		r.NoError(tx.WithCount("Books", "books_count").Find(&user, u1.ID))
+		r.Equal(2, user.BooksCount)
+
+		r.Error(tx.WithCount("Books", "unknown").First(&user))
+		r.Error(tx.WithCount("Name", "books_count").First(&user))
+	})
+}
+
+func Test_CountAssociation(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	transaction(func(tx *Connection) {
+		u1 := &User{Name: nulls.NewString("A")}
+		u2 := &User{Name: nulls.NewString("B")}
+		r.NoError(tx.Create(u1))
+		r.NoError(tx.Create(u2))
+
+		r.NoError(tx.Create(&Book{Title: "Pop", Isbn: "PB1", UserID: nulls.NewInt(u1.ID)}))
+		r.NoError(tx.Create(&Book{Title: "Buffalo", Isbn: "PB2", UserID: nulls.NewInt(u1.ID)}))
+		r.NoError(tx.Create(&Book{Title: "Soda", Isbn: "PB3", UserID: nulls.NewInt(u2.ID)}))
+
+		a := &Address{Street: "Pop Avenue", HouseNumber: 1}
+		r.NoError(tx.Create(a))
+		r.NoError(tx.Create(&UsersAddress{UserID: u2.ID, AddressID: a.ID}))
+
+		n, err := tx.CountAssociation(&UserWithCounts{ID: u1.ID}, "Books")
+		r.NoError(err)
+		r.Equal(2, n)
+		n, err = tx.Q().CountAssociation(&UserWithCounts{ID: u2.ID}, "Houses")
+		r.NoError(err)
+		r.Equal(1, n)
+		n, err = tx.CountAssociation(&UserWithCounts{ID: u1.ID}, "Houses")
+		r.NoError(err)
+		r.Equal(0, n)
+
+		_, err = tx.CountAssociation(&UserWithCounts{ID: u1.ID}, "Unknown")
+		r.Error(err)
+		_, err = tx.CountAssociation(&UserWithCounts{ID: u1.ID}, "Name")
+		r.Error(err)
+		_, err = tx.CountAssociation(&[]UserWithCounts{}, "Books")
+		r.Error(err)
+	})
+}
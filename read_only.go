@@ -0,0 +1,55 @@
+package pop
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrReadOnly is returned by write operations (Create, Update, Destroy,
+// Exec, ...) when the connection, or the context it uses, is in read-only
+// mode.
+var ErrReadOnly = errors.New("connection is in read-only mode")
+
+type readOnlyKey struct{}
+
+// WithReadOnly returns a copy of the context putting the connections
+// using it in read-only mode.
+//
+//	tx.WithContext(pop.WithReadOnly(ctx)).Create(&user) // => ErrReadOnly
+func WithReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+// SetReadOnly enables or disables the read-only mode of the connection.
+// While enabled, all the write operations return ErrReadOnly. The mode is
+// shared with the transactions and copies (e.g. WithContext) of the
+// connection, which makes it suitable for maintenance windows.
+func (c *Connection) SetReadOnly(readOnly bool) {
+	if c.readOnly == nil {
+		c.readOnly = new(int32)
+	}
+	var v int32
+	if readOnly {
+		v = 1
+	}
+	atomic.StoreInt32(c.readOnly, v)
+}
+
+// IsReadOnly returns true if the connection, or the context it uses, is in
+// read-only mode.
+func (c *Connection) IsReadOnly() bool {
+	if c.readOnly != nil && atomic.LoadInt32(c.readOnly) == 1 {
+		return true
+	}
+	ro, _ := c.Context().Value(readOnlyKey{}).(bool)
+	return ro
+}
+
+// checkWritable returns ErrReadOnly if the connection is in read-only mode.
+func (c *Connection) checkWritable() error {
+	if c.IsReadOnly() {
+		return ErrReadOnly
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package pop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReadOnly(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{}
+	r.False(c.IsReadOnly())
+	r.NoError(c.checkWritable())
+
+	c.SetReadOnly(true)
+	r.True(c.IsReadOnly())
+	r.ErrorIs(c.checkWritable(), ErrReadOnly)
+
+	// copies share the mode of the original connection
+	cp := c.copy()
+	r.True(cp.IsReadOnly())
+	c.SetReadOnly(false)
+	r.False(cp.IsReadOnly())
+
+	cp = c.WithContext(WithReadOnly(context.Background()))
+	r.True(cp.IsReadOnly())
+	r.False(c.IsReadOnly())
+}
+
+func Test_ReadOnly_Writes(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	transaction(func(tx *Connection) {
+		u := &User{Name: nulls.NewString("Mark")}
+		r.NoError(tx.Create(u))
+
+		ro := tx.WithContext(WithReadOnly(context.Background()))
+		r.ErrorIs(ro.Create(&User{Name: nulls.NewString("Jane")}), ErrReadOnly)
+		r.ErrorIs(ro.Update(u), ErrReadOnly)
+		r.ErrorIs(ro.UpdateColumns(u, "name"), ErrReadOnly)
+		r.ErrorIs(ro.Destroy(u), ErrReadOnly)
+		r.ErrorIs(ro.RawQuery("DELETE FROM users").Exec(), ErrReadOnly)
+		_, err := ro.Where("1 = 1").UpdateQuery(&User{}, "name")
+		r.ErrorIs(err, ErrReadOnly)
+
+		// reads keep working
+		r.NoError(ro.Find(&User{}, u.ID))
+		count, err := ro.Count(&User{})
+		r.NoError(err)
+		r.Equal(1, count)
+	})
+}
@@ -0,0 +1,168 @@
+package pop
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ReaderPolicy is how the reads of a connection are spread over its
+// readers, see ConnectionDetails.Readers.
+type ReaderPolicy string
+
+const (
+	// ReaderRoundRobin sends the reads to each reader in turn. It is the
+	// default policy.
+	ReaderRoundRobin ReaderPolicy = "round-robin"
+	// ReaderLeastConn sends the reads to the reader with the fewest
+	// connections in use.
+	ReaderLeastConn ReaderPolicy = "least-conn"
+)
+
+type writerKey struct{}
+
+// WithWriter returns a copy of the context sending the reads of the
+// connections using it to the writer rather than to the readers, to read
+// the rows they just wrote.
+//
+//	c.WithContext(pop.WithWriter(ctx)).Find(&user, id)
+func WithWriter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, writerKey{}, true)
+}
+
+// readQuery matches the statements which can be run on a reader, and
+// writerOnly the ones which must not be, even though they select.
+var (
+	readQuery  = regexp.MustCompile(`(?i)^\s*SELECT\b`)
+	writerOnly = regexp.MustCompile(`(?i)\bFOR\s+(NO\s+KEY\s+)?(UPDATE|SHARE)\b|\bFOR\s+KEY\s+SHARE\b|\bLOCK\s+IN\s+SHARE\s+MODE\b|\b(NEXTVAL|CURRVAL|LAST_INSERT_ID)\b`)
+)
+
+// splitStore sends the reads run outside of transactions to the readers,
+// and everything else to the writer.
+type splitStore struct {
+	store
+	readers []*dB
+	policy  ReaderPolicy
+	next    *uint32
+}
+
+// openReaders opens the readers of the connection details, with the pool
+// settings of the writer.
+func openReaders(deets *ConnectionDetails) ([]*dB, error) {
+	var readers []*dB
+	for _, u := range deets.Readers {
+		rd := *deets
+		rd.URL = u
+		rd.Readers = nil
+		rd.Options = make(map[string]string, len(deets.Options))
+		for k, v := range deets.Options {
+			rd.Options[k] = v
+		}
+		err := rd.Finalize()
+		var d dialect
+		if err == nil {
+			d, err = newConnection[rd.Dialect](&rd)
+		}
+		var db *sqlx.DB
+		if err == nil {
+			db, err = openPotentiallyInstrumentedConnection(d, d.URL())
+		}
+		if err == nil {
+			if err = waitReady(db, deets); err != nil {
+				db.Close()
+			}
+		}
+		if err != nil {
+			for _, r := range readers {
+				r.Close()
+			}
+			return nil, fmt.Errorf("could not open reader %s: %w", maskSecrets(u), err)
+		}
+
+		db.SetMaxOpenConns(deets.Pool)
+		if deets.IdlePool != 0 {
+			db.SetMaxIdleConns(deets.IdlePool)
+		}
+		if deets.ConnMaxLifetime > 0 {
+			db.SetConnMaxLifetime(deets.ConnMaxLifetime)
+		}
+		if deets.ConnMaxIdleTime > 0 {
+			db.SetConnMaxIdleTime(deets.ConnMaxIdleTime)
+		}
+		if deets.Unsafe || deets.TolerateSchemaDrift {
+			db = db.Unsafe()
+		}
+		readers = append(readers, &dB{DB: db, stmts: newStmtCache(db, deets.StatementCacheSize)})
+	}
+	return readers, nil
+}
+
+// split wraps the writer store with the readers, if any.
+func split(writer store, readers []*dB, policy ReaderPolicy) store {
+	if len(readers) == 0 {
+		return writer
+	}
+	return splitStore{store: writer, readers: readers, policy: policy, next: new(uint32)}
+}
+
+func (s splitStore) unwrap() store {
+	return s.store
+}
+
+// storeFor returns the store running the query.
+func (s splitStore) storeFor(ctx context.Context, query string) store {
+	if w, _ := ctx.Value(writerKey{}).(bool); w {
+		return s.store
+	}
+	if !readQuery.MatchString(query) || writerOnly.MatchString(query) {
+		return s.store
+	}
+	if s.policy == ReaderLeastConn {
+		best := s.readers[0]
+		for _, r := range s.readers[1:] {
+			if r.Stats().InUse < best.Stats().InUse {
+				best = r
+			}
+		}
+		return best
+	}
+	i := atomic.AddUint32(s.next, 1) - 1
+	return s.readers[int(i%uint32(len(s.readers)))]
+}
+
+func (s splitStore) Select(dest interface{}, query string, args ...interface{}) error {
+	return s.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (s splitStore) Get(dest interface{}, query string, args ...interface{}) error {
+	return s.GetContext(context.Background(), dest, query, args...)
+}
+
+func (s splitStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.storeFor(ctx, query).SelectContext(ctx, dest, query, args...)
+}
+
+func (s splitStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return s.storeFor(ctx, query).GetContext(ctx, dest, query, args...)
+}
+
+func (s splitStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return s.storeFor(ctx, query).QueryxContext(ctx, query, args...)
+}
+
+// Close closes the readers and the writer.
+func (s splitStore) Close() error {
+	var err error
+	for _, r := range s.readers {
+		if rerr := r.Close(); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	if werr := s.store.Close(); werr != nil {
+		return werr
+	}
+	return err
+}
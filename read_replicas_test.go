@@ -0,0 +1,80 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type replicatedNote struct {
+	ID   int    `db:"id"`
+	Body string `db:"body"`
+}
+
+func Test_Readers(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	writerURL := "sqlite://" + filepath.Join(dir, "writer.db")
+	readerURL := "sqlite://" + filepath.Join(dir, "reader.db")
+
+	reader, err := NewConnection(&ConnectionDetails{URL: readerURL})
+	r.NoError(err)
+	r.NoError(reader.Open())
+	r.NoError(reader.RawQuery("CREATE TABLE replicated_notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL)").Exec())
+	r.NoError(reader.Create(&replicatedNote{ID: 1, Body: "replica"}))
+	r.NoError(reader.Close())
+
+	c, err := NewConnection(&ConnectionDetails{URL: writerURL, Readers: []string{readerURL}})
+	r.NoError(err)
+	r.NoError(c.Open())
+	defer c.Close()
+	r.NoError(c.RawQuery("CREATE TABLE replicated_notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL)").Exec())
+	r.NoError(c.Create(&replicatedNote{ID: 1, Body: "writer"}))
+
+	note := &replicatedNote{}
+	r.NoError(c.Find(note, 1))
+	r.Equal("replica", note.Body)
+
+	r.NoError(c.WithContext(WithWriter(context.Background())).Find(note, 1))
+	r.Equal("writer", note.Body)
+
+	r.NoError(c.Transaction(func(tx *Connection) error {
+		return tx.Find(note, 1)
+	}))
+	r.Equal("writer", note.Body)
+
+	_, err = NewConnection(&ConnectionDetails{URL: writerURL, ReaderPolicy: "random"})
+	r.Error(err)
+}
+
+func Test_splitStore_storeFor(t *testing.T) {
+	r := require.New(t)
+
+	w := &dB{}
+	readers := []*dB{{}, {}}
+	s := split(w, readers, ReaderRoundRobin).(splitStore)
+	ctx := context.Background()
+
+	r.True(s.storeFor(ctx, "SELECT * FROM users") == readers[0])
+	r.True(s.storeFor(ctx, " select count(*) from users") == readers[1])
+	r.True(s.storeFor(ctx, "SELECT * FROM users") == readers[0])
+
+	for _, q := range []string{
+		"INSERT INTO users (name) VALUES (?)",
+		"SELECT * FROM users WHERE id = ? FOR UPDATE",
+		"SELECT * FROM jobs FOR NO KEY UPDATE SKIP LOCKED",
+		"SELECT nextval('users_id_seq')",
+		"WITH d AS (DELETE FROM users RETURNING id) SELECT id FROM d",
+	} {
+		r.True(s.storeFor(ctx, q) == store(w), q)
+	}
+	r.True(s.storeFor(WithWriter(ctx), "SELECT * FROM users") == store(w))
+
+	r.True(split(w, nil, "") == store(w))
+}
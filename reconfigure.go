@@ -0,0 +1,255 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Reconfigure opens a new pool with the details, e.g. with the rotated
+// credentials of the database or its new hosts, and swaps it for the pool
+// of the connection, then drains and closes the previous one: the
+// statements and transactions already running on it finish, the ones
+// starting run on the new pool, shared by the copies of the connection,
+// e.g. by WithContext. If the new pool can not be opened, the connection
+// keeps the previous one.
+//
+// The details must be of the same dialect. Their hosts, credentials,
+// readers and pool settings are used for the new pool, while the
+// connection keeps its dialect, building its statements, and its
+// allowlist. Reconfigure returns once the transactions begun on the
+// previous pool are committed or rolled back, and the pool is closed: it
+// must not be called from the function of a Transaction, which it would
+// wait for.
+//
+//	deets.Password = rotated
+//	err := c.Reconfigure(deets)
+func (c *Connection) Reconfigure(deets *ConnectionDetails) error {
+	if c.TX != nil {
+		return errors.New("can not reconfigure a transaction")
+	}
+	if err := deets.Finalize(); err != nil {
+		return err
+	}
+	nc, ok := newConnection[deets.Dialect]
+	if !ok {
+		return fmt.Errorf("could not found connection creator for %v", deets.Dialect)
+	}
+	d, err := nc(deets)
+	if err != nil {
+		return fmt.Errorf("could not create new connection: %w", err)
+	}
+	if d.Name() != c.Dialect.Name() {
+		return fmt.Errorf("can not reconfigure a %s connection as a %s one", c.Dialect.Name(), d.Name())
+	}
+
+	r := rotatingOf(c.Store)
+	if r == nil {
+		if c.Store != nil {
+			return errors.New("can not reconfigure the connection, its store is not rotatable")
+		}
+		// not opened yet, Open uses the new dialect
+		c.Dialect = d
+		return nil
+	}
+	s, err := openStore(d, c.allowlists)
+	if err != nil {
+		return err
+	}
+	previous := r.swap(s, d)
+	previous.inFlight.Wait()
+	if err := previous.store.Close(); err != nil {
+		return fmt.Errorf("couldn't close the previous pool: %w", err)
+	}
+	return nil
+}
+
+// rotation is a store and the dialect it was opened with.
+type rotation struct {
+	store   store
+	dialect dialect
+	// inFlight counts the calls running on the store and the transactions
+	// begun on it and not ended yet.
+	inFlight sync.WaitGroup
+}
+
+// begin holds the rotation until the transaction, begun on its store, is
+// committed or rolled back.
+func (rot *rotation) begin(tx *Tx, err error) (*Tx, error) {
+	if err != nil {
+		rot.inFlight.Done()
+		return nil, err
+	}
+	tx.onEnd(rot.inFlight.Done)
+	return tx, nil
+}
+
+// rotatingStore is the store of an opened connection, shared by its
+// copies, whose pool Reconfigure swaps.
+type rotatingStore struct {
+	mu      sync.RWMutex
+	current *rotation
+}
+
+func newRotatingStore(s store, d dialect) *rotatingStore {
+	return &rotatingStore{current: &rotation{store: s, dialect: d}}
+}
+
+// rotatingOf returns the rotating store wrapped by the store, or nil.
+func rotatingOf(s store) *rotatingStore {
+	for s != nil {
+		if r, ok := s.(*rotatingStore); ok {
+			return r
+		}
+		w, ok := s.(wrappedStore)
+		if !ok {
+			return nil
+		}
+		s = w.unwrap()
+	}
+	return nil
+}
+
+func (r *rotatingStore) load() *rotation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// acquire returns the current rotation, held until its inFlight is done:
+// once swapped, a rotation is no longer acquired, and its calls can be
+// waited for.
+func (r *rotatingStore) acquire() *rotation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	r.current.inFlight.Add(1)
+	return r.current
+}
+
+// swap sets the store and returns the previous rotation.
+func (r *rotatingStore) swap(s store, d dialect) *rotation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	previous := r.current
+	r.current = &rotation{store: s, dialect: d}
+	return previous
+}
+
+func (r *rotatingStore) unwrap() store {
+	return r.load().store
+}
+
+func (r *rotatingStore) Select(dest interface{}, query string, args ...interface{}) error {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.Select(dest, query, args...)
+}
+
+func (r *rotatingStore) Get(dest interface{}, query string, args ...interface{}) error {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.Get(dest, query, args...)
+}
+
+func (r *rotatingStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.NamedExec(query, arg)
+}
+
+func (r *rotatingStore) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.NamedQuery(query, arg)
+}
+
+func (r *rotatingStore) Exec(query string, args ...interface{}) (sql.Result, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.Exec(query, args...)
+}
+
+func (r *rotatingStore) PrepareNamed(query string) (*sqlx.NamedStmt, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.PrepareNamed(query)
+}
+
+func (r *rotatingStore) Transaction() (*Tx, error) {
+	rot := r.acquire()
+	return rot.begin(rot.store.Transaction())
+}
+
+func (r *rotatingStore) Rollback() error {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.Rollback()
+}
+
+func (r *rotatingStore) Commit() error {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.Commit()
+}
+
+// Close closes the current store. The connection is closed, none of its
+// calls are waited for.
+func (r *rotatingStore) Close() error {
+	return r.load().store.Close()
+}
+
+func (r *rotatingStore) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.SelectContext(ctx, dest, query, args...)
+}
+
+func (r *rotatingStore) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.GetContext(ctx, dest, query, args...)
+}
+
+func (r *rotatingStore) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.NamedExecContext(ctx, query, arg)
+}
+
+func (r *rotatingStore) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.NamedQueryContext(ctx, query, arg)
+}
+
+func (r *rotatingStore) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.ExecContext(ctx, query, args...)
+}
+
+func (r *rotatingStore) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.QueryxContext(ctx, query, args...)
+}
+
+func (r *rotatingStore) PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error) {
+	rot := r.acquire()
+	defer rot.inFlight.Done()
+	return rot.store.PrepareNamedContext(ctx, query)
+}
+
+func (r *rotatingStore) TransactionContext(ctx context.Context) (*Tx, error) {
+	rot := r.acquire()
+	return rot.begin(rot.store.TransactionContext(ctx))
+}
+
+func (r *rotatingStore) TransactionContextOptions(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	rot := r.acquire()
+	return rot.begin(rot.store.TransactionContextOptions(ctx, opts))
+}
@@ -0,0 +1,118 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Reconfigure(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE rotated (name TEXT)").Exec())
+	r.NoError(c.RawQuery("INSERT INTO rotated (name) VALUES ('old')").Exec())
+	copied := c.WithContext(context.Background())
+	previous := unwrapStore(c.Store).(*dB)
+
+	database := filepath.Join(t.TempDir(), "rotated.sqlite")
+	r.NoError(c.Reconfigure(&ConnectionDetails{Dialect: "sqlite3", Database: database}))
+	r.Contains(c.URL(), database)
+	r.Error(previous.Ping(), "the previous pool is closed")
+
+	// the copies made before run on the new pool
+	r.NoError(copied.RawQuery("CREATE TABLE rotated (name TEXT)").Exec())
+	r.NoError(copied.RawQuery("INSERT INTO rotated (name) VALUES ('new')").Exec())
+	var names []string
+	r.NoError(c.Store.Select(&names, "SELECT name FROM rotated"))
+	r.Equal([]string{"new"}, names)
+	r.NoError(c.Transaction(func(tx *Connection) error {
+		r.Error(tx.Reconfigure(&ConnectionDetails{Dialect: "sqlite3", Database: database}))
+		return nil
+	}))
+
+	// a pool which can not be opened leaves the connection as it is
+	r.Error(c.Reconfigure(&ConnectionDetails{Dialect: "sqlite3", Database: filepath.Join(t.TempDir(), "missing", "db.sqlite")}))
+	r.Error(c.Reconfigure(&ConnectionDetails{Dialect: "postgres", Database: "pop_test"}))
+	r.Contains(c.URL(), database)
+	r.NoError(c.Store.Select(&names, "SELECT name FROM rotated"))
+
+	// a connection not opened yet opens the new details
+	nc, err := NewConnection(&ConnectionDetails{Dialect: "sqlite3", Database: filepath.Join(t.TempDir(), "unused.sqlite")})
+	r.NoError(err)
+	r.NoError(nc.Reconfigure(&ConnectionDetails{Dialect: "sqlite3", Database: database}))
+	r.NoError(nc.Open())
+	defer nc.Close()
+	r.NoError(nc.Store.Select(&names, "SELECT name FROM rotated"))
+	r.Equal([]string{"new"}, names)
+}
+
+func Test_Reconfigure_Concurrent(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	details := func(i int) *ConnectionDetails {
+		return &ConnectionDetails{Dialect: "sqlite3", Database: filepath.Join(dir, fmt.Sprintf("rotated%d.sqlite", i%2)), StatementCacheSize: 4}
+	}
+	for i := 0; i < 2; i++ {
+		nc, err := NewConnection(details(i))
+		r.NoError(err)
+		r.NoError(nc.Open())
+		r.NoError(nc.RawQuery("CREATE TABLE rotated (name TEXT)").Exec())
+		r.NoError(nc.RawQuery("INSERT INTO rotated (name) VALUES (?)", fmt.Sprint(i)).Exec())
+		r.NoError(nc.Close())
+	}
+	c, err := NewConnection(details(0))
+	r.NoError(err)
+	r.NoError(c.Open())
+	defer c.Close()
+
+	// the statements and transactions running during the swaps run on
+	// the previous pool or the new one, never on a closed one
+	done := make(chan struct{})
+	errs := make(chan error, 4)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				var names []string
+				err := c.Store.Select(&names, "SELECT name FROM rotated WHERE name <> ?", "")
+				if err == nil {
+					err = c.Transaction(func(tx *Connection) error {
+						if err := tx.RawQuery("SELECT name FROM rotated WHERE name <> ?", "").All(&names); err != nil {
+							return err
+						}
+						return tx.RawQuery("SELECT name FROM rotated WHERE name = ?", "0").All(&names)
+					})
+				}
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	for i := 1; i <= 20; i++ {
+		r.NoError(c.Reconfigure(details(i)))
+	}
+	close(done)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		r.NoError(err)
+	}
+}
@@ -0,0 +1,198 @@
+package replication
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// relation is a table described by a pgoutput Relation message.
+type relation struct {
+	schema  string
+	table   string
+	columns []relationColumn
+}
+
+type relationColumn struct {
+	name string
+	oid  uint32
+}
+
+// pgOutputDecoder decodes the messages of the version 1 of the pgoutput
+// protocol. The Relation messages describing the tables are sent before
+// their first change, and kept.
+type pgOutputDecoder struct {
+	relations map[uint32]relation
+}
+
+// errShortMessage is returned for the messages ending too early.
+var errShortMessage = errors.New("replication: short pgoutput message")
+
+// pgReader reads the fields of a pgoutput message.
+type pgReader struct {
+	data []byte
+	err  error
+}
+
+func (r *pgReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = errShortMessage
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *pgReader) byte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *pgReader) uint16() uint16 {
+	if b := r.next(2); b != nil {
+		return binary.BigEndian.Uint16(b)
+	}
+	return 0
+}
+
+func (r *pgReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *pgReader) string() string {
+	if r.err != nil {
+		return ""
+	}
+	i := bytes.IndexByte(r.data, 0)
+	if i < 0 {
+		r.err = errShortMessage
+		return ""
+	}
+	s := string(r.data[:i])
+	r.data = r.data[i+1:]
+	return s
+}
+
+func (d *pgOutputDecoder) decode(data []byte) ([]Event, bool, error) {
+	if len(data) == 0 {
+		return nil, false, errShortMessage
+	}
+	r := &pgReader{data: data[1:]}
+	var events []Event
+	switch data[0] {
+	case 'C':
+		return nil, true, nil
+	case 'R':
+		id := r.uint32()
+		rel := relation{schema: r.string(), table: r.string()}
+		r.byte() // replica identity
+		n := int(r.uint16())
+		for i := 0; i < n && r.err == nil; i++ {
+			r.byte() // flags
+			col := relationColumn{name: r.string(), oid: r.uint32()}
+			r.uint32() // type modifier
+			rel.columns = append(rel.columns, col)
+		}
+		if r.err == nil {
+			d.relations[id] = rel
+		}
+	case 'I':
+		rel, err := d.relation(r.uint32())
+		if err != nil {
+			return nil, false, err
+		}
+		r.byte() // N
+		e := Event{Action: Insert, Schema: rel.schema, Table: rel.table}
+		if e.Values, err = d.tuple(r, rel); err != nil {
+			return nil, false, err
+		}
+		events = append(events, e)
+	case 'U':
+		rel, err := d.relation(r.uint32())
+		if err != nil {
+			return nil, false, err
+		}
+		e := Event{Action: Update, Schema: rel.schema, Table: rel.table}
+		kind := r.byte()
+		if kind == 'K' || kind == 'O' {
+			if e.OldValues, err = d.tuple(r, rel); err != nil {
+				return nil, false, err
+			}
+			r.byte() // N
+		}
+		if e.Values, err = d.tuple(r, rel); err != nil {
+			return nil, false, err
+		}
+		events = append(events, e)
+	case 'D':
+		rel, err := d.relation(r.uint32())
+		if err != nil {
+			return nil, false, err
+		}
+		r.byte() // K or O
+		e := Event{Action: Delete, Schema: rel.schema, Table: rel.table}
+		if e.Values, err = d.tuple(r, rel); err != nil {
+			return nil, false, err
+		}
+		events = append(events, e)
+	case 'T':
+		n := int(r.uint32())
+		r.byte() // options
+		for i := 0; i < n && r.err == nil; i++ {
+			rel, err := d.relation(r.uint32())
+			if err != nil {
+				return nil, false, err
+			}
+			events = append(events, Event{Action: Truncate, Schema: rel.schema, Table: rel.table})
+		}
+	}
+	// the Begin, Origin, Type and Message messages carry no change
+	return events, false, r.err
+}
+
+// relation returns the relation described with the ID.
+func (d *pgOutputDecoder) relation(id uint32) (relation, error) {
+	rel, ok := d.relations[id]
+	if !ok {
+		return rel, fmt.Errorf("replication: unknown relation %d", id)
+	}
+	return rel, nil
+}
+
+// tuple reads the values of the columns of a row of the relation, leaving
+// out the unchanged TOAST ones.
+func (d *pgOutputDecoder) tuple(r *pgReader, rel relation) (map[string]interface{}, error) {
+	n := int(r.uint16())
+	if r.err == nil && n > len(rel.columns) {
+		return nil, fmt.Errorf("replication: %d columns sent for %s.%s, %d described", n, rel.schema, rel.table, len(rel.columns))
+	}
+	values := make(map[string]interface{}, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		col := rel.columns[i]
+		switch r.byte() {
+		case 'n':
+			values[col.name] = nil
+		case 't':
+			text := r.next(int(r.uint32()))
+			if r.err != nil {
+				break
+			}
+			v, err := textValue(col.oid, string(text))
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", rel.table, col.name, err)
+			}
+			values[col.name] = v
+		}
+	}
+	return values, r.err
+}
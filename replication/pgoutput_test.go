@@ -0,0 +1,81 @@
+package replication
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pgMessage builds a pgoutput message.
+type pgMessage []byte
+
+func (m pgMessage) byte(b byte) pgMessage { return append(m, b) }
+
+func (m pgMessage) uint16(n uint16) pgMessage {
+	return append(m, byte(n>>8), byte(n))
+}
+
+func (m pgMessage) uint32(n uint32) pgMessage {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, n)
+	return append(m, b...)
+}
+
+func (m pgMessage) string(s string) pgMessage { return append(append(m, s...), 0) }
+
+func (m pgMessage) text(s string) pgMessage { return m.byte('t').uint32(uint32(len(s))).append(s) }
+
+func (m pgMessage) append(s string) pgMessage { return append(m, s...) }
+
+func Test_pgOutputDecoder(t *testing.T) {
+	r := require.New(t)
+
+	d := &pgOutputDecoder{relations: map[uint32]relation{}}
+	rel := pgMessage{'R'}.uint32(42).string("public").string("users").byte('d').uint16(4).
+		byte(1).string("id").uint32(int8OID).uint32(0xffffffff).
+		byte(0).string("name").uint32(25).uint32(0xffffffff).
+		byte(0).string("admin").uint32(boolOID).uint32(0xffffffff).
+		byte(0).string("created_at").uint32(timestamptzOID).uint32(0xffffffff)
+	events, commit, err := d.decode(rel)
+	r.NoError(err)
+	r.False(commit)
+	r.Empty(events)
+
+	events, _, err = d.decode(pgMessage{'I'}.uint32(42).byte('N').uint16(4).text("7").text("Mark").text("t").text("2024-03-01 10:20:30.5+02"))
+	r.NoError(err)
+	r.Len(events, 1)
+	r.Equal(Insert, events[0].Action)
+	r.Equal("users", events[0].Table)
+	r.Equal(int64(7), events[0].Values["id"])
+	r.Equal("Mark", events[0].Values["name"])
+	r.Equal(true, events[0].Values["admin"])
+	r.True(time.Date(2024, 3, 1, 8, 20, 30, 500000000, time.UTC).Equal(events[0].Values["created_at"].(time.Time)))
+
+	events, _, err = d.decode(pgMessage{'U'}.uint32(42).byte('K').uint16(4).text("6").byte('n').byte('n').byte('n').
+		byte('N').uint16(4).text("7").byte('n').text("f").byte('u'))
+	r.NoError(err)
+	r.Equal(Update, events[0].Action)
+	r.Equal(map[string]interface{}{"id": int64(6), "name": nil, "admin": nil, "created_at": nil}, events[0].OldValues)
+	r.Equal(map[string]interface{}{"id": int64(7), "name": nil, "admin": false}, events[0].Values)
+
+	events, _, err = d.decode(pgMessage{'D'}.uint32(42).byte('K').uint16(1).text("7"))
+	r.NoError(err)
+	r.Equal(Delete, events[0].Action)
+	r.Equal(map[string]interface{}{"id": int64(7)}, events[0].Values)
+
+	events, _, err = d.decode(pgMessage{'T'}.uint32(1).byte(0).uint32(42))
+	r.NoError(err)
+	r.Equal([]Event{{Action: Truncate, Schema: "public", Table: "users"}}, events)
+
+	_, commit, err = d.decode(pgMessage{'C'}.byte(0))
+	r.NoError(err)
+	r.True(commit)
+
+	_, _, err = d.decode(pgMessage{'I'}.uint32(43).byte('N').uint16(0))
+	r.EqualError(err, "replication: unknown relation 43")
+
+	_, _, err = d.decode(pgMessage{'I'}.uint32(42).byte('N').uint16(1).byte('t').uint32(10).append("7"))
+	r.Equal(errShortMessage, err)
+}
@@ -0,0 +1,330 @@
+// Package replication consumes the changes of a PostgreSQL database from a
+// logical replication slot, decoded by the pgoutput or wal2json plugins,
+// as events holding the rows changed, mapped to the pop models registered
+// for their tables. Caches, search indexes or copies of the tables can
+// then be kept up to date without triggers or polling.
+//
+//	consumer, err := replication.NewConsumer(c, "cache", replication.PgOutput)
+//	consumer.Publications = []string{"cache"}
+//	consumer.Register(&models.User{})
+//	err = consumer.Run(ctx, func(e replication.Event) error {
+//		if user, ok := e.Model.(*models.User); ok {
+//			return cache.Set(user)
+//		}
+//		return nil
+//	})
+package replication
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgproto3/v2"
+	"github.com/jackc/pgx/v4"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// Format is the output plugin decoding the changes of the slot.
+type Format string
+
+const (
+	// PgOutput is the plugin of the built-in logical replication, which
+	// streams the changes of the tables of publications.
+	PgOutput Format = "pgoutput"
+	// Wal2JSON is the wal2json plugin, in its format version 2.
+	Wal2JSON Format = "wal2json"
+)
+
+// Action is the kind of change of an event.
+type Action string
+
+const (
+	// Insert events hold the row inserted.
+	Insert Action = "INSERT"
+	// Update events hold the row updated and, if the replica identity of
+	// the table sends it, its previous key or row.
+	Update Action = "UPDATE"
+	// Delete events hold the key, or the row with the FULL replica
+	// identity, of the row deleted.
+	Delete Action = "DELETE"
+	// Truncate events hold no row.
+	Truncate Action = "TRUNCATE"
+)
+
+// LSN is a position in the write-ahead log.
+type LSN uint64
+
+func (l LSN) String() string {
+	return fmt.Sprintf("%X/%X", uint32(l>>32), uint32(l))
+}
+
+// Event is a change of a row.
+type Event struct {
+	Action Action
+	Schema string
+	Table  string
+	// Values are the columns of the row, as the database driver would
+	// read them. The unchanged TOAST columns of the updated rows are not
+	// sent, nor held.
+	Values map[string]interface{}
+	// OldValues are the columns of the key, or of the whole row, of the
+	// row before an update, if the replica identity of the table sends
+	// them.
+	OldValues map[string]interface{}
+	// Model is a pointer to a new model of the type registered for the
+	// table, holding the values, or nil if none is registered.
+	Model interface{}
+	// LSN is the position of the change in the write-ahead log.
+	LSN LSN
+}
+
+// Consumer reads the changes of a logical replication slot.
+type Consumer struct {
+	// Slot is the name of the replication slot.
+	Slot string
+	// Format is the output plugin of the slot.
+	Format Format
+	// Publications are the publications streamed by PgOutput.
+	Publications []string
+	// CreateSlot creates the slot when Run starts, if it does not exist.
+	CreateSlot bool
+	// StatusInterval is the interval at which the position of the events
+	// handled is reported to the database, which may then recycle the
+	// write-ahead log before it. Defaults to 10 seconds.
+	StatusInterval time.Duration
+
+	url    string
+	mapper *reflectx.Mapper
+	models map[string]reflect.Type
+}
+
+// NewConsumer returns a consumer of the slot of the database of the
+// connection, which must be a PostgreSQL one.
+func NewConsumer(c *pop.Connection, slot string, format Format) (*Consumer, error) {
+	if c.Dialect.Name() != "postgres" {
+		return nil, fmt.Errorf("logical replication is not supported by %s", c.Dialect.Name())
+	}
+	switch format {
+	case PgOutput, Wal2JSON:
+	default:
+		return nil, fmt.Errorf("unknown replication format %s", format)
+	}
+	return &Consumer{
+		Slot:           slot,
+		Format:         format,
+		StatusInterval: 10 * time.Second,
+		url:            c.URL(),
+		mapper:         reflectx.NewMapperFunc("db", sqlx.NameMapper),
+		models:         map[string]reflect.Type{},
+	}, nil
+}
+
+// Register maps the table of the model to its type: the events of the
+// table hold a new model of that type.
+func (c *Consumer) Register(model interface{}) {
+	t := reflect.TypeOf(model)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	c.models[pop.NewModel(model, context.Background()).TableName()] = t
+}
+
+// model returns a pointer to a new model of the type registered for the
+// table holding the values, or nil.
+func (c *Consumer) model(schema, table string, values map[string]interface{}) (interface{}, error) {
+	t, ok := c.models[schema+"."+table]
+	if !ok {
+		if t, ok = c.models[table]; !ok {
+			return nil, nil
+		}
+	}
+	v := reflect.New(t)
+	names := c.mapper.TypeMap(t).Names
+	for col, value := range values {
+		fi, ok := names[col]
+		if !ok {
+			continue
+		}
+		if err := assign(reflectx.FieldByIndexes(v.Elem(), fi.Index), value); err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", table, col, err)
+		}
+	}
+	return v.Interface(), nil
+}
+
+// decoder decodes the messages of the output plugin of the slot.
+type decoder interface {
+	// decode returns the events of the message, and true if it commits a
+	// transaction.
+	decode(data []byte) ([]Event, bool, error)
+}
+
+// startSQL returns the statement starting the replication of the slot.
+func (c *Consumer) startSQL() (string, error) {
+	var options string
+	switch c.Format {
+	case PgOutput:
+		if len(c.Publications) == 0 {
+			return "", errors.New("pgoutput streams the changes of publications, none given")
+		}
+		options = fmt.Sprintf("proto_version '1', publication_names '%s'", strings.ReplaceAll(strings.Join(c.Publications, ","), "'", "''"))
+	case Wal2JSON:
+		options = `"format-version" '2', "include-transaction" '1', "include-types" '1'`
+	}
+	return fmt.Sprintf("START_REPLICATION SLOT %s LOGICAL 0/0 (%s)", pgx.Identifier{c.Slot}.Sanitize(), options), nil
+}
+
+// Run streams the changes of the slot, from the last position reported,
+// to handle, until the context is done, the database ends the stream or
+// handle returns an error. The position of a transaction is reported once
+// handle returned nil for all of its events: after an error or a crash,
+// the events of the transactions not reported are streamed again, so
+// handle must be idempotent.
+func (c *Consumer) Run(ctx context.Context, handle func(Event) error) error {
+	start, err := c.startSQL()
+	if err != nil {
+		return err
+	}
+	cfg, err := pgconn.ParseConfig(c.url)
+	if err != nil {
+		return err
+	}
+	cfg.RuntimeParams["replication"] = "database"
+	conn, err := pgconn.ConnectConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if c.CreateSlot {
+		stmt := fmt.Sprintf("CREATE_REPLICATION_SLOT %s LOGICAL %s", pgx.Identifier{c.Slot}.Sanitize(), c.Format)
+		_, err := conn.Exec(ctx, stmt).ReadAll()
+		var pgErr *pgconn.PgError
+		if err != nil && !(errors.As(err, &pgErr) && pgErr.Code == "42710") {
+			return fmt.Errorf("could not create slot %s: %w", c.Slot, err)
+		}
+	}
+	if err := startReplication(ctx, conn, start); err != nil {
+		return err
+	}
+
+	var d decoder = &pgOutputDecoder{relations: map[uint32]relation{}}
+	if c.Format == Wal2JSON {
+		d = wal2JSONDecoder{}
+	}
+	interval := c.StatusInterval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	var received, handled LSN
+	next := time.Now().Add(interval)
+	for {
+		if !time.Now().Before(next) {
+			if err := sendStatus(ctx, conn, received, handled); err != nil {
+				return err
+			}
+			next = time.Now().Add(interval)
+		}
+		rctx, cancel := context.WithDeadline(ctx, next)
+		msg, err := conn.ReceiveMessage(rctx)
+		cancel()
+		if err != nil {
+			if ctx.Err() != nil {
+				// best effort, the connection may be closed
+				_ = sendStatus(context.Background(), conn, received, handled)
+				return ctx.Err()
+			}
+			if pgconn.Timeout(err) {
+				continue
+			}
+			return err
+		}
+
+		switch msg := msg.(type) {
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(msg)
+		case *pgproto3.CopyDone:
+			return sendStatus(ctx, conn, received, handled)
+		case *pgproto3.CopyData:
+			if len(msg.Data) == 0 {
+				continue
+			}
+			switch msg.Data[0] {
+			case 'k':
+				// primary keepalive: WAL end, time, reply requested
+				if len(msg.Data) >= 18 && msg.Data[17] == 1 {
+					next = time.Now()
+				}
+			case 'w':
+				// XLogData: WAL start, WAL end, time, data
+				if len(msg.Data) < 25 {
+					return errors.New("replication: short XLogData message")
+				}
+				lsn := LSN(binary.BigEndian.Uint64(msg.Data[1:]))
+				data := msg.Data[25:]
+				if end := lsn + LSN(len(data)); end > received {
+					received = end
+				}
+				events, commit, err := d.decode(data)
+				if err != nil {
+					return err
+				}
+				for _, e := range events {
+					e.LSN = lsn
+					if e.Model, err = c.model(e.Schema, e.Table, e.Values); err != nil {
+						return err
+					}
+					if err := handle(e); err != nil {
+						return err
+					}
+				}
+				if commit {
+					handled = received
+				}
+			}
+		}
+	}
+}
+
+// startReplication sends the START_REPLICATION statement and waits for the
+// database to start streaming.
+func startReplication(ctx context.Context, conn *pgconn.PgConn, stmt string) error {
+	if err := conn.SendBytes(ctx, (&pgproto3.Query{String: stmt}).Encode(nil)); err != nil {
+		return err
+	}
+	for {
+		msg, err := conn.ReceiveMessage(ctx)
+		if err != nil {
+			return err
+		}
+		switch msg := msg.(type) {
+		case *pgproto3.CopyBothResponse:
+			return nil
+		case *pgproto3.ErrorResponse:
+			return pgconn.ErrorResponseToPgError(msg)
+		}
+	}
+}
+
+// pgEpoch is the epoch of the times of the replication protocol.
+var pgEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// sendStatus reports the positions received and handled, flushed and
+// applied, to the database.
+func sendStatus(ctx context.Context, conn *pgconn.PgConn, received, handled LSN) error {
+	data := make([]byte, 34)
+	data[0] = 'r'
+	binary.BigEndian.PutUint64(data[1:], uint64(received))
+	binary.BigEndian.PutUint64(data[9:], uint64(handled))
+	binary.BigEndian.PutUint64(data[17:], uint64(handled))
+	binary.BigEndian.PutUint64(data[25:], uint64(time.Since(pgEpoch).Microseconds()))
+	return conn.SendBytes(ctx, (&pgproto3.CopyData{Data: data}).Encode(nil))
+}
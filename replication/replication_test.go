@@ -0,0 +1,67 @@
+package replication
+
+import (
+	"testing"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/gobuffalo/nulls"
+	"github.com/gofrs/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type replicatedUser struct {
+	ID        int64        `db:"id"`
+	UUID      uuid.UUID    `db:"uuid"`
+	Name      string       `db:"name"`
+	Nickname  nulls.String `db:"nickname"`
+	Admin     *bool        `db:"admin"`
+	Age       uint8        `db:"age"`
+	CreatedAt time.Time    `db:"created_at"`
+}
+
+func (replicatedUser) TableName() string { return "users" }
+
+func Test_Consumer(t *testing.T) {
+	r := require.New(t)
+
+	mysql, err := pop.NewConnection(&pop.ConnectionDetails{Dialect: "mysql", Database: "pop_test"})
+	r.NoError(err)
+	_, err = NewConsumer(mysql, "cache", PgOutput)
+	r.EqualError(err, "logical replication is not supported by mysql")
+
+	c, err := pop.NewConnection(&pop.ConnectionDetails{Dialect: "postgres", Database: "pop_test"})
+	r.NoError(err)
+	consumer, err := NewConsumer(c, "cache", PgOutput)
+	r.NoError(err)
+	_, err = consumer.startSQL()
+	r.Error(err)
+	consumer.Publications = []string{"users", "posts"}
+	stmt, err := consumer.startSQL()
+	r.NoError(err)
+	r.Equal(`START_REPLICATION SLOT "cache" LOGICAL 0/0 (proto_version '1', publication_names 'users,posts')`, stmt)
+
+	consumer.Register(&replicatedUser{})
+	id := uuid.Must(uuid.NewV4())
+	now := time.Now().UTC().Truncate(time.Second)
+	m, err := consumer.model("public", "users", map[string]interface{}{
+		"id":         int64(7),
+		"uuid":       id.String(),
+		"name":       "Mark",
+		"nickname":   "mk",
+		"admin":      true,
+		"age":        int64(42),
+		"created_at": now,
+		"ignored":    "x",
+	})
+	r.NoError(err)
+	admin := true
+	r.Equal(&replicatedUser{ID: 7, UUID: id, Name: "Mark", Nickname: nulls.NewString("mk"), Admin: &admin, Age: 42, CreatedAt: now}, m)
+
+	m, err = consumer.model("public", "posts", map[string]interface{}{"id": int64(1)})
+	r.NoError(err)
+	r.Nil(m)
+
+	_, err = consumer.model("public", "users", map[string]interface{}{"age": int64(300)})
+	r.Error(err)
+}
@@ -0,0 +1,152 @@
+package replication
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The OIDs of the types whose text is converted by textValue.
+const (
+	boolOID        = 16
+	byteaOID       = 17
+	int8OID        = 20
+	int2OID        = 21
+	int4OID        = 23
+	float4OID      = 700
+	float8OID      = 701
+	dateOID        = 1082
+	timestampOID   = 1114
+	timestamptzOID = 1184
+)
+
+// typeOIDs are the OIDs of the types by the names wal2json gives them.
+var typeOIDs = map[string]uint32{
+	"boolean":                     boolOID,
+	"bytea":                       byteaOID,
+	"bigint":                      int8OID,
+	"smallint":                    int2OID,
+	"integer":                     int4OID,
+	"real":                        float4OID,
+	"double precision":            float8OID,
+	"date":                        dateOID,
+	"timestamp without time zone": timestampOID,
+	"timestamp with time zone":    timestamptzOID,
+}
+
+// typmod matches the modifier of a type name, e.g. (6) in
+// timestamp(6) without time zone.
+var typmod = regexp.MustCompile(`\(\d+(,\d+)?\)`)
+
+// typeOID returns the OID of the type name, or 0 if textValue keeps its
+// values as text.
+func typeOID(name string) uint32 {
+	return typeOIDs[typmod.ReplaceAllString(name, "")]
+}
+
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00:00",
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// textValue returns the value of the text of a column of the type, as the
+// driver would read it: an int64, float64, bool, time.Time or []byte, or
+// the text itself for the other types.
+func textValue(oid uint32, text string) (interface{}, error) {
+	switch oid {
+	case boolOID:
+		return strconv.ParseBool(text)
+	case int2OID, int4OID, int8OID:
+		return strconv.ParseInt(text, 10, 64)
+	case float4OID, float8OID:
+		return strconv.ParseFloat(text, 64)
+	case byteaOID:
+		return hex.DecodeString(strings.TrimPrefix(text, `\x`))
+	case dateOID, timestampOID, timestamptzOID:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, text); err == nil {
+				return t, nil
+			}
+		}
+		// infinity and the dates BC
+		return text, nil
+	}
+	return text, nil
+}
+
+// assign sets the field to the value read by textValue, through its
+// sql.Scanner if it implements it. NULL zeroes the field.
+func assign(field reflect.Value, v interface{}) error {
+	if v == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if field.CanAddr() {
+		if s, ok := field.Addr().Interface().(sql.Scanner); ok {
+			return s.Scan(v)
+		}
+	}
+	if field.Kind() == reflect.Ptr {
+		p := reflect.New(field.Type().Elem())
+		if err := assign(p.Elem(), v); err != nil {
+			return err
+		}
+		field.Set(p)
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Type().AssignableTo(field.Type()) {
+		field.Set(rv)
+		return nil
+	}
+	s := fmt.Sprint(v)
+	if b, ok := v.([]byte); ok {
+		s = string(b)
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+		return nil
+	}
+	if rv.Type().ConvertibleTo(field.Type()) {
+		field.Set(rv.Convert(field.Type()))
+		return nil
+	}
+	return fmt.Errorf("can not assign %T to %s", v, field.Type())
+}
@@ -0,0 +1,93 @@
+package replication
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// wal2JSONChange is a message of the version 2 of the wal2json format.
+type wal2JSONChange struct {
+	Action   string           `json:"action"`
+	Schema   string           `json:"schema"`
+	Table    string           `json:"table"`
+	Columns  []wal2JSONColumn `json:"columns"`
+	Identity []wal2JSONColumn `json:"identity"`
+}
+
+type wal2JSONColumn struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// wal2JSONDecoder decodes the messages of the version 2 of the wal2json
+// format, one per change.
+type wal2JSONDecoder struct{}
+
+func (wal2JSONDecoder) decode(data []byte) ([]Event, bool, error) {
+	var c wal2JSONChange
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&c); err != nil {
+		return nil, false, fmt.Errorf("replication: %w", err)
+	}
+	e := Event{Schema: c.Schema, Table: c.Table}
+	var err error
+	switch c.Action {
+	case "C":
+		return nil, true, nil
+	case "I":
+		e.Action = Insert
+		e.Values, err = wal2JSONValues(c.Table, c.Columns)
+	case "U":
+		e.Action = Update
+		if e.Values, err = wal2JSONValues(c.Table, c.Columns); err == nil && len(c.Identity) > 0 {
+			e.OldValues, err = wal2JSONValues(c.Table, c.Identity)
+		}
+	case "D":
+		e.Action = Delete
+		e.Values, err = wal2JSONValues(c.Table, c.Identity)
+	case "T":
+		e.Action = Truncate
+	default:
+		// the Begin and Message actions carry no change
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return []Event{e}, false, nil
+}
+
+// wal2JSONValues returns the values of the columns, as textValue does.
+func wal2JSONValues(table string, cols []wal2JSONColumn) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(cols))
+	for _, col := range cols {
+		var text string
+		switch v := col.Value.(type) {
+		case nil:
+			values[col.Name] = nil
+			continue
+		case bool:
+			values[col.Name] = v
+			continue
+		case json.Number:
+			text = v.String()
+		case string:
+			text = v
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			text = string(b)
+		}
+		v, err := textValue(typeOID(col.Type), text)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", table, col.Name, err)
+		}
+		values[col.Name] = v
+	}
+	return values, nil
+}
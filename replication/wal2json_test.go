@@ -0,0 +1,51 @@
+package replication
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_wal2JSONDecoder(t *testing.T) {
+	r := require.New(t)
+
+	d := wal2JSONDecoder{}
+	events, commit, err := d.decode([]byte(`{"action":"B"}`))
+	r.NoError(err)
+	r.False(commit)
+	r.Empty(events)
+
+	events, _, err = d.decode([]byte(`{"action":"I","schema":"public","table":"users","columns":[
+		{"name":"id","type":"bigint","value":9007199254740993},
+		{"name":"name","type":"character varying(255)","value":"Mark"},
+		{"name":"admin","type":"boolean","value":true},
+		{"name":"score","type":"double precision","value":1.5},
+		{"name":"avatar","type":"bytea","value":"\\x4142"},
+		{"name":"deleted_at","type":"timestamp(6) without time zone","value":null}]}`))
+	r.NoError(err)
+	r.Equal([]Event{{Action: Insert, Schema: "public", Table: "users", Values: map[string]interface{}{
+		"id":         int64(9007199254740993),
+		"name":       "Mark",
+		"admin":      true,
+		"score":      1.5,
+		"avatar":     []byte("AB"),
+		"deleted_at": nil,
+	}}}, events)
+
+	events, _, err = d.decode([]byte(`{"action":"U","schema":"public","table":"users","columns":[{"name":"id","type":"bigint","value":2}],"identity":[{"name":"id","type":"bigint","value":1}]}`))
+	r.NoError(err)
+	r.Equal(map[string]interface{}{"id": int64(2)}, events[0].Values)
+	r.Equal(map[string]interface{}{"id": int64(1)}, events[0].OldValues)
+
+	events, _, err = d.decode([]byte(`{"action":"D","schema":"public","table":"users","identity":[{"name":"id","type":"integer","value":2}]}`))
+	r.NoError(err)
+	r.Equal(Delete, events[0].Action)
+	r.Equal(map[string]interface{}{"id": int64(2)}, events[0].Values)
+
+	_, commit, err = d.decode([]byte(`{"action":"C"}`))
+	r.NoError(err)
+	r.True(commit)
+
+	_, _, err = d.decode([]byte(`{"action":"I","table":"users","columns":[{"name":"id","type":"integer","value":"x"}]}`))
+	r.Error(err)
+}
@@ -0,0 +1,144 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ResultSetTooLargeError is returned by All when the query matches more
+// records than allowed by SetMaxResults.
+type ResultSetTooLargeError struct {
+	// Max is the maximum number of records All is allowed to load.
+	Max int
+}
+
+func (e *ResultSetTooLargeError) Error() string {
+	return fmt.Sprintf("query matches more than %d records, use Paginate or Limit to load them", e.Max)
+}
+
+// maximum number of records loaded by All, 0 means unlimited.
+var maxResults = 0
+
+// SetMaxResults caps the number of records All loads for queries without
+// an explicit Limit or pagination: if the query matches more records, All
+// returns a *ResultSetTooLargeError instead of loading them. The records
+// are fetched with "LIMIT max+1" so a runaway query never loads more than
+// one record above the cap; raw SQL queries are only checked once loaded.
+// Defaults to 0 "unlimited".
+//
+//	pop.SetMaxResults(10000)
+func SetMaxResults(max int) {
+	maxResults = max
+}
+
+// capResults returns whether the result set of the query must be checked
+// against maxResults.
+func (q *Query) capResults() bool {
+	return maxResults > 0 && q.limitResults == 0 && q.Paginator == nil
+}
+
+// checkResultsSize returns a *ResultSetTooLargeError if more than
+// maxResults records were loaded into models.
+func checkResultsSize(models interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(models))
+	if v.Kind() == reflect.Slice && v.Len() > maxResults {
+		return &ResultSetTooLargeError{Max: maxResults}
+	}
+	return nil
+}
+
+// DefaultLimitable is implemented by the models limiting the number of
+// records All loads for the queries without an explicit Limit or
+// pagination, to keep them from loading an unbounded result set by
+// accident. Unlimited lifts the limit.
+//
+//	func (AuditLog) DefaultLimit() int {
+//		return 100
+//	}
+type DefaultLimitable interface {
+	DefaultLimit() int
+}
+
+// MaxLimitable is implemented by the models capping the Limit of the
+// queries, and the records per page of their pagination, e.g. to bound the
+// per_page parameter of PaginateFromParams. Unlimited lifts the cap.
+//
+//	func (AuditLog) MaxLimit() int {
+//		return 1000
+//	}
+type MaxLimitable interface {
+	MaxLimit() int
+}
+
+// Unlimited lifts the limits of the model, see Query.Unlimited.
+func (c *Connection) Unlimited() *Query {
+	return Q(c).Unlimited()
+}
+
+// Unlimited lifts the DefaultLimit and the MaxLimit of the model for the
+// query. SetMaxResults still applies.
+//
+//	tx.Unlimited().All(&logs) // e.g. for an export
+func (q *Query) Unlimited() *Query {
+	q.unlimited = true
+	return q
+}
+
+// modelLimits returns the default and the maximum limits of the model, or
+// of the elements of the slice, 0 meaning none.
+func modelLimits(m *Model) (def int, max int) {
+	t := reflect.TypeOf(m.Value)
+	if t == nil {
+		return 0, 0
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	v := reflect.New(t).Interface()
+	if d, ok := v.(DefaultLimitable); ok {
+		def = d.DefaultLimit()
+	}
+	if l, ok := v.(MaxLimitable); ok {
+		max = l.MaxLimit()
+	}
+	if max > 0 && def > max {
+		def = max
+	}
+	return def, max
+}
+
+// applyModelLimits applies the DefaultLimit and the MaxLimit of the model
+// to the query. The per page of the paginators is capped in place, for
+// the totals of the pages to be computed with the actual one.
+func (q *Query) applyModelLimits(m *Model) {
+	if q.unlimited || q.RawSQL.Fragment != "" {
+		return
+	}
+	def, max := modelLimits(m)
+	if max > 0 {
+		if q.limitResults > max {
+			q.limitResults = max
+		}
+		if p := q.Paginator; p != nil && p.PerPage > max {
+			p.PerPage = max
+			p.Offset = (p.Page - 1) * p.PerPage
+		}
+		if p := q.CursorPaginator; p != nil {
+			if p.Limit > max {
+				p.Limit = max
+			}
+			// the record loaded above the limit tells whether there is a
+			// next page
+			q.limitResults = p.Limit + 1
+		}
+	}
+	if def > 0 && q.limitResults == 0 && q.Paginator == nil && q.CursorPaginator == nil {
+		q.limitResults = def
+	}
+}
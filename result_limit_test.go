@@ -0,0 +1,142 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MaxResults(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	defer SetMaxResults(0)
+
+	transaction(func(tx *Connection) {
+		for _, name := range []string{"A", "B", "C"} {
+			r.NoError(tx.Create(&User{Name: nulls.NewString(name)}))
+		}
+
+		SetMaxResults(3)
+		users := []User{}
+		r.NoError(tx.All(&users))
+		r.Len(users, 3)
+
+		SetMaxResults(2)
+		users = []User{}
+		err := tx.All(&users)
+		var tooLarge *ResultSetTooLargeError
+		r.True(errors.As(err, &tooLarge))
+		r.Equal(2, tooLarge.Max)
+		r.Len(users, 3)
+
+		users = []User{}
+		err = tx.RawQuery("SELECT * FROM users").All(&users)
+		r.True(errors.As(err, &tooLarge))
+
+		// explicit limits and pagination are not capped
+		users = []User{}
+		r.NoError(tx.Limit(3).All(&users))
+		r.Len(users, 3)
+
+		users = []User{}
+		r.NoError(tx.Paginate(1, 3).All(&users))
+		r.Len(users, 3)
+	})
+}
+
+type limitedLog struct {
+	ID   int    `db:"id"`
+	Text string `db:"text"`
+}
+
+func (limitedLog) TableName() string {
+	return "limited_logs"
+}
+
+func (limitedLog) DefaultLimit() int {
+	return 2
+}
+
+func (*limitedLog) MaxLimit() int {
+	return 3
+}
+
+func Test_ModelLimits(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE limited_logs (id INTEGER PRIMARY KEY, text TEXT)").Exec())
+	for i := 0; i < 5; i++ {
+		r.NoError(c.Create(&limitedLog{Text: "log"}))
+	}
+
+	logs := []limitedLog{}
+	r.NoError(c.All(&logs))
+	r.Len(logs, 2)
+
+	logs = []limitedLog{}
+	r.NoError(c.Limit(10).All(&logs))
+	r.Len(logs, 3)
+
+	ptrs := []*limitedLog{}
+	r.NoError(c.Limit(1).All(&ptrs))
+	r.Len(ptrs, 1)
+
+	q := c.Paginate(2, 10)
+	logs = []limitedLog{}
+	r.NoError(q.All(&logs))
+	r.Len(logs, 2)
+	r.Equal(3, q.Paginator.PerPage)
+	r.Equal(2, q.Paginator.TotalPages)
+
+	logs = []limitedLog{}
+	r.NoError(c.Unlimited().All(&logs))
+	r.Len(logs, 5)
+
+	logs = []limitedLog{}
+	r.NoError(c.RawQuery("SELECT * FROM limited_logs").All(&logs))
+	r.Len(logs, 5)
+
+	sql, _, err := Q(c).SelectSQL(&[]limitedLog{})
+	r.NoError(err)
+	r.Contains(sql, "LIMIT 2")
+}
+
+func Test_ModelLimits_Batches(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE limited_logs (id INTEGER PRIMARY KEY, text TEXT)").Exec())
+	for i := 0; i < 10; i++ {
+		r.NoError(c.Create(&limitedLog{Text: "log"}))
+	}
+
+	q := c.PaginateByCursor("", 5)
+	logs := []limitedLog{}
+	r.NoError(q.All(&logs))
+	r.Len(logs, 3)
+	r.Equal(3, q.CursorPaginator.Limit)
+	r.NotEmpty(q.CursorPaginator.NextCursor)
+
+	q = c.PaginateByCursor(q.CursorPaginator.NextCursor, 3)
+	logs = []limitedLog{}
+	r.NoError(q.All(&logs))
+	r.Len(logs, 3)
+	r.Equal(4, logs[0].ID)
+	r.NotEmpty(q.CursorPaginator.NextCursor)
+
+	visited := 0
+	r.NoError(c.EachBatch(&logs, 5, func() error {
+		r.LessOrEqual(len(logs), 3)
+		visited += len(logs)
+		return nil
+	}))
+	r.Equal(10, visited)
+}
@@ -0,0 +1,128 @@
+package pop
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/jmoiron/sqlx"
+)
+
+// returnedColumns returns the quoted columns of the model read back by the
+// statements with a RETURNING clause. The columns computed with a select
+// tag are left out, they are not columns of the table, as are the except
+// ones.
+func returnedColumns(c *Connection, model *Model, quoter quotable, except ...string) string {
+	alias := model.Alias()
+	left := map[string]bool{}
+	for _, name := range except {
+		left[name] = true
+	}
+	var xs []string
+	for _, col := range c.existingColumns(model.Columns()).Readable().Cols {
+		if col.SelectSQL != alias+"."+col.Name || left[col.Name] {
+			continue
+		}
+		xs = append(xs, quoter.Quote(col.Name))
+	}
+	sort.Strings(xs)
+	return strings.Join(xs, ", ")
+}
+
+// scanReturned scans the row returned by a statement into the model. It
+// reports false when the statement returned no row.
+func scanReturned(rows *sqlx.Rows, model *Model) (bool, error) {
+	defer rows.Close()
+	if !rows.Next() {
+		return false, rows.Err()
+	}
+	scan := rows.StructScan
+	if serializedFields(rows.Mapper, reflect.TypeOf(model.Value).Elem()) != nil {
+		scan = func(dest interface{}) error { return scanSerialized(rows, dest) }
+	}
+	if err := scan(model.Value); err != nil {
+		return false, fmt.Errorf("scan: %w", err)
+	}
+	if err := rows.Close(); err != nil {
+		return false, fmt.Errorf("close: %w", err)
+	}
+	return true, nil
+}
+
+// namedQueryInto runs the INSERT ... RETURNING statement of the model,
+// reading the inserted row back into it.
+func namedQueryInto(c *Connection, model *Model, query string) error {
+	rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
+	if err != nil {
+		return fmt.Errorf("named insert: %w", err)
+	}
+	ok, err := scanReturned(rows, model)
+	if err != nil {
+		return fmt.Errorf("named insert: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("named insert: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// returningUpdate updates the model with UPDATE ... RETURNING, reading back
+// the columns written and the read-only ones, e.g. the ones changed by
+// triggers. The writeable columns left out of the update, by the excluded
+// columns or SelectColumns and OmitColumns, are not read back: their values
+// in the model are kept.
+func returningUpdate(c *Connection, model *Model, cols columns.Columns, quoter quotable) error {
+	written := cols.Writeable()
+	var unwritten []string
+	for _, col := range model.Columns().Writeable().Cols {
+		if _, ok := written.Cols[col.Name]; !ok && col.Name != model.IDField() {
+			unwritten = append(unwritten, col.Name)
+		}
+	}
+	stmt := fmt.Sprintf("UPDATE %s AS %s SET %s WHERE %s RETURNING %s", quoter.Quote(model.TableName()), model.Alias(), written.QuotedUpdateString(quoter), model.whereNamedUpdate(cols), returnedColumns(c, model, quoter, unwritten...))
+	stmt, err := beforeNamedExec(Update, model, stmt)
+	if err != nil {
+		return err
+	}
+	txlog(logging.SQL, c, stmt, model.ID())
+	rows, err := c.Store.NamedQueryContext(model.ctx, stmt, model.Value)
+	if err != nil {
+		return err
+	}
+	ok, err := scanReturned(rows, model)
+	if err != nil {
+		return fmt.Errorf("named update: %w", err)
+	}
+	if !ok {
+		return checkStale(model, cols, driver.RowsAffected(0))
+	}
+	return nil
+}
+
+// versionAtLeast reports whether the version of the server, e.g.
+// "10.6.12-MariaDB-log" or "3.45.1", is at least the minimum one.
+func versionAtLeast(version string, min ...int) bool {
+	if i := strings.IndexFunc(version, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); i >= 0 {
+		version = version[:i]
+	}
+	parts := strings.Split(version, ".")
+	for i, m := range min {
+		if i >= len(parts) {
+			return false
+		}
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return false
+		}
+		if n != m {
+			return n > m
+		}
+	}
+	return true
+}
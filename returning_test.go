@@ -0,0 +1,23 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_versionAtLeast(t *testing.T) {
+	r := require.New(t)
+
+	r.True(versionAtLeast("10.6.12-MariaDB-1:10.6.12+maria~ubu2004", 10, 5))
+	r.True(versionAtLeast("10.5.0-MariaDB", 10, 5))
+	r.False(versionAtLeast("10.4.28-MariaDB-log", 10, 5))
+	r.True(versionAtLeast("11.0.2-MariaDB", 10, 5))
+	r.True(versionAtLeast("3.45.1", 3, 35))
+	r.False(versionAtLeast("3.31.1", 3, 35))
+	r.False(versionAtLeast("3", 3, 35))
+	r.False(versionAtLeast("", 3, 35))
+
+	r.True(supports(&mariaDB{mysql{commonDialect: commonDialect{version: "10.6.12-MariaDB"}}}, supportsReturning))
+	r.False(supports(&mariaDB{mysql{commonDialect: commonDialect{version: "10.4.28-MariaDB-log"}}}, supportsReturning))
+}
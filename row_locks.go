@@ -0,0 +1,84 @@
+package pop
+
+// LockForUpdate locks the rows read by the query for update, see
+// Query.LockForUpdate.
+func (c *Connection) LockForUpdate() *Query {
+	return Q(c).LockForUpdate()
+}
+
+// LockForShare locks the rows read by the query for share, see
+// Query.LockForShare.
+func (c *Connection) LockForShare() *Query {
+	return Q(c).LockForShare()
+}
+
+// LockForUpdate locks the rows read by the query with FOR UPDATE until the
+// end of the transaction of the connection, so that the other transactions
+// can neither change nor lock them. The finders fail with ErrUnsupported on
+// SQLite, DuckDB and Spanner. Oracle can not lock the rows of the queries
+// limiting their results.
+//
+//	err := tx.Where("id = ?", id).LockForUpdate().First(&account)
+func (q *Query) LockForUpdate() *Query {
+	return q.lockRows("FOR UPDATE")
+}
+
+// LockForShare locks the rows read by the query with FOR SHARE, or LOCK IN
+// SHARE MODE on MariaDB, until the end of the transaction of the
+// connection, so that the other transactions can read but not change them.
+// The finders fail with ErrUnsupported where LockForUpdate does, and on
+// Oracle.
+func (q *Query) LockForShare() *Query {
+	if q.Connection.Dialect.Name() == nameOracle {
+		q.err = errUnsupported(q.Connection.Dialect, "FOR SHARE")
+		return q
+	}
+	if q.Connection.Dialect.Name() == nameMariaDB {
+		return q.lockRows("LOCK IN SHARE MODE")
+	}
+	return q.lockRows("FOR SHARE")
+}
+
+// NoWait makes the query locking its rows fail rather than wait for the
+// ones locked by the other transactions.
+//
+//	err := tx.Where("id = ?", id).LockForUpdate().NoWait().First(&account)
+func (q *Query) NoWait() *Query {
+	q.rowLockWait = "NOWAIT"
+	return q
+}
+
+// SkipLocked makes the query locking its rows skip the ones locked by the
+// other transactions rather than wait for them, as job queues do. The
+// finders fail with ErrUnsupported on the databases not supporting it.
+//
+//	err := tx.Where("claimed_at IS NULL").Order("id").Limit(10).LockForUpdate().SkipLocked().All(&jobs)
+func (q *Query) SkipLocked() *Query {
+	if !supports(q.Connection.Dialect, supportsSkipLocked) {
+		q.err = errUnsupported(q.Connection.Dialect, "SKIP LOCKED")
+		return q
+	}
+	q.rowLockWait = "SKIP LOCKED"
+	return q
+}
+
+func (q *Query) lockRows(lock string) *Query {
+	if !supports(q.Connection.Dialect, supportsRowLocks) {
+		q.err = errUnsupported(q.Connection.Dialect, lock)
+		return q
+	}
+	q.rowLock = lock
+	return q
+}
+
+// buildLockClause appends the row-locking clause of the query, if any.
+func (sq *sqlBuilder) buildLockClause(sql string) string {
+	if sq.Query.rowLock == "" {
+		return sql
+	}
+	sql += " " + sq.Query.rowLock
+	if sq.Query.rowLockWait != "" {
+		sql += " " + sq.Query.rowLockWait
+	}
+	return sql
+}
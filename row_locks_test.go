@@ -0,0 +1,54 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LockForUpdate_SQL(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &postgresql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	q := Q(c).Where("claimed_at IS NULL").Limit(10).LockForUpdate().SkipLocked()
+	r.NoError(q.err)
+	sql := newSQLBuilder(*q, NewModel(&feedItem{}, nil)).buildSelectSQL()
+	r.True(strings.HasSuffix(sql, "WHERE claimed_at IS NULL LIMIT 10 FOR UPDATE SKIP LOCKED"), sql)
+
+	q = Q(c).NoWait().LockForShare()
+	sql = newSQLBuilder(*q, NewModel(&feedItem{}, nil)).buildSelectSQL()
+	r.True(strings.HasSuffix(sql, "FROM feed_items AS feed_items FOR SHARE NOWAIT"), sql)
+
+	// the wait options are ignored without lock
+	q = Q(c).NoWait()
+	sql = newSQLBuilder(*q, NewModel(&feedItem{}, nil)).buildSelectSQL()
+	r.True(strings.HasSuffix(sql, "FROM feed_items AS feed_items"), sql)
+
+	c = &Connection{Dialect: &mariaDB{mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}}
+	q = Q(c).LockForShare().SkipLocked()
+	sql = newSQLBuilder(*q, NewModel(&feedItem{}, nil)).buildSelectSQL()
+	r.True(strings.HasSuffix(sql, "FROM feed_items AS feed_items LOCK IN SHARE MODE SKIP LOCKED"), sql)
+}
+
+func Test_LockForUpdate_Unsupported(t *testing.T) {
+	r := require.New(t)
+
+	tidb := &Connection{Dialect: &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{
+		Options: map[string]string{"compatibility": compatibilityTiDB},
+	}}}}
+	r.NoError(Q(tidb).LockForUpdate().NoWait().err)
+	r.True(errors.Is(Q(tidb).LockForUpdate().SkipLocked().err, ErrUnsupported))
+
+	oracle := &Connection{Dialect: &oracle{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	r.NoError(Q(oracle).LockForUpdate().err)
+	r.True(errors.Is(Q(oracle).LockForShare().err, ErrUnsupported))
+
+	c := openSQLite(t, nil)
+	err := c.LockForUpdate().First(&feedItem{})
+	r.True(errors.Is(err, ErrUnsupported), err)
+}
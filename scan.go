@@ -0,0 +1,213 @@
+package pop
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+var _scannerInterface = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// scanPlanKey identifies the scan plan of a struct type for a given list of
+// columns and mapper.
+type scanPlanKey struct {
+	m       *reflectx.Mapper
+	t       reflect.Type
+	columns string
+}
+
+// scanPlans caches the field index paths of the columns, per struct type
+// and list of columns, so they are only computed once.
+var scanPlans sync.Map
+
+// scanValues pools the buffers holding the field pointers of a row.
+var scanValues = sync.Pool{
+	New: func() interface{} {
+		return &[]interface{}{}
+	},
+}
+
+// selectMany runs the query and scans all the rows into models, a pointer
+// to a slice of structs. Unlike sqlx's Select, rows are scanned directly
+// into the slice elements using cached field index paths, which saves an
+// allocation and a copy per row. Any other kind of destination falls back
+// to sqlx.
+func selectMany(ctx context.Context, c *Connection, models interface{}, query string, args ...interface{}) error {
+	slice := reflect.ValueOf(models)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return c.Store.SelectContext(ctx, models, query, args...)
+	}
+	slice = slice.Elem()
+	t := slice.Type().Elem()
+	if t.Kind() != reflect.Struct || reflect.PtrTo(t).Implements(_scannerInterface) {
+		return c.Store.SelectContext(ctx, models, query, args...)
+	}
+
+	rows, err := c.Store.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	traversals := scanPlan(rows.Mapper, t, cols)
+	serialized := serializedColumns(rows.Mapper, t, cols, traversals)
+	if deets := c.Dialect.Details(); !deets.Unsafe && !deets.TolerateSchemaDrift {
+		for i, traversal := range traversals {
+			if len(traversal) == 0 {
+				return fmt.Errorf("missing destination name %s in %T", cols[i], models)
+			}
+		}
+	}
+
+	vp := scanValues.Get().(*[]interface{})
+	values := (*vp)[:0]
+	defer func() {
+		// the pointers would keep the rows of the caller alive
+		for i := range values {
+			values[i] = nil
+		}
+		*vp = values[:0]
+		scanValues.Put(vp)
+	}()
+	var ignored interface{}
+
+	// the rows replace the elements of the slice, in a new array rather
+	// than in the one of the slice, which other slices may share.
+	if !slice.IsNil() {
+		slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+	}
+	for rows.Next() {
+		n := slice.Len()
+		if n == slice.Cap() {
+			grown := reflect.MakeSlice(slice.Type(), n, 2*n+1)
+			reflect.Copy(grown, slice)
+			slice.Set(grown)
+		}
+		slice.SetLen(n + 1)
+		elem := slice.Index(n)
+		elem.Set(reflect.Zero(t))
+
+		values = scanTargets(values[:0], elem, traversals, serialized, &ignored)
+		if err := rows.Scan(values...); err != nil {
+			slice.SetLen(n)
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// scanPlan returns the field index path of each column in the struct type
+// t, an empty path meaning there is no field for the column.
+func scanPlan(m *reflectx.Mapper, t reflect.Type, cols []string) [][]int {
+	key := scanPlanKey{m: m, t: t, columns: strings.Join(cols, ",")}
+	if traversals, ok := scanPlans.Load(key); ok {
+		return traversals.([][]int)
+	}
+	traversals := m.TraversalsByName(t, cols)
+	scanPlans.Store(key, traversals)
+	return traversals
+}
+
+// serializedColumns returns the serialized field of each column in the
+// struct type t, or nil if none of the columns is serialized.
+func serializedColumns(m *reflectx.Mapper, t reflect.Type, cols []string, traversals [][]int) []*reflectx.FieldInfo {
+	fields := serializedFields(m, t)
+	if fields == nil {
+		return nil
+	}
+	var serialized []*reflectx.FieldInfo
+	for i, col := range cols {
+		fi, ok := fields[col]
+		if !ok || len(traversals[i]) == 0 {
+			continue
+		}
+		if serialized == nil {
+			serialized = make([]*reflectx.FieldInfo, len(cols))
+		}
+		serialized[i] = fi
+	}
+	return serialized
+}
+
+// scanTargets appends to values the destinations of the columns in the
+// struct elem: the fields at the traversals, the serialized ones wrapped
+// to be deserialized, and ignored for the columns without a field.
+func scanTargets(values []interface{}, elem reflect.Value, traversals [][]int, serialized []*reflectx.FieldInfo, ignored *interface{}) []interface{} {
+	for i, traversal := range traversals {
+		if len(traversal) == 0 {
+			values = append(values, ignored)
+			continue
+		}
+		f := reflectx.FieldByIndexes(elem, traversal)
+		if serialized != nil && serialized[i] != nil {
+			values = append(values, newSerializedValue(f, serialized[i]))
+			continue
+		}
+		values = append(values, f.Addr().Interface())
+	}
+	return values
+}
+
+// scanSerialized scans the current row into dest, a pointer to a struct
+// with serialized fields.
+func scanSerialized(rows *sqlx.Rows, dest interface{}) error {
+	elem := reflect.ValueOf(dest).Elem()
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	traversals := scanPlan(rows.Mapper, elem.Type(), cols)
+	var ignored interface{}
+	values := scanTargets(nil, elem, traversals, serializedColumns(rows.Mapper, elem.Type(), cols, traversals), &ignored)
+	return rows.Scan(values...)
+}
+
+// hasSerializedFields reports whether dest is a pointer to a struct with
+// serialized fields, for the mapper of the store of the connection.
+func hasSerializedFields(c *Connection, dest interface{}) bool {
+	t := reflect.TypeOf(dest)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	var m *reflectx.Mapper
+	switch s := unwrapStore(c.Store).(type) {
+	case *dB:
+		m = s.Mapper
+	case *Tx:
+		m = s.Mapper
+	}
+	return m != nil && serializedFields(m, t.Elem()) != nil
+}
+
+// selectOne runs the query and scans its first row into dest. The structs
+// with serialized fields are scanned by pop, others by sqlx.
+func selectOne(ctx context.Context, c *Connection, dest interface{}, query string, args ...interface{}) error {
+	if !hasSerializedFields(c, dest) {
+		return c.Store.GetContext(ctx, dest, query, args...)
+	}
+	rows, err := c.Store.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := scanSerialized(rows, dest); err != nil {
+		return err
+	}
+	return rows.Close()
+}
@@ -0,0 +1,52 @@
+package pop
+
+import (
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SelectMany_Scan(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+
+	transaction(func(tx *Connection) {
+		r.NoError(tx.Create(&User{Name: nulls.NewString("Mark"), Bio: nulls.NewString("Bio")}))
+		r.NoError(tx.Create(&User{Name: nulls.NewString("Jane")}))
+
+		users := []User{}
+		r.NoError(tx.Order("id asc").All(&users))
+		r.Len(users, 2)
+		r.Equal("Mark", users[0].Name.String)
+		r.Equal("Mark", users[0].FullName.String)
+		r.Equal("Bio", users[0].Bio.String)
+		r.False(users[1].Bio.Valid)
+
+		// reused capacity must not leak values of the previous records
+		users = users[:0]
+		r.NoError(tx.Order("id desc").Select("id", "name").All(&users))
+		r.Len(users, 2)
+		r.Equal("Jane", users[0].Name.String)
+		r.False(users[0].Bio.Valid)
+		r.False(users[1].Bio.Valid)
+
+		// the slices sharing the array of the destination are left untouched
+		all := []User{}
+		r.NoError(tx.Order("id asc").All(&all))
+		page := all[:0]
+		r.NoError(tx.Order("id desc").All(&page))
+		r.Equal("Jane", page[0].Name.String)
+		r.Equal("Mark", all[0].Name.String)
+
+		type nameOnly struct {
+			Name string `db:"name"`
+		}
+		names := []nameOnly{}
+		err := tx.RawQuery("SELECT id, name FROM users").All(&names)
+		r.Error(err)
+		r.Contains(err.Error(), "missing destination name id")
+	})
+}
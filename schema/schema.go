@@ -0,0 +1,95 @@
+// Package schema reads the tables, columns, indexes and foreign keys of the
+// database of a connection from its catalog, with the queries of its
+// dialect, see pop.Connection.Schema.
+//
+//	s := schema.Of(c)
+//	tables, err := s.Tables()
+//	cols, err := s.Columns("users")
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/WilliamNHarvey/pop/v6"
+)
+
+// ErrTableNotFound is returned for the tables missing from the database.
+var ErrTableNotFound = errors.New("table not found")
+
+// Table is a table of the database, with its columns, indexes and foreign
+// keys.
+type Table = pop.SchemaTable
+
+// Column is a column of a table, in the order of the table.
+type Column = pop.SchemaColumn
+
+// Index is an index of a table, but its primary key.
+type Index = pop.SchemaIndex
+
+// ForeignKey is a foreign key of a table.
+type ForeignKey = pop.SchemaForeignKey
+
+// Inspector reads the schema of the database of a connection. The schema
+// is read once, on the first call, and kept: use a new Inspector to see
+// the later changes.
+type Inspector struct {
+	c *pop.Connection
+
+	once   sync.Once
+	tables []Table
+	err    error
+}
+
+// Of returns the Inspector of the schema of the database of the
+// connection. It fails with pop.ErrUnsupported on the dialects not reading
+// their catalog.
+func Of(c *pop.Connection) *Inspector {
+	return &Inspector{c: c}
+}
+
+func (s *Inspector) load() ([]Table, error) {
+	s.once.Do(func() {
+		s.tables, s.err = s.c.Schema()
+	})
+	return s.tables, s.err
+}
+
+// Tables returns the tables of the database, sorted by name, but the
+// migration table.
+func (s *Inspector) Tables() ([]Table, error) {
+	return s.load()
+}
+
+// Table returns the table of the given name.
+func (s *Inspector) Table(name string) (Table, error) {
+	tables, err := s.load()
+	if err != nil {
+		return Table{}, err
+	}
+	for _, t := range tables {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+	return Table{}, fmt.Errorf("%w: %s", ErrTableNotFound, name)
+}
+
+// Columns returns the columns of the table, in the order of the table.
+func (s *Inspector) Columns(table string) ([]Column, error) {
+	t, err := s.Table(table)
+	return t.Columns, err
+}
+
+// Indexes returns the indexes of the table, but its primary key.
+func (s *Inspector) Indexes(table string) ([]Index, error) {
+	t, err := s.Table(table)
+	return t.Indexes, err
+}
+
+// ForeignKeys returns the foreign keys of the table.
+func (s *Inspector) ForeignKeys(table string) ([]ForeignKey, error) {
+	t, err := s.Table(table)
+	return t.ForeignKeys, err
+}
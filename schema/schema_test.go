@@ -0,0 +1,57 @@
+//go:build sqlite
+// +build sqlite
+
+package schema
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Inspector(t *testing.T) {
+	r := require.New(t)
+
+	c, err := pop.NewConnection(&pop.ConnectionDetails{
+		URL: "sqlite://" + filepath.Join(t.TempDir(), "schema.db") + "?_fk=true",
+	})
+	r.NoError(err)
+	r.NoError(c.Open())
+	defer c.Close()
+	for _, stmt := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL)",
+		"CREATE UNIQUE INDEX users_email_idx ON users (email)",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id))",
+	} {
+		r.NoError(c.RawQuery(stmt).Exec())
+	}
+
+	s := Of(c)
+	tables, err := s.Tables()
+	r.NoError(err)
+	r.Len(tables, 2)
+	r.Equal("posts", tables[0].Name)
+
+	cols, err := s.Columns("users")
+	r.NoError(err)
+	r.Equal([]Column{
+		{Name: "id", Type: "INTEGER", PrimaryKey: true},
+		{Name: "email", Type: "TEXT"},
+	}, cols)
+
+	indexes, err := s.Indexes("users")
+	r.NoError(err)
+	r.Equal([]Index{{Name: "users_email_idx", Columns: []string{"email"}, Unique: true}}, indexes)
+
+	fks, err := s.ForeignKeys("posts")
+	r.NoError(err)
+	r.Len(fks, 1)
+	r.Equal("users", fks[0].RefTable)
+	r.Equal([]string{"user_id"}, fks[0].Columns)
+
+	_, err = s.Columns("comments")
+	r.True(errors.Is(err, ErrTableNotFound))
+}
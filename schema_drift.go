@@ -0,0 +1,32 @@
+package pop
+
+import (
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// existingColumns returns the columns of cols which exist in their table,
+// when the connection tolerates schema drift, see
+// ConnectionDetails.TolerateSchemaDrift.
+func (c *Connection) existingColumns(cols columns.Columns) columns.Columns {
+	if !c.Dialect.Details().TolerateSchemaDrift {
+		return cols
+	}
+	existing, err := c.tableColumns(cols.TableName)
+	if err != nil {
+		log(logging.Warn, "could not read the columns of %s: %s", cols.TableName, err)
+		return cols
+	}
+
+	rc := columns.NewColumnsWithAlias(cols.TableName, cols.TableAlias, cols.IDField)
+	for name, col := range cols.Cols {
+		if _, ok := existing[strings.ToLower(name)]; !ok {
+			log(logging.Debug, "skipping %s, the column does not exist in %s", name, cols.TableName)
+			continue
+		}
+		rc.Cols[name] = col
+	}
+	return rc
+}
@@ -0,0 +1,49 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type driftWidget struct {
+	ID    int    `db:"id"`
+	Name  string `db:"name"`
+	Color string `db:"color"`
+}
+
+func (driftWidget) TableName() string {
+	return "drift_widgets"
+}
+
+func Test_TolerateSchemaDrift(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, &ConnectionDetails{TolerateSchemaDrift: true})
+
+	// the color column is not created yet, the size column is dropped.
+	r.NoError(c.RawQuery("CREATE TABLE drift_widgets (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT, size INTEGER)").Exec())
+
+	w := &driftWidget{Name: "bolt", Color: "red"}
+	r.NoError(c.Create(w))
+	r.NotZero(w.ID)
+
+	w.Name = "nut"
+	r.NoError(c.Update(w))
+	r.NoError(c.UpdateColumns(w, "name", "color"))
+
+	found := &driftWidget{}
+	r.NoError(c.RawQuery("SELECT * FROM drift_widgets WHERE id = ?", w.ID).First(found))
+	r.Equal("nut", found.Name)
+
+	widgets := []driftWidget{}
+	r.NoError(c.RawQuery("SELECT * FROM drift_widgets").All(&widgets))
+	r.Len(widgets, 1)
+
+	c.Dialect.Details().TolerateSchemaDrift = false
+	r.Error(c.Create(&driftWidget{Name: "bolt"}))
+	r.Error(c.RawQuery("SELECT * FROM drift_widgets").All(&widgets))
+}
@@ -0,0 +1,145 @@
+package pop
+
+import (
+	"sort"
+	"strings"
+)
+
+// SchemaTable is a table of the database, see Connection.Schema.
+type SchemaTable struct {
+	Name        string
+	Columns     []SchemaColumn
+	ForeignKeys []SchemaForeignKey
+	// Indexes are the indexes of the table but its primary key, see
+	// SchemaColumn.PrimaryKey.
+	Indexes []SchemaIndex
+}
+
+// SchemaColumn is a column of a table, in the order of the table.
+type SchemaColumn struct {
+	Name     string
+	Type     string
+	Nullable bool
+	// Default is the SQL expression of the default, nil for none.
+	Default    *string
+	PrimaryKey bool
+}
+
+// SchemaForeignKey is a foreign key of a table. SQLite does not name them.
+type SchemaForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+// SchemaIndex is an index of a table.
+type SchemaIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
+
+// Schema returns the tables of the database, sorted by name, but the
+// migration table. The schema package reads them table by table.
+func (c *Connection) Schema() ([]SchemaTable, error) {
+	d, ok := c.Dialect.(schemaIntrospectable)
+	if !ok {
+		return nil, errUnsupported(c.Dialect, "schema introspection")
+	}
+	tables, err := d.Schema(c.Context(), c.Store)
+	if err != nil {
+		return nil, err
+	}
+	schema := make([]SchemaTable, 0, len(tables))
+	for _, t := range tables {
+		if t.Name != c.MigrationTableName() {
+			schema = append(schema, t)
+		}
+	}
+	sort.Slice(schema, func(i, j int) bool {
+		return schema[i].Name < schema[j].Name
+	})
+	return schema, nil
+}
+
+// schemaBuilder gathers the tables of the schema from the rows of the
+// catalog queries of the dialects, in the order of the rows.
+type schemaBuilder struct {
+	tables []*SchemaTable
+	byName map[string]*SchemaTable
+	// fkIDs are the ids of the last foreign keys of the tables.
+	fkIDs map[string]string
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{byName: map[string]*SchemaTable{}, fkIDs: map[string]string{}}
+}
+
+func (b *schemaBuilder) table(name string) *SchemaTable {
+	t, ok := b.byName[name]
+	if !ok {
+		t = &SchemaTable{Name: name}
+		b.tables = append(b.tables, t)
+		b.byName[name] = t
+	}
+	return t
+}
+
+func (b *schemaBuilder) addColumn(table string, col SchemaColumn) {
+	t := b.table(table)
+	t.Columns = append(t.Columns, col)
+}
+
+// setPrimaryKey marks the columns of the table as its primary key.
+func (b *schemaBuilder) setPrimaryKey(table string, columns []string) {
+	t := b.table(table)
+	for _, name := range columns {
+		for i := range t.Columns {
+			if t.Columns[i].Name == name {
+				t.Columns[i].PrimaryKey = true
+			}
+		}
+	}
+}
+
+// addForeignKeyColumn adds the column to the foreign key of the table, the
+// foreign keys being the ones of the consecutive rows of the same id.
+func (b *schemaBuilder) addForeignKeyColumn(table, id, name, refTable, column, refColumn string) {
+	t := b.table(table)
+	if len(t.ForeignKeys) == 0 || b.fkIDs[table] != id {
+		t.ForeignKeys = append(t.ForeignKeys, SchemaForeignKey{Name: name, RefTable: refTable})
+		b.fkIDs[table] = id
+	}
+	fk := &t.ForeignKeys[len(t.ForeignKeys)-1]
+	fk.Columns = append(fk.Columns, column)
+	fk.RefColumns = append(fk.RefColumns, refColumn)
+}
+
+// addIndexColumn adds the column to the index of the table, the indexes
+// being the ones of the consecutive rows of the same name.
+func (b *schemaBuilder) addIndexColumn(table, name string, unique bool, column string) {
+	t := b.table(table)
+	if n := len(t.Indexes); n == 0 || t.Indexes[n-1].Name != name {
+		t.Indexes = append(t.Indexes, SchemaIndex{Name: name, Unique: unique})
+	}
+	idx := &t.Indexes[len(t.Indexes)-1]
+	idx.Columns = append(idx.Columns, column)
+}
+
+func (b *schemaBuilder) schema() []SchemaTable {
+	tables := make([]SchemaTable, len(b.tables))
+	for i, t := range b.tables {
+		tables[i] = *t
+	}
+	return tables
+}
+
+// splitColumns splits the comma separated list of columns of the catalog
+// queries.
+func splitColumns(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
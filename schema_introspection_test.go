@@ -0,0 +1,56 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Schema(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	for _, stmt := range []string{
+		"CREATE TABLE schema_migration (version TEXT NOT NULL)",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL, name TEXT DEFAULT 'anonymous')",
+		"CREATE UNIQUE INDEX users_email_idx ON users (email)",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id), title VARCHAR(255))",
+		"CREATE INDEX posts_user_id_title_idx ON posts (user_id, title)",
+	} {
+		r.NoError(c.RawQuery(stmt).Exec())
+	}
+
+	anonymous := "'anonymous'"
+	schema, err := c.Schema()
+	r.NoError(err)
+	r.Equal([]SchemaTable{
+		{
+			Name: "posts",
+			Columns: []SchemaColumn{
+				{Name: "id", Type: "INTEGER", PrimaryKey: true},
+				{Name: "user_id", Type: "INTEGER"},
+				{Name: "title", Type: "VARCHAR(255)", Nullable: true},
+			},
+			ForeignKeys: []SchemaForeignKey{
+				{Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+			},
+			Indexes: []SchemaIndex{
+				{Name: "posts_user_id_title_idx", Columns: []string{"user_id", "title"}},
+			},
+		},
+		{
+			Name: "users",
+			Columns: []SchemaColumn{
+				{Name: "id", Type: "INTEGER", PrimaryKey: true},
+				{Name: "email", Type: "TEXT"},
+				{Name: "name", Type: "TEXT", Nullable: true, Default: &anonymous},
+			},
+			Indexes: []SchemaIndex{
+				{Name: "users_email_idx", Columns: []string{"email"}, Unique: true},
+			},
+		},
+	}, schema)
+}
@@ -1,9 +1,18 @@
 package pop
 
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
 // ScopeFunc applies a custom operation on a given `Query`
 type ScopeFunc func(q *Query) *Query
 
-// Scope the query by using a `ScopeFunc`
+// Scope is a reusable filter of the queries, see Query.Scope.
+type Scope = ScopeFunc
+
+// Scope the query by using `ScopeFunc`s, applied in order
 //
 //	func ByName(name string) ScopeFunc {
 //		return func(q *Query) *Query {
@@ -15,12 +24,15 @@ type ScopeFunc func(q *Query) *Query
 //		return q.Where("deleted_at is null")
 //	}
 //
-//	c.Scope(ByName("mark)).Scope(WithDeleted).First(&User{})
-func (q *Query) Scope(sf ScopeFunc) *Query {
-	return sf(q)
+//	c.Scope(ByName("mark"), WithDeleted).First(&User{})
+func (q *Query) Scope(sfs ...ScopeFunc) *Query {
+	for _, sf := range sfs {
+		q = sf(q)
+	}
+	return q
 }
 
-// Scope the query by using a `ScopeFunc`
+// Scope the query by using `ScopeFunc`s, applied in order
 //
 //	func ByName(name string) ScopeFunc {
 //		return func(q *Query) *Query {
@@ -32,7 +44,53 @@ func (q *Query) Scope(sf ScopeFunc) *Query {
 //		return q.Where("deleted_at is null")
 //	}
 //
-//	c.Scope(ByName("mark)).Scope(WithDeleted).First(&User{})
-func (c *Connection) Scope(sf ScopeFunc) *Query {
-	return Q(c).Scope(sf)
+//	c.Scope(ByName("mark"), WithDeleted).First(&User{})
+func (c *Connection) Scope(sfs ...ScopeFunc) *Query {
+	return Q(c).Scope(sfs...)
+}
+
+var (
+	namedScopesMu sync.RWMutex
+	// namedScopes are the scopes registered by name, by table.
+	namedScopes = map[string]map[string]ScopeFunc{}
+)
+
+// RegisterScope registers the scope under the name for the model, applied
+// by NamedScopes. Registering it again replaces it.
+//
+//	pop.RegisterScope(&User{}, "active", func(q *pop.Query) *pop.Query {
+//		return q.Where("active = ?", true)
+//	})
+func RegisterScope(model interface{}, name string, sf ScopeFunc) {
+	table := NewModel(model, context.Background()).TableName()
+	namedScopesMu.Lock()
+	defer namedScopesMu.Unlock()
+	if namedScopes[table] == nil {
+		namedScopes[table] = map[string]ScopeFunc{}
+	}
+	namedScopes[table][name] = sf
+}
+
+// NamedScopes returns the scope applying the scopes registered under the
+// names for the model, in order. The query fails if one of them is not
+// registered.
+//
+//	c.Scope(pop.NamedScopes(&User{}, "active", "recent")).All(&users)
+func NamedScopes(model interface{}, names ...string) ScopeFunc {
+	table := NewModel(model, context.Background()).TableName()
+	return func(q *Query) *Query {
+		sfs := make([]ScopeFunc, len(names))
+		namedScopesMu.RLock()
+		for i, name := range names {
+			sfs[i] = namedScopes[table][name]
+		}
+		namedScopesMu.RUnlock()
+		for i, sf := range sfs {
+			if sf == nil {
+				q.err = fmt.Errorf("no scope %q registered for %s", names[i], table)
+				return q
+			}
+		}
+		return q.Scope(sfs...)
+	}
 }
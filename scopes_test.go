@@ -27,3 +27,29 @@ func Test_Scopes(t *testing.T) {
 	s, _ = q.ToSQL(m)
 	r.Equal(ts(oql+" WHERE id = ?"), s)
 }
+
+func Test_NamedScopes(t *testing.T) {
+	r := require.New(t)
+
+	RegisterScope(&Enemy{}, "active", func(q *Query) *Query {
+		return q.Where("active = ?", true)
+	})
+	RegisterScope(&Enemy{}, "recent", func(q *Query) *Query {
+		return q.Where("created_at > ?", "2024-01-01")
+	})
+
+	d, err := newPostgreSQL(&ConnectionDetails{})
+	r.NoError(err)
+	c := &Connection{Dialect: d}
+	m := NewModel(new(Enemy), context.Background())
+	q := c.Scope(NamedScopes(&[]Enemy{}, "active", "recent"), func(q *Query) *Query {
+		return q.Order("id")
+	})
+	r.NoError(q.err)
+	s, args := q.ToSQL(m)
+	r.Equal("SELECT enemies.A FROM enemies AS enemies WHERE active = $1 AND created_at > $2 ORDER BY id", s)
+	r.Equal([]interface{}{true, "2024-01-01"}, args)
+
+	q = c.Scope(NamedScopes(&Enemy{}, "active", "archived"))
+	r.EqualError(q.err, `no scope "archived" registered for enemies`)
+}
@@ -0,0 +1,147 @@
+package seed
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"gopkg.in/yaml.v2"
+)
+
+// file is a YAML or JSON seed file.
+type file struct {
+	DependsOn []string    `yaml:"depends_on" json:"depends_on"`
+	Tables    []tableRows `yaml:"tables" json:"tables"`
+}
+
+// tableRows are the rows a seed file inserts in a table.
+type tableRows struct {
+	Table string                   `yaml:"table" json:"table"`
+	Rows  []map[string]interface{} `yaml:"rows" json:"rows"`
+}
+
+// Load returns the seeds of the .yml, .yaml and .json files of the
+// directory, named after their file without its extension. A seed file
+// lists the rows it inserts by table, in order:
+//
+//	depends_on:
+//	  - users
+//	tables:
+//	  - table: posts
+//	    rows:
+//	      - {id: 1, user_id: 1, title: Hello}
+//	      - {id: 2, user_id: 1, title: World}
+//
+// The rows are inserted as they are: the columns without a default, such
+// as the timestamps of the models, must be given.
+func Load(dir string) ([]Seed, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var seeds []Seed
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(e.Name())
+		switch ext {
+		case ".yml", ".yaml", ".json":
+		default:
+			continue
+		}
+		s, err := loadFile(filepath.Join(dir, e.Name()), strings.TrimSuffix(e.Name(), ext))
+		if err != nil {
+			return nil, err
+		}
+		seeds = append(seeds, s)
+	}
+	return seeds, nil
+}
+
+// loadFile returns the seed of the file.
+func loadFile(path, name string) (Seed, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Seed{}, err
+	}
+	var f file
+	if filepath.Ext(path) == ".json" {
+		d := json.NewDecoder(bytes.NewReader(b))
+		d.UseNumber()
+		err = d.Decode(&f)
+	} else {
+		err = yaml.Unmarshal(b, &f)
+	}
+	if err != nil {
+		return Seed{}, fmt.Errorf("could not parse seed %s: %w", path, err)
+	}
+	for _, t := range f.Tables {
+		if t.Table == "" {
+			return Seed{}, fmt.Errorf("seed %s: rows without a table", path)
+		}
+	}
+	return Seed{
+		Name:      name,
+		DependsOn: f.DependsOn,
+		Run: func(tx *pop.Connection) error {
+			for _, t := range f.Tables {
+				for _, row := range t.Rows {
+					if err := insertRow(tx, t.Table, row); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// insertRow inserts the row, its columns in the order of their names.
+func insertRow(tx *pop.Connection, table string, row map[string]interface{}) error {
+	if len(row) == 0 {
+		return fmt.Errorf("empty row of %s", table)
+	}
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	quoted := make([]string, len(cols))
+	args := make([]interface{}, len(cols))
+	for i, col := range cols {
+		quoted[i] = tx.Dialect.Quote(col)
+		args[i] = rowValue(row[col])
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		tx.Dialect.Quote(table),
+		strings.Join(quoted, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", "),
+	)
+	if err := tx.RawQuery(query, args...).Exec(); err != nil {
+		return fmt.Errorf("could not insert into %s: %w", table, err)
+	}
+	return nil
+}
+
+// rowValue returns the value of a column decoded from a seed file as
+// bound by the drivers: the JSON numbers as integers, if they are, or as
+// floats.
+func rowValue(v interface{}) interface{} {
+	n, ok := v.(json.Number)
+	if !ok {
+		return v
+	}
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
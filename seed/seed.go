@@ -0,0 +1,265 @@
+// Package seed loads the seeds of a database, the rows it needs to be of
+// any use such as the roles, the countries or the demo accounts, and
+// applies them once, in the order of their dependencies.
+//
+// The seeds are Go functions registered with Register, usually from the
+// init of their file, and YAML or JSON files of rows, see Load. The names
+// of the seeds applied are stored in a table, like the versions of the
+// migrations, so that running the seeds again only applies the new ones.
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/gobuffalo/fizz"
+)
+
+// DefaultTableName is the name of the table of the seeds applied.
+const DefaultTableName = "schema_seed"
+
+// Seed is a named set of rows.
+type Seed struct {
+	// Name identifies the seed in the table of the seeds applied and in
+	// the dependencies of the other seeds.
+	Name string
+	// DependsOn are the names of the seeds applied before this one.
+	DependsOn []string
+	// Run inserts the rows of the seed in the transaction.
+	Run func(tx *pop.Connection) error
+}
+
+var (
+	registeredLock sync.Mutex
+	registered     []Seed
+)
+
+// Register adds a Go seed to the ones run by NewSeeder and
+// NewFileSeeder. It is usually called from the init of the file of the
+// seed:
+//
+//	func init() {
+//		seed.Register(seed.Seed{
+//			Name:      "admins",
+//			DependsOn: []string{"roles"},
+//			Run: func(tx *pop.Connection) error {
+//				return tx.Create(&models.User{Email: "admin@example.com", Role: "admin"})
+//			},
+//		})
+//	}
+func Register(s Seed) {
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+	registered = append(registered, s)
+}
+
+// Registered returns the Go seeds registered.
+func Registered() []Seed {
+	registeredLock.Lock()
+	defer registeredLock.Unlock()
+	return append([]Seed{}, registered...)
+}
+
+// Seeder applies seeds to the database of its connection.
+type Seeder struct {
+	Connection *pop.Connection
+	// TableName is the table of the seeds applied. Defaults to
+	// DefaultTableName.
+	TableName string
+	Seeds     []Seed
+}
+
+// NewSeeder returns a Seeder of the Go seeds registered and of the given
+// ones.
+func NewSeeder(c *pop.Connection, seeds ...Seed) Seeder {
+	return Seeder{
+		Connection: c,
+		TableName:  DefaultTableName,
+		Seeds:      append(Registered(), seeds...),
+	}
+}
+
+// NewFileSeeder returns a Seeder of the Go seeds registered and of the
+// seed files of the directory, see Load.
+func NewFileSeeder(dir string, c *pop.Connection) (Seeder, error) {
+	seeds, err := Load(dir)
+	if err != nil {
+		return Seeder{}, err
+	}
+	return NewSeeder(c, seeds...), nil
+}
+
+func (s Seeder) tableName() string {
+	if s.TableName == "" {
+		return DefaultTableName
+	}
+	return s.TableName
+}
+
+// Run applies the seeds not applied yet, in the order of their
+// dependencies, then of their names, in a single transaction: either all
+// of them are applied or none is. It returns the names of the seeds
+// applied.
+func (s Seeder) Run() ([]string, error) {
+	seeds, err := sortSeeds(s.Seeds)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.CreateSchemaSeeds(); err != nil {
+		return nil, err
+	}
+
+	var ran []string
+	table := s.Connection.Dialect.Quote(s.tableName())
+	err = s.Connection.Transaction(func(tx *pop.Connection) error {
+		applied, err := s.applied(tx)
+		if err != nil {
+			return err
+		}
+		for _, seed := range seeds {
+			if applied[seed.Name] {
+				continue
+			}
+			if err := seed.Run(tx); err != nil {
+				return fmt.Errorf("seed %s: %w", seed.Name, err)
+			}
+			err := tx.RawQuery(fmt.Sprintf("INSERT INTO %s (name, applied_at) VALUES (?, ?)", table), seed.Name, time.Now().UTC()).Exec()
+			if err != nil {
+				return fmt.Errorf("could not record seed %s: %w", seed.Name, err)
+			}
+			ran = append(ran, seed.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ran, nil
+}
+
+// Applied returns the names of the seeds applied, sorted.
+func (s Seeder) Applied() ([]string, error) {
+	if err := s.CreateSchemaSeeds(); err != nil {
+		return nil, err
+	}
+	applied, err := s.applied(s.Connection)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(applied))
+	for name := range applied {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (s Seeder) applied(c *pop.Connection) (map[string]bool, error) {
+	var names []string
+	query := fmt.Sprintf("SELECT name FROM %s", c.Dialect.Quote(s.tableName()))
+	if err := c.Store.SelectContext(c.Context(), &names, query); err != nil {
+		return nil, fmt.Errorf("could not read the seeds applied: %w", err)
+	}
+	applied := make(map[string]bool, len(names))
+	for _, name := range names {
+		applied[name] = true
+	}
+	return applied, nil
+}
+
+// CreateSchemaSeeds sets up the table of the seeds applied. This is an
+// idempotent operation.
+func (s Seeder) CreateSchemaSeeds() error {
+	c := s.Connection
+	if err := c.Open(); err != nil {
+		return fmt.Errorf("could not open connection: %w", err)
+	}
+	name := s.tableName()
+	if _, err := c.Store.Exec(fmt.Sprintf("select * from %s", c.Dialect.Quote(name))); err == nil {
+		return nil
+	}
+	table := fizz.Table{
+		Name: name,
+		Columns: []fizz.Column{
+			{Name: "name", ColType: "string", Options: map[string]interface{}{"size": 255}},
+			{Name: "applied_at", ColType: "timestamp"},
+		},
+	}
+	if err := table.PrimaryKey("name"); err != nil {
+		return err
+	}
+	stmt, err := c.Dialect.FizzTranslator().CreateTable(table)
+	if err != nil {
+		return fmt.Errorf("could not build SQL for the seeds table: %w", err)
+	}
+	if err := c.RawQuery(stmt).Exec(); err != nil {
+		return fmt.Errorf("could not execute %s: %w", stmt, err)
+	}
+	return nil
+}
+
+// sortSeeds returns the seeds in the order of their dependencies, the
+// seeds ready at once in the order of their names.
+func sortSeeds(seeds []Seed) ([]Seed, error) {
+	byName := make(map[string]Seed, len(seeds))
+	for _, s := range seeds {
+		if s.Name == "" {
+			return nil, errors.New("seed without a name")
+		}
+		if s.Run == nil {
+			return nil, fmt.Errorf("seed %s has nothing to run", s.Name)
+		}
+		if _, ok := byName[s.Name]; ok {
+			return nil, fmt.Errorf("duplicate seed %s", s.Name)
+		}
+		byName[s.Name] = s
+	}
+
+	pending := make(map[string]int, len(seeds))
+	dependents := map[string][]string{}
+	for _, s := range seeds {
+		for _, dep := range s.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("seed %s depends on unknown seed %s", s.Name, dep)
+			}
+			pending[s.Name]++
+			dependents[dep] = append(dependents[dep], s.Name)
+		}
+	}
+
+	var ready []string
+	for _, s := range seeds {
+		if pending[s.Name] == 0 {
+			ready = append(ready, s.Name)
+		}
+	}
+	sorted := make([]Seed, 0, len(seeds))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		sorted = append(sorted, byName[name])
+		for _, d := range dependents[name] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	if len(sorted) < len(seeds) {
+		var cycle []string
+		for _, s := range seeds {
+			if pending[s.Name] > 0 {
+				cycle = append(cycle, s.Name)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("seeds with circular dependencies: %s", strings.Join(cycle, ", "))
+	}
+	return sorted, nil
+}
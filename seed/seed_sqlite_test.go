@@ -0,0 +1,76 @@
+//go:build sqlite
+// +build sqlite
+
+package seed
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Seeder_Run(t *testing.T) {
+	r := require.New(t)
+
+	c, err := pop.NewConnection(&pop.ConnectionDetails{
+		URL: "sqlite://" + filepath.Join(t.TempDir(), "seed.db"),
+	})
+	r.NoError(err)
+	r.NoError(c.Open())
+	defer c.Close()
+	r.NoError(c.RawQuery("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, admin BOOLEAN NOT NULL DEFAULT false)").Exec())
+	r.NoError(c.RawQuery("CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id), title TEXT)").Exec())
+
+	dir := t.TempDir()
+	r.NoError(os.WriteFile(filepath.Join(dir, "posts.json"), []byte(`{"depends_on": ["users"], "tables": [{"table": "posts", "rows": [{"id": 1, "user_id": 2, "title": "Hello"}]}]}`), 0644))
+	r.NoError(os.WriteFile(filepath.Join(dir, "users.yml"), []byte("tables:\n  - table: users\n    rows:\n      - {id: 1, name: Mark}\n"), 0644))
+
+	admin := Seed{
+		Name:      "admin",
+		DependsOn: []string{"users"},
+		Run: func(tx *pop.Connection) error {
+			return tx.RawQuery("INSERT INTO users (id, name, admin) VALUES (2, 'Root', true)").Exec()
+		},
+	}
+	s, err := NewFileSeeder(dir, c)
+	r.NoError(err)
+	s.Seeds = append(s.Seeds, admin)
+
+	ran, err := s.Run()
+	r.NoError(err)
+	r.Equal([]string{"users", "admin", "posts"}, ran)
+
+	count, err := c.RawQuery("SELECT * FROM posts WHERE user_id = 2").Count(nil)
+	r.NoError(err)
+	r.Equal(1, count)
+
+	applied, err := s.Applied()
+	r.NoError(err)
+	r.Equal([]string{"admin", "posts", "users"}, applied)
+
+	ran, err = s.Run()
+	r.NoError(err)
+	r.Empty(ran)
+
+	// a failing seed rolls back the seeds applied with it
+	s.Seeds = append(s.Seeds,
+		Seed{Name: "tags", Run: func(tx *pop.Connection) error {
+			return tx.RawQuery("INSERT INTO users (id, name) VALUES (3, 'Tagger')").Exec()
+		}},
+		Seed{Name: "zeta", DependsOn: []string{"tags"}, Run: func(tx *pop.Connection) error {
+			return errors.New("boom")
+		}},
+	)
+	_, err = s.Run()
+	r.EqualError(err, "seed zeta: boom")
+	count, err = c.RawQuery("SELECT * FROM users").Count(nil)
+	r.NoError(err)
+	r.Equal(2, count)
+	applied, err = s.Applied()
+	r.NoError(err)
+	r.Equal([]string{"admin", "posts", "users"}, applied)
+}
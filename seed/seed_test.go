@@ -0,0 +1,62 @@
+package seed
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func noop(tx *pop.Connection) error { return nil }
+
+func names(seeds []Seed) []string {
+	var ns []string
+	for _, s := range seeds {
+		ns = append(ns, s.Name)
+	}
+	return ns
+}
+
+func Test_sortSeeds(t *testing.T) {
+	r := require.New(t)
+
+	sorted, err := sortSeeds([]Seed{
+		{Name: "posts", DependsOn: []string{"users", "tags"}, Run: noop},
+		{Name: "users", DependsOn: []string{"roles"}, Run: noop},
+		{Name: "tags", Run: noop},
+		{Name: "roles", Run: noop},
+		{Name: "countries", Run: noop},
+	})
+	r.NoError(err)
+	r.Equal([]string{"countries", "roles", "tags", "users", "posts"}, names(sorted))
+
+	_, err = sortSeeds([]Seed{{Name: "a", DependsOn: []string{"b"}, Run: noop}, {Name: "b", DependsOn: []string{"a"}, Run: noop}, {Name: "c", Run: noop}})
+	r.EqualError(err, "seeds with circular dependencies: a, b")
+
+	_, err = sortSeeds([]Seed{{Name: "a", DependsOn: []string{"z"}, Run: noop}})
+	r.EqualError(err, "seed a depends on unknown seed z")
+
+	_, err = sortSeeds([]Seed{{Name: "a", Run: noop}, {Name: "a", Run: noop}})
+	r.EqualError(err, "duplicate seed a")
+}
+
+func Test_Load(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	r.NoError(os.WriteFile(filepath.Join(dir, "users.yml"), []byte("tables:\n  - table: users\n    rows:\n      - {id: 1, name: Mark}\n"), 0644))
+	r.NoError(os.WriteFile(filepath.Join(dir, "posts.json"), []byte(`{"depends_on": ["users"], "tables": [{"table": "posts", "rows": [{"id": 1, "user_id": 1}]}]}`), 0644))
+	r.NoError(os.WriteFile(filepath.Join(dir, "README.md"), []byte("# seeds"), 0644))
+
+	seeds, err := Load(dir)
+	r.NoError(err)
+	r.Equal([]string{"posts", "users"}, names(seeds))
+	r.Equal([]string{"users"}, seeds[0].DependsOn)
+	r.Empty(seeds[1].DependsOn)
+
+	r.NoError(os.WriteFile(filepath.Join(dir, "broken.yml"), []byte("tables:\n  - rows:\n      - {id: 1}\n"), 0644))
+	_, err = Load(dir)
+	r.Error(err)
+}
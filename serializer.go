@@ -0,0 +1,164 @@
+package pop
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// serializedFieldsKey identifies the serialized fields of a struct type for
+// a given mapper.
+type serializedFieldsKey struct {
+	m *reflectx.Mapper
+	t reflect.Type
+}
+
+// serializedFieldsCache holds the serialized fields of the struct types, by
+// type and mapper.
+var serializedFieldsCache sync.Map
+
+// serializedFields returns the fields of the struct type t tagged with a
+// serializer, e.g. `db:"settings" serializer:"json"`, by column. Their
+// values are stored serialized instead of as they are, so any struct, map
+// or slice can be mapped to a column without implementing driver.Valuer
+// and sql.Scanner.
+func serializedFields(m *reflectx.Mapper, t reflect.Type) map[string]*reflectx.FieldInfo {
+	key := serializedFieldsKey{m: m, t: t}
+	if cached, ok := serializedFieldsCache.Load(key); ok {
+		return cached.(map[string]*reflectx.FieldInfo)
+	}
+	var fields map[string]*reflectx.FieldInfo
+	for _, fi := range m.TypeMap(t).Index {
+		if fi.Field.Tag.Get("serializer") == "" {
+			continue
+		}
+		if fields == nil {
+			fields = map[string]*reflectx.FieldInfo{}
+		}
+		fields[fi.Path] = fi
+	}
+	serializedFieldsCache.Store(key, fields)
+	return fields
+}
+
+// serializedValue is a field stored serialized by the named serializer.
+// It is the argument bound for the field, and the destination it is
+// scanned through.
+type serializedValue struct {
+	v          reflect.Value
+	serializer string
+}
+
+func newSerializedValue(v reflect.Value, fi *reflectx.FieldInfo) serializedValue {
+	return serializedValue{v: v, serializer: fi.Field.Tag.Get("serializer")}
+}
+
+// Value returns the serialized field, or NULL for a nil pointer, map or
+// slice.
+func (s serializedValue) Value() (driver.Value, error) {
+	if s.serializer != "json" {
+		return nil, fmt.Errorf("unknown serializer %s", s.serializer)
+	}
+	switch s.v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if s.v.IsNil() {
+			return nil, nil
+		}
+	}
+	b, err := json.Marshal(s.v.Interface())
+	if err != nil {
+		return nil, fmt.Errorf("serialize: %w", err)
+	}
+	return string(b), nil
+}
+
+// Scan deserializes the column into the field, zeroing it for NULL.
+func (s serializedValue) Scan(src interface{}) error {
+	if s.serializer != "json" {
+		return fmt.Errorf("unknown serializer %s", s.serializer)
+	}
+	var b []byte
+	switch src := src.(type) {
+	case nil:
+		s.v.Set(reflect.Zero(s.v.Type()))
+		return nil
+	case []byte:
+		b = src
+	case string:
+		b = []byte(src)
+	default:
+		return fmt.Errorf("deserialize: unsupported type %T", src)
+	}
+	s.v.Set(reflect.Zero(s.v.Type()))
+	if err := json.Unmarshal(b, s.v.Addr().Interface()); err != nil {
+		return fmt.Errorf("deserialize: %w", err)
+	}
+	return nil
+}
+
+// serializedArg returns the argument of a named statement with the
+// serialized fields of its struct, or of the structs of its slice, bound
+// serialized. Other arguments are returned as they are.
+func serializedArg(m *reflectx.Mapper, arg interface{}) interface{} {
+	v := reflect.Indirect(reflect.ValueOf(arg))
+	switch v.Kind() {
+	case reflect.Struct:
+		if serializedFields(m, v.Type()) == nil {
+			return arg
+		}
+		return serializedMap(m, v)
+	case reflect.Slice, reflect.Array:
+		t := v.Type().Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || serializedFields(m, t) == nil {
+			return arg
+		}
+		xs := make([]interface{}, v.Len())
+		for i := range xs {
+			xs[i] = serializedMap(m, reflect.Indirect(v.Index(i)))
+		}
+		return xs
+	}
+	return arg
+}
+
+// serializedMap returns the values of the fields of the struct by name,
+// the serialized ones wrapped to be bound serialized. The fields behind a
+// nil pointer are left out, rather than allocated like sqlx does.
+func serializedMap(m *reflectx.Mapper, v reflect.Value) map[string]interface{} {
+	fields := serializedFields(m, v.Type())
+	values := map[string]interface{}{}
+	for name, fi := range m.TypeMap(v.Type()).Names {
+		f, ok := fieldByIndexes(v, fi.Index)
+		if !ok {
+			continue
+		}
+		if fi, ok := fields[name]; ok {
+			values[name] = newSerializedValue(f, fi)
+			continue
+		}
+		values[name] = f.Interface()
+	}
+	return values
+}
+
+// fieldByIndexes returns the field of v at the index path, reporting false
+// when the path goes through a nil pointer.
+func fieldByIndexes(v reflect.Value, indexes []int) (reflect.Value, bool) {
+	for _, i := range indexes {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
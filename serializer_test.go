@@ -0,0 +1,65 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type preferenceSettings struct {
+	Theme  string `json:"theme"`
+	Alerts bool   `json:"alerts"`
+}
+
+type preference struct {
+	ID       int                    `db:"id"`
+	Settings preferenceSettings     `db:"settings" serializer:"json"`
+	Tags     []string               `db:"tags" serializer:"json"`
+	Meta     map[string]interface{} `db:"meta" serializer:"json"`
+	Extra    *preferenceSettings    `db:"extra" serializer:"json"`
+}
+
+func Test_Serializer_JSON(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE preferences (id INTEGER PRIMARY KEY, settings TEXT, tags TEXT, meta TEXT, extra TEXT)").Exec())
+
+	p := &preference{
+		Settings: preferenceSettings{Theme: "dark", Alerts: true},
+		Tags:     []string{"a", "b"},
+		Meta:     map[string]interface{}{"plan": "pro"},
+	}
+	r.NoError(c.Create(p))
+
+	var raw struct {
+		Settings string  `db:"settings"`
+		Tags     string  `db:"tags"`
+		Extra    *string `db:"extra"`
+	}
+	r.NoError(c.RawQuery("SELECT settings, tags, extra FROM preferences WHERE id = ?", p.ID).First(&raw))
+	r.Equal(`{"theme":"dark","alerts":true}`, raw.Settings)
+	r.Equal(`["a","b"]`, raw.Tags)
+	r.Nil(raw.Extra)
+
+	found := &preference{}
+	r.NoError(c.Find(found, p.ID))
+	r.Equal(p, found)
+
+	found.Extra = &preferenceSettings{Theme: "light"}
+	found.Tags = nil
+	r.NoError(c.Update(found))
+
+	r.NoError(c.Transaction(func(tx *Connection) error {
+		var all []preference
+		if err := tx.All(&all); err != nil {
+			return err
+		}
+		r.Len(all, 1)
+		r.Equal(*found, all[0])
+		return nil
+	}))
+}
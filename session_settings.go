@@ -0,0 +1,117 @@
+package pop
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sort"
+)
+
+// sessionSettable is implemented by the dialects applying the session
+// settings of the connection details on each connection of the pool, see
+// ConnectionDetails.SessionSettings.
+type sessionSettable interface {
+	// StrictSettings returns the settings of ConnectionDetails.StrictMode.
+	StrictSettings() map[string]string
+	// SessionStatement returns the statement setting the session setting
+	// to the value, an SQL expression.
+	SessionStatement(name, value string) string
+}
+
+// sessionStatements returns the statements setting the strict mode
+// settings of the dialect, if enabled, and the session settings of its
+// connection details, sorted by setting.
+func sessionStatements(d dialect) ([]string, error) {
+	deets := d.Details()
+	if !deets.StrictMode && len(deets.SessionSettings) == 0 {
+		return nil, nil
+	}
+	s, ok := d.(sessionSettable)
+	if !ok {
+		return nil, errUnsupported(d, "session settings")
+	}
+	settings := map[string]string{}
+	if deets.StrictMode {
+		for k, v := range s.StrictSettings() {
+			settings[k] = v
+		}
+	}
+	for k, v := range deets.SessionSettings {
+		settings[k] = v
+	}
+	names := make([]string, 0, len(settings))
+	for k := range settings {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	statements := make([]string, len(names))
+	for i, k := range names {
+		statements[i] = s.SessionStatement(k, settings[k])
+	}
+	return statements, nil
+}
+
+// sessionConnector runs the session statements on each connection it
+// opens, before the pool hands it out.
+type sessionConnector struct {
+	driver.Connector
+	statements []string
+}
+
+func (s sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := s.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range s.statements {
+		if err := execConn(ctx, conn, stmt); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("session setting %q: %w", stmt, err)
+		}
+	}
+	return conn, nil
+}
+
+// execConn runs the statement on the driver connection.
+func execConn(ctx context.Context, conn driver.Conn, stmt string) error {
+	if ex, ok := conn.(driver.ExecerContext); ok {
+		_, err := ex.ExecContext(ctx, stmt, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+	st, err := conn.Prepare(stmt)
+	if err != nil {
+		return err
+	}
+	defer st.Close()
+	if sx, ok := st.(driver.StmtExecContext); ok {
+		_, err = sx.ExecContext(ctx, nil)
+		return err
+	}
+	_, err = st.Exec(nil)
+	return err
+}
+
+// dsnConnector opens the connections of a driver without
+// driver.DriverContext from the DSN.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (d dsnConnector) Connect(_ context.Context) (driver.Conn, error) {
+	return d.driver.Open(d.dsn)
+}
+
+func (d dsnConnector) Driver() driver.Driver {
+	return d.driver
+}
+
+// driverConnector returns the connector of the driver for the DSN.
+func driverConnector(d driver.Driver, dsn string) (driver.Connector, error) {
+	if dc, ok := d.(driver.DriverContext); ok {
+		return dc.OpenConnector(dsn)
+	}
+	return dsnConnector{dsn: dsn, driver: d}, nil
+}
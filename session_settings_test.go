@@ -0,0 +1,65 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_sessionStatements(t *testing.T) {
+	r := require.New(t)
+
+	cd := &ConnectionDetails{
+		StrictMode:      true,
+		SessionSettings: map[string]string{"search_path": "app, public", "standard_conforming_strings": "off"},
+	}
+	d, err := newPostgreSQL(cd)
+	r.NoError(err)
+	statements, err := sessionStatements(d)
+	r.NoError(err)
+	r.Equal([]string{"SET search_path = app, public", "SET standard_conforming_strings = off"}, statements)
+
+	d, err = newMySQL(&ConnectionDetails{StrictMode: true})
+	r.NoError(err)
+	statements, err = sessionStatements(d)
+	r.NoError(err)
+	r.Equal([]string{"SET SESSION sql_mode = 'STRICT_ALL_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION'"}, statements)
+
+	d = &spanner{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}
+	statements, err = sessionStatements(d)
+	r.NoError(err)
+	r.Empty(statements)
+
+	d.Details().StrictMode = true
+	_, err = sessionStatements(d)
+	r.ErrorIs(err, ErrUnsupported)
+}
+
+func Test_SessionSettings_SQLite(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, &ConnectionDetails{
+		Pool:            2,
+		StrictMode:      true,
+		SessionSettings: map[string]string{"cache_size": "-4000"},
+	})
+	db := unwrapStore(c.Store).(*dB)
+
+	// every connection of the pool has the settings.
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		conn, err := db.Connx(ctx)
+		r.NoError(err)
+		defer conn.Close()
+
+		var trusted, cacheSize int
+		r.NoError(conn.GetContext(ctx, &trusted, "PRAGMA trusted_schema"))
+		r.NoError(conn.GetContext(ctx, &cacheSize, "PRAGMA cache_size"))
+		r.Equal(0, trusted)
+		r.Equal(-4000, cacheSize)
+	}
+}
@@ -0,0 +1,61 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type copiedNote struct {
+	ID        int       `db:"id"`
+	Body      string    `db:"body"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Hooked    int       `db:"-"`
+}
+
+func (n *copiedNote) BeforeSave(tx *Connection) error {
+	n.Hooked++
+	return nil
+}
+
+func (n *copiedNote) AfterCreate(tx *Connection) error {
+	n.Hooked++
+	return nil
+}
+
+func Test_SkipCallbacks_SkipTimestamps(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE copied_notes (id INTEGER PRIMARY KEY, body TEXT, created_at DATETIME, updated_at DATETIME)").Exec())
+
+	n := &copiedNote{Body: "a"}
+	r.NoError(c.Create(n))
+	r.Equal(2, n.Hooked)
+
+	then := time.Date(2020, 5, 17, 10, 0, 0, 0, time.UTC)
+	n = &copiedNote{Body: "b", CreatedAt: then, UpdatedAt: then.Add(time.Hour)}
+	r.NoError(c.SkipCallbacks().SkipTimestamps().Create(n))
+	r.Zero(n.Hooked)
+
+	found := &copiedNote{}
+	r.NoError(c.Find(found, n.ID))
+	r.True(then.Equal(found.CreatedAt))
+	r.True(then.Add(time.Hour).Equal(found.UpdatedAt))
+
+	found.Body = "c"
+	found.Hooked = 0
+	r.NoError(c.SkipTimestamps().Update(found))
+	r.Equal(1, found.Hooked)
+	r.NoError(c.Find(found, n.ID))
+	r.Equal("c", found.Body)
+	r.True(then.Add(time.Hour).Equal(found.UpdatedAt))
+
+	r.NoError(c.SkipCallbacks().Update(found))
+	r.True(found.UpdatedAt.After(then.Add(time.Hour)))
+}
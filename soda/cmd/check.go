@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/WilliamNHarvey/pop/v6/soda/cmd/db"
+	"github.com/spf13/cobra"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Tools for checking the data of your database",
+}
+
+func init() {
+	checkCmd.AddCommand(db.IntegrityCmd)
+	RootCmd.AddCommand(checkCmd)
+}
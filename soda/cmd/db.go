@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/WilliamNHarvey/pop/v6/soda/cmd/db"
+	"github.com/spf13/cobra"
+)
+
+// dbCmd represents the db command
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Tools for keeping your database in sync with your models",
+}
+
+func init() {
+	dbCmd.AddCommand(db.SyncDefaultsCmd)
+	RootCmd.AddCommand(dbCmd)
+}
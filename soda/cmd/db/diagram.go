@@ -0,0 +1,247 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/gobuffalo/flect/name"
+	"github.com/spf13/cobra"
+)
+
+var diagramOptions = struct {
+	models string
+	format string
+	output string
+}{}
+
+// DiagramCmd renders the entity-relationship diagram of the selected
+// database, from its foreign keys and the associations of the models, in
+// the Graphviz dot or Mermaid format.
+var DiagramCmd = &cobra.Command{
+	Use:   "diagram",
+	Short: "Generates the entity-relationship diagram of the selected database",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if diagramOptions.format != "dot" && diagramOptions.format != "mermaid" {
+			return fmt.Errorf("unknown format %s, expected dot or mermaid", diagramOptions.format)
+		}
+		schema, models, err := loadSchema(cmd, diagramOptions.models)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := renderDiagram(&buf, diagramOptions.format, newDiagram(schema, models)); err != nil {
+			return err
+		}
+
+		output := diagramOptions.output
+		if output == "" {
+			output = "./docs/schema.dot"
+			if diagramOptions.format == "mermaid" {
+				output = "./docs/schema.mmd"
+			}
+		}
+		return writeOutput(cmd, output, buf.Bytes())
+	},
+}
+
+func init() {
+	DiagramCmd.Flags().StringVarP(&diagramOptions.models, "models", "m", "./models", "The path to the package of the models.")
+	DiagramCmd.Flags().StringVarP(&diagramOptions.format, "format", "f", "dot", "The format of the diagram, dot or mermaid.")
+	DiagramCmd.Flags().StringVarP(&diagramOptions.output, "output", "o", "", "The path to write the diagram to, - for the standard output. Defaults to ./docs/schema.dot, or ./docs/schema.mmd.")
+}
+
+// diagram is the entity-relationship diagram of the schema.
+type diagram struct {
+	Tables    []pop.SchemaTable
+	Relations []relation
+}
+
+// relation is an edge of the diagram, from a foreign key or from an
+// association of a model not backed by one.
+type relation struct {
+	From string
+	To   string
+	// Kind is the tag of the association, empty for the foreign keys.
+	Kind  string
+	Label string
+	// Optional is set for the foreign keys whose columns are nullable.
+	Optional bool
+}
+
+func newDiagram(schema []pop.SchemaTable, models []model) diagram {
+	d := diagram{Tables: schema}
+	tables := map[string]bool{}
+	fks := map[[2]string]bool{}
+	for _, t := range schema {
+		tables[t.Name] = true
+		for _, fk := range t.ForeignKeys {
+			d.Relations = append(d.Relations, relation{
+				From:     t.Name,
+				To:       fk.RefTable,
+				Label:    strings.Join(fk.Columns, ", "),
+				Optional: nullable(t, fk.Columns),
+			})
+			fks[[2]string{t.Name, fk.RefTable}] = true
+		}
+	}
+
+	modelTables := map[string]string{}
+	for _, m := range models {
+		modelTables[m.Name] = m.Table
+	}
+	for _, m := range models {
+		if !tables[m.Table] {
+			continue
+		}
+		for _, a := range associations(m) {
+			target, ok := modelTables[a.Target]
+			if !ok {
+				target = name.Tableize(a.Target)
+			}
+			if !tables[target] {
+				continue
+			}
+			switch a.Kind {
+			case "belongs_to":
+				if fks[[2]string{m.Table, target}] {
+					continue
+				}
+			case "has_one", "has_many":
+				if fks[[2]string{target, m.Table}] {
+					continue
+				}
+			}
+			d.Relations = append(d.Relations, relation{From: m.Table, To: target, Kind: a.Kind, Label: a.Field})
+		}
+	}
+	return d
+}
+
+// nullable reports whether the columns of the table are all nullable.
+func nullable(t pop.SchemaTable, columns []string) bool {
+	for _, name := range columns {
+		for _, c := range t.Columns {
+			if c.Name == name && !c.Nullable {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// renderDiagram renders the diagram in the format, dot or mermaid.
+func renderDiagram(w io.Writer, format string, d diagram) error {
+	if format == "mermaid" {
+		return renderMermaid(w, d)
+	}
+	return renderDot(w, d)
+}
+
+func renderDot(w io.Writer, d diagram) error {
+	var b strings.Builder
+	b.WriteString("digraph schema {\n\trankdir=LR;\n\tnode [shape=record];\n")
+	for _, t := range d.Tables {
+		fields := make([]string, len(t.Columns))
+		for i, c := range t.Columns {
+			fields[i] = dotEscape(fmt.Sprintf("%s : %s", c.Name, c.Type))
+			if keys := columnKeys(t, c); keys != "" {
+				fields[i] += dotEscape(fmt.Sprintf(" (%s)", keys))
+			}
+		}
+		fmt.Fprintf(&b, "\t%q [label=\"{%s|%s\\l}\"];\n", t.Name, dotEscape(t.Name), strings.Join(fields, "\\l"))
+	}
+	for _, r := range d.Relations {
+		attrs := fmt.Sprintf("label=%q", r.Label)
+		switch {
+		case r.Kind != "":
+			attrs = fmt.Sprintf("label=%q, style=dashed", r.Kind+" "+r.Label)
+		case r.Optional:
+			attrs += ", arrowhead=odiamond"
+		}
+		fmt.Fprintf(&b, "\t%q -> %q [%s];\n", r.From, r.To, attrs)
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotEscape escapes the characters of the record labels of dot.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`).Replace(s)
+}
+
+// mermaidCardinalities are the cardinalities of the relations of Mermaid,
+// by kind of association.
+var mermaidCardinalities = map[string]string{
+	"belongs_to":   "}o..||",
+	"has_one":      "||..o|",
+	"has_many":     "||..o{",
+	"many_to_many": "}o..o{",
+}
+
+func renderMermaid(w io.Writer, d diagram) error {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, t := range d.Tables {
+		fmt.Fprintf(&b, "\t%s {\n", mermaidName(t.Name))
+		for _, c := range t.Columns {
+			fmt.Fprintf(&b, "\t\t%s %s", mermaidType(c.Type), mermaidName(c.Name))
+			if keys := columnKeys(t, c); keys != "" {
+				fmt.Fprintf(&b, " %s", keys)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\t}\n")
+	}
+	for _, r := range d.Relations {
+		cardinality := "}o--||"
+		switch {
+		case r.Kind != "":
+			cardinality = mermaidCardinalities[r.Kind]
+		case r.Optional:
+			cardinality = "}o--o|"
+		}
+		fmt.Fprintf(&b, "\t%s %s %s : %q\n", mermaidName(r.From), cardinality, mermaidName(r.To), r.Label)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var mermaidInvalid = regexp.MustCompile(`[^A-Za-z0-9_\-()\[\]]+`)
+
+// mermaidName returns the name of an entity or attribute Mermaid accepts.
+func mermaidName(s string) string {
+	return mermaidInvalid.ReplaceAllString(s, "_")
+}
+
+// mermaidType returns the type of an attribute Mermaid accepts, e.g.
+// character_varying(255).
+func mermaidType(s string) string {
+	s = mermaidName(strings.ReplaceAll(s, ", ", ","))
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+// columnKeys returns the keys the column is part of: PK and FK.
+func columnKeys(t pop.SchemaTable, c pop.SchemaColumn) string {
+	var keys []string
+	if c.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+	for _, fk := range t.ForeignKeys {
+		for _, name := range fk.Columns {
+			if name == c.Name {
+				keys = append(keys, "FK")
+				return strings.Join(keys, ", ")
+			}
+		}
+	}
+	return strings.Join(keys, ", ")
+}
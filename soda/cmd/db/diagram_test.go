@@ -0,0 +1,81 @@
+package db
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func diagramFixture(t *testing.T) diagram {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	source := docsModelsSource + "\ntype Comment struct {\n\tID   int   `db:\"id\"`\n\tTags []Tag `many_to_many:\"comments_tags\"`\n}\n"
+	r.NoError(os.WriteFile(filepath.Join(dir, "models.go"), []byte(source), 0644))
+	models, err := parseModels(dir)
+	r.NoError(err)
+
+	schema := append(docsSchema(),
+		pop.SchemaTable{Name: "comments", Columns: []pop.SchemaColumn{{Name: "id", Type: "integer", PrimaryKey: true}}},
+		pop.SchemaTable{Name: "tags", Columns: []pop.SchemaColumn{{Name: "name", Type: "character varying(255)"}}},
+	)
+	return newDiagram(schema, models)
+}
+
+func Test_newDiagram(t *testing.T) {
+	r := require.New(t)
+
+	d := diagramFixture(t)
+	r.Equal([]relation{
+		{From: "posts", To: "users", Label: "user_id"},
+		{From: "comments", To: "tags", Kind: "many_to_many", Label: "Tags"},
+	}, d.Relations)
+}
+
+func Test_renderDiagram_Dot(t *testing.T) {
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	r.NoError(renderDiagram(&buf, "dot", diagramFixture(t)))
+	r.Equal(`digraph schema {
+	rankdir=LR;
+	node [shape=record];
+	"posts" [label="{posts|id : integer (PK)\luser_id : integer (FK)\lstatus : text\l}"];
+	"users" [label="{users|id : integer (PK)\lemail : text\l}"];
+	"comments" [label="{comments|id : integer (PK)\l}"];
+	"tags" [label="{tags|name : character varying(255)\l}"];
+	"posts" -> "users" [label="user_id"];
+	"comments" -> "tags" [label="many_to_many Tags", style=dashed];
+}
+`, buf.String())
+}
+
+func Test_renderDiagram_Mermaid(t *testing.T) {
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	r.NoError(renderDiagram(&buf, "mermaid", diagramFixture(t)))
+	r.Equal(`erDiagram
+	posts {
+		integer id PK
+		integer user_id FK
+		text status
+	}
+	users {
+		integer id PK
+		text email
+	}
+	comments {
+		integer id PK
+	}
+	tags {
+		character_varying(255) name
+	}
+	posts }o--|| users : "user_id"
+	comments }o..o{ tags : "Tags"
+`, buf.String())
+}
@@ -0,0 +1,293 @@
+package db
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/spf13/cobra"
+)
+
+var docsOptions = struct {
+	models string
+	format string
+	output string
+}{}
+
+// DocsCmd renders the schema of the selected database, and the models
+// mapped to its tables, as Markdown or HTML.
+var DocsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generates the documentation of the schema of the selected database",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if docsOptions.format != "markdown" && docsOptions.format != "html" {
+			return fmt.Errorf("unknown format %s, expected markdown or html", docsOptions.format)
+		}
+		schema, models, err := loadSchema(cmd, docsOptions.models)
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := renderDocs(&buf, docsOptions.format, newDocs(schema, models)); err != nil {
+			return err
+		}
+
+		output := docsOptions.output
+		if output == "" {
+			output = "./docs/schema.md"
+			if docsOptions.format == "html" {
+				output = "./docs/schema.html"
+			}
+		}
+		return writeOutput(cmd, output, buf.Bytes())
+	},
+}
+
+func init() {
+	DocsCmd.Flags().StringVarP(&docsOptions.models, "models", "m", "./models", "The path to the package of the models.")
+	DocsCmd.Flags().StringVarP(&docsOptions.format, "format", "f", "markdown", "The format of the documentation, markdown or html.")
+	DocsCmd.Flags().StringVarP(&docsOptions.output, "output", "o", "", "The path to write the documentation to, - for the standard output. Defaults to ./docs/schema.md, or ./docs/schema.html.")
+}
+
+// loadSchema returns the schema of the database of the environment of the
+// command, and the models of the package in dir, if any.
+func loadSchema(cmd *cobra.Command, dir string) ([]pop.SchemaTable, []model, error) {
+	env := cmd.Flag("env")
+	if env == nil {
+		return nil, nil, errors.New("env is required")
+	}
+	c, err := pop.Connect(env.Value.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	schema, err := c.Schema()
+	if err != nil {
+		return nil, nil, err
+	}
+	var models []model
+	if _, err := os.Stat(dir); err == nil {
+		if models, err = parseModels(dir); err != nil {
+			return nil, nil, err
+		}
+	}
+	return schema, models, nil
+}
+
+// writeOutput writes b to the output file, or to the output of the command
+// for -.
+func writeOutput(cmd *cobra.Command, output string, b []byte) error {
+	if output == "-" {
+		_, err := cmd.OutOrStdout().Write(b)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(output, b, 0644)
+}
+
+// docs is the documentation of the schema.
+type docs struct {
+	Tables []docsTable
+	// Unmapped are the models whose table is not in the schema.
+	Unmapped []string
+}
+
+// docsTable is a table of the schema and the models mapped to it.
+type docsTable struct {
+	pop.SchemaTable
+	Models       []string
+	Associations []association
+}
+
+// association is an association of a model, set by the tag of its field.
+type association struct {
+	Model string
+	Field string
+	// Kind is the tag of the association, e.g. has_many.
+	Kind   string
+	Target string
+}
+
+var associationTags = []string{"belongs_to", "has_one", "has_many", "many_to_many"}
+
+func newDocs(schema []pop.SchemaTable, models []model) docs {
+	d := docs{}
+	byTable := map[string][]model{}
+	for _, m := range models {
+		if mapped(m) {
+			byTable[m.Table] = append(byTable[m.Table], m)
+		}
+	}
+	tables := map[string]bool{}
+	for _, t := range schema {
+		tables[t.Name] = true
+		dt := docsTable{SchemaTable: t}
+		for _, m := range byTable[t.Name] {
+			dt.Models = append(dt.Models, m.Name)
+			dt.Associations = append(dt.Associations, associations(m)...)
+		}
+		d.Tables = append(d.Tables, dt)
+	}
+	for table, ms := range byTable {
+		if tables[table] {
+			continue
+		}
+		for _, m := range ms {
+			d.Unmapped = append(d.Unmapped, fmt.Sprintf("%s (%s)", m.Name, table))
+		}
+	}
+	sort.Strings(d.Unmapped)
+	return d
+}
+
+// mapped reports whether the model maps fields to columns, rather than
+// being a helper type of the package.
+func mapped(m model) bool {
+	for _, f := range m.Fields {
+		if _, ok := f.Tag.Lookup("db"); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func associations(m model) []association {
+	var as []association
+	for _, f := range m.Fields {
+		for _, kind := range associationTags {
+			if _, ok := f.Tag.Lookup(kind); ok {
+				target := strings.TrimLeft(f.Type, "[]*")
+				as = append(as, association{Model: m.Name, Field: f.Name, Kind: kind, Target: target})
+			}
+		}
+	}
+	return as
+}
+
+// Key returns the keys the column is part of: PK, and FK followed by the
+// referenced table.
+func (t docsTable) Key(column string) string {
+	var keys []string
+	for _, c := range t.Columns {
+		if c.Name == column && c.PrimaryKey {
+			keys = append(keys, "PK")
+		}
+	}
+	for _, fk := range t.ForeignKeys {
+		for _, c := range fk.Columns {
+			if c == column {
+				keys = append(keys, "FK "+fk.RefTable)
+			}
+		}
+	}
+	return strings.Join(keys, ", ")
+}
+
+var docsFuncs = map[string]interface{}{
+	"join": func(s []string) string {
+		return strings.Join(s, ", ")
+	},
+	"default": func(def *string) string {
+		if def == nil {
+			return ""
+		}
+		return *def
+	},
+	"cell": func(s string) string {
+		return strings.ReplaceAll(s, "|", "\\|")
+	},
+}
+
+const markdownDocs = `# Database schema
+{{range .Tables}}
+- [{{.Name}}](#{{.Name}}){{end}}
+{{range $t := .Tables}}
+## {{.Name}}
+{{if .Models}}
+Models: {{join .Models}}
+{{end}}
+| Column | Type | Nullable | Default | Key |
+| --- | --- | --- | --- | --- |
+{{range .Columns}}| {{.Name}} | {{cell .Type}} | {{if .Nullable}}yes{{else}}no{{end}} | {{cell (default .Default)}} | {{$t.Key .Name}} |
+{{end}}{{if .ForeignKeys}}
+Foreign keys:
+{{range .ForeignKeys}}
+- {{join .Columns}} references {{.RefTable}} ({{join .RefColumns}}){{if .Name}}, {{.Name}}{{end}}{{end}}
+{{end}}{{if .Indexes}}
+Indexes:
+{{range .Indexes}}
+- {{.Name}}{{if .Unique}} (unique){{end}}: {{join .Columns}}{{end}}
+{{end}}{{if .Associations}}
+Associations:
+{{range .Associations}}
+- {{.Model}}.{{.Field}}: {{.Kind}} {{.Target}}{{end}}
+{{end}}{{end}}{{if .Unmapped}}
+## Models without a table
+{{range .Unmapped}}
+- {{.}}{{end}}
+{{end}}`
+
+const htmlDocs = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Database schema</title>
+</head>
+<body>
+<h1>Database schema</h1>
+<ul>{{range .Tables}}
+<li><a href="#{{.Name}}">{{.Name}}</a></li>{{end}}
+</ul>
+{{range $t := .Tables}}
+<h2 id="{{.Name}}">{{.Name}}</h2>
+{{if .Models}}<p>Models: {{join .Models}}</p>
+{{end}}<table>
+<tr><th>Column</th><th>Type</th><th>Nullable</th><th>Default</th><th>Key</th></tr>{{range .Columns}}
+<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{if .Nullable}}yes{{else}}no{{end}}</td><td>{{default .Default}}</td><td>{{$t.Key .Name}}</td></tr>{{end}}
+</table>
+{{if .ForeignKeys}}<p>Foreign keys:</p>
+<ul>{{range .ForeignKeys}}
+<li>{{join .Columns}} references {{.RefTable}} ({{join .RefColumns}}){{if .Name}}, {{.Name}}{{end}}</li>{{end}}
+</ul>
+{{end}}{{if .Indexes}}<p>Indexes:</p>
+<ul>{{range .Indexes}}
+<li>{{.Name}}{{if .Unique}} (unique){{end}}: {{join .Columns}}</li>{{end}}
+</ul>
+{{end}}{{if .Associations}}<p>Associations:</p>
+<ul>{{range .Associations}}
+<li>{{.Model}}.{{.Field}}: {{.Kind}} {{.Target}}</li>{{end}}
+</ul>
+{{end}}{{end}}{{if .Unmapped}}
+<h2>Models without a table</h2>
+<ul>{{range .Unmapped}}
+<li>{{.}}</li>{{end}}
+</ul>
+{{end}}</body>
+</html>
+`
+
+// renderDocs renders the documentation in the format, markdown or html.
+func renderDocs(w io.Writer, format string, d docs) error {
+	if format == "html" {
+		t, err := htmltemplate.New("docs").Funcs(docsFuncs).Parse(htmlDocs)
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, d)
+	}
+	t, err := template.New("docs").Funcs(docsFuncs).Parse(markdownDocs)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, d)
+}
@@ -0,0 +1,135 @@
+package db
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+const docsModelsSource = `package models
+
+type User struct {
+	ID    int    ` + "`db:\"id\"`" + `
+	Email string ` + "`db:\"email\"`" + `
+	Posts []Post ` + "`has_many:\"posts\"`" + `
+}
+
+type Post struct {
+	ID     int   ` + "`db:\"id\"`" + `
+	UserID int   ` + "`db:\"user_id\"`" + `
+	User   *User ` + "`belongs_to:\"user\"`" + `
+}
+
+type Tag struct {
+	Name string ` + "`db:\"name\"`" + `
+}
+
+type Options struct {
+	Limit int
+}
+`
+
+func docsSchema() []pop.SchemaTable {
+	def := "'draft|published'"
+	return []pop.SchemaTable{
+		{
+			Name: "posts",
+			Columns: []pop.SchemaColumn{
+				{Name: "id", Type: "integer", PrimaryKey: true},
+				{Name: "user_id", Type: "integer"},
+				{Name: "status", Type: "text", Nullable: true, Default: &def},
+			},
+			ForeignKeys: []pop.SchemaForeignKey{
+				{Name: "posts_user_id_fkey", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}},
+			},
+			Indexes: []pop.SchemaIndex{
+				{Name: "posts_user_id_idx", Columns: []string{"user_id"}},
+			},
+		},
+		{
+			Name: "users",
+			Columns: []pop.SchemaColumn{
+				{Name: "id", Type: "integer", PrimaryKey: true},
+				{Name: "email", Type: "text"},
+			},
+			Indexes: []pop.SchemaIndex{
+				{Name: "users_email_idx", Columns: []string{"email"}, Unique: true},
+			},
+		},
+	}
+}
+
+func Test_renderDocs_Markdown(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	r.NoError(os.WriteFile(filepath.Join(dir, "models.go"), []byte(docsModelsSource), 0644))
+	models, err := parseModels(dir)
+	r.NoError(err)
+
+	var buf bytes.Buffer
+	r.NoError(renderDocs(&buf, "markdown", newDocs(docsSchema(), models)))
+	r.Equal(`# Database schema
+
+- [posts](#posts)
+- [users](#users)
+
+## posts
+
+Models: Post
+
+| Column | Type | Nullable | Default | Key |
+| --- | --- | --- | --- | --- |
+| id | integer | no |  | PK |
+| user_id | integer | no |  | FK users |
+| status | text | yes | 'draft\|published' |  |
+
+Foreign keys:
+
+- user_id references users (id), posts_user_id_fkey
+
+Indexes:
+
+- posts_user_id_idx: user_id
+
+Associations:
+
+- Post.User: belongs_to User
+
+## users
+
+Models: User
+
+| Column | Type | Nullable | Default | Key |
+| --- | --- | --- | --- | --- |
+| id | integer | no |  | PK |
+| email | text | no |  |  |
+
+Indexes:
+
+- users_email_idx (unique): email
+
+Associations:
+
+- User.Posts: has_many Post
+
+## Models without a table
+
+- Tag (tags)
+`, buf.String())
+}
+
+func Test_renderDocs_HTML(t *testing.T) {
+	r := require.New(t)
+
+	var buf bytes.Buffer
+	r.NoError(renderDocs(&buf, "html", newDocs(docsSchema(), nil)))
+	r.Contains(buf.String(), `<h2 id="posts">posts</h2>`)
+	r.Contains(buf.String(), `<tr><td>status</td><td>text</td><td>yes</td><td>&#39;draft|published&#39;</td><td></td></tr>`)
+	r.Contains(buf.String(), `<li>users_email_idx (unique): email</li>`)
+	r.NotContains(buf.String(), "Models without a table")
+}
@@ -0,0 +1,154 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/spf13/cobra"
+)
+
+// IndexesReportCmd lists the unused indexes of the selected database, from
+// its statistics, and the redundant ones, whose columns lead another index.
+var IndexesReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Lists the unused and redundant indexes of the selected database",
+	Long: `Lists the indexes of the selected database which were not read since its
+statistics were reset, from pg_stat_user_indexes on PostgreSQL and the sys
+schema on MySQL and MariaDB, and the indexes made redundant by another one
+whose leading columns they are. The unique indexes enforce constraints and are
+never reported unused.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env := cmd.Flag("env")
+		if env == nil {
+			return errors.New("env is required")
+		}
+		c, err := pop.Connect(env.Value.String())
+		if err != nil {
+			return err
+		}
+		schema, err := c.Schema()
+		if err != nil {
+			return err
+		}
+		usage, err := c.IndexUsage()
+		if err != nil && !errors.Is(err, pop.ErrUnsupported) {
+			return err
+		}
+		return writeIndexesReport(cmd.OutOrStdout(), schema, usage, err == nil)
+	},
+}
+
+// redundantIndex is an index whose columns lead the ones of another index
+// of its table.
+type redundantIndex struct {
+	Table     string
+	Index     string
+	Columns   []string
+	CoveredBy string
+}
+
+// reportIndex is an index of a table, or its primary key.
+type reportIndex struct {
+	name    string
+	columns []string
+	unique  bool
+}
+
+// redundantIndexes returns the indexes of the schema made redundant by
+// another index of their table: the ones whose columns lead the columns of
+// the other, unless they are unique and the other one is not or has more
+// columns. Of two identical indexes, the last one by name is redundant.
+func redundantIndexes(schema []pop.SchemaTable) []redundantIndex {
+	var redundant []redundantIndex
+	for _, t := range schema {
+		var indexes []reportIndex
+		var pk []string
+		for _, col := range t.Columns {
+			if col.PrimaryKey {
+				pk = append(pk, col.Name)
+			}
+		}
+		if len(pk) > 0 {
+			indexes = append(indexes, reportIndex{name: "primary key", columns: pk, unique: true})
+		}
+		for _, idx := range t.Indexes {
+			indexes = append(indexes, reportIndex{name: idx.Name, columns: idx.Columns, unique: idx.Unique})
+		}
+
+		for _, idx := range t.Indexes {
+			for _, other := range indexes {
+				if other.name == idx.Name || !leadingColumns(idx.Columns, other.columns) {
+					continue
+				}
+				same := len(idx.Columns) == len(other.columns) && idx.Unique == other.unique
+				if same && other.name != "primary key" && other.name > idx.Name {
+					continue
+				}
+				if idx.Unique && (!other.unique || len(idx.Columns) < len(other.columns)) {
+					continue
+				}
+				redundant = append(redundant, redundantIndex{Table: t.Name, Index: idx.Name, Columns: idx.Columns, CoveredBy: other.name})
+				break
+			}
+		}
+	}
+	return redundant
+}
+
+// leadingColumns reports whether cols are the first columns of other.
+func leadingColumns(cols, other []string) bool {
+	if len(cols) == 0 || len(cols) > len(other) {
+		return false
+	}
+	for i, col := range cols {
+		if !strings.EqualFold(col, other[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// unusedIndexes returns the indexes of the schema which were not read, but
+// the unique ones.
+func unusedIndexes(schema []pop.SchemaTable, usage []pop.IndexUsage) []pop.IndexUsage {
+	unique := map[string]bool{}
+	known := map[string]bool{}
+	for _, t := range schema {
+		for _, idx := range t.Indexes {
+			known[t.Name+"."+idx.Name] = true
+			unique[t.Name+"."+idx.Name] = idx.Unique
+		}
+	}
+	var unused []pop.IndexUsage
+	for _, u := range usage {
+		key := u.Table + "." + u.Index
+		if u.Reads == 0 && known[key] && !unique[key] {
+			unused = append(unused, u)
+		}
+	}
+	return unused
+}
+
+// writeIndexesReport writes the report of the indexes to out, without the
+// unused ones when the database has no usage statistics.
+func writeIndexesReport(out io.Writer, schema []pop.SchemaTable, usage []pop.IndexUsage, hasUsage bool) error {
+	if hasUsage {
+		unused := unusedIndexes(schema, usage)
+		fmt.Fprintf(out, "Unused indexes: %d\n", len(unused))
+		for _, u := range unused {
+			fmt.Fprintf(out, "  %s.%s\n", u.Table, u.Index)
+		}
+	} else {
+		fmt.Fprintln(out, "Unused indexes: the database has no index usage statistics")
+	}
+
+	redundant := redundantIndexes(schema)
+	fmt.Fprintf(out, "Redundant indexes: %d\n", len(redundant))
+	for _, r := range redundant {
+		fmt.Fprintf(out, "  %s.%s (%s), covered by %s\n", r.Table, r.Index, strings.Join(r.Columns, ", "), r.CoveredBy)
+	}
+	return nil
+}
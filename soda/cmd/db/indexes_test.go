@@ -0,0 +1,74 @@
+package db
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func indexesSchema() []pop.SchemaTable {
+	return []pop.SchemaTable{
+		{
+			Name: "posts",
+			Columns: []pop.SchemaColumn{
+				{Name: "id", PrimaryKey: true},
+				{Name: "user_id"},
+				{Name: "slug"},
+				{Name: "created_at"},
+			},
+			Indexes: []pop.SchemaIndex{
+				{Name: "posts_id_idx", Columns: []string{"id"}},
+				{Name: "posts_slug_idx", Columns: []string{"slug"}, Unique: true},
+				{Name: "posts_slug_key", Columns: []string{"slug"}, Unique: true},
+				{Name: "posts_user_id_created_at_idx", Columns: []string{"user_id", "created_at"}},
+				{Name: "posts_user_id_idx", Columns: []string{"user_id"}},
+				{Name: "posts_user_id_slug_idx", Columns: []string{"user_id", "slug"}, Unique: true},
+			},
+		},
+		{
+			Name:    "tags",
+			Columns: []pop.SchemaColumn{{Name: "name"}},
+			Indexes: []pop.SchemaIndex{
+				{Name: "tags_name_idx", Columns: []string{"name"}},
+			},
+		},
+	}
+}
+
+func Test_redundantIndexes(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal([]redundantIndex{
+		{Table: "posts", Index: "posts_id_idx", Columns: []string{"id"}, CoveredBy: "primary key"},
+		{Table: "posts", Index: "posts_slug_key", Columns: []string{"slug"}, CoveredBy: "posts_slug_idx"},
+		{Table: "posts", Index: "posts_user_id_idx", Columns: []string{"user_id"}, CoveredBy: "posts_user_id_created_at_idx"},
+	}, redundantIndexes(indexesSchema()))
+}
+
+func Test_writeIndexesReport(t *testing.T) {
+	r := require.New(t)
+
+	usage := []pop.IndexUsage{
+		{Table: "posts", Index: "posts_pkey", Reads: 0},
+		{Table: "posts", Index: "posts_id_idx", Reads: 0},
+		{Table: "posts", Index: "posts_slug_idx", Reads: 0},
+		{Table: "posts", Index: "posts_user_id_idx", Reads: 12},
+		{Table: "tags", Index: "tags_name_idx", Reads: 0},
+	}
+	out := &bytes.Buffer{}
+	r.NoError(writeIndexesReport(out, indexesSchema(), usage, true))
+	r.Equal(`Unused indexes: 2
+  posts.posts_id_idx
+  tags.tags_name_idx
+Redundant indexes: 3
+  posts.posts_id_idx (id), covered by primary key
+  posts.posts_slug_key (slug), covered by posts_slug_idx
+  posts.posts_user_id_idx (user_id), covered by posts_user_id_created_at_idx
+`, out.String())
+
+	out.Reset()
+	r.NoError(writeIndexesReport(out, indexesSchema()[1:], nil, false))
+	r.Equal("Unused indexes: the database has no index usage statistics\nRedundant indexes: 0\n", out.String())
+}
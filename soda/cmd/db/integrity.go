@@ -0,0 +1,296 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/gobuffalo/flect"
+	"github.com/gobuffalo/flect/name"
+	"github.com/spf13/cobra"
+)
+
+var integrityOptions = struct {
+	models string
+}{}
+
+// IntegrityCmd checks the rows of the selected database against its
+// foreign keys and the associations of the models, and writes the problems
+// found as JSON.
+var IntegrityCmd = &cobra.Command{
+	Use:   "integrity",
+	Short: "Finds the orphaned rows, dangling references and stale counters of the selected database",
+	Long: `Finds the rows of the selected database referencing a missing row, through
+a foreign key, not enforced e.g. on SQLite without foreign_keys, or through
+the belongs_to, has_one and has_many associations of the models with no
+foreign key backing them.
+
+Polymorphic references are pairs of <name>_type and <name>_id columns, the
+type being the table, or the model, of the referenced row. Counter caches
+are <table>_count columns of the referenced tables, e.g. posts_count on users
+for posts.user_id.
+
+The report is written as JSON to the standard output.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env := cmd.Flag("env")
+		if env == nil {
+			return fmt.Errorf("env is required")
+		}
+		c, err := pop.Connect(env.Value.String())
+		if err != nil {
+			return err
+		}
+		schema, err := c.Schema()
+		if err != nil {
+			return err
+		}
+		var models []model
+		if _, err := os.Stat(integrityOptions.models); err == nil {
+			if models, err = parseModels(integrityOptions.models); err != nil {
+				return err
+			}
+		}
+		report, err := checkIntegrity(c, schema, models)
+		if err != nil {
+			return err
+		}
+		return writeIntegrityReport(cmd.OutOrStdout(), report)
+	},
+}
+
+func init() {
+	IntegrityCmd.Flags().StringVarP(&integrityOptions.models, "models", "m", "./models", "The path to the package of the models.")
+}
+
+// integrityReport is the report of the integrity check.
+type integrityReport struct {
+	Orphans     []orphanedRows    `json:"orphans"`
+	Polymorphic []danglingRows    `json:"polymorphic"`
+	Counters    []counterMismatch `json:"counters"`
+}
+
+// orphanedRows are the rows of a table whose column references a missing
+// row of another table.
+type orphanedRows struct {
+	Table     string `json:"table"`
+	Column    string `json:"column"`
+	RefTable  string `json:"ref_table"`
+	RefColumn string `json:"ref_column"`
+	// Source is foreign_key, or the tag of the association.
+	Source string `json:"source"`
+	Rows   int    `json:"rows"`
+}
+
+// danglingRows are the rows of a table whose polymorphic reference of the
+// given type is missing. RefTable is empty for the types with no table.
+type danglingRows struct {
+	Table      string `json:"table"`
+	TypeColumn string `json:"type_column"`
+	IDColumn   string `json:"id_column"`
+	Type       string `json:"type"`
+	RefTable   string `json:"ref_table"`
+	Rows       int    `json:"rows"`
+}
+
+// counterMismatch are the rows of a table whose counter cache differs from
+// the count of the rows referencing them.
+type counterMismatch struct {
+	Table       string `json:"table"`
+	Column      string `json:"column"`
+	ChildTable  string `json:"child_table"`
+	ChildColumn string `json:"child_column"`
+	Rows        int    `json:"rows"`
+}
+
+// integrityRelation is a single column reference of a table to another.
+type integrityRelation struct {
+	Table     string
+	Column    string
+	RefTable  string
+	RefColumn string
+	Source    string
+}
+
+// integrityRelations returns the single column foreign keys of the schema,
+// and the references of the associations of the models between tables of
+// the schema which no foreign key covers.
+func integrityRelations(schema []pop.SchemaTable, models []model) []integrityRelation {
+	tables := map[string]pop.SchemaTable{}
+	covered := map[[2]string]bool{}
+	var relations []integrityRelation
+	for _, t := range schema {
+		tables[t.Name] = t
+		for _, fk := range t.ForeignKeys {
+			if len(fk.Columns) != 1 || len(fk.RefColumns) != 1 {
+				continue
+			}
+			relations = append(relations, integrityRelation{Table: t.Name, Column: fk.Columns[0], RefTable: fk.RefTable, RefColumn: fk.RefColumns[0], Source: "foreign_key"})
+			covered[[2]string{t.Name, fk.Columns[0]}] = true
+		}
+	}
+
+	byName := map[string]model{}
+	for _, m := range models {
+		byName[m.Name] = m
+	}
+	tableOf := func(n string) string {
+		if m, ok := byName[n]; ok {
+			return m.Table
+		}
+		return name.Tableize(n)
+	}
+	for _, m := range models {
+		for _, f := range m.Fields {
+			for _, kind := range []string{"belongs_to", "has_one", "has_many"} {
+				if _, ok := f.Tag.Lookup(kind); !ok {
+					continue
+				}
+				target := strings.TrimLeft(f.Type, "[]*")
+				r := integrityRelation{RefColumn: "id", Source: kind}
+				fk := f.Tag.Get("fk_id")
+				if kind == "belongs_to" {
+					r.Table, r.RefTable = m.Table, tableOf(target)
+					r.Column = fieldColumn(m, defaultString(fk, f.Name+"ID"))
+				} else {
+					r.Table, r.RefTable = tableOf(target), m.Table
+					r.Column = defaultString(fk, flect.Underscore(m.Name)+"_id")
+					if owner, ok := byName[target]; ok {
+						r.Column = fieldColumn(owner, r.Column)
+					}
+				}
+				if covered[[2]string{r.Table, r.Column}] || !hasColumn(tables[r.Table], r.Column) || !hasColumn(tables[r.RefTable], r.RefColumn) {
+					continue
+				}
+				relations = append(relations, r)
+				covered[[2]string{r.Table, r.Column}] = true
+			}
+		}
+	}
+	return relations
+}
+
+// fieldColumn returns the column of the field of the model named n, or n
+// when there is none, fk_id naming either.
+func fieldColumn(m model, n string) string {
+	for _, f := range m.Fields {
+		if f.Name == n {
+			return f.Column()
+		}
+	}
+	return n
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+func hasColumn(t pop.SchemaTable, column string) bool {
+	for _, c := range t.Columns {
+		if c.Name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIntegrity counts the orphaned rows of the relations of the schema
+// and the models, the dangling polymorphic references and the stale
+// counter caches of the database.
+func checkIntegrity(c *pop.Connection, schema []pop.SchemaTable, models []model) (integrityReport, error) {
+	report := integrityReport{Orphans: []orphanedRows{}, Polymorphic: []danglingRows{}, Counters: []counterMismatch{}}
+	q := c.Dialect.Quote
+	tables := map[string]pop.SchemaTable{}
+	for _, t := range schema {
+		tables[t.Name] = t
+	}
+
+	for _, r := range integrityRelations(schema, models) {
+		n, err := c.RawQuery(fmt.Sprintf("SELECT c.* FROM %s AS c LEFT JOIN %s AS p ON p.%s = c.%s WHERE c.%s IS NOT NULL AND p.%s IS NULL",
+			q(r.Table), q(r.RefTable), q(r.RefColumn), q(r.Column), q(r.Column), q(r.RefColumn))).Count(nil)
+		if err != nil {
+			return report, fmt.Errorf("%s.%s: %w", r.Table, r.Column, err)
+		}
+		if n > 0 {
+			report.Orphans = append(report.Orphans, orphanedRows{Table: r.Table, Column: r.Column, RefTable: r.RefTable, RefColumn: r.RefColumn, Source: r.Source, Rows: n})
+		}
+
+		counter := r.Table + "_count"
+		if !hasColumn(tables[r.RefTable], counter) {
+			continue
+		}
+		n, err = c.RawQuery(fmt.Sprintf("SELECT p.* FROM %s AS p WHERE p.%s <> (SELECT COUNT(*) FROM %s AS c WHERE c.%s = p.%s)",
+			q(r.RefTable), q(counter), q(r.Table), q(r.Column), q(r.RefColumn))).Count(nil)
+		if err != nil {
+			return report, fmt.Errorf("%s.%s: %w", r.RefTable, counter, err)
+		}
+		if n > 0 {
+			report.Counters = append(report.Counters, counterMismatch{Table: r.RefTable, Column: counter, ChildTable: r.Table, ChildColumn: r.Column, Rows: n})
+		}
+	}
+
+	for _, t := range schema {
+		for _, col := range t.Columns {
+			if !strings.HasSuffix(col.Name, "_type") {
+				continue
+			}
+			idColumn := strings.TrimSuffix(col.Name, "_type") + "_id"
+			if !hasColumn(t, idColumn) {
+				continue
+			}
+			dangling, err := danglingReferences(c, tables, t.Name, col.Name, idColumn)
+			if err != nil {
+				return report, fmt.Errorf("%s.%s: %w", t.Name, col.Name, err)
+			}
+			report.Polymorphic = append(report.Polymorphic, dangling...)
+		}
+	}
+	return report, nil
+}
+
+// danglingReferences returns, for each type of the polymorphic reference
+// of the table, the rows referencing a missing row.
+func danglingReferences(c *pop.Connection, tables map[string]pop.SchemaTable, table, typeColumn, idColumn string) ([]danglingRows, error) {
+	q := c.Dialect.Quote
+	var types []string
+	if err := c.RawQuery(fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s IS NOT NULL", q(typeColumn), q(table), q(typeColumn))).All(&types); err != nil {
+		return nil, err
+	}
+	sort.Strings(types)
+
+	var dangling []danglingRows
+	for _, typ := range types {
+		refTable := typ
+		if _, ok := tables[refTable]; !ok {
+			refTable = name.Tableize(typ)
+		}
+		var n int
+		var err error
+		if hasColumn(tables[refTable], "id") {
+			n, err = c.RawQuery(fmt.Sprintf("SELECT c.* FROM %s AS c LEFT JOIN %s AS p ON p.%s = c.%s WHERE c.%s = ? AND c.%s IS NOT NULL AND p.%s IS NULL",
+				q(table), q(refTable), q("id"), q(idColumn), q(typeColumn), q(idColumn), q("id")), typ).Count(nil)
+		} else {
+			refTable = ""
+			n, err = c.RawQuery(fmt.Sprintf("SELECT * FROM %s WHERE %s = ? AND %s IS NOT NULL", q(table), q(typeColumn), q(idColumn)), typ).Count(nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			dangling = append(dangling, danglingRows{Table: table, TypeColumn: typeColumn, IDColumn: idColumn, Type: typ, RefTable: refTable, Rows: n})
+		}
+	}
+	return dangling, nil
+}
+
+func writeIntegrityReport(w io.Writer, report integrityReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
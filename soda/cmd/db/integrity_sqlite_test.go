@@ -0,0 +1,56 @@
+//go:build sqlite
+// +build sqlite
+
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkIntegrity(t *testing.T) {
+	r := require.New(t)
+
+	c, err := pop.NewConnection(&pop.ConnectionDetails{
+		URL: "sqlite://" + filepath.Join(t.TempDir(), "integrity.db") + "?_fk=false",
+	})
+	r.NoError(err)
+	r.NoError(c.Open())
+	defer c.Close()
+	for _, stmt := range []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, posts_count INTEGER NOT NULL DEFAULT 0)",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users (id))",
+		"CREATE TABLE comments (id INTEGER PRIMARY KEY, commentable_type TEXT, commentable_id INTEGER)",
+		"INSERT INTO users (id, posts_count) VALUES (1, 1), (2, 3)",
+		"INSERT INTO posts (id, user_id) VALUES (1, 1), (2, 2), (3, 9), (4, NULL)",
+		"INSERT INTO comments (id, commentable_type, commentable_id) VALUES (1, 'Post', 1), (2, 'Post', 7), (3, 'users', 5), (4, 'Photo', 1)",
+	} {
+		r.NoError(c.RawQuery(stmt).Exec())
+	}
+	schema, err := c.Schema()
+	r.NoError(err)
+
+	report, err := checkIntegrity(c, schema, nil)
+	r.NoError(err)
+	r.Equal(integrityReport{
+		Orphans: []orphanedRows{{Table: "posts", Column: "user_id", RefTable: "users", RefColumn: "id", Source: "foreign_key", Rows: 1}},
+		Polymorphic: []danglingRows{
+			{Table: "comments", TypeColumn: "commentable_type", IDColumn: "commentable_id", Type: "Photo", Rows: 1},
+			{Table: "comments", TypeColumn: "commentable_type", IDColumn: "commentable_id", Type: "Post", RefTable: "posts", Rows: 1},
+			{Table: "comments", TypeColumn: "commentable_type", IDColumn: "commentable_id", Type: "users", RefTable: "users", Rows: 1},
+		},
+		Counters: []counterMismatch{{Table: "users", Column: "posts_count", ChildTable: "posts", ChildColumn: "user_id", Rows: 1}},
+	}, report)
+
+	var buf bytes.Buffer
+	r.NoError(writeIntegrityReport(&buf, report))
+	var decoded map[string][]map[string]interface{}
+	r.NoError(json.Unmarshal(buf.Bytes(), &decoded))
+	r.Equal("posts", decoded["orphans"][0]["table"])
+	r.EqualValues(1, decoded["counters"][0]["rows"])
+}
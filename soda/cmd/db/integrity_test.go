@@ -0,0 +1,55 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+const integrityModelsSource = `package models
+
+type User struct {
+	ID       int       ` + "`db:\"id\"`" + `
+	Posts    []Post    ` + "`has_many:\"posts\"`" + `
+	Profile  *Profile  ` + "`has_one:\"profile\" fk_id:\"OwnerID\"`" + `
+}
+
+type Post struct {
+	ID       int   ` + "`db:\"id\"`" + `
+	AuthorID int   ` + "`db:\"author\"`" + `
+	Author   *User ` + "`belongs_to:\"user\" fk_id:\"AuthorID\"`" + `
+}
+
+type Profile struct {
+	ID      int ` + "`db:\"id\"`" + `
+	OwnerID int ` + "`db:\"owner_id\"`" + `
+}
+`
+
+func Test_integrityRelations(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	r.NoError(os.WriteFile(filepath.Join(dir, "models.go"), []byte(integrityModelsSource), 0644))
+	models, err := parseModels(dir)
+	r.NoError(err)
+
+	id := pop.SchemaColumn{Name: "id", Type: "integer", PrimaryKey: true}
+	schema := []pop.SchemaTable{
+		{Name: "users", Columns: []pop.SchemaColumn{id}},
+		{
+			Name:        "posts",
+			Columns:     []pop.SchemaColumn{id, {Name: "author", Type: "integer"}, {Name: "user_id", Type: "integer"}},
+			ForeignKeys: []pop.SchemaForeignKey{{Name: "posts_author_fkey", Columns: []string{"author"}, RefTable: "users", RefColumns: []string{"id"}}},
+		},
+		{Name: "profiles", Columns: []pop.SchemaColumn{id, {Name: "owner_id", Type: "integer"}}},
+	}
+	r.Equal([]integrityRelation{
+		{Table: "posts", Column: "author", RefTable: "users", RefColumn: "id", Source: "foreign_key"},
+		{Table: "posts", Column: "user_id", RefTable: "users", RefColumn: "id", Source: "has_many"},
+		{Table: "profiles", Column: "owner_id", RefTable: "users", RefColumn: "id", Source: "has_one"},
+	}, integrityRelations(schema, models))
+}
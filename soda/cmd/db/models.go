@@ -0,0 +1,147 @@
+package db
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gobuffalo/flect/name"
+)
+
+// model is a struct of the package of the models, parsed from its source.
+type model struct {
+	Name   string
+	Table  string
+	Fields []modelField
+}
+
+// modelField is a named field of a model.
+type modelField struct {
+	Name string
+	// Type is the type of the field as written in the source, e.g.
+	// []Comment or *User.
+	Type string
+	Tag  reflect.StructTag
+}
+
+// Column returns the column of the field, "-" for the ones not mapped.
+func (f modelField) Column() string {
+	column := strings.Split(f.Tag.Get("db"), ",")[0]
+	if column == "" {
+		column = strings.ToLower(f.Name)
+	}
+	return column
+}
+
+// parseModels parses the Go files of the package of the models in dir and
+// returns its structs, in the order of the files. Their tables are the
+// tableized names of the structs, or the literals returned by their
+// TableName method.
+func parseModels(dir string) ([]model, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []model
+	for _, pkg := range pkgs {
+		tables := tableNames(pkg)
+		for _, f := range sortedFiles(pkg) {
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					st, ok := ts.Type.(*ast.StructType)
+					if !ok {
+						continue
+					}
+					table, ok := tables[ts.Name.Name]
+					if !ok {
+						table = name.Tableize(ts.Name.Name)
+					}
+					models = append(models, model{Name: ts.Name.Name, Table: table, Fields: structFields(st)})
+				}
+			}
+		}
+	}
+	return models, nil
+}
+
+// structFields returns the named fields of the struct.
+func structFields(st *ast.StructType) []modelField {
+	var fields []modelField
+	for _, field := range st.Fields.List {
+		var tag reflect.StructTag
+		if field.Tag != nil {
+			s, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				continue
+			}
+			tag = reflect.StructTag(s)
+		}
+		for _, n := range field.Names {
+			fields = append(fields, modelField{Name: n.Name, Type: types.ExprString(field.Type), Tag: tag})
+		}
+	}
+	return fields
+}
+
+// tableNames returns the table names returned as literals by the TableName
+// methods of the models of the package.
+func tableNames(pkg *ast.Package) map[string]string {
+	tables := map[string]string{}
+	for _, f := range pkg.Files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Name.Name != "TableName" || fd.Recv == nil || fd.Body == nil || len(fd.Body.List) != 1 {
+				continue
+			}
+			ret, ok := fd.Body.List[0].(*ast.ReturnStmt)
+			if !ok || len(ret.Results) != 1 {
+				continue
+			}
+			lit, ok := ret.Results[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				continue
+			}
+			table, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				continue
+			}
+			recv := fd.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if id, ok := recv.(*ast.Ident); ok {
+				tables[id.Name] = table
+			}
+		}
+	}
+	return tables
+}
+
+// sortedFiles returns the files of the package sorted by name.
+func sortedFiles(pkg *ast.Package) []*ast.File {
+	names := make([]string, 0, len(pkg.Files))
+	for n := range pkg.Files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	files := make([]*ast.File, len(names))
+	for i, n := range names {
+		files[i] = pkg.Files[n]
+	}
+	return files
+}
@@ -0,0 +1,229 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/gobuffalo/flect/name"
+	"github.com/gobuffalo/genny/v2"
+	"github.com/spf13/cobra"
+)
+
+var nowFunc = time.Now
+
+var syncDefaultsOptions = struct {
+	models string
+}{}
+
+// SyncDefaultsCmd compares the default tags of the models with the defaults
+// of their columns, and generates the SQL migration aligning the database
+// with the models.
+var SyncDefaultsCmd = &cobra.Command{
+	Use:   "sync-defaults [name]",
+	Short: "Generates a migration aligning the column defaults with the default tags of the models",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n := "sync_column_defaults"
+		if len(args) > 0 {
+			n = args[0]
+		}
+		env := cmd.Flag("env")
+		if env == nil {
+			return errors.New("env is required")
+		}
+		path := "migrations"
+		if p := cmd.Flag("path"); p != nil {
+			path = p.Value.String()
+		}
+
+		c, err := pop.Connect(env.Value.String())
+		if err != nil {
+			return err
+		}
+		defs, err := modelDefaults(syncDefaultsOptions.models)
+		if err != nil {
+			return err
+		}
+		drifts, err := defaultsDrift(c, defs)
+		if err != nil {
+			return err
+		}
+		if len(drifts) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "The column defaults are in sync with the models.")
+			return nil
+		}
+		for _, d := range drifts {
+			fmt.Fprintf(cmd.OutOrStdout(), "%s.%s: %s in %s, %s in the database\n", d.Table, d.Column, d.Default, d.Model, showDefault(d.Database))
+		}
+
+		translator, ok := c.Dialect.FizzTranslator().(interface{ Name() string })
+		if !ok {
+			return errors.New("invalid fizz translator")
+		}
+		g, err := syncDefaultsMigration(c, drifts, path, n, translator.Name())
+		if err != nil {
+			return err
+		}
+		run := genny.WetRunner(context.Background())
+		run.With(g)
+		return run.Run()
+	},
+}
+
+func init() {
+	SyncDefaultsCmd.Flags().StringVarP(&syncDefaultsOptions.models, "models", "m", "./models", "The path to the package of the models.")
+}
+
+// modelDefault is the default of a column, set by the default tag of the
+// field of a model:
+//
+//	Status string `db:"status" default:"'draft'"`
+//
+// The default is the SQL expression of the column default, "NULL" for none.
+type modelDefault struct {
+	Model   string
+	Table   string
+	Column  string
+	Default string
+}
+
+// modelDefaults returns the defaults of the fields of the models in dir.
+func modelDefaults(dir string) ([]modelDefault, error) {
+	models, err := parseModels(dir)
+	if err != nil {
+		return nil, err
+	}
+	var defs []modelDefault
+	for _, m := range models {
+		for _, f := range m.Fields {
+			def, ok := f.Tag.Lookup("default")
+			if !ok || f.Column() == "-" {
+				continue
+			}
+			defs = append(defs, modelDefault{Model: m.Name, Table: m.Table, Column: f.Column(), Default: def})
+		}
+	}
+	return defs, nil
+}
+
+// drift is a column whose default in the database is not the one of the
+// default tag of its model.
+type drift struct {
+	modelDefault
+	// Database is the default in the database, nil for none.
+	Database *string
+}
+
+// defaultsDrift returns the columns whose default in the database differs
+// from the default tag of their model. The columns not in the database yet
+// are left to the migrations creating them.
+func defaultsDrift(c *pop.Connection, defs []modelDefault) ([]drift, error) {
+	tables := map[string]map[string]*string{}
+	var drifts []drift
+	for _, d := range defs {
+		cols, ok := tables[d.Table]
+		if !ok {
+			var err error
+			cols, err = c.ColumnDefaults(d.Table)
+			if err != nil {
+				return nil, fmt.Errorf("could not read the column defaults of %s: %w", d.Table, err)
+			}
+			tables[d.Table] = cols
+		}
+		def, ok := cols[strings.ToLower(d.Column)]
+		if !ok {
+			continue
+		}
+		if normalizeDefault(d.Default) != normalizeDefault(showDefault(def)) {
+			drifts = append(drifts, drift{modelDefault: d, Database: def})
+		}
+	}
+	return drifts, nil
+}
+
+// syncDefaultsMigration returns the generator of the SQL migration setting
+// the defaults of the models up, and the ones of the database back down.
+func syncDefaultsMigration(c *pop.Connection, drifts []drift, path, n, translator string) (*genny.Generator, error) {
+	var up, down []string
+	for _, d := range drifts {
+		var def *string
+		if normalizeDefault(d.Default) != "" {
+			def = &d.Default
+		}
+		stmt, err := c.AlterColumnDefaultSQL(d.Table, d.Column, def)
+		if err != nil {
+			return nil, err
+		}
+		up = append(up, stmt+";")
+		stmt, err = c.AlterColumnDefaultSQL(d.Table, d.Column, d.Database)
+		if err != nil {
+			return nil, err
+		}
+		down = append(down, stmt+";")
+	}
+
+	n = fmt.Sprintf("%s_%s", nowFunc().UTC().Format("20060102150405"), name.New(n).Underscore())
+	g := genny.New()
+	g.File(genny.NewFileS(filepath.Join(path, fmt.Sprintf("%s.%s.up.sql", n, translator)), strings.Join(up, "\n")+"\n"))
+	g.File(genny.NewFileS(filepath.Join(path, fmt.Sprintf("%s.%s.down.sql", n, translator)), strings.Join(down, "\n")+"\n"))
+	return g, nil
+}
+
+// normalizeDefault returns the comparable form of the SQL expression of a
+// default: without the parentheses around it, lowercased outside of the
+// string literals, and empty for NULL.
+func normalizeDefault(def string) string {
+	def = strings.TrimSpace(def)
+	for strings.HasPrefix(def, "(") && strings.HasSuffix(def, ")") && balanced(def[1:len(def)-1]) {
+		def = strings.TrimSpace(def[1 : len(def)-1])
+	}
+	if strings.EqualFold(def, "NULL") {
+		return ""
+	}
+	var b strings.Builder
+	quoted := false
+	for _, r := range def {
+		if r == '\'' {
+			quoted = !quoted
+		}
+		if !quoted {
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// balanced reports whether the parentheses of the expression are balanced,
+// outside of its string literals.
+func balanced(expr string) bool {
+	depth := 0
+	quoted := false
+	for _, r := range expr {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+		case quoted:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+func showDefault(def *string) string {
+	if def == nil {
+		return "NULL"
+	}
+	return *def
+}
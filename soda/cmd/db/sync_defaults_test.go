@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/gobuffalo/genny/v2"
+	"github.com/stretchr/testify/require"
+)
+
+const modelsSource = `package models
+
+type Post struct {
+	ID     int    ` + "`db:\"id\"`" + `
+	Status string ` + "`db:\"status\" default:\"'draft'\"`" + `
+	Views  int    ` + "`db:\"views\" default:\"0\"`" + `
+	Notes  string ` + "`db:\"-\" default:\"'x'\"`" + `
+}
+
+type Account struct {
+	Plan string ` + "`default:\"'free'\"`" + `
+}
+
+func (Account) TableName() string {
+	return "billing_accounts"
+}
+`
+
+func Test_modelDefaults(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	r.NoError(os.WriteFile(filepath.Join(dir, "models.go"), []byte(modelsSource), 0644))
+	r.NoError(os.WriteFile(filepath.Join(dir, "models_test.go"), []byte("package models\n\ntype Fixture struct {\n\tName string `default:\"'x'`\n}\n"), 0644))
+
+	defs, err := modelDefaults(dir)
+	r.NoError(err)
+	r.Equal([]modelDefault{
+		{Model: "Post", Table: "posts", Column: "status", Default: "'draft'"},
+		{Model: "Post", Table: "posts", Column: "views", Default: "0"},
+		{Model: "Account", Table: "billing_accounts", Column: "plan", Default: "'free'"},
+	}, defs)
+}
+
+func Test_normalizeDefault(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("current_timestamp", normalizeDefault("CURRENT_TIMESTAMP"))
+	r.Equal("'Draft'", normalizeDefault(" ('Draft') "))
+	r.Equal("gen_random_uuid()", normalizeDefault("(gen_random_uuid())"))
+	r.Equal("(1) + (2)", normalizeDefault("(1) + (2)"))
+	r.Equal("", normalizeDefault("NULL"))
+	r.Equal("", normalizeDefault(""))
+}
+
+func Test_syncDefaultsMigration(t *testing.T) {
+	r := require.New(t)
+
+	nowFunc = func() time.Time {
+		return time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	}
+	defer func() { nowFunc = time.Now }()
+
+	c, err := pop.NewConnection(&pop.ConnectionDetails{Dialect: "postgres", Database: "pop_test"})
+	r.NoError(err)
+
+	status, views := "'published'", "0"
+	drifts := []drift{
+		{modelDefault: modelDefault{Model: "Post", Table: "posts", Column: "status", Default: "'draft'"}, Database: &status},
+		{modelDefault: modelDefault{Model: "Post", Table: "posts", Column: "views", Default: "NULL"}, Database: &views},
+	}
+	g, err := syncDefaultsMigration(c, drifts, "migrations", "sync defaults", "postgres")
+	r.NoError(err)
+
+	run := genny.DryRunner(context.Background())
+	run.With(g)
+	r.NoError(run.Run())
+
+	res := run.Results()
+	r.Len(res.Files, 2)
+	r.Equal(filepath.Join("migrations", "20240506070809_sync_defaults.postgres.down.sql"), res.Files[0].Name())
+	r.Equal("ALTER TABLE \"posts\" ALTER COLUMN \"status\" SET DEFAULT 'published';\nALTER TABLE \"posts\" ALTER COLUMN \"views\" SET DEFAULT 0;\n", res.Files[0].String())
+	r.Equal(filepath.Join("migrations", "20240506070809_sync_defaults.postgres.up.sql"), res.Files[1].Name())
+	r.Equal("ALTER TABLE \"posts\" ALTER COLUMN \"status\" SET DEFAULT 'draft';\nALTER TABLE \"posts\" ALTER COLUMN \"views\" DROP DEFAULT;\n", res.Files[1].String())
+}
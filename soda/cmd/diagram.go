@@ -0,0 +1,9 @@
+package cmd
+
+import (
+	"github.com/WilliamNHarvey/pop/v6/soda/cmd/db"
+)
+
+func init() {
+	RootCmd.AddCommand(db.DiagramCmd)
+}
@@ -2,12 +2,14 @@ package generate
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 
 	"github.com/WilliamNHarvey/pop/v6"
 	"github.com/WilliamNHarvey/pop/v6/genny/fizz/ctable"
 	gmodel "github.com/WilliamNHarvey/pop/v6/genny/model"
+	"github.com/WilliamNHarvey/pop/v6/schema"
 	"github.com/gobuffalo/attrs"
 	"github.com/gobuffalo/fizz"
 	"github.com/gobuffalo/genny/v2"
@@ -21,6 +23,7 @@ var modelCmdConfig struct {
 	StructTag     string
 	MigrationType string
 	ModelPath     string
+	FromTable     string
 }
 
 func init() {
@@ -28,6 +31,7 @@ func init() {
 	ModelCmd.Flags().StringVarP(&modelCmdConfig.MigrationType, "migration-type", "", "fizz", "sets the type of migration files for model (sql or fizz)")
 	ModelCmd.Flags().BoolVarP(&modelCmdConfig.SkipMigration, "skip-migration", "s", false, "Skip creating a new fizz migration for this model.")
 	ModelCmd.Flags().StringVarP(&modelCmdConfig.ModelPath, "models-path", "", "models", "the path the model will be created in")
+	ModelCmd.Flags().StringVarP(&modelCmdConfig.FromTable, "from-table", "", "", "generates the model of an existing table of the database, without migration")
 }
 
 // ModelCmd is the cmd to generate a model
@@ -52,6 +56,20 @@ var ModelCmd = &cobra.Command{
 			}
 		}
 
+		opts := &gmodel.Options{
+			Name:                   name,
+			Attrs:                  atts,
+			Path:                   modelCmdConfig.ModelPath,
+			Encoding:               modelCmdConfig.StructTag,
+			ForceDefaultID:         true,
+			ForceDefaultTimestamps: true,
+		}
+		if modelCmdConfig.FromTable != "" {
+			if err := fromTable(cmd, opts); err != nil {
+				return err
+			}
+		}
+
 		run := genny.WetRunner(context.Background())
 
 		// Ensure the generator is as verbose as the old one.
@@ -59,14 +77,7 @@ var ModelCmd = &cobra.Command{
 		run.Logger = lg
 
 		// Mount models generator
-		g, err := gmodel.New(&gmodel.Options{
-			Name:                   name,
-			Attrs:                  atts,
-			Path:                   modelCmdConfig.ModelPath,
-			Encoding:               modelCmdConfig.StructTag,
-			ForceDefaultID:         true,
-			ForceDefaultTimestamps: true,
-		})
+		g, err := gmodel.New(opts)
 		if err != nil {
 			return err
 		}
@@ -88,7 +99,7 @@ var ModelCmd = &cobra.Command{
 		}
 
 		// Mount migrations generator
-		if !modelCmdConfig.SkipMigration {
+		if !modelCmdConfig.SkipMigration && modelCmdConfig.FromTable == "" {
 			p := cmd.Flag("path")
 			path := ""
 			if p != nil {
@@ -122,3 +133,36 @@ var ModelCmd = &cobra.Command{
 		return run.Run()
 	},
 }
+
+// fromTable sets the attributes and the associations of the model to the
+// ones of the table given with --from-table, read from the database.
+func fromTable(cmd *cobra.Command, opts *gmodel.Options) error {
+	if opts.Name == "" {
+		opts.Name = modelCmdConfig.FromTable
+	}
+	if len(opts.Attrs) > 0 {
+		return errors.New("the attributes of the model are read from the table")
+	}
+
+	env := ""
+	if e := cmd.Flag("env"); e != nil {
+		env = e.Value.String()
+	}
+	c, err := pop.Connect(env)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	t, err := schema.Of(c).Table(modelCmdConfig.FromTable)
+	if err != nil {
+		return err
+	}
+
+	opts.Attrs, opts.Associations, err = gmodel.FromTable(t)
+	if err != nil {
+		return err
+	}
+	opts.ForceDefaultID = false
+	opts.ForceDefaultTimestamps = false
+	return nil
+}
@@ -0,0 +1,49 @@
+//go:build sqlite
+// +build sqlite
+
+package generate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ModelCmd_FromTable(t *testing.T) {
+	r := require.New(t)
+
+	tdir := t.TempDir()
+	c, err := pop.NewConnection(&pop.ConnectionDetails{
+		URL: "sqlite://" + filepath.Join(tdir, "models.db") + "?_fk=true",
+	})
+	r.NoError(err)
+	r.NoError(c.Open())
+	r.NoError(c.RawQuery("CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL)").Exec())
+	r.NoError(c.RawQuery("CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id), title TEXT)").Exec())
+
+	connections := pop.Connections
+	pop.Connections = map[string]*pop.Connection{"development": c}
+	defer func() {
+		pop.Connections = connections
+	}()
+
+	pwd, err := os.Getwd()
+	r.NoError(err)
+	os.Chdir(tdir)
+	defer os.Chdir(pwd)
+
+	cmd := ModelCmd
+	cmd.SetArgs([]string{"--from-table", "posts"})
+	defer cmd.Flags().Set("from-table", "")
+	r.NoError(cmd.Execute())
+
+	r.NoDirExists(filepath.Join(tdir, "migrations"))
+	b, err := os.ReadFile(filepath.Join(tdir, "models", "post.go"))
+	r.NoError(err)
+	r.Contains(string(b), "UserID int          `json:\"user_id\" db:\"user_id\"`")
+	r.Contains(string(b), "Title  nulls.String `json:\"title\" db:\"title\"`")
+	r.Contains(string(b), "User   *User        `json:\"user,omitempty\" belongs_to:\"user\"`")
+}
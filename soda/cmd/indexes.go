@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/WilliamNHarvey/pop/v6/soda/cmd/db"
+	"github.com/spf13/cobra"
+)
+
+// indexesCmd represents the indexes command
+var indexesCmd = &cobra.Command{
+	Use:   "indexes",
+	Short: "Tools for reviewing the indexes of your database",
+}
+
+func init() {
+	indexesCmd.AddCommand(db.IndexesReportCmd)
+	RootCmd.AddCommand(indexesCmd)
+}
@@ -1,25 +1,40 @@
 package cmd
 
 import (
+	"errors"
+
 	"github.com/WilliamNHarvey/pop/v6"
 	"github.com/spf13/cobra"
 )
 
 var migrationStepDown int
+var migrationDownTo string
 
 var migrateDownCmd = &cobra.Command{
 	Use:   "down",
 	Short: "Apply one or more of the 'down' migrations.",
+	Long: `Rolls back the last applied migration, the last --steps ones, or the ones
+applied after the version given to --to, 0 rolling back all of them.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if cmd.Flags().Changed("to") && (cmd.Flags().Changed("steps") || cmd.Flags().Changed("step")) {
+			return errors.New("--to and --steps can not be used together")
+		}
 		mig, err := pop.NewFileMigrator(migrationPath, getConn())
 		if err != nil {
 			return err
 		}
+		if migrationDownTo != "" {
+			_, err = mig.DownTo(migrationDownTo)
+			return err
+		}
 		return mig.Down(migrationStepDown)
 	},
 }
 
 func init() {
 	migrateCmd.AddCommand(migrateDownCmd)
-	migrateDownCmd.Flags().IntVarP(&migrationStepDown, "step", "s", 1, "Number of migration to down")
+	migrateDownCmd.Flags().IntVarP(&migrationStepDown, "steps", "s", 1, "Number of migrations to roll back. Use 0 to roll back all.")
+	migrateDownCmd.Flags().IntVar(&migrationStepDown, "step", 1, "Number of migrations to roll back.")
+	migrateDownCmd.Flags().MarkDeprecated("step", "use --steps instead")
+	migrateDownCmd.Flags().StringVar(&migrationDownTo, "to", "", "Version to roll back to, the migrations applied after it are rolled back. Use 0 to roll back all.")
 }
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/spf13/cobra"
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:   "sandbox",
+	Short: "Runs SQL statements in a transaction rolled back on exit",
+	Long: `Runs the SQL statements read from the standard input, each ended by a
+semicolon, in a transaction which is rolled back on exit, so the data can be
+explored and changed safely. Type \q or exit, or send EOF, to leave.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := getConn()
+		if err := c.Open(); err != nil {
+			return err
+		}
+		var err error
+		rerr := c.Rollback(func(tx *pop.Connection) {
+			err = runSandbox(tx, cmd.InOrStdin(), cmd.OutOrStdout())
+		})
+		if err != nil {
+			return err
+		}
+		if rerr != nil {
+			return rerr
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "Rolled back all the changes.")
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(sandboxCmd)
+}
+
+// sandboxQuery matches the statements returning rows.
+var sandboxQuery = regexp.MustCompile(`(?is)^\s*(SELECT|WITH|SHOW|EXPLAIN|PRAGMA|VALUES|DESCRIBE|TABLE)\b|\bRETURNING\b`)
+
+// runSandbox runs the statements read from in, in the transaction tx, and
+// writes their results to out.
+func runSandbox(tx *pop.Connection, in io.Reader, out io.Writer) error {
+	// PostgreSQL and CockroachDB abort the transaction on the first error,
+	// the statements are run in a savepoint to keep it usable.
+	savepoints := false
+	switch tx.Dialect.Name() {
+	case "postgres", "cockroach":
+		savepoints = true
+	}
+
+	fmt.Fprintf(out, "sandbox mode, all the changes to %s will be rolled back on exit\n", tx.Dialect.Details().Database)
+	scanner := bufio.NewScanner(in)
+	var stmt strings.Builder
+	for {
+		if stmt.Len() == 0 {
+			fmt.Fprint(out, "sandbox> ")
+		} else {
+			fmt.Fprint(out, "     ... ")
+		}
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if stmt.Len() == 0 && (line == `\q` || line == "exit" || line == "quit") {
+			return nil
+		}
+		if line == "" {
+			continue
+		}
+		stmt.WriteString(line)
+		stmt.WriteString("\n")
+		if !strings.HasSuffix(line, ";") {
+			continue
+		}
+
+		query := strings.TrimSuffix(strings.TrimSpace(stmt.String()), ";")
+		stmt.Reset()
+		if err := sandboxStatement(tx, query, savepoints, out); err != nil {
+			fmt.Fprintf(out, "error: %s\n", err)
+		}
+	}
+}
+
+// sandboxStatement runs a statement of the sandbox, in a savepoint if
+// needed, and writes its result to out.
+func sandboxStatement(tx *pop.Connection, query string, savepoint bool, out io.Writer) error {
+	if savepoint {
+		if _, err := tx.Store.Exec("SAVEPOINT soda_sandbox"); err != nil {
+			return err
+		}
+	}
+	err := sandboxRun(tx, query, out)
+	if savepoint {
+		release := "RELEASE SAVEPOINT soda_sandbox"
+		if err != nil {
+			release = "ROLLBACK TO SAVEPOINT soda_sandbox"
+		}
+		if _, serr := tx.Store.Exec(release); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	return err
+}
+
+func sandboxRun(tx *pop.Connection, query string, out io.Writer) error {
+	if !sandboxQuery.MatchString(query) {
+		res, err := tx.Store.Exec(query)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			fmt.Fprintf(out, "%d row(s) affected\n", n)
+		}
+		return nil
+	}
+
+	rows, err := tx.Store.QueryxContext(tx.Context(), query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(cols, "\t"))
+	n := 0
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return err
+		}
+		cells := make([]string, len(values))
+		for i, v := range values {
+			switch x := v.(type) {
+			case nil:
+				cells[i] = "NULL"
+			case []byte:
+				cells[i] = string(x)
+			default:
+				cells[i] = fmt.Sprint(x)
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "(%d row(s))\n", n)
+	return nil
+}
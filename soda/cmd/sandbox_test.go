@@ -0,0 +1,49 @@
+//go:build sqlite
+// +build sqlite
+
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_runSandbox(t *testing.T) {
+	r := require.New(t)
+
+	c, err := pop.NewConnection(&pop.ConnectionDetails{
+		URL: "sqlite://" + filepath.Join(t.TempDir(), "sandbox.db"),
+	})
+	r.NoError(err)
+	r.NoError(c.Open())
+	defer c.Close()
+	r.NoError(c.RawQuery("CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT)").Exec())
+
+	in := strings.NewReader(`INSERT INTO widgets (name)
+VALUES ('a'), ('b');
+SELECT id, name FROM widgets ORDER BY id;
+SELECT * FROM missing;
+\q
+SELECT 1;
+`)
+	out := &bytes.Buffer{}
+	r.NoError(c.Rollback(func(tx *pop.Connection) {
+		r.NoError(runSandbox(tx, in, out))
+	}))
+
+	s := out.String()
+	r.Contains(s, "2 row(s) affected")
+	r.Contains(s, "id  name\n1   a\n2   b\n(2 row(s))")
+	r.Contains(s, "error: ")
+	r.Equal(1, strings.Count(s, "(2 row(s))"))
+	r.NotContains(s, "(1 row(s))")
+
+	var count int
+	r.NoError(c.RawQuery("SELECT COUNT(*) FROM widgets").First(&count))
+	r.Equal(0, count)
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var seedPath string
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Applies the seeds of your database.",
+}
+
+func init() {
+	RootCmd.AddCommand(seedCmd)
+	seedCmd.PersistentFlags().StringVarP(&seedPath, "seeds", "", "./seeds", "Path to the seeds folder")
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/WilliamNHarvey/pop/v6/seed"
+	"github.com/spf13/cobra"
+)
+
+var seedRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Applies the seeds not applied yet.",
+	Long: `Applies the YAML and JSON seeds of the seeds folder not applied yet, in the
+order of their dependencies, in a single transaction. The seeds applied are
+recorded in the schema_seed table.
+
+The Go seeds, registered with seed.Register, are applied by the binary they
+are compiled in, with seed.NewFileSeeder.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := seed.NewFileSeeder(seedPath, getConn())
+		if err != nil {
+			return err
+		}
+		ran, err := s.Run()
+		if err != nil {
+			return err
+		}
+		for _, name := range ran {
+			fmt.Fprintf(cmd.OutOrStdout(), "applied seed %s\n", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	seedCmd.AddCommand(seedRunCmd)
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/spf13/cobra"
+)
+
+var verifyOptions = struct {
+	counts    bool
+	checksums bool
+	step      int
+	allow     []string
+}{}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Applies the 'up' migrations and reports the unexpected changes of the data",
+	Long: `Records the row counts, and with --checksums the checksums of the rows, of
+the tables before and after applying the pending 'up' migrations, and reports
+the tables whose data changed, as a quick data loss check of risky schema
+changes. The tables expected to change are listed with --allow. The command
+fails when the data of the other ones changed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !verifyOptions.counts && !verifyOptions.checksums {
+			return errors.New("nothing to verify, use --counts and/or --checksums")
+		}
+		c := getConn()
+		if err := c.Open(); err != nil {
+			return err
+		}
+		mig, err := pop.NewFileMigrator(migrationPath, c)
+		if err != nil {
+			return err
+		}
+		return verifyMigration(c, func() error {
+			_, err := mig.UpTo(verifyOptions.step)
+			return err
+		}, verifyOptions.checksums, verifyOptions.allow, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().BoolVar(&verifyOptions.counts, "counts", false, "Compare the row counts of the tables")
+	verifyCmd.Flags().BoolVar(&verifyOptions.checksums, "checksums", false, "Compare the checksums of the rows of the tables whose columns did not change, as well as their counts")
+	verifyCmd.Flags().IntVarP(&verifyOptions.step, "step", "s", 0, "Number of migrations to apply. Use 0 to apply all pending.")
+	verifyCmd.Flags().StringSliceVar(&verifyOptions.allow, "allow", nil, "Tables whose data is expected to change")
+}
+
+// tableSnapshot is the state of the data of a table.
+type tableSnapshot struct {
+	rows     int64
+	columns  string
+	checksum uint64
+}
+
+// verifyMigration runs migrate between two snapshots of the tables of c,
+// writes their differences to out, and fails if the data of tables not
+// allowed to change did.
+func verifyMigration(c *pop.Connection, migrate func() error, checksums bool, allow []string, out io.Writer) error {
+	before, err := snapshotTables(c, checksums)
+	if err != nil {
+		return err
+	}
+	if err := migrate(); err != nil {
+		return err
+	}
+	after, err := snapshotTables(c, checksums)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	unexpected := 0
+	for _, name := range names {
+		b, hadTable := before[name]
+		a, hasTable := after[name]
+		var change string
+		switch {
+		case !hadTable:
+			fmt.Fprintf(out, "%s: created, %d row(s)\n", name, a.rows)
+			continue
+		case !hasTable:
+			change = fmt.Sprintf("dropped, had %d row(s)", b.rows)
+		case a.rows != b.rows:
+			change = fmt.Sprintf("%d row(s) -> %d row(s)", b.rows, a.rows)
+		case checksums && a.columns == b.columns && a.checksum != b.checksum:
+			change = "rows changed"
+		default:
+			continue
+		}
+		if containsTable(allow, name) {
+			fmt.Fprintf(out, "%s: %s (allowed)\n", name, change)
+			continue
+		}
+		fmt.Fprintf(out, "%s: %s (unexpected)\n", name, change)
+		unexpected++
+	}
+
+	if unexpected > 0 {
+		return fmt.Errorf("the data of %d table(s) changed unexpectedly", unexpected)
+	}
+	fmt.Fprintln(out, "No unexpected change of the data.")
+	return nil
+}
+
+// snapshotTables returns the snapshots of the tables of c, by name.
+func snapshotTables(c *pop.Connection, checksums bool) (map[string]tableSnapshot, error) {
+	schema, err := c.Schema()
+	if err != nil {
+		return nil, err
+	}
+	snapshots := make(map[string]tableSnapshot, len(schema))
+	for _, t := range schema {
+		s, err := snapshotTable(c, t, checksums)
+		if err != nil {
+			return nil, fmt.Errorf("could not snapshot %s: %w", t.Name, err)
+		}
+		snapshots[t.Name] = s
+	}
+	return snapshots, nil
+}
+
+// snapshotTable counts the rows of the table and, with checksums, sums the
+// hashes of their values so that the checksum does not depend on the order
+// of the rows.
+func snapshotTable(c *pop.Connection, t pop.SchemaTable, checksums bool) (tableSnapshot, error) {
+	cols := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		cols[i] = col.Name
+	}
+	s := tableSnapshot{columns: strings.Join(cols, ",")}
+	table := c.Dialect.Quote(t.Name)
+
+	if !checksums {
+		err := c.Store.GetContext(c.Context(), &s.rows, "SELECT COUNT(*) FROM "+table)
+		return s, err
+	}
+
+	rows, err := c.Store.QueryxContext(c.Context(), "SELECT * FROM "+table)
+	if err != nil {
+		return s, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		values, err := rows.SliceScan()
+		if err != nil {
+			return s, err
+		}
+		h := fnv.New64a()
+		for _, v := range values {
+			switch x := v.(type) {
+			case nil:
+				h.Write([]byte{0})
+			case []byte:
+				h.Write([]byte{1})
+				h.Write(x)
+			default:
+				fmt.Fprintf(h, "\x01%v", x)
+			}
+			h.Write([]byte{0xff})
+		}
+		s.checksum += h.Sum64()
+		s.rows++
+	}
+	return s, rows.Err()
+}
+
+func containsTable(tables []string, name string) bool {
+	for _, t := range tables {
+		if strings.EqualFold(t, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+//go:build sqlite
+// +build sqlite
+
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/WilliamNHarvey/pop/v6"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_verifyMigration(t *testing.T) {
+	r := require.New(t)
+
+	c, err := pop.NewConnection(&pop.ConnectionDetails{
+		URL: "sqlite://" + filepath.Join(t.TempDir(), "verify.db"),
+	})
+	r.NoError(err)
+	r.NoError(c.Open())
+	defer c.Close()
+	r.NoError(c.RawQuery("CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT)").Exec())
+	r.NoError(c.RawQuery("CREATE TABLE gadgets (id INTEGER PRIMARY KEY, name TEXT)").Exec())
+	r.NoError(c.RawQuery("INSERT INTO widgets (name) VALUES ('a'), ('b'), (NULL)").Exec())
+	r.NoError(c.RawQuery("INSERT INTO gadgets (name) VALUES ('a')").Exec())
+
+	out := &bytes.Buffer{}
+	err = verifyMigration(c, func() error {
+		if err := c.RawQuery("DELETE FROM widgets WHERE name IS NULL").Exec(); err != nil {
+			return err
+		}
+		if err := c.RawQuery("UPDATE gadgets SET name = 'b'").Exec(); err != nil {
+			return err
+		}
+		return c.RawQuery("CREATE TABLE things (id INTEGER PRIMARY KEY)").Exec()
+	}, false, nil, out)
+	r.EqualError(err, "the data of 1 table(s) changed unexpectedly")
+	r.Equal("things: created, 0 row(s)\nwidgets: 3 row(s) -> 2 row(s) (unexpected)\n", out.String())
+
+	out.Reset()
+	err = verifyMigration(c, func() error {
+		return c.RawQuery("UPDATE gadgets SET name = 'c'").Exec()
+	}, true, nil, out)
+	r.EqualError(err, "the data of 1 table(s) changed unexpectedly")
+	r.Equal("gadgets: rows changed (unexpected)\n", out.String())
+
+	out.Reset()
+	err = verifyMigration(c, func() error {
+		if err := c.RawQuery("DROP TABLE things").Exec(); err != nil {
+			return err
+		}
+		return c.RawQuery("UPDATE gadgets SET name = 'd'").Exec()
+	}, true, []string{"gadgets", "things"}, out)
+	r.NoError(err)
+	r.Equal("gadgets: rows changed (allowed)\nthings: dropped, had 0 row(s) (allowed)\nNo unexpected change of the data.\n", out.String())
+}
@@ -0,0 +1,89 @@
+package pop
+
+import (
+	"database/sql"
+	"reflect"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+	"github.com/gobuffalo/nulls"
+)
+
+// Unscoped returns a copy of the connection which reads the soft deleted
+// rows and deletes the rows for good. The models with a nullable DeletedAt
+// field, a nulls.Time, a sql.NullTime or a *time.Time, are soft deleted:
+// Destroy sets their DeletedAt rather than deleting them, and the queries
+// skip the rows whose deleted_at is set, as do the association filters of
+// WhereHas and the counts of WithCount.
+//
+//	c.Unscoped().Destroy(&user) // deletes the user for good
+func (c *Connection) Unscoped() *Connection {
+	cn := c.copy()
+	cn.eager = c.eager
+	cn.eagerFields = c.eagerFields
+	cn.unscoped = true
+	return cn
+}
+
+// Unscoped makes the query read the soft deleted rows too, see
+// Connection.Unscoped.
+//
+//	q.Unscoped().Where("deleted_at IS NOT NULL").All(&users)
+func (q *Query) Unscoped() *Query {
+	q.unscoped = true
+	return q
+}
+
+var (
+	nullsTimeType   = reflect.TypeOf(nulls.Time{})
+	sqlNullTimeType = reflect.TypeOf(sql.NullTime{})
+	timePtrType     = reflect.TypeOf(&time.Time{})
+)
+
+// deletedAtColumn returns the column of the DeletedAt field of the model,
+// "" when the model is not soft deleted.
+func (m *Model) deletedAtColumn() string {
+	t := reflect.TypeOf(m.Value)
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Ptr || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+	f, ok := t.FieldByName("DeletedAt")
+	if !ok {
+		return ""
+	}
+	switch f.Type {
+	case nullsTimeType, sqlNullTimeType, timePtrType:
+	default:
+		return ""
+	}
+	if column := f.Tag.Get("db"); column != "" && column != "-" {
+		return column
+	}
+	return "deleted_at"
+}
+
+func (m *Model) setDeletedAt(now time.Time) {
+	fbn, err := m.fieldByName("DeletedAt")
+	if err != nil {
+		return
+	}
+	switch fbn.Type() {
+	case nullsTimeType:
+		fbn.Set(reflect.ValueOf(nulls.NewTime(now)))
+	case sqlNullTimeType:
+		fbn.Set(reflect.ValueOf(sql.NullTime{Time: now, Valid: true}))
+	case timePtrType:
+		fbn.Set(reflect.ValueOf(&now))
+	}
+}
+
+// softDestroy sets the deleted_at column of the row of the model.
+func (c *Connection) softDestroy(m *Model, column string) error {
+	m.setDeletedAt(nowFunc().Truncate(time.Microsecond))
+	cols := columns.NewColumnsWithAlias(m.TableName(), m.As, columns.IDField{Name: m.IDField(), Writeable: !m.UsingAutoIncrement()})
+	cols.Add(column)
+	return c.Dialect.Update(c, m, cols)
+}
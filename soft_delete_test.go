@@ -0,0 +1,121 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+type archivedNote struct {
+	ID        int        `db:"id"`
+	Body      string     `db:"body"`
+	DeletedAt nulls.Time `db:"deleted_at"`
+}
+
+func Test_SoftDelete(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE archived_notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL, deleted_at DATETIME)").Exec())
+
+	first := &archivedNote{Body: "first"}
+	second := &archivedNote{Body: "second"}
+	r.NoError(c.Create(first))
+	r.NoError(c.Create(second))
+
+	r.NoError(c.Destroy(first))
+	r.True(first.DeletedAt.Valid)
+
+	count, err := c.Count(&archivedNote{})
+	r.NoError(err)
+	r.Equal(1, count)
+	r.Error(c.Find(&archivedNote{}, first.ID))
+
+	notes := []archivedNote{}
+	r.NoError(c.Where("body <> ?", "").All(&notes))
+	r.Len(notes, 1)
+	r.Equal("second", notes[0].Body)
+	r.NoError(c.Where("body = ? OR body = ?", "first", "second").All(&notes))
+	r.Len(notes, 1)
+	r.Equal("second", notes[0].Body)
+
+	deleted := &archivedNote{}
+	r.NoError(c.Unscoped().Find(deleted, first.ID))
+	r.True(deleted.DeletedAt.Valid)
+	count, err = c.Q().Unscoped().Count(&archivedNote{})
+	r.NoError(err)
+	r.Equal(2, count)
+
+	r.NoError(c.Unscoped().Destroy(first))
+	count, err = c.Unscoped().Count(&archivedNote{})
+	r.NoError(err)
+	r.Equal(1, count)
+}
+
+func Test_SoftDelete_SQL(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &mysql{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	m := NewModel(&archivedNote{}, nil)
+	sql, _ := Q(c).Where("body = ?", "x").ToSQL(m)
+	r.True(strings.HasSuffix(sql, "WHERE (body = ?) AND archived_notes.deleted_at IS NULL"), sql)
+	sql, _ = Q(c).Where("body = ? OR body = ?", "x", "y").ToSQL(m)
+	r.True(strings.HasSuffix(sql, "WHERE (body = ? OR body = ?) AND archived_notes.deleted_at IS NULL"), sql)
+	sql, _ = Q(c).ToSQL(m)
+	r.True(strings.HasSuffix(sql, "WHERE archived_notes.deleted_at IS NULL"), sql)
+
+	sql, _ = Q(c).Unscoped().ToSQL(m)
+	r.NotContains(sql, "deleted_at IS NULL")
+
+	type plain struct {
+		ID        int `db:"id"`
+		DeletedAt int `db:"deleted_at"`
+	}
+	r.Equal("", NewModel(&plain{}, nil).deletedAtColumn())
+}
+
+type archivedAuthor struct {
+	ID         int            `db:"id"`
+	Name       string         `db:"name"`
+	Notes      []archivedNote `has_many:"archived_notes" fk_id:"author_id"`
+	NotesCount int            `db:"-" count:"notes_count"`
+}
+
+func Test_SoftDelete_Associations(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE archived_authors (id INTEGER PRIMARY KEY, name TEXT NOT NULL)").Exec())
+	r.NoError(c.RawQuery("CREATE TABLE archived_notes (id INTEGER PRIMARY KEY, body TEXT NOT NULL, author_id INTEGER, deleted_at DATETIME)").Exec())
+	r.NoError(c.RawQuery("INSERT INTO archived_authors (id, name) VALUES (1, 'kept'), (2, 'deleted')").Exec())
+	r.NoError(c.RawQuery("INSERT INTO archived_notes (id, body, author_id, deleted_at) VALUES (1, 'a', 1, NULL), (2, 'b', 1, CURRENT_TIMESTAMP), (3, 'c', 2, CURRENT_TIMESTAMP)").Exec())
+
+	authors := []archivedAuthor{}
+	r.NoError(c.WhereHas("Notes", nil).All(&authors))
+	r.Len(authors, 1)
+	r.Equal("kept", authors[0].Name)
+
+	r.NoError(c.WhereDoesntHave("Notes", nil).All(&authors))
+	r.Len(authors, 1)
+	r.Equal("deleted", authors[0].Name)
+
+	r.NoError(c.Q().Unscoped().WhereHas("Notes", nil).All(&authors))
+	r.Len(authors, 2)
+	r.NoError(c.WhereHas("Notes", func(q *Query) { q.Unscoped() }).All(&authors))
+	r.Len(authors, 2)
+	r.NoError(c.WhereHas("Notes", func(q *Query) { q.Where("body = ? OR body = ?", "b", "c") }).All(&authors))
+	r.Len(authors, 0)
+
+	r.NoError(c.WithCount("Notes", "notes_count").Order("id").All(&authors))
+	r.Equal(1, authors[0].NotesCount)
+	r.Equal(0, authors[1].NotesCount)
+
+	r.NoError(c.Q().Unscoped().WithCount("Notes", "notes_count").Order("id").All(&authors))
+	r.Equal(2, authors[0].NotesCount)
+	r.Equal(1, authors[1].NotesCount)
+}
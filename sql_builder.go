@@ -74,7 +74,7 @@ var inRegex = regexp.MustCompile(`(?i)in\s*\(\s*\?\s*\)`)
 func (sq *sqlBuilder) compile() {
 	if sq.sql == "" {
 		if sq.Query.RawSQL.Fragment != "" {
-			if sq.Query.Paginator != nil && !hasLimitOrOffset(sq.Query.RawSQL.Fragment) {
+			if sq.Query.Paginator != nil && !pagerFor(sq.Query.Connection.Dialect).Paginated(strings.TrimSpace(sq.Query.RawSQL.Fragment)) {
 				sq.sql = sq.buildPaginationClauses(sq.Query.RawSQL.Fragment)
 			} else {
 				if sq.Query.Paginator != nil {
@@ -110,17 +110,20 @@ func (sq *sqlBuilder) compile() {
 }
 
 func (sq *sqlBuilder) buildSelectSQL() string {
-	cols := sq.buildColumns()
+	cols := restrictColumns(sq.buildColumns(), sq.Query.selectColumns, sq.Query.omitColumns)
 
 	fc := sq.buildfromClauses()
 
 	sql := fmt.Sprintf("SELECT %s FROM %s", cols.Readable().SelectString(), fc)
 
 	sql = sq.buildJoinClauses(sql)
+	sql = sq.buildAsOfClause(sql)
 	sql = sq.buildWhereClauses(sql)
 	sql = sq.buildGroupClauses(sql)
 	sql = sq.buildOrderClauses(sql)
 	sql = sq.buildPaginationClauses(sql)
+	sql = sq.buildLockClause(sql)
+	sql = sq.buildTimeoutHint(sql)
 
 	return sql
 }
@@ -157,6 +160,9 @@ func (sq *sqlBuilder) buildfromClauses() fromClauses {
 	fc := sq.Query.fromClauses
 	for _, m := range models {
 		tableName := m.TableName()
+		if m == sq.Model && sq.Query.table != "" {
+			tableName = sq.Query.table
+		}
 		asName := m.Alias()
 		fc = append(fc, fromClause{
 			From: tableName,
@@ -170,13 +176,32 @@ func (sq *sqlBuilder) buildfromClauses() fromClauses {
 func (sq *sqlBuilder) buildWhereClauses(sql string) string {
 	mcs := sq.Query.belongsToThroughClauses
 	for _, mc := range mcs {
-		sq.Query.Where(fmt.Sprintf("%s.%s = ?", mc.Through.TableName(), mc.BelongsTo.associationName()), mc.BelongsTo.ID())
-		sq.Query.Where(fmt.Sprintf("%s.id = %s.%s", sq.Model.TableName(), mc.Through.TableName(), sq.Model.associationName()))
+		sq.Query.Where(fmt.Sprintf("%s.%s = ?", mc.Through.Alias(), mc.BelongsTo.associationName()), mc.BelongsTo.ID())
+		sq.Query.Where(fmt.Sprintf("%s.id = %s.%s", sq.Model.Alias(), mc.Through.Alias(), sq.Model.associationName()))
 	}
 
 	wc := sq.Query.whereClauses
-	if len(wc) > 0 {
-		sql = fmt.Sprintf("%s WHERE %s", sql, wc.Join(" AND "))
+	for _, whc := range sq.Query.whereHasClauses {
+		c, err := whc.toClause(sq.Model, sq.Query.unscoped)
+		if err != nil {
+			// never drop the filter silently, it would widen the result set
+			log(logging.Error, "could not build association filter: %v", err)
+			c = clause{Fragment: "1 = 0"}
+		}
+		wc = append(wc, c)
+	}
+	where := wc.Join(" AND ")
+	if column := sq.Model.deletedAtColumn(); column != "" && !sq.Query.unscoped {
+		// the clauses are parenthesized, an OR in them would bypass the scope
+		scope := fmt.Sprintf("%s.%s IS NULL", sq.Model.Alias(), column)
+		if where == "" {
+			where = scope
+		} else {
+			where = fmt.Sprintf("(%s) AND %s", where, scope)
+		}
+	}
+	if where != "" {
+		sql = fmt.Sprintf("%s WHERE %s", sql, where)
 		sq.args = append(sq.args, wc.Args()...)
 	}
 	return sql
@@ -229,14 +254,11 @@ func (sq *sqlBuilder) buildOrderClauses(sql string) string {
 }
 
 func (sq *sqlBuilder) buildPaginationClauses(sql string) string {
-	if sq.Query.limitResults > 0 && sq.Query.Paginator == nil {
-		sql = fmt.Sprintf("%s LIMIT %d", sql, sq.Query.limitResults)
-	}
+	pager := pagerFor(sq.Query.Connection.Dialect)
 	if sq.Query.Paginator != nil {
-		sql = fmt.Sprintf("%s LIMIT %d", sql, sq.Query.Paginator.PerPage)
-		sql = fmt.Sprintf("%s OFFSET %d", sql, sq.Query.Paginator.Offset)
+		return pager.Paginate(sql, sq.Query.Paginator.PerPage, sq.Query.Paginator.Offset)
 	}
-	return sql
+	return pager.Paginate(sql, sq.Query.limitResults, 0)
 }
 
 // columnCache is used to prevent columns rebuilding.
@@ -0,0 +1,46 @@
+package pop
+
+// BeforeExecHook is called with every INSERT, UPDATE and DELETE statement
+// generated by pop, right before it is sent to the database. op is one of
+// "INSERT", "UPDATE" or "DELETE" and table is the name of the table of the
+// model. The hook may return a modified statement and arguments, or an
+// error to veto the statement, in which case the error is returned to the
+// caller as is.
+//
+// Statements using named parameters (e.g. "INSERT ... VALUES (:name)") are
+// bound from the model: args then only contains the model, and the returned
+// args are ignored.
+type BeforeExecHook func(op string, table string, sql string, args []interface{}) (string, []interface{}, error)
+
+var beforeExecHook BeforeExecHook
+
+// SetBeforeExecHook sets the hook called before executing INSERT, UPDATE
+// and DELETE statements. Use nil to remove it.
+//
+//	pop.SetBeforeExecHook(func(op, table, sql string, args []interface{}) (string, []interface{}, error) {
+//		if maintenance.Active() {
+//			return "", nil, ErrMaintenance
+//		}
+//		return sql, args, nil
+//	})
+func SetBeforeExecHook(hook BeforeExecHook) {
+	beforeExecHook = hook
+}
+
+// beforeExec runs the statement hook, if any.
+func beforeExec(op operation, model *Model, sql string, args ...interface{}) (string, []interface{}, error) {
+	if beforeExecHook == nil {
+		return sql, args, nil
+	}
+	return beforeExecHook(string(op), model.TableName(), sql, args)
+}
+
+// beforeNamedExec runs the statement hook, if any, for a statement bound
+// from the model using named parameters.
+func beforeNamedExec(op operation, model *Model, sql string) (string, error) {
+	if beforeExecHook == nil {
+		return sql, nil
+	}
+	sql, _, err := beforeExecHook(string(op), model.TableName(), sql, []interface{}{model.Value})
+	return sql, err
+}
@@ -0,0 +1,77 @@
+package pop
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_BeforeExecHook(t *testing.T) {
+	r := require.New(t)
+	defer SetBeforeExecHook(nil)
+
+	m := NewModel(&User{}, context.Background())
+
+	sql, args, err := beforeExec(Delete, m, "DELETE FROM users WHERE id = ?", 1)
+	r.NoError(err)
+	r.Equal("DELETE FROM users WHERE id = ?", sql)
+	r.Equal([]interface{}{1}, args)
+
+	var calls []string
+	SetBeforeExecHook(func(op, table, sql string, args []interface{}) (string, []interface{}, error) {
+		calls = append(calls, op+" "+table)
+		return sql + " AND tenant_id = ?", append(args, 42), nil
+	})
+
+	sql, args, err = beforeExec(Delete, m, "DELETE FROM users WHERE id = ?", 1)
+	r.NoError(err)
+	r.Equal("DELETE FROM users WHERE id = ? AND tenant_id = ?", sql)
+	r.Equal([]interface{}{1, 42}, args)
+
+	sql, err = beforeNamedExec(Update, m, "UPDATE users SET name = :name WHERE id = :id")
+	r.NoError(err)
+	r.Equal("UPDATE users SET name = :name WHERE id = :id AND tenant_id = ?", sql)
+	r.Equal([]string{"DELETE users", "UPDATE users"}, calls)
+
+	errVeto := errors.New("vetoed")
+	SetBeforeExecHook(func(op, table, sql string, args []interface{}) (string, []interface{}, error) {
+		return "", nil, errVeto
+	})
+	_, err = beforeNamedExec(Insert, m, "INSERT INTO users (name) VALUES (:name)")
+	r.ErrorIs(err, errVeto)
+}
+
+func Test_BeforeExecHook_Veto(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	r := require.New(t)
+	defer SetBeforeExecHook(nil)
+
+	errVeto := errors.New("writes are disabled")
+	transaction(func(tx *Connection) {
+		u := &User{Name: nulls.NewString("Mark")}
+		r.NoError(tx.Create(u))
+
+		var statements []string
+		SetBeforeExecHook(func(op, table, sql string, args []interface{}) (string, []interface{}, error) {
+			statements = append(statements, op)
+			if op == string(Delete) {
+				return "", nil, errVeto
+			}
+			return sql, args, nil
+		})
+
+		u.Name = nulls.NewString("Jane")
+		r.NoError(tx.Update(u))
+		r.ErrorIs(tx.Destroy(u), errVeto)
+		r.Equal([]string{"UPDATE", "DELETE"}, statements)
+
+		SetBeforeExecHook(nil)
+		r.NoError(tx.Reload(u))
+		r.Equal("Jane", u.Name.String)
+	})
+}
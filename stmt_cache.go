@@ -0,0 +1,131 @@
+package pop
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// stmtCache is a LRU cache of the statements prepared on a database, by
+// SQL, see ConnectionDetails.StatementCacheSize.
+type stmtCache struct {
+	db   *sqlx.DB
+	size int
+
+	mu    sync.Mutex
+	stmts map[string]*cachedStmt
+	lru   *list.List
+}
+
+// cachedStmt is a statement of the cache, closed once evicted and no
+// longer in use.
+type cachedStmt struct {
+	*sqlx.Stmt
+	query   string
+	elem    *list.Element
+	refs    int
+	evicted bool
+}
+
+// newStmtCache returns a cache of size statements prepared on db, or nil
+// if size is not positive.
+func newStmtCache(db *sqlx.DB, size int) *stmtCache {
+	if size <= 0 {
+		return nil
+	}
+	return &stmtCache{db: db, size: size, stmts: map[string]*cachedStmt{}, lru: list.New()}
+}
+
+// caches returns true if the statements of the query are cached. The
+// statements without arguments, such as the migrations, and the ones
+// which may be scripts are not prepared. Neither are the ones commented
+// with their correlation ID or tags: their SQL differs on each request,
+// they would be evicted before being run again.
+func (c *stmtCache) caches(query string, args []interface{}) bool {
+	return c != nil && len(args) > 0 && !strings.Contains(query, ";") && !isCommented(query)
+}
+
+// stmt returns the statement of the query, prepared on the database or
+// taken from the cache, bound to tx if not nil. The returned function
+// must be called once the statement has been run, its rows may still be
+// read.
+func (c *stmtCache) stmt(ctx context.Context, tx *sqlx.Tx, query string) (*sqlx.Stmt, func(), error) {
+	cs, err := c.acquire(ctx, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tx == nil {
+		return cs.Stmt, func() { c.release(cs) }, nil
+	}
+	st := tx.StmtxContext(ctx, cs.Stmt)
+	return st, func() {
+		// closing the statement of the transaction waits for its rows
+		go st.Close()
+		c.release(cs)
+	}, nil
+}
+
+func (c *stmtCache) acquire(ctx context.Context, query string) (*cachedStmt, error) {
+	c.mu.Lock()
+	if cs, ok := c.stmts[query]; ok {
+		cs.refs++
+		c.lru.MoveToFront(cs.elem)
+		c.mu.Unlock()
+		return cs, nil
+	}
+	c.mu.Unlock()
+
+	st, err := c.db.PreparexContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cs, ok := c.stmts[query]; ok {
+		// prepared concurrently
+		go st.Close()
+		cs.refs++
+		c.lru.MoveToFront(cs.elem)
+		return cs, nil
+	}
+	cs := &cachedStmt{Stmt: st, query: query, refs: 1}
+	cs.elem = c.lru.PushFront(cs)
+	c.stmts[query] = cs
+	for c.lru.Len() > c.size {
+		c.evict(c.lru.Back().Value.(*cachedStmt))
+	}
+	return cs, nil
+}
+
+func (c *stmtCache) release(cs *cachedStmt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cs.refs--
+	if cs.refs == 0 && cs.evicted {
+		go cs.Close()
+	}
+}
+
+// evict removes the statement from the cache, closing it unless in use.
+func (c *stmtCache) evict(cs *cachedStmt) {
+	c.lru.Remove(cs.elem)
+	delete(c.stmts, cs.query)
+	cs.evicted = true
+	if cs.refs == 0 {
+		// closing the statement waits for its rows
+		go cs.Close()
+	}
+}
+
+// close evicts all the statements.
+func (c *stmtCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.lru.Len() > 0 {
+		c.evict(c.lru.Back().Value.(*cachedStmt))
+	}
+}
@@ -0,0 +1,87 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StatementCache(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, &ConnectionDetails{StatementCacheSize: 2})
+	r.NoError(c.RawQuery("CREATE TABLE cached (id INTEGER PRIMARY KEY, name TEXT)").Exec())
+
+	stmts := c.Store.(*rotatingStore).unwrap().(*dB).stmts
+	r.NotNil(stmts)
+	r.Zero(stmts.lru.Len())
+
+	r.NoError(c.RawQuery("INSERT INTO cached (id, name) VALUES (?, ?)", 1, "a").Exec())
+	r.NoError(c.RawQuery("INSERT INTO cached (id, name) VALUES (?, ?)", 2, "b").Exec())
+	r.Equal(1, stmts.lru.Len())
+
+	var name string
+	r.NoError(c.RawQuery("SELECT name FROM cached WHERE id = ?", 2).First(&name))
+	r.Equal("b", name)
+	r.Equal(2, stmts.lru.Len())
+
+	var ids []int
+	r.NoError(c.RawQuery("SELECT id FROM cached WHERE id > ? ORDER BY id", 0).All(&ids))
+	r.Equal([]int{1, 2}, ids)
+	r.Equal(2, stmts.lru.Len())
+	_, ok := stmts.stmts["INSERT INTO cached (id, name) VALUES (?, ?)"]
+	r.False(ok)
+
+	r.NoError(c.Transaction(func(tx *Connection) error {
+		if err := tx.RawQuery("INSERT INTO cached (id, name) VALUES (?, ?)", 3, "c").Exec(); err != nil {
+			return err
+		}
+		return tx.RawQuery("SELECT name FROM cached WHERE id = ?", 3).First(&name)
+	}))
+	r.Equal("c", name)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var n int
+			r.NoError(c.RawQuery("SELECT COUNT(*) FROM cached WHERE id > ?", i%4).First(&n))
+		}(i)
+	}
+	wg.Wait()
+	r.Equal(2, stmts.lru.Len())
+	for _, cs := range stmts.stmts {
+		r.Zero(cs.refs)
+	}
+}
+
+func Test_StatementCache_CommentCorrelationID(t *testing.T) {
+	r := require.New(t)
+
+	SetCorrelationIDFunc(func(ctx context.Context) string {
+		id, _ := ctx.Value(requestIDKey{}).(string)
+		return id
+	})
+	defer SetCorrelationIDFunc(nil)
+
+	c := openSQLite(t, &ConnectionDetails{StatementCacheSize: 2, CommentCorrelationID: true})
+	r.NoError(c.RawQuery("CREATE TABLE cached (id INTEGER PRIMARY KEY, name TEXT)").Exec())
+	stmts := c.Store.(*rotatingStore).unwrap().(commentStore).store.(*dB).stmts
+
+	for _, id := range []string{"req-1", "req-2", "req-3"} {
+		ctx := context.WithValue(context.Background(), requestIDKey{}, id)
+		r.NoError(c.WithContext(ctx).RawQuery("INSERT INTO cached (name) VALUES (?)", id).Exec())
+	}
+	r.Zero(stmts.lru.Len())
+
+	var n int
+	r.NoError(c.RawQuery("SELECT COUNT(*) FROM cached WHERE id > ?", 0).First(&n))
+	r.Equal(3, n)
+	r.Equal(1, stmts.lru.Len())
+}
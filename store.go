@@ -27,17 +27,38 @@ type store interface {
 	NamedExecContext(context.Context, string, interface{}) (sql.Result, error)
 	NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error)
 	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	QueryxContext(context.Context, string, ...interface{}) (*sqlx.Rows, error)
 	PrepareNamedContext(context.Context, string) (*sqlx.NamedStmt, error)
 	TransactionContext(context.Context) (*Tx, error)
 	TransactionContextOptions(context.Context, *sql.TxOptions) (*Tx, error)
 }
 
+// wrappedStore is implemented by the stores wrapping another one.
+type wrappedStore interface {
+	unwrap() store
+}
+
+// unwrapStore returns the innermost store wrapped by the given one.
+func unwrapStore(s store) store {
+	for {
+		w, ok := s.(wrappedStore)
+		if !ok {
+			return s
+		}
+		s = w.unwrap()
+	}
+}
+
 // ContextStore wraps a store with a Context, so passes it with the functions that don't take it.
 type contextStore struct {
 	store
 	ctx context.Context
 }
 
+func (s contextStore) unwrap() store {
+	return s.store
+}
+
 func (s contextStore) Transaction() (*Tx, error) {
 	return s.store.TransactionContext(s.ctx)
 }
@@ -50,6 +71,9 @@ func (s contextStore) Get(dest interface{}, query string, args ...interface{}) e
 func (s contextStore) NamedExec(query string, arg interface{}) (sql.Result, error) {
 	return s.store.NamedExecContext(s.ctx, query, arg)
 }
+func (s contextStore) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return s.store.NamedQueryContext(s.ctx, query, arg)
+}
 func (s contextStore) Exec(query string, args ...interface{}) (sql.Result, error) {
 	return s.store.ExecContext(s.ctx, query, args...)
 }
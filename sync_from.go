@@ -0,0 +1,97 @@
+package pop
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// syncBatchSize is the number of keys whose rows SyncFrom reads at once.
+const syncBatchSize = 500
+
+// SyncReport reports the rows written by SyncFrom.
+type SyncReport struct {
+	// Inserted is the number of rows which were missing.
+	Inserted int
+	// Updated is the number of rows whose checksum differed.
+	Updated int
+	// Unchanged is the number of rows left as they were.
+	Unchanged int
+}
+
+// SyncFrom keeps the table of the models, a slice of entries read from
+// another database, consistent with them: the rows missing are inserted,
+// the ones whose checksum, see RowChecksum, differs from the one of their
+// entry are updated, and the others are left as they are. The rows are
+// matched by the key column, the ID or a unique column, and written with
+// Upsert on that column, so that the entries arriving out of order, or
+// the rows written meanwhile, do not fail the sync. The models are written
+// as they are, with the IDs of the entries, e.g. tagged no_auto_increment,
+// and their timestamps.
+//
+//	report, err := replica.SyncFrom(&users, "id")
+func (c *Connection) SyncFrom(models interface{}, key string) (*SyncReport, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	v := reflect.Indirect(reflect.ValueOf(models))
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("can not sync %T, not a slice", models)
+	}
+	report := &SyncReport{}
+	if v.Len() == 0 {
+		return report, nil
+	}
+	et := v.Type().Elem()
+	if et.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can not sync %T, not a slice of structs", models)
+	}
+	model := reflect.New(et).Interface()
+
+	tc := c.SkipTimestamps()
+	for start := 0; start < v.Len(); start += syncBatchSize {
+		end := start + syncBatchSize
+		if end > v.Len() {
+			end = v.Len()
+		}
+		keys := make([]interface{}, 0, end-start)
+		for i := start; i < end; i++ {
+			f, ok := fieldByColumn(v.Index(i), key)
+			if !ok {
+				return report, fmt.Errorf("%T has no field for column %s", model, key)
+			}
+			keys = append(keys, f.Interface())
+		}
+		current, err := Q(c).Where(key+" IN (?)", keys...).checksums(model, key, nil, false)
+		if err != nil {
+			return report, err
+		}
+
+		changed := reflect.MakeSlice(v.Type(), 0, end-start)
+		for i := start; i < end; i++ {
+			sum, err := RowChecksum(v.Index(i).Addr().Interface())
+			if err != nil {
+				return report, err
+			}
+			was, ok := current[checksumKey(keys[i-start])]
+			switch {
+			case !ok:
+				report.Inserted++
+			case was != sum:
+				report.Updated++
+			default:
+				report.Unchanged++
+				continue
+			}
+			changed = reflect.Append(changed, v.Index(i))
+		}
+		if changed.Len() == 0 {
+			continue
+		}
+		ptr := reflect.New(changed.Type())
+		ptr.Elem().Set(changed)
+		if err := tc.Upsert(ptr.Interface(), []string{key}, nil); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
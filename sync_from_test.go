@@ -0,0 +1,55 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type syncedEntry struct {
+	ID        int       `db:"id" no_auto_increment:"true"`
+	Title     string    `db:"title"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func Test_SyncFrom(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE synced_entries (id INTEGER PRIMARY KEY, title TEXT, created_at DATETIME, updated_at DATETIME)").Exec())
+
+	at := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	source := []syncedEntry{
+		{ID: 3, Title: "c", CreatedAt: at, UpdatedAt: at},
+		{ID: 1, Title: "a", CreatedAt: at, UpdatedAt: at},
+	}
+	report, err := c.SyncFrom(source, "id")
+	r.NoError(err)
+	r.Equal(&SyncReport{Inserted: 2}, report)
+
+	later := at.Add(time.Hour)
+	source[1].Title, source[1].UpdatedAt = "a2", later
+	source = append(source, syncedEntry{ID: 2, Title: "b", CreatedAt: at, UpdatedAt: at})
+	report, err = c.SyncFrom(&source, "id")
+	r.NoError(err)
+	r.Equal(&SyncReport{Inserted: 1, Updated: 1, Unchanged: 1}, report)
+
+	var copied []syncedEntry
+	r.NoError(c.Order("id").All(&copied))
+	r.Len(copied, 3)
+	r.Equal("a2", copied[0].Title)
+	r.True(later.Equal(copied[0].UpdatedAt))
+	r.Equal("b", copied[1].Title)
+
+	report, err = c.SyncFrom(source, "id")
+	r.NoError(err)
+	r.Equal(&SyncReport{Unchanged: 3}, report)
+
+	_, err = c.SyncFrom(source, "nope")
+	r.Error(err)
+}
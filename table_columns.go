@@ -0,0 +1,83 @@
+package pop
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tableColumnsTTL is the time the columns of the tables are cached for, so
+// the columns added or changed by the migrations are seen without
+// restarting.
+const tableColumnsTTL = time.Minute
+
+// tableColumn is a column of a table, as read from the database.
+type tableColumn struct {
+	// Size is the maximum number of characters of the column, 0 when it
+	// is not limited or not known.
+	Size int
+	// Charset is the character set of the column, when known.
+	Charset string
+}
+
+type tableColumnsKey struct {
+	details *ConnectionDetails
+	table   string
+}
+
+type tableColumnsEntry struct {
+	columns map[string]tableColumn
+	at      time.Time
+}
+
+// tableColumnsCache caches the columns of the tables, per connection.
+var tableColumnsCache sync.Map
+
+// tableColumns returns the columns of the table, keyed by their lowercased
+// name.
+func (c *Connection) tableColumns(table string) (map[string]tableColumn, error) {
+	key := tableColumnsKey{details: c.Dialect.Details(), table: table}
+	if e, ok := tableColumnsCache.Load(key); ok && time.Since(e.(tableColumnsEntry).at) < tableColumnsTTL {
+		return e.(tableColumnsEntry).columns, nil
+	}
+
+	var cols map[string]tableColumn
+	var err error
+	if d, ok := c.Dialect.(columnIntrospectable); ok {
+		cols, err = d.TableColumns(c.Context(), c.Store, table)
+	} else {
+		cols, err = genericTableColumns(c.Context(), c.Store, c.Dialect.Quote(table))
+	}
+	if err != nil {
+		return nil, err
+	}
+	tableColumnsCache.Store(key, tableColumnsEntry{columns: cols, at: time.Now()})
+	return cols, nil
+}
+
+// genericTableColumns reads the columns of the table from the result of an
+// empty query, their sizes being the ones reported by the driver.
+func genericTableColumns(ctx context.Context, s store, quotedTable string) (map[string]tableColumn, error) {
+	rows, err := s.QueryxContext(ctx, fmt.Sprintf("SELECT * FROM %s WHERE 1 = 0", quotedTable))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make(map[string]tableColumn, len(types))
+	for _, t := range types {
+		col := tableColumn{}
+		if size, ok := t.Length(); ok && size > 0 && size < math.MaxInt32 {
+			col.Size = int(size)
+		}
+		cols[strings.ToLower(t.Name())] = col
+	}
+	return cols, nil
+}
@@ -0,0 +1,358 @@
+package pop
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/WilliamNHarvey/pop/v6/logging"
+)
+
+// TableRebuild is the online rebuild of a table into a new one, such as
+// when changing the type of its primary key from int to bigint: the new
+// table is created by a migration, then
+//
+//   - Install keeps it in sync with the writes to the old table,
+//   - Backfill copies the rows written before,
+//   - Verify checks that both tables hold the same rows,
+//   - Cutover swaps the tables,
+//
+// each of them being safe to run again after a failure. Uninstall aborts
+// the rebuild. The writes are copied by triggers on SQLite, PostgreSQL,
+// MySQL and MariaDB, the other dialects are not supported.
+//
+//	r := &pop.TableRebuild{Old: "events", New: "events_bigint"}
+//	err := r.Install(c)
+//	_, err = r.Backfill(c)
+//	err = r.Cutover(c) // events is now the new table, events_old the old one
+type TableRebuild struct {
+	// Old is the name of the rebuilt table.
+	Old string
+	// New is the name of the table replacing it.
+	New string
+	// Key is the column identifying the rows, unique in both tables.
+	// Defaults to "id".
+	Key string
+	// Columns are the copied columns. Defaults to the columns of New
+	// which Old has too.
+	Columns []string
+	// BatchSize is the number of rows copied by each statement of
+	// Backfill. Defaults to 1000.
+	BatchSize int
+	// ArchiveAs is the name of the old table after the cutover. Defaults
+	// to Old with an "_old" suffix.
+	ArchiveAs string
+}
+
+// RebuildReport compares the rows of the tables of a rebuild.
+type RebuildReport struct {
+	OldRows int64
+	NewRows int64
+	// Missing is the number of rows of the old table missing from the new
+	// one, and Extra the number of rows of the new table not in the old
+	// one.
+	Missing int64
+	Extra   int64
+}
+
+// InSync reports whether both tables hold the same rows.
+func (r RebuildReport) InSync() bool {
+	return r.OldRows == r.NewRows && r.Missing == 0 && r.Extra == 0
+}
+
+func (r RebuildReport) String() string {
+	return fmt.Sprintf("%d old row(s), %d new row(s), %d missing, %d extra", r.OldRows, r.NewRows, r.Missing, r.Extra)
+}
+
+func (r *TableRebuild) key() string {
+	if r.Key == "" {
+		return "id"
+	}
+	return r.Key
+}
+
+func (r *TableRebuild) archiveAs() string {
+	if r.ArchiveAs == "" {
+		return r.Old + "_old"
+	}
+	return r.ArchiveAs
+}
+
+// triggerName returns the name of the trigger copying the op writes.
+func (r *TableRebuild) triggerName(op string) string {
+	return fmt.Sprintf("pop_rebuild_%s_%s", r.Old, op)
+}
+
+// columns returns the copied columns.
+func (r *TableRebuild) columns(c *Connection) ([]string, error) {
+	if len(r.Columns) > 0 {
+		return r.Columns, nil
+	}
+	schema, err := c.Schema()
+	if err != nil {
+		return nil, err
+	}
+	var oldCols, cols []string
+	found := 0
+	for _, t := range schema {
+		switch t.Name {
+		case r.Old:
+			found++
+			for _, col := range t.Columns {
+				oldCols = append(oldCols, col.Name)
+			}
+		case r.New:
+			found++
+			for _, col := range t.Columns {
+				cols = append(cols, col.Name)
+			}
+		}
+	}
+	if found != 2 {
+		return nil, fmt.Errorf("could not find the tables %s and %s", r.Old, r.New)
+	}
+	var common []string
+	for _, col := range cols {
+		if containsString(oldCols, col) {
+			common = append(common, col)
+		}
+	}
+	if !containsString(common, r.key()) {
+		return nil, fmt.Errorf("key column %s is not in both %s and %s", r.key(), r.Old, r.New)
+	}
+	return common, nil
+}
+
+func (r *TableRebuild) quoteColumns(c *Connection, cols []string, prefix string) string {
+	quoted := make([]string, len(cols))
+	for i, col := range cols {
+		quoted[i] = prefix + c.Dialect.Quote(col)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Install creates the triggers copying the inserts, updates and deletes of
+// the old table to the new one.
+func (r *TableRebuild) Install(c *Connection) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	cols, err := r.columns(c)
+	if err != nil {
+		return err
+	}
+
+	d := c.Dialect
+	old, nw, key := d.Quote(r.Old), d.Quote(r.New), d.Quote(r.key())
+	names, newValues := r.quoteColumns(c, cols, ""), r.quoteColumns(c, cols, "NEW.")
+	del := fmt.Sprintf("DELETE FROM %s WHERE %s = OLD.%s", nw, key, key)
+
+	var stmts []string
+	switch d.Name() {
+	case nameSQLite3:
+		ins := fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s)", nw, names, newValues)
+		stmts = []string{
+			fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER INSERT ON %s BEGIN %s; END", r.triggerName("insert"), old, ins),
+			fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE ON %s BEGIN %s; %s; END", r.triggerName("update"), old, del, ins),
+			fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER DELETE ON %s BEGIN %s; END", r.triggerName("delete"), old, del),
+		}
+	case nameMySQL, nameMariaDB:
+		ins := fmt.Sprintf("REPLACE INTO %s (%s) VALUES (%s)", nw, names, newValues)
+		stmts = []string{
+			fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER INSERT ON %s FOR EACH ROW %s", r.triggerName("insert"), old, ins),
+			fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER UPDATE ON %s FOR EACH ROW BEGIN %s; %s; END", r.triggerName("update"), old, del, ins),
+			fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %s AFTER DELETE ON %s FOR EACH ROW %s", r.triggerName("delete"), old, del),
+		}
+	case namePostgreSQL:
+		fn := r.triggerName("copy")
+		stmts = []string{
+			fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $pop$
+BEGIN
+	IF TG_OP IN ('UPDATE', 'DELETE') THEN
+		%s;
+	END IF;
+	IF TG_OP IN ('INSERT', 'UPDATE') THEN
+		INSERT INTO %s (%s) VALUES (%s);
+	END IF;
+	RETURN NULL;
+END
+$pop$`, fn, del, nw, names, newValues),
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", fn, old),
+			fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE ON %s FOR EACH ROW EXECUTE PROCEDURE %s()", fn, old, fn),
+		}
+	default:
+		return errUnsupported(d, "table rebuild triggers")
+	}
+	return r.exec(c, stmts...)
+}
+
+// Uninstall drops the triggers created by Install, leaving both tables as
+// they are.
+func (r *TableRebuild) Uninstall(c *Connection) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	var stmts []string
+	switch c.Dialect.Name() {
+	case nameSQLite3, nameMySQL, nameMariaDB:
+		for _, op := range []string{"insert", "update", "delete"} {
+			stmts = append(stmts, fmt.Sprintf("DROP TRIGGER IF EXISTS %s", r.triggerName(op)))
+		}
+	case namePostgreSQL:
+		fn := r.triggerName("copy")
+		stmts = []string{
+			fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", fn, c.Dialect.Quote(r.Old)),
+			fmt.Sprintf("DROP FUNCTION IF EXISTS %s()", fn),
+		}
+	default:
+		return errUnsupported(c.Dialect, "table rebuild triggers")
+	}
+	return r.exec(c, stmts...)
+}
+
+// Backfill copies the rows of the old table missing from the new one, in
+// the order of the key, BatchSize rows per statement so that none of them
+// locks the tables for long. It returns the number of copied rows.
+func (r *TableRebuild) Backfill(c *Connection) (int64, error) {
+	if err := c.checkWritable(); err != nil {
+		return 0, err
+	}
+	cols, err := r.columns(c)
+	if err != nil {
+		return 0, err
+	}
+	size := r.BatchSize
+	if size < 1 {
+		size = 1000
+	}
+
+	d := c.Dialect
+	old, nw, key := d.Quote(r.Old), d.Quote(r.New), d.Quote(r.key())
+	names := r.quoteColumns(c, cols, "")
+	var insert, conflict string
+	switch d.Name() {
+	case nameSQLite3:
+		insert = "INSERT OR IGNORE INTO"
+	case nameMySQL, nameMariaDB:
+		insert = "INSERT IGNORE INTO"
+	case namePostgreSQL:
+		insert, conflict = "INSERT INTO", fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", key)
+	default:
+		return 0, errUnsupported(d, "table rebuild backfill")
+	}
+
+	var copied int64
+	var last interface{}
+	for {
+		where, args := "", []interface{}{}
+		if last != nil {
+			where, args = fmt.Sprintf(" WHERE %s > ?", key), []interface{}{last}
+		}
+		var upTo interface{}
+		bound := d.TranslateSQL(fmt.Sprintf("SELECT MAX(%s) FROM (SELECT %s FROM %s%s ORDER BY %s LIMIT %d) pop_batch", key, key, old, where, key, size))
+		txlog(logging.SQL, c, bound, args...)
+		if err := c.Store.GetContext(c.Context(), &upTo, bound, args...); err != nil {
+			return copied, fmt.Errorf("could not read the next batch of %s: %w", r.Old, err)
+		}
+		if upTo == nil {
+			return copied, nil
+		}
+
+		cond := fmt.Sprintf("%s <= ?", key)
+		if last != nil {
+			cond = fmt.Sprintf("%s > ? AND %s", key, cond)
+		}
+		stmt := d.TranslateSQL(fmt.Sprintf("%s %s (%s) SELECT %s FROM %s WHERE %s%s", insert, nw, names, names, old, cond, conflict))
+		args = append(args, upTo)
+		txlog(logging.SQL, c, stmt, args...)
+		res, err := c.Store.ExecContext(c.Context(), stmt, args...)
+		if err != nil {
+			return copied, fmt.Errorf("could not copy the rows of %s: %w", r.Old, err)
+		}
+		if n, err := res.RowsAffected(); err == nil {
+			copied += n
+		}
+		last = upTo
+		log(logging.Debug, "backfilled %d row(s) of %s", copied, r.Old)
+	}
+}
+
+// Verify compares the rows of the tables by their key.
+func (r *TableRebuild) Verify(c *Connection) (RebuildReport, error) {
+	d := c.Dialect
+	old, nw, key := d.Quote(r.Old), d.Quote(r.New), d.Quote(r.key())
+	var report RebuildReport
+	counts := []struct {
+		dest  *int64
+		query string
+	}{
+		{&report.OldRows, fmt.Sprintf("SELECT COUNT(*) FROM %s", old)},
+		{&report.NewRows, fmt.Sprintf("SELECT COUNT(*) FROM %s", nw)},
+		{&report.Missing, fmt.Sprintf("SELECT COUNT(*) FROM %s o WHERE NOT EXISTS (SELECT 1 FROM %s n WHERE n.%s = o.%s)", old, nw, key, key)},
+		{&report.Extra, fmt.Sprintf("SELECT COUNT(*) FROM %s n WHERE NOT EXISTS (SELECT 1 FROM %s o WHERE o.%s = n.%s)", nw, old, key, key)},
+	}
+	for _, count := range counts {
+		txlog(logging.SQL, c, count.query)
+		if err := c.Store.GetContext(c.Context(), count.dest, count.query); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Cutover verifies that the tables are in sync, renames the old table to
+// ArchiveAs and the new one to Old, and drops the triggers. The tables are
+// swapped in a single transaction, or statement on MySQL and MariaDB.
+func (r *TableRebuild) Cutover(c *Connection) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	d := c.Dialect
+	old, nw, archive := d.Quote(r.Old), d.Quote(r.New), d.Quote(r.archiveAs())
+
+	cutover := func(tx *Connection) error {
+		report, err := r.Verify(tx)
+		if err != nil {
+			return err
+		}
+		if !report.InSync() {
+			return fmt.Errorf("%w: %s", ErrRebuildOutOfSync, report)
+		}
+		switch d.Name() {
+		case nameMySQL, nameMariaDB:
+			// the triggers keep copying the writes until the tables are
+			// swapped, they follow the old table afterwards.
+			if err := r.exec(tx, fmt.Sprintf("RENAME TABLE %s TO %s, %s TO %s", old, archive, nw, old)); err != nil {
+				return err
+			}
+			return r.Uninstall(tx)
+		}
+		if err := r.Uninstall(tx); err != nil {
+			return err
+		}
+		return r.exec(tx,
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s", old, archive),
+			fmt.Sprintf("ALTER TABLE %s RENAME TO %s", nw, old),
+		)
+	}
+	// MySQL and MariaDB commit the DDL implicitly, RENAME TABLE swaps the
+	// tables atomically there.
+	if c.TX != nil || d.Name() == nameMySQL || d.Name() == nameMariaDB {
+		return cutover(c)
+	}
+	return c.Transaction(cutover)
+}
+
+// ErrRebuildOutOfSync is returned, wrapped, by TableRebuild.Cutover when
+// the tables do not hold the same rows.
+var ErrRebuildOutOfSync = errors.New("the tables of the rebuild are out of sync")
+
+func (r *TableRebuild) exec(c *Connection, stmts ...string) error {
+	for _, stmt := range stmts {
+		txlog(logging.SQL, c, stmt)
+		if _, err := c.Store.ExecContext(c.Context(), stmt); err != nil {
+			return fmt.Errorf("rebuild of %s: %w", r.Old, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TableRebuild(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE events (id INTEGER PRIMARY KEY, name TEXT, legacy TEXT)").Exec())
+	r.NoError(c.RawQuery("CREATE TABLE events_v2 (id INTEGER PRIMARY KEY, name TEXT NOT NULL, kind TEXT NOT NULL DEFAULT 'event')").Exec())
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		r.NoError(c.RawQuery("INSERT INTO events (name, legacy) VALUES (?, 'x')", name).Exec())
+	}
+
+	rb := &TableRebuild{Old: "events", New: "events_v2", BatchSize: 2}
+	cols, err := rb.columns(c)
+	r.NoError(err)
+	r.Equal([]string{"id", "name"}, cols)
+
+	r.NoError(rb.Install(c))
+	r.NoError(rb.Install(c))
+	r.NoError(c.RawQuery("INSERT INTO events (name) VALUES ('f')").Exec())
+	r.NoError(c.RawQuery("UPDATE events SET name = 'bb' WHERE id = 2").Exec())
+	r.NoError(c.RawQuery("DELETE FROM events WHERE id = 3").Exec())
+
+	report, err := rb.Verify(c)
+	r.NoError(err)
+	r.Equal(RebuildReport{OldRows: 5, NewRows: 2, Missing: 3}, report)
+	r.False(report.InSync())
+	err = rb.Cutover(c)
+	r.True(errors.Is(err, ErrRebuildOutOfSync), err)
+
+	n, err := rb.Backfill(c)
+	r.NoError(err)
+	r.Equal(int64(3), n)
+	n, err = rb.Backfill(c)
+	r.NoError(err)
+	r.Zero(n)
+
+	report, err = rb.Verify(c)
+	r.NoError(err)
+	r.True(report.InSync(), report.String())
+
+	r.NoError(rb.Cutover(c))
+	var names []string
+	r.NoError(c.Store.Select(&names, "SELECT name || ':' || kind FROM events ORDER BY id"))
+	r.Equal([]string{"a:event", "bb:event", "d:event", "e:event", "f:event"}, names)
+
+	// the writes are not copied anymore
+	r.NoError(c.RawQuery("INSERT INTO events_old (name) VALUES ('g')").Exec())
+	var count int
+	r.NoError(c.Store.Get(&count, "SELECT COUNT(*) FROM events"))
+	r.Equal(5, count)
+}
+
+func Test_TableRebuild_Unsupported(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &cockroach{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	rb := &TableRebuild{Old: "events", New: "events_v2", Columns: []string{"id"}}
+	r.True(errors.Is(rb.Install(c), ErrUnsupported))
+	_, err := rb.Backfill(c)
+	r.True(errors.Is(err, ErrUnsupported))
+}
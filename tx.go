@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -18,11 +20,20 @@ func init() {
 type Tx struct {
 	ID int
 	*sqlx.Tx
+	statement atomic.Value
+	// savepoints is the number of savepoints created in the transaction.
+	savepoints int32
+	// stmts is the statement cache of the database, if enabled.
+	stmts *stmtCache
+	// ended is called once the transaction is committed or rolled back.
+	ended   func()
+	endOnce sync.Once
 }
 
 func newTX(ctx context.Context, db *dB, opts *sql.TxOptions) (*Tx, error) {
 	t := &Tx{
-		ID: rand.Int(),
+		ID:    rand.Int(),
+		stmts: db.stmts,
 	}
 	tx, err := db.BeginTxx(ctx, opts)
 	t.Tx = tx
@@ -50,6 +61,30 @@ func (tx *Tx) Transaction() (*Tx, error) {
 	return tx, nil
 }
 
+// Commit commits the transaction.
+func (tx *Tx) Commit() error {
+	defer tx.end()
+	return tx.Tx.Commit()
+}
+
+// Rollback rolls the transaction back.
+func (tx *Tx) Rollback() error {
+	defer tx.end()
+	return tx.Tx.Rollback()
+}
+
+// onEnd sets the function called once the transaction is committed or
+// rolled back.
+func (tx *Tx) onEnd(fn func()) {
+	tx.ended = fn
+}
+
+func (tx *Tx) end() {
+	if tx.ended != nil {
+		tx.endOnce.Do(tx.ended)
+	}
+}
+
 // Close does nothing. This is defined so it implements the `Store` interface.
 func (tx *Tx) Close() error {
 	return nil
@@ -57,5 +92,105 @@ func (tx *Tx) Close() error {
 
 // Workaround for https://github.com/jmoiron/sqlx/issues/447
 func (tx *Tx) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
-	return sqlx.NamedQueryContext(ctx, tx, query, arg)
+	tx.track(query)
+	return sqlx.NamedQueryContext(ctx, tx.Tx, query, serializedArg(tx.Mapper, arg))
+}
+
+// Statement returns the last statement run in the transaction, the one in
+// flight if any.
+func (tx *Tx) Statement() string {
+	s, _ := tx.statement.Load().(string)
+	return s
+}
+
+func (tx *Tx) track(query string) {
+	tx.statement.Store(query)
+}
+
+func (tx *Tx) Select(dest interface{}, query string, args ...interface{}) error {
+	return tx.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (tx *Tx) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	tx.track(query)
+	if !tx.stmts.caches(query, args) {
+		return tx.Tx.SelectContext(ctx, dest, query, args...)
+	}
+	st, done, err := tx.stmts.stmt(ctx, tx.Tx, query)
+	if err != nil {
+		return err
+	}
+	defer done()
+	return st.SelectContext(ctx, dest, args...)
+}
+
+func (tx *Tx) Get(dest interface{}, query string, args ...interface{}) error {
+	return tx.GetContext(context.Background(), dest, query, args...)
+}
+
+func (tx *Tx) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	tx.track(query)
+	if !tx.stmts.caches(query, args) {
+		return tx.Tx.GetContext(ctx, dest, query, args...)
+	}
+	st, done, err := tx.stmts.stmt(ctx, tx.Tx, query)
+	if err != nil {
+		return err
+	}
+	defer done()
+	return st.GetContext(ctx, dest, args...)
+}
+
+func (tx *Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return tx.ExecContext(context.Background(), query, args...)
+}
+
+func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	tx.track(query)
+	if !tx.stmts.caches(query, args) {
+		return tx.Tx.ExecContext(ctx, query, args...)
+	}
+	st, done, err := tx.stmts.stmt(ctx, tx.Tx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return st.ExecContext(ctx, args...)
+}
+
+func (tx *Tx) NamedExec(query string, arg interface{}) (sql.Result, error) {
+	tx.track(query)
+	return tx.Tx.NamedExec(query, serializedArg(tx.Mapper, arg))
+}
+
+func (tx *Tx) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	tx.track(query)
+	return tx.Tx.NamedExecContext(ctx, query, serializedArg(tx.Mapper, arg))
+}
+
+func (tx *Tx) NamedQuery(query string, arg interface{}) (*sqlx.Rows, error) {
+	return tx.NamedQueryContext(context.Background(), query, arg)
+}
+
+func (tx *Tx) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	tx.track(query)
+	if !tx.stmts.caches(query, args) {
+		return tx.Tx.QueryxContext(ctx, query, args...)
+	}
+	st, done, err := tx.stmts.stmt(ctx, tx.Tx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return st.QueryxContext(ctx, args...)
+}
+
+func (tx *Tx) PrepareNamed(query string) (*sqlx.NamedStmt, error) {
+	tx.track(query)
+	return tx.Tx.PrepareNamed(query)
+}
+
+func (tx *Tx) PrepareNamedContext(ctx context.Context, query string) (*sqlx.NamedStmt, error) {
+	tx.track(query)
+	return tx.Tx.PrepareNamedContext(ctx, query)
 }
@@ -0,0 +1,68 @@
+package pop
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// TxPanic is the error returned by the Transaction and Rollback of the
+// connections returned by RecoverTx when their inner function panicked:
+// the transaction was rolled back and the panic recovered. Without
+// RecoverTx, the panic is raised again with its own value.
+type TxPanic struct {
+	// Value is the value the inner function panicked with.
+	Value interface{}
+	// TX is the ID of the transaction.
+	TX int
+	// Statement is the last statement run in the transaction, the one in
+	// flight when the panic was raised from the driver.
+	Statement string
+	// Stack is the stack of the goroutine at the time of the panic.
+	Stack []byte
+	// RollbackErr is the error of the rollback of the transaction, if it
+	// failed.
+	RollbackErr error
+}
+
+func (p *TxPanic) Error() string {
+	if p.Statement == "" {
+		return fmt.Sprintf("panic in transaction %d: %v", p.TX, p.Value)
+	}
+	return fmt.Sprintf("panic in transaction %d after %q: %v", p.TX, p.Statement, p.Value)
+}
+
+// Unwrap returns the value of the panic when it is an error.
+func (p *TxPanic) Unwrap() error {
+	err, _ := p.Value.(error)
+	return err
+}
+
+// newTxPanic wraps the value recovered from the inner function of the
+// transaction cn. A *TxPanic value, e.g. returned by RecoverTx and raised
+// again, is kept as is.
+func newTxPanic(cn *Connection, v interface{}) *TxPanic {
+	if p, ok := v.(*TxPanic); ok {
+		return p
+	}
+	p := &TxPanic{Value: v, Stack: debug.Stack()}
+	if cn.TX != nil {
+		p.TX = cn.TX.ID
+		p.Statement = cn.TX.Statement()
+	}
+	return p
+}
+
+// RecoverTx returns a copy of the connection whose Transaction and Rollback
+// return the panics of their inner function as a *TxPanic error rather
+// than raising them again, e.g. for the job runners which must not crash.
+//
+//	err := c.RecoverTx().Transaction(func(tx *pop.Connection) error {
+//		return job.Run(tx)
+//	})
+func (c *Connection) RecoverTx() *Connection {
+	cn := c.copy()
+	cn.eager = c.eager
+	cn.eagerFields = c.eagerFields
+	cn.recoverTx = true
+	return cn
+}
@@ -0,0 +1,271 @@
+package pop
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+// UnitOfWork collects the creates, updates and deletes of models, e.g.
+// while handling a request, and runs them together in one transaction on
+// Flush. The code registering them does not talk to the database in the
+// meantime.
+//
+// Flush runs the creates, then the updates, then the deletes. The models
+// are ordered by their associations: the owners of belongs_to
+// associations are created and updated before the models belonging to
+// them, and deleted after them. A created model whose belongs_to field
+// points to another created model gets its foreign key set, as Create
+// does.
+//
+//	uow := pop.NewUnitOfWork(tx)
+//	uow.Create(book)   // book.User == user
+//	uow.Create(user)
+//	err := uow.Flush() // creates user, then book with its user_id
+type UnitOfWork struct {
+	conn *Connection
+
+	mu  sync.Mutex
+	ops []unitOfWorkOp
+}
+
+type unitOfWorkOp struct {
+	op             operation
+	model          interface{}
+	excludeColumns []string
+}
+
+// NewUnitOfWork returns an empty unit of work flushed on c.
+func NewUnitOfWork(c *Connection) *UnitOfWork {
+	return &UnitOfWork{conn: c}
+}
+
+// Create registers the creation of the model, or slice of models, see
+// Connection.Create.
+func (u *UnitOfWork) Create(model interface{}, excludeColumns ...string) {
+	u.register(Insert, model, excludeColumns)
+}
+
+// Update registers the update of the model, or slice of models, see
+// Connection.Update.
+func (u *UnitOfWork) Update(model interface{}, excludeColumns ...string) {
+	u.register(Update, model, excludeColumns)
+}
+
+// Destroy registers the deletion of the model, or slice of models, see
+// Connection.Destroy.
+func (u *UnitOfWork) Destroy(model interface{}) {
+	u.register(Delete, model, nil)
+}
+
+func (u *UnitOfWork) register(op operation, model interface{}, excludeColumns []string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.ops = append(u.ops, unitOfWorkOp{op: op, model: model, excludeColumns: excludeColumns})
+}
+
+// Len returns the number of operations waiting for Flush.
+func (u *UnitOfWork) Len() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return len(u.ops)
+}
+
+// Discard forgets the operations registered since the last Flush.
+func (u *UnitOfWork) Discard() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.ops = nil
+}
+
+// Flush runs the registered operations in one transaction. When the
+// connection is in a transaction already, e.g. the one of the request,
+// they run in a savepoint of it, or in it on the databases without
+// savepoints. They are forgotten once done; on error, they are rolled
+// back and kept, but the models may hold the IDs and timestamps set before
+// the failure.
+func (u *UnitOfWork) Flush() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if len(u.ops) == 0 {
+		return nil
+	}
+
+	run := u.conn.Transaction
+	if u.conn.TX != nil {
+		run = func(fn func(tx *Connection) error) error {
+			if supports(u.conn.Dialect, supportsSavepoints) {
+				return u.conn.savepointTransaction(fn, false)
+			}
+			return fn(u.conn)
+		}
+	}
+
+	ops := u.sortedOps()
+	err := run(func(tx *Connection) error {
+		for _, o := range ops {
+			var err error
+			switch o.op {
+			case Insert:
+				err = tx.Create(o.model, o.excludeColumns...)
+			case Update:
+				err = tx.Update(o.model, o.excludeColumns...)
+			case Delete:
+				err = tx.Destroy(o.model)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	u.ops = nil
+	return nil
+}
+
+// sortedOps returns the creates, the updates and the deletes, each sorted
+// by the rank of their table, reversed for the deletes.
+func (u *UnitOfWork) sortedOps() []unitOfWorkOp {
+	ctx := u.conn.Context()
+	tables := make([]string, len(u.ops))
+	for i, o := range u.ops {
+		tables[i] = NewModel(o.model, ctx).TableName()
+	}
+	rank := u.tableRanks(ctx, tables)
+
+	phase := map[operation]int{Insert: 0, Update: 1, Delete: 2}
+	idx := make([]int, len(u.ops))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		oa, ob := u.ops[idx[a]], u.ops[idx[b]]
+		if phase[oa.op] != phase[ob.op] {
+			return phase[oa.op] < phase[ob.op]
+		}
+		ra, rb := rank[tables[idx[a]]], rank[tables[idx[b]]]
+		if oa.op == Delete {
+			return ra > rb
+		}
+		return ra < rb
+	})
+
+	ops := make([]unitOfWorkOp, len(idx))
+	for i, j := range idx {
+		ops[i] = u.ops[j]
+	}
+	return ops
+}
+
+// tableRanks orders the tables of the operations so that the owner of a
+// belongs_to, has_one or has_many association comes before the table of
+// the models belonging to it. The tables in a cycle keep the order they
+// were registered in.
+func (u *UnitOfWork) tableRanks(ctx context.Context, tables []string) map[string]int {
+	order := []string{}
+	owners := map[string]map[string]bool{}
+	for _, t := range tables {
+		if owners[t] == nil {
+			order = append(order, t)
+			owners[t] = map[string]bool{}
+		}
+	}
+	for i, o := range u.ops {
+		for _, a := range ownedAssociations(ctx, o.model) {
+			if a.owner == "" {
+				a.owner = tables[i]
+			} else {
+				a.owned = tables[i]
+			}
+			// only the tables of the unit of work are ordered.
+			if a.owner != a.owned && owners[a.owner] != nil && owners[a.owned] != nil {
+				owners[a.owned][a.owner] = true
+			}
+		}
+	}
+
+	rank := map[string]int{}
+	for len(rank) < len(order) {
+		next := ""
+		for _, t := range order {
+			if _, ok := rank[t]; ok {
+				continue
+			}
+			if next == "" {
+				// the first table left, taken on a cycle.
+				next = t
+			}
+			ready := true
+			for owner := range owners[t] {
+				if _, ok := rank[owner]; !ok {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = t
+				break
+			}
+		}
+		rank[next] = len(rank)
+	}
+	return rank
+}
+
+// tableAssociation is an association between the tables of two models;
+// the side left empty is the table of the model declaring it.
+type tableAssociation struct {
+	owner string
+	owned string
+}
+
+// ownedAssociations returns the belongs_to, has_one and has_many
+// associations declared by the model.
+func ownedAssociations(ctx context.Context, model interface{}) []tableAssociation {
+	t := reflectx.Deref(reflect.TypeOf(model))
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = reflectx.Deref(t.Elem())
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var asos []tableAssociation
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		ft := reflectx.Deref(f.Type)
+		if ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			ft = reflectx.Deref(ft.Elem())
+		}
+		if ft.Kind() != reflect.Struct {
+			continue
+		}
+		switch {
+		case f.Tag.Get("belongs_to") != "":
+			asos = append(asos, tableAssociation{owner: NewModel(reflect.New(ft).Interface(), ctx).TableName()})
+		case f.Tag.Get("has_one") != "", f.Tag.Get("has_many") != "":
+			asos = append(asos, tableAssociation{owned: NewModel(reflect.New(ft).Interface(), ctx).TableName()})
+		}
+	}
+	return asos
+}
+
+type unitOfWorkKey struct{}
+
+// WithUnitOfWork returns a copy of the context carrying the unit of work,
+// e.g. set by a middleware flushing it at the end of the request.
+func WithUnitOfWork(ctx context.Context, u *UnitOfWork) context.Context {
+	return context.WithValue(ctx, unitOfWorkKey{}, u)
+}
+
+// UnitOfWorkFrom returns the unit of work of the context, or nil.
+func UnitOfWorkFrom(ctx context.Context) *UnitOfWork {
+	u, _ := ctx.Value(unitOfWorkKey{}).(*UnitOfWork)
+	return u
+}
@@ -0,0 +1,92 @@
+package pop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobuffalo/nulls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_UnitOfWork_Order(t *testing.T) {
+	r := require.New(t)
+
+	user := &pointerAssocsUser{}
+	book := &pointerAssocsBook{}
+	song := &Song{}
+
+	u := NewUnitOfWork(&Connection{})
+	u.Destroy(user)
+	u.Create(book)
+	u.Update(song)
+	u.Create(user)
+	u.Destroy(book)
+	r.Equal(5, u.Len())
+
+	ops := u.sortedOps()
+	r.Len(ops, 5)
+	r.Equal(unitOfWorkOp{op: Insert, model: user}, ops[0])
+	r.Equal(unitOfWorkOp{op: Insert, model: book}, ops[1])
+	r.Equal(unitOfWorkOp{op: Update, model: song}, ops[2])
+	r.Equal(unitOfWorkOp{op: Delete, model: book}, ops[3])
+	r.Equal(unitOfWorkOp{op: Delete, model: user}, ops[4])
+
+	u.Discard()
+	r.Zero(u.Len())
+
+	ctx := WithUnitOfWork(context.Background(), u)
+	r.Same(u, UnitOfWorkFrom(ctx))
+	r.Nil(UnitOfWorkFrom(context.Background()))
+}
+
+type unitOfWorkMissing struct {
+	ID int `db:"id"`
+}
+
+func (unitOfWorkMissing) TableName() string {
+	return "unit_of_work_missing"
+}
+
+func Test_UnitOfWork_Flush(t *testing.T) {
+	if PDB == nil {
+		t.Skip("skipping integration tests")
+	}
+	transaction(func(tx *Connection) {
+		r := require.New(t)
+
+		user := &pointerAssocsUser{Name: nulls.NewString("Mark")}
+		book := &pointerAssocsBook{Title: "Pop", User: user}
+
+		u := NewUnitOfWork(tx)
+		u.Create(book)
+		u.Create(user)
+		r.Zero(user.ID)
+
+		r.NoError(u.Flush())
+		r.Zero(u.Len())
+		r.NotZero(user.ID)
+		r.Equal(nulls.NewInt(user.ID), book.UserID)
+
+		n, err := tx.Where("user_id = ?", user.ID).Count(&pointerAssocsBook{})
+		r.NoError(err)
+		r.Equal(1, n)
+
+		u.Destroy(user)
+		u.Destroy(book)
+		r.NoError(u.Flush())
+		n, err = tx.Where("user_id = ?", user.ID).Count(&pointerAssocsBook{})
+		r.NoError(err)
+		r.Zero(n)
+
+		other := &pointerAssocsUser{Name: nulls.NewString("Jane")}
+		u.Create(other)
+		u.Create(&unitOfWorkMissing{})
+		r.Error(u.Flush())
+		r.Equal(2, u.Len())
+		if supports(tx.Dialect, supportsSavepoints) {
+			exists, err := tx.Where("id = ?", other.ID).Exists(&pointerAssocsUser{})
+			r.NoError(err)
+			r.False(exists)
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package pop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/WilliamNHarvey/pop/v6/columns"
+	"github.com/WilliamNHarvey/pop/v6/logging"
+	"github.com/gofrs/uuid"
+)
+
+// Upsert inserts the entry, or updates the row conflicting with it on the
+// conflict columns in the same statement: with INSERT ... ON CONFLICT on
+// PostgreSQL, CockroachDB, SQLite and DuckDB, and INSERT ... ON DUPLICATE
+// KEY UPDATE on MySQL and MariaDB, which ignore the conflict columns and
+// update the row conflicting on any unique key.
+//
+// The update columns are the columns updated on conflict, all the inserted
+// columns but the conflict ones, the ID and created_at when none are
+// given; with none of them, the conflicting row is left as it is. The
+// `updated_at` column is updated automatically. The ID of the entry is set
+// to the one of the inserted or updated row.
+//
+// The whole written row is read back into the entry, with the `created_at`
+// of the updated row: with RETURNING, or by a SELECT on the conflict
+// columns on the versions of SQLite without it. MySQL and MariaDB only set
+// CreatedAt when the row is inserted: on conflict it is left as it was
+// before the call, as are the other columns of the entry.
+//
+// Upsert runs the (before|after)Save callbacks, not the Create and Update
+// ones. If model is a slice, each item of the slice is upserted.
+//
+//	err := c.Upsert(&user, []string{"email"}, []string{"name"})
+func (c *Connection) Upsert(model interface{}, conflictColumns, updateColumns []string) error {
+	if err := c.checkWritable(); err != nil {
+		return err
+	}
+	d, ok := c.Dialect.(upsertable)
+	if !ok {
+		return errUnsupported(c.Dialect, "upsert")
+	}
+	sm := NewModel(model, c.Context())
+	return sm.iterate(func(m *Model) error {
+		return c.timeFunc("Upsert", model, func() error {
+			if err := m.beforeSave(c); err != nil {
+				return err
+			}
+
+			keyType, err := m.PrimaryKeyType()
+			if err != nil {
+				return err
+			}
+			if keyType == "UUID" && m.ID() == emptyUUID {
+				u, err := uuid.NewV4()
+				if err != nil {
+					return err
+				}
+				m.setID(u)
+			}
+
+			now := nowFunc().Truncate(time.Microsecond)
+			restoreCreatedAt := m.keepCreatedAt()
+			c.setTimestamps(m, now, true)
+			by := actor(c.Context())
+			m.setCreatedBy(by)
+			m.setUpdatedBy(by)
+
+			cols := restrictColumns(m.Columns(), c.selectColumns, c.omitColumns, m.IDField(), "created_at", "updated_at", createdByColumn, updatedByColumn)
+			w := c.existingColumns(cols).Writeable()
+			if keyType != compositeKeyType && !upsertAutoID(m) {
+				w.Add(m.IDField())
+			}
+
+			update := upsertColumns(m, w, conflictColumns, updateColumns)
+			read, err := d.Upsert(c, m, w, conflictColumns, update)
+			if err != nil {
+				return err
+			}
+			if !read {
+				restoreCreatedAt()
+			}
+			return m.afterSave(c)
+		})
+	})
+}
+
+// upsertColumns returns the columns updated on conflict.
+func upsertColumns(m *Model, w *columns.WriteableColumns, conflict, update []string) []string {
+	var cols []string
+	if len(update) > 0 {
+		cols = append(cols, update...)
+		for _, name := range []string{"updated_at", updatedByColumn} {
+			if _, ok := w.Cols[name]; ok && !containsString(cols, name) {
+				cols = append(cols, name)
+			}
+		}
+		return cols
+	}
+
+	skip := append([]string{m.IDField(), "created_at", createdByColumn}, conflict...)
+	skip = append(skip, m.keyColumns()...)
+	for name := range w.Cols {
+		if !containsString(skip, name) {
+			cols = append(cols, name)
+		}
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+// upsertAutoID reports whether the ID of the model is generated by the
+// database on insert.
+func upsertAutoID(m *Model) bool {
+	keyType, err := m.PrimaryKeyType()
+	if err != nil || !m.UsingAutoIncrement() {
+		return false
+	}
+	switch keyType {
+	case "int", "int64":
+		return IsZeroOfUnderlyingType(m.ID())
+	}
+	return false
+}
+
+// onConflictClause returns the ON CONFLICT clause of the upserts, which
+// update the columns to the ones of the row proposed for insertion.
+func onConflictClause(quoter quotable, conflict, update []string) string {
+	target := make([]string, len(conflict))
+	for i, col := range conflict {
+		target[i] = quoter.Quote(col)
+	}
+	if len(update) == 0 {
+		return fmt.Sprintf("ON CONFLICT (%s) DO NOTHING", strings.Join(target, ", "))
+	}
+	sets := make([]string, len(update))
+	for i, col := range update {
+		sets[i] = fmt.Sprintf("%s = EXCLUDED.%s", quoter.Quote(col), quoter.Quote(col))
+	}
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(target, ", "), strings.Join(sets, ", "))
+}
+
+// genericUpsert upserts the model with INSERT ... ON CONFLICT, reading the
+// inserted or updated row back with RETURNING. Without RETURNING, the
+// written row is read back by its conflict columns once written. It
+// reports false when no row was written, on conflict with DO NOTHING.
+func genericUpsert(c *Connection, model *Model, cols *columns.WriteableColumns, conflict, update []string, quoter quotable) (bool, error) {
+	if len(conflict) == 0 {
+		return false, fmt.Errorf("upsert into %s: the conflict columns are required", model.TableName())
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s", quoter.Quote(model.TableName()), cols.QuotedString(quoter), cols.SymbolizedString(), onConflictClause(quoter, conflict, update))
+	returning := supports(c.Dialect, supportsReturning)
+	if returning {
+		query += " RETURNING " + returnedColumns(c, model, quoter)
+	}
+	query, err := beforeNamedExec(Insert, model, query)
+	if err != nil {
+		return false, err
+	}
+	txlog(logging.SQL, c, query, model.Value)
+	if !returning {
+		res, err := c.Store.NamedExecContext(model.ctx, query, model.Value)
+		if err != nil {
+			return false, err
+		}
+		if n, err := res.RowsAffected(); err == nil && n == 0 {
+			return false, nil
+		}
+		return upsertReadBack(c, model, conflict, quoter)
+	}
+
+	rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
+	if err != nil {
+		return false, err
+	}
+	// DO NOTHING returns no row on conflict
+	read, err := scanReturned(rows, model)
+	if err != nil {
+		return false, fmt.Errorf("upsert: %w", err)
+	}
+	return read, nil
+}
+
+// upsertReadBack reads the row written by an upsert back into the model,
+// selecting it by its conflict columns.
+func upsertReadBack(c *Connection, model *Model, conflict []string, quoter quotable) (bool, error) {
+	where := make([]string, len(conflict))
+	for i, col := range conflict {
+		where[i] = fmt.Sprintf("%s = :%s", quoter.Quote(col), col)
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s", returnedColumns(c, model, quoter), quoter.Quote(model.TableName()), strings.Join(where, " AND "))
+	txlog(logging.SQL, c, query, model.Value)
+	rows, err := c.Store.NamedQueryContext(model.ctx, query, model.Value)
+	if err != nil {
+		return false, err
+	}
+	read, err := scanReturned(rows, model)
+	if err != nil {
+		return false, fmt.Errorf("upsert: %w", err)
+	}
+	return read, nil
+}
@@ -0,0 +1,109 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type subscriber struct {
+	ID        int       `db:"id"`
+	Email     string    `db:"email"`
+	Name      string    `db:"name"`
+	Visits    int       `db:"visits"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+func Test_Upsert(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE subscribers (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, name TEXT NOT NULL, visits INTEGER NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)").Exec())
+
+	first := &subscriber{Email: "ann@example.com", Name: "Ann", Visits: 1}
+	r.NoError(c.Upsert(first, []string{"email"}, nil))
+	r.NotZero(first.ID)
+
+	originalNowFunc := nowFunc
+	defer func() {
+		nowFunc = originalNowFunc
+	}()
+	SetNowFunc(func() time.Time { return originalNowFunc().Add(time.Hour) })
+
+	again := &subscriber{Email: "ann@example.com", Name: "Ann B.", Visits: 2}
+	r.NoError(c.Upsert(again, []string{"email"}, []string{"name"}))
+	r.Equal(first.ID, again.ID)
+	r.Equal(first.CreatedAt.Unix(), again.CreatedAt.Unix())
+	r.Equal(1, again.Visits)
+
+	found := &subscriber{}
+	r.NoError(c.Find(found, first.ID))
+	r.Equal("Ann B.", found.Name)
+	r.Equal(1, found.Visits)
+	r.Equal(first.CreatedAt.Unix(), found.CreatedAt.Unix())
+
+	r.NoError(c.Upsert(&subscriber{Email: "ann@example.com", Name: "Ann C.", Visits: 3}, []string{"email"}, nil))
+	r.NoError(c.Find(found, first.ID))
+	r.Equal("Ann C.", found.Name)
+	r.Equal(3, found.Visits)
+
+	subs := []subscriber{{Email: "bob@example.com", Name: "Bob"}, {Email: "ann@example.com", Name: "Ann D."}}
+	r.NoError(c.Upsert(&subs, []string{"email"}, []string{"name"}))
+	r.NotZero(subs[0].ID)
+	r.Equal(first.ID, subs[1].ID)
+
+	count, err := c.Count(&subscriber{})
+	r.NoError(err)
+	r.Equal(2, count)
+
+	r.Error(c.Upsert(&subscriber{Email: "cid@example.com"}, nil, nil))
+}
+
+func Test_Upsert_WithoutReturning(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	// as on SQLite before 3.35
+	c.Dialect.(*sqlite).returning = false
+	r.False(supports(c.Dialect, supportsReturning))
+	r.NoError(c.RawQuery("CREATE TABLE subscribers (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, name TEXT NOT NULL, visits INTEGER NOT NULL, created_at DATETIME NOT NULL, updated_at DATETIME NOT NULL)").Exec())
+
+	first := &subscriber{Email: "ann@example.com", Name: "Ann", Visits: 1}
+	r.NoError(c.Upsert(first, []string{"email"}, nil))
+	r.NotZero(first.ID)
+
+	originalNowFunc := nowFunc
+	defer func() {
+		nowFunc = originalNowFunc
+	}()
+	SetNowFunc(func() time.Time { return originalNowFunc().Add(time.Hour) })
+
+	// the ID is not generated, the row is read back by its email
+	again := &subscriber{ID: 99, Email: "ann@example.com", Name: "Ann B.", Visits: 2}
+	r.NoError(c.Upsert(again, []string{"email"}, []string{"name"}))
+	r.Equal(first.ID, again.ID)
+	r.Equal(first.CreatedAt.Unix(), again.CreatedAt.Unix())
+	r.Equal(1, again.Visits)
+}
+
+func Test_Upsert_Unsupported(t *testing.T) {
+	r := require.New(t)
+
+	c := &Connection{Dialect: &oracle{commonDialect: commonDialect{ConnectionDetails: &ConnectionDetails{}}}}
+	err := c.Upsert(&subscriber{}, []string{"email"}, nil)
+	r.True(errors.Is(err, ErrUnsupported))
+}
+
+func Test_onConflictClause(t *testing.T) {
+	r := require.New(t)
+
+	p := &postgresql{}
+	r.Equal(`ON CONFLICT ("org_id", "email") DO UPDATE SET "name" = EXCLUDED."name"`, onConflictClause(p, []string{"org_id", "email"}, []string{"name"}))
+	r.Equal(`ON CONFLICT ("email") DO NOTHING`, onConflictClause(p, []string{"email"}, nil))
+}
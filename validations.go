@@ -6,10 +6,50 @@ import (
 	"github.com/gobuffalo/validate/v3"
 )
 
+// Validation contexts set by ValidateAndCreate, ValidateAndUpdate and
+// ValidateAndSave. See (*Connection).ValidationContext.
+const (
+	ValidationCreate = "create"
+	ValidationUpdate = "update"
+	ValidationSave   = "save"
+)
+
 type beforeValidatable interface {
 	BeforeValidations(*Connection) error
 }
 
+// WithValidationContext returns a copy of the connection validating
+// models with the given custom context instead of the operation one.
+//
+//	tx.WithValidationContext("signup").ValidateAndCreate(&user)
+//
+//	func (u *User) Validate(tx *pop.Connection) (*validate.Errors, error) {
+//		if tx.ValidationContext() == "signup" {
+//			// ...
+//		}
+//	}
+func (c *Connection) WithValidationContext(name string) *Connection {
+	cn := c.copy()
+	cn.validationContext = name
+	return cn
+}
+
+// ValidationContext returns the validation context of the connection: the
+// one set with WithValidationContext or, while validating, "create",
+// "update" or "save" depending on the ValidateAnd* method used.
+func (c *Connection) ValidationContext() string {
+	return c.validationContext
+}
+
+// validatingFor returns a connection with the given validation context,
+// unless a custom one is already set.
+func (c *Connection) validatingFor(name string) *Connection {
+	if c.validationContext != "" {
+		return c
+	}
+	return c.WithValidationContext(name)
+}
+
 type validateable interface {
 	Validate(*Connection) (*validate.Errors, error)
 }
@@ -22,10 +62,15 @@ func (m *Model) validate(c *Connection) (*validate.Errors, error) {
 			return validate.NewErrors(), err
 		}
 	}
+	verrs := m.validateColumnSizes(c)
 	if x, ok := m.Value.(validateable); ok {
-		return x.Validate(c)
+		vs, err := x.Validate(c)
+		if vs != nil {
+			verrs.Append(vs)
+		}
+		return verrs, err
 	}
-	return validate.NewErrors(), nil
+	return verrs, nil
 }
 
 type validateCreateable interface {
@@ -33,6 +78,7 @@ type validateCreateable interface {
 }
 
 func (m *Model) validateCreate(c *Connection) (*validate.Errors, error) {
+	c = c.validatingFor(ValidationCreate)
 	return m.iterateAndValidate(func(model *Model) (*validate.Errors, error) {
 		verrs, err := model.validate(c)
 		if err != nil {
@@ -53,6 +99,7 @@ func (m *Model) validateCreate(c *Connection) (*validate.Errors, error) {
 }
 
 func (m *Model) validateAndOnlyCreate(c *Connection) (*validate.Errors, error) {
+	c = c.validatingFor(ValidationCreate)
 	return m.iterateAndValidate(func(model *Model) (*validate.Errors, error) {
 		id, err := model.fieldByName("ID")
 		if err != nil {
@@ -85,6 +132,7 @@ type validateSaveable interface {
 }
 
 func (m *Model) validateSave(c *Connection) (*validate.Errors, error) {
+	c = c.validatingFor(ValidationSave)
 	return m.iterateAndValidate(func(model *Model) (*validate.Errors, error) {
 		verrs, err := model.validate(c)
 		if err != nil {
@@ -109,6 +157,7 @@ type validateUpdateable interface {
 }
 
 func (m *Model) validateUpdate(c *Connection) (*validate.Errors, error) {
+	c = c.validatingFor(ValidationUpdate)
 	return m.iterateAndValidate(func(model *Model) (*validate.Errors, error) {
 		verrs, err := model.validate(c)
 		if err != nil {
@@ -0,0 +1,58 @@
+package pop
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobuffalo/validate/v3"
+	"github.com/stretchr/testify/require"
+)
+
+type contextValidated struct {
+	ID       int      `db:"id"`
+	Before   string   `db:"-"`
+	Contexts []string `db:"-"`
+}
+
+func (v *contextValidated) BeforeValidate(tx *Connection) error {
+	v.Before = tx.ValidationContext()
+	return nil
+}
+
+func (v *contextValidated) Validate(tx *Connection) (*validate.Errors, error) {
+	v.Contexts = append(v.Contexts, tx.ValidationContext())
+	return validate.NewErrors(), nil
+}
+
+func Test_ValidationContext(t *testing.T) {
+	r := require.New(t)
+	c := &Connection{}
+	r.Empty(c.ValidationContext())
+
+	for name, fn := range map[string]func(*Model, *Connection) (*validate.Errors, error){
+		ValidationCreate: (*Model).validateCreate,
+		ValidationUpdate: (*Model).validateUpdate,
+		ValidationSave:   (*Model).validateSave,
+	} {
+		v := &contextValidated{}
+		_, err := fn(NewModel(v, context.Background()), c)
+		r.NoError(err)
+		r.Equal([]string{name}, v.Contexts)
+	}
+	r.Empty(c.ValidationContext())
+
+	signup := c.WithValidationContext("signup")
+	r.Equal("signup", signup.ValidationContext())
+	r.Equal("signup", signup.WithContext(context.Background()).ValidationContext())
+
+	v := &contextValidated{}
+	_, err := NewModel(v, context.Background()).validateUpdate(signup)
+	r.NoError(err)
+	r.Equal([]string{"signup"}, v.Contexts)
+
+	v = &contextValidated{}
+	r.NoError(NewModel(v, context.Background()).beforeValidate(c.validatingFor(ValidationCreate)))
+	r.Equal(ValidationCreate, v.Before)
+	r.NoError(NewModel(v, context.Background()).beforeValidate(signup.validatingFor(ValidationCreate)))
+	r.Equal("signup", v.Before)
+}
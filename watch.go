@@ -0,0 +1,154 @@
+package pop
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// watchBatchSize is the number of rows Watch loads at once.
+const watchBatchSize = 500
+
+// ChangeSet holds the rows changed between two runs of a watched query.
+type ChangeSet struct {
+	// Added is a pointer to a slice of the models of the rows which
+	// started matching the query, all of them on the first run.
+	Added interface{}
+	// Updated is a pointer to a slice of the models of the rows whose
+	// checksum changed.
+	Updated interface{}
+	// Removed are the IDs of the rows which no longer match the query.
+	Removed []string
+	// Err is the error of the run, if it failed. The query is run again
+	// at the next interval.
+	Err error
+}
+
+// Watch runs the query every interval, until the context is done, and
+// sends the rows added, updated and removed since the previous run to the
+// returned channel, which is closed once the context is done. The rows
+// are compared by ID and checksum, see Query.Checksums: only the
+// checksums of the rows are read on each run, and the changed rows are
+// then loaded into the models of the type of models, a pointer to a slice.
+// The runs without a change send nothing.
+//
+// Watch polls, so that it runs on any dialect, whether it can notify the
+// changes or not.
+//
+//	changes := c.Where("status = ?", "open").Watch(ctx, &[]Ticket{}, 5*time.Second)
+//	for cs := range changes {
+//		if cs.Err != nil {
+//			log.Println(cs.Err)
+//			continue
+//		}
+//		dashboard.Apply(cs)
+//	}
+func (q *Query) Watch(ctx context.Context, models interface{}, interval time.Duration) <-chan ChangeSet {
+	changes := make(chan ChangeSet)
+	go func() {
+		defer close(changes)
+		send := func(cs ChangeSet) bool {
+			select {
+			case changes <- cs:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var previous map[string]string
+		for {
+			cs, current, err := q.watchChanges(models, previous)
+			if err != nil {
+				if !send(ChangeSet{Err: err}) {
+					return
+				}
+			} else {
+				changed := previous == nil || len(cs.Removed) > 0 ||
+					reflect.ValueOf(cs.Added).Elem().Len() > 0 || reflect.ValueOf(cs.Updated).Elem().Len() > 0
+				previous = current
+				if changed && !send(cs) {
+					return
+				}
+			}
+
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return changes
+}
+
+// Watch runs the query of the table of the models every interval and
+// sends their changes, see Query.Watch.
+func (c *Connection) Watch(ctx context.Context, models interface{}, interval time.Duration) <-chan ChangeSet {
+	return Q(c).Watch(ctx, models, interval)
+}
+
+// watchChanges returns the changes of the rows matching the query since
+// the previous checksums, and the current ones.
+func (q *Query) watchChanges(models interface{}, previous map[string]string) (ChangeSet, map[string]string, error) {
+	t := reflect.TypeOf(models)
+	if t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Slice || t.Elem().Elem().Kind() != reflect.Struct {
+		return ChangeSet{}, nil, fmt.Errorf("can not watch %T, not a pointer to a slice of structs", models)
+	}
+	model := reflect.New(t.Elem().Elem()).Interface()
+	_, inDatabase := q.Connection.Dialect.(rowChecksummable)
+	current, err := q.checksums(model, "", nil, inDatabase)
+	if err != nil {
+		return ChangeSet{}, nil, err
+	}
+
+	var added, updated []interface{}
+	for id, sum := range current {
+		was, ok := previous[id]
+		switch {
+		case !ok:
+			added = append(added, id)
+		case was != sum:
+			updated = append(updated, id)
+		}
+	}
+	cs := ChangeSet{}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			cs.Removed = append(cs.Removed, id)
+		}
+	}
+	sort.Strings(cs.Removed)
+	if cs.Added, err = q.watchLoad(t, model, added); err != nil {
+		return ChangeSet{}, nil, err
+	}
+	if cs.Updated, err = q.watchLoad(t, model, updated); err != nil {
+		return ChangeSet{}, nil, err
+	}
+	return cs, current, nil
+}
+
+// watchLoad returns a pointer to a slice of the type t of the models with
+// the IDs.
+func (q *Query) watchLoad(t reflect.Type, model interface{}, ids []interface{}) (interface{}, error) {
+	loaded := reflect.New(t.Elem())
+	loaded.Elem().Set(reflect.MakeSlice(t.Elem(), 0, len(ids)))
+	m := NewModel(model, q.Connection.Context())
+	key := fmt.Sprintf("%s.%s", m.Alias(), m.IDField())
+	for start := 0; start < len(ids); start += watchBatchSize {
+		end := start + watchBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		batch := reflect.New(t.Elem())
+		lq := Q(q.Connection)
+		lq.unscoped = q.unscoped
+		if err := lq.Where(key+" IN (?)", ids[start:end]...).Order(key + " ASC").All(batch.Interface()); err != nil {
+			return nil, err
+		}
+		loaded.Elem().Set(reflect.AppendSlice(loaded.Elem(), batch.Elem()))
+	}
+	return loaded.Interface(), nil
+}
@@ -0,0 +1,58 @@
+//go:build sqlite
+// +build sqlite
+
+package pop
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type watchedTicket struct {
+	ID     int    `db:"id"`
+	Title  string `db:"title"`
+	Status string `db:"status"`
+}
+
+func Test_Watch(t *testing.T) {
+	r := require.New(t)
+
+	c := openSQLite(t, nil)
+	r.NoError(c.RawQuery("CREATE TABLE watched_tickets (id INTEGER PRIMARY KEY, title TEXT, status TEXT)").Exec())
+	r.NoError(c.RawQuery("INSERT INTO watched_tickets (id, title, status) VALUES (1, 'a', 'open'), (2, 'b', 'open'), (3, 'c', 'closed')").Exec())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := c.Where("status = ?", "open").Watch(ctx, &[]watchedTicket{}, 10*time.Millisecond)
+
+	cs := <-changes
+	r.NoError(cs.Err)
+	r.ElementsMatch([]watchedTicket{{1, "a", "open"}, {2, "b", "open"}}, *cs.Added.(*[]watchedTicket))
+	r.Empty(*cs.Updated.(*[]watchedTicket))
+	r.Empty(cs.Removed)
+
+	// in a transaction, so that no run sees a part of the changes
+	r.NoError(c.Transaction(func(tx *Connection) error {
+		r.NoError(tx.RawQuery("UPDATE watched_tickets SET title = 'a2' WHERE id = 1").Exec())
+		r.NoError(tx.RawQuery("UPDATE watched_tickets SET status = 'closed' WHERE id = 2").Exec())
+		return tx.RawQuery("UPDATE watched_tickets SET status = 'open' WHERE id = 3").Exec()
+	}))
+
+	cs = <-changes
+	r.NoError(cs.Err)
+	r.Equal([]watchedTicket{{3, "c", "open"}}, *cs.Added.(*[]watchedTicket))
+	r.Equal([]watchedTicket{{1, "a2", "open"}}, *cs.Updated.(*[]watchedTicket))
+	r.Equal([]string{"2"}, cs.Removed)
+
+	cancel()
+	for range changes {
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	cs = <-c.Watch(ctx, []watchedTicket{}, time.Hour)
+	r.Error(cs.Err)
+}
@@ -0,0 +1,96 @@
+package pop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+)
+
+// whereHasClause holds an association existence filter. It is resolved
+// against the model only when the SQL is built, since the model is not
+// known when the query is being set up.
+type whereHasClause struct {
+	Association string
+	Negate      bool
+	Query       *Query
+}
+
+type whereHasClauses []whereHasClause
+
+// toClause builds an EXISTS (or NOT EXISTS) sub-query for the association
+// of the given parent model. The soft deleted records of the association
+// are skipped, unless the query or the sub-query is unscoped.
+func (c whereHasClause) toClause(parent *Model, unscoped bool) (clause, error) {
+	mmi := NewModelMetaInfo(parent)
+	fi := mmi.GetByPath(c.Association)
+	if fi == nil {
+		return clause{}, fmt.Errorf("field %s does not exist in model %s", c.Association, parent.TableName())
+	}
+	if !isFieldAssociation(fi.Field) {
+		return clause{}, fmt.Errorf("field %s of model %s is not an association", c.Association, parent.TableName())
+	}
+	asoc := NewAssociationMetaInfo(fi)
+
+	asocModel := NewModel(asoc.toSlice().Interface(), parent.ctx)
+	asocTable := asocModel.TableName()
+	asocAlias := asocModel.Alias()
+
+	var from string
+	var where []string
+	switch {
+	case fi.Field.Tag.Get("has_many") != "" || fi.Field.Tag.Get("has_one") != "":
+		fk := fi.Field.Tag.Get("fk_id")
+		if fk == "" {
+			fk = parent.associationName()
+		}
+		from = fmt.Sprintf("%s AS %s", asocTable, asocAlias)
+		where = append(where, fmt.Sprintf("%s.%s = %s.%s", asocAlias, fk, parent.Alias(), parent.IDField()))
+	case fi.Field.Tag.Get("belongs_to") != "":
+		fk := asoc.fkName()
+		if mmi.getDBFieldTaggedWith(fk) == nil {
+			fk = fmt.Sprintf("%s%s", flect.Underscore(asoc.Path), "_id")
+		}
+		pk := "id"
+		if tag := fi.Field.Tag.Get("primary_id"); tag != "" {
+			pk = flect.Underscore(tag)
+		}
+		from = fmt.Sprintf("%s AS %s", asocTable, asocAlias)
+		where = append(where, fmt.Sprintf("%s.%s = %s.%s", asocAlias, pk, parent.Alias(), fk))
+	case fi.Field.Tag.Get("many_to_many") != "":
+		joinTable := fi.Field.Tag.Get("many_to_many")
+		parentFk := parent.associationName()
+		if strings.Contains(joinTable, ":") {
+			parentFk = strings.TrimSpace(joinTable[strings.Index(joinTable, ":")+1:])
+			joinTable = strings.TrimSpace(joinTable[:strings.Index(joinTable, ":")])
+		}
+		from = fmt.Sprintf("%s AS %s JOIN %s ON %s.%s = %s.id", asocTable, asocAlias, joinTable, joinTable, asoc.fkName(), asocAlias)
+		where = append(where, fmt.Sprintf("%s.%s = %s.%s", joinTable, parentFk, parent.Alias(), parent.IDField()))
+	}
+
+	if c.Query != nil && c.Query.unscoped {
+		unscoped = true
+	}
+	var args []interface{}
+	if c.Query != nil && len(c.Query.whereClauses) > 0 {
+		fragment := c.Query.whereClauses.Join(" AND ")
+		if asocModel.deletedAtColumn() != "" && !unscoped {
+			// an OR in the clauses would bypass the scope
+			fragment = "(" + fragment + ")"
+		}
+		where = append(where, fragment)
+		args = append(args, c.Query.whereClauses.Args()...)
+	}
+	if column := asocModel.deletedAtColumn(); column != "" && !unscoped {
+		where = append(where, fmt.Sprintf("%s.%s IS NULL", asocAlias, column))
+	}
+
+	exists := "EXISTS"
+	if c.Negate {
+		exists = "NOT EXISTS"
+	}
+	return clause{
+		Fragment:  fmt.Sprintf("%s (SELECT 1 FROM %s WHERE %s)", exists, from, strings.Join(where, " AND ")),
+		Arguments: args,
+	}, nil
+}